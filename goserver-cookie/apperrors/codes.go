@@ -0,0 +1,349 @@
+package apperrors
+
+import "net/http"
+
+// Code adalah kode error yang stabil dan bisa dibaca mesin.
+// Nilai kode tidak boleh diubah setelah dirilis karena dipakai oleh client.
+type Code string
+
+const (
+	CodeInternal         Code = "internal_error"
+	CodeRouteNotFound    Code = "route_not_found"
+	CodeMethodNotAllowed Code = "method_not_allowed"
+
+	// Input request
+	CodeInvalidJSON      Code = "invalid_json"
+	CodeInvalidForm      Code = "invalid_form"
+	CodeValidationFailed Code = "validation_failed"
+
+	// Autentikasi
+	CodeTokenMissing       Code = "token_missing"
+	CodeTokenInvalid       Code = "token_invalid"
+	CodeInvalidCredentials Code = "invalid_credentials"
+	CodeAdminRequired      Code = "admin_required"
+
+	// User
+	CodeInvalidUserID    Code = "invalid_user_id"
+	CodeUserNotFound     Code = "user_not_found"
+	CodeUserExists       Code = "user_exists"
+	CodeNotProfileOwner  Code = "not_profile_owner"
+	CodeUsernameReserved Code = "username_reserved"
+
+	// Produk
+	CodeInvalidProductID  Code = "invalid_product_id"
+	CodeProductNotFound   Code = "product_not_found"
+	CodePriceRequired     Code = "price_required"
+	CodeInvalidPrice      Code = "invalid_price"
+	CodeNotProductOwner   Code = "not_product_owner"
+	CodeOwnerMismatch     Code = "owner_mismatch"
+	CodeImageUploadFailed Code = "image_upload_failed"
+	CodeInvalidCursor     Code = "invalid_cursor"
+	CodeInvalidImageID    Code = "invalid_image_id"
+	CodeImageNotFound     Code = "image_not_found"
+	CodeInvalidVariantID  Code = "invalid_variant_id"
+	CodeVariantNotFound   Code = "variant_not_found"
+	CodeSKUTaken          Code = "sku_taken"
+	CodeVariantExists     Code = "variant_exists"
+	CodeOptionInUse       Code = "option_in_use"
+	CodeInvalidRevision   Code = "invalid_revision"
+	CodeRevisionNotFound  Code = "revision_not_found"
+	CodeVersionMismatch   Code = "version_mismatch"
+	CodeProductModified   Code = "product_modified"
+
+	// Status dan jadwal publikasi produk
+	CodeInvalidStatusTransition Code = "invalid_status_transition"
+
+	// Stok dan reservasi
+	CodeInsufficientStock    Code = "insufficient_stock"
+	CodeInvalidReservationID Code = "invalid_reservation_id"
+	CodeReservationNotFound  Code = "reservation_not_found"
+	CodeNotReservationOwner  Code = "not_reservation_owner"
+	CodeReservationClosed    Code = "reservation_closed"
+
+	// Kategori
+	CodeInvalidCategoryID   Code = "invalid_category_id"
+	CodeCategoryNotFound    Code = "category_not_found"
+	CodeCategoryExists      Code = "category_exists"
+	CodeCategoryHasChildren Code = "category_has_children"
+
+	// Kurs
+	CodeExchangeRateNotFound Code = "exchange_rate_not_found"
+	CodeInvalidCSV           Code = "invalid_csv"
+
+	// Import dan export produk
+	CodeInvalidImportFile    Code = "invalid_import_file"
+	CodeInvalidImportArchive Code = "invalid_import_archive"
+	CodeInvalidImportID      Code = "invalid_import_id"
+	CodeImportNotFound       Code = "import_not_found"
+	CodeImportKeyMismatch    Code = "import_key_mismatch"
+	CodeExternalIDTaken      Code = "external_id_taken"
+	CodeImageFetchFailed     Code = "image_fetch_failed"
+	CodeImportInterrupted    Code = "import_interrupted"
+
+	// GraphQL
+	CodeQueryTooDeep    Code = "query_too_deep"
+	CodeQueryTooComplex Code = "query_too_complex"
+)
+
+// entry adalah definisi satu kode error di katalog
+type entry struct {
+	Status   int
+	Messages map[Lang]string
+}
+
+// catalog berisi semua kode error beserta status HTTP dan pesan per bahasa
+var catalog = map[Code]entry{
+	CodeInternal: {http.StatusInternalServerError, map[Lang]string{
+		LangEN: "An unexpected error occurred",
+		LangID: "Terjadi kesalahan pada server",
+	}},
+	CodeRouteNotFound: {http.StatusNotFound, map[Lang]string{
+		LangEN: "Route not found",
+		LangID: "Route tidak ditemukan",
+	}},
+	CodeMethodNotAllowed: {http.StatusMethodNotAllowed, map[Lang]string{
+		LangEN: "Method not allowed",
+		LangID: "Method tidak diizinkan",
+	}},
+	CodeInvalidJSON: {http.StatusBadRequest, map[Lang]string{
+		LangEN: "Request body is not valid JSON",
+		LangID: "Body request bukan JSON yang valid",
+	}},
+	CodeInvalidForm: {http.StatusBadRequest, map[Lang]string{
+		LangEN: "Request body is not a valid form",
+		LangID: "Body request bukan form yang valid",
+	}},
+	CodeValidationFailed: {http.StatusBadRequest, map[Lang]string{
+		LangEN: "One or more fields are invalid",
+		LangID: "Satu atau lebih field tidak valid",
+	}},
+	CodeTokenMissing: {http.StatusUnauthorized, map[Lang]string{
+		LangEN: "Token not found",
+		LangID: "Token tidak ditemukan",
+	}},
+	CodeTokenInvalid: {http.StatusUnauthorized, map[Lang]string{
+		LangEN: "Invalid token",
+		LangID: "Token tidak valid",
+	}},
+	CodeInvalidCredentials: {http.StatusUnauthorized, map[Lang]string{
+		LangEN: "Invalid credential",
+		LangID: "Username atau password salah",
+	}},
+	CodeInvalidUserID: {http.StatusBadRequest, map[Lang]string{
+		LangEN: "Invalid user ID",
+		LangID: "ID user tidak valid",
+	}},
+	CodeUserNotFound: {http.StatusNotFound, map[Lang]string{
+		LangEN: "User not found",
+		LangID: "User tidak ditemukan",
+	}},
+	CodeUserExists: {http.StatusConflict, map[Lang]string{
+		LangEN: "Username or email is already in use",
+		LangID: "Username atau Email sudah digunakan",
+	}},
+	CodeNotProfileOwner: {http.StatusForbidden, map[Lang]string{
+		LangEN: "You can only update your own profile",
+		LangID: "Anda hanya boleh mengubah profil milik sendiri",
+	}},
+	CodeUsernameReserved: {http.StatusConflict, map[Lang]string{
+		LangEN: "This username is reserved",
+		LangID: "Username ini sudah dicadangkan",
+	}},
+	CodeInvalidProductID: {http.StatusBadRequest, map[Lang]string{
+		LangEN: "Invalid product ID",
+		LangID: "ID produk tidak valid",
+	}},
+	CodeProductNotFound: {http.StatusNotFound, map[Lang]string{
+		LangEN: "Product not found",
+		LangID: "Produk tidak ditemukan",
+	}},
+	CodePriceRequired: {http.StatusBadRequest, map[Lang]string{
+		LangEN: "Price is required",
+		LangID: "Harga harus diisi",
+	}},
+	CodeInvalidPrice: {http.StatusBadRequest, map[Lang]string{
+		LangEN: "Invalid price format",
+		LangID: "Format harga tidak valid",
+	}},
+	CodeNotProductOwner: {http.StatusForbidden, map[Lang]string{
+		LangEN: "You do not own this product",
+		LangID: "Anda bukan pemilik produk ini",
+	}},
+	CodeOwnerMismatch: {http.StatusForbidden, map[Lang]string{
+		LangEN: "Products can only be owned by the logged-in user",
+		LangID: "Produk hanya boleh dimiliki oleh user yang login",
+	}},
+	CodeImageUploadFailed: {http.StatusInternalServerError, map[Lang]string{
+		LangEN: "Failed to upload image",
+		LangID: "Gagal mengunggah gambar",
+	}},
+	CodeInvalidCursor: {http.StatusBadRequest, map[Lang]string{
+		LangEN: "Invalid cursor, request the first page again with the same filters and sort",
+		LangID: "Cursor tidak valid, minta ulang halaman pertama dengan filter dan sort yang sama",
+	}},
+	CodeInvalidImageID: {http.StatusBadRequest, map[Lang]string{
+		LangEN: "Invalid image ID",
+		LangID: "ID gambar tidak valid",
+	}},
+	CodeImageNotFound: {http.StatusNotFound, map[Lang]string{
+		LangEN: "Image not found",
+		LangID: "Gambar tidak ditemukan",
+	}},
+	CodeInvalidVariantID: {http.StatusBadRequest, map[Lang]string{
+		LangEN: "Invalid variant ID",
+		LangID: "ID varian tidak valid",
+	}},
+	CodeVariantNotFound: {http.StatusNotFound, map[Lang]string{
+		LangEN: "Variant not found",
+		LangID: "Varian tidak ditemukan",
+	}},
+	CodeSKUTaken: {http.StatusConflict, map[Lang]string{
+		LangEN: "SKU is already used by another variant",
+		LangID: "SKU sudah dipakai varian lain",
+	}},
+	CodeVariantExists: {http.StatusConflict, map[Lang]string{
+		LangEN: "A variant with the same option values already exists",
+		LangID: "Varian dengan nilai option yang sama sudah ada",
+	}},
+	CodeOptionInUse: {http.StatusConflict, map[Lang]string{
+		LangEN: "Some variants use option values that would be removed, update or delete those variants first",
+		LangID: "Beberapa varian memakai nilai option yang akan dihapus, ubah atau hapus varian tersebut terlebih dahulu",
+	}},
+	CodeInvalidRevision: {http.StatusBadRequest, map[Lang]string{
+		LangEN: "Invalid revision number",
+		LangID: "Nomor revisi tidak valid",
+	}},
+	CodeRevisionNotFound: {http.StatusNotFound, map[Lang]string{
+		LangEN: "Revision not found",
+		LangID: "Revisi tidak ditemukan",
+	}},
+	CodeVersionMismatch: {http.StatusPreconditionFailed, map[Lang]string{
+		LangEN: "The product has changed since you loaded it, reload it and try again",
+		LangID: "Produk sudah berubah sejak Anda memuatnya, muat ulang lalu coba lagi",
+	}},
+	CodeProductModified: {http.StatusConflict, map[Lang]string{
+		LangEN: "The product was changed by another request at the same time, try again",
+		LangID: "Produk sedang diubah oleh request lain, coba lagi",
+	}},
+	CodeInvalidStatusTransition: {http.StatusConflict, map[Lang]string{
+		LangEN: "The product cannot move from its current status to the requested status, archived products must become drafts before they are published again",
+		LangID: "Status produk tidak bisa diubah ke status yang diminta, produk yang diarsipkan harus dijadikan draft sebelum dipublikasikan lagi",
+	}},
+	CodeInsufficientStock: {http.StatusConflict, map[Lang]string{
+		LangEN: "Not enough stock available",
+		LangID: "Stok yang tersedia tidak cukup",
+	}},
+	CodeInvalidReservationID: {http.StatusBadRequest, map[Lang]string{
+		LangEN: "Invalid reservation ID",
+		LangID: "ID reservasi tidak valid",
+	}},
+	CodeReservationNotFound: {http.StatusNotFound, map[Lang]string{
+		LangEN: "Reservation not found",
+		LangID: "Reservasi tidak ditemukan",
+	}},
+	CodeNotReservationOwner: {http.StatusForbidden, map[Lang]string{
+		LangEN: "You can only change your own reservations",
+		LangID: "Anda hanya boleh mengubah reservasi milik sendiri",
+	}},
+	CodeReservationClosed: {http.StatusConflict, map[Lang]string{
+		LangEN: "Reservation is no longer pending, it was already confirmed, released or has expired",
+		LangID: "Reservasi sudah tidak pending, sudah dikonfirmasi, dilepas atau kedaluwarsa",
+	}},
+	CodeAdminRequired: {http.StatusForbidden, map[Lang]string{
+		LangEN: "Only admins can perform this action",
+		LangID: "Hanya admin yang boleh melakukan aksi ini",
+	}},
+	CodeInvalidCategoryID: {http.StatusBadRequest, map[Lang]string{
+		LangEN: "Invalid category ID",
+		LangID: "ID kategori tidak valid",
+	}},
+	CodeCategoryNotFound: {http.StatusNotFound, map[Lang]string{
+		LangEN: "Category not found",
+		LangID: "Kategori tidak ditemukan",
+	}},
+	CodeCategoryExists: {http.StatusConflict, map[Lang]string{
+		LangEN: "A category with this slug already exists",
+		LangID: "Kategori dengan slug ini sudah ada",
+	}},
+	CodeCategoryHasChildren: {http.StatusConflict, map[Lang]string{
+		LangEN: "Delete or move the sub-categories first",
+		LangID: "Hapus atau pindahkan sub-kategori terlebih dahulu",
+	}},
+	CodeExchangeRateNotFound: {http.StatusNotFound, map[Lang]string{
+		LangEN: "Exchange rate not found",
+		LangID: "Kurs tidak ditemukan",
+	}},
+	CodeInvalidCSV: {http.StatusBadRequest, map[Lang]string{
+		LangEN: "File must be a CSV with the header base,quote,rate and an optional as_of column",
+		LangID: "File harus CSV dengan header base,quote,rate dan kolom as_of opsional",
+	}},
+	CodeInvalidImportFile: {http.StatusBadRequest, map[Lang]string{
+		LangEN: "File must be a CSV with a header of known columns, or JSON Lines with one product per line",
+		LangID: "File harus CSV dengan header berisi kolom yang dikenal, atau JSON Lines dengan satu produk per baris",
+	}},
+	CodeInvalidImportArchive: {http.StatusBadRequest, map[Lang]string{
+		LangEN: "Images archive must be a ZIP file",
+		LangID: "Arsip gambar harus berupa file ZIP",
+	}},
+	CodeInvalidImportID: {http.StatusBadRequest, map[Lang]string{
+		LangEN: "Import ID is invalid",
+		LangID: "ID import tidak valid",
+	}},
+	CodeImportNotFound: {http.StatusNotFound, map[Lang]string{
+		LangEN: "Import not found",
+		LangID: "Import tidak ditemukan",
+	}},
+	CodeImportKeyMismatch: {http.StatusConflict, map[Lang]string{
+		LangEN: "external_id and sku refer to different products",
+		LangID: "external_id dan sku merujuk produk yang berbeda",
+	}},
+	CodeExternalIDTaken: {http.StatusConflict, map[Lang]string{
+		LangEN: "external_id is already used by another of your products, including products in the trash",
+		LangID: "external_id sudah dipakai produk Anda yang lain, termasuk produk di trash",
+	}},
+	CodeImageFetchFailed: {http.StatusBadGateway, map[Lang]string{
+		LangEN: "Image could not be downloaded from its URL",
+		LangID: "Gambar tidak bisa diunduh dari URL-nya",
+	}},
+	CodeImportInterrupted: {http.StatusInternalServerError, map[Lang]string{
+		LangEN: "Import stopped because the server restarted, run it again",
+		LangID: "Import berhenti karena server dijalankan ulang, jalankan lagi",
+	}},
+	CodeQueryTooDeep: {http.StatusBadRequest, map[Lang]string{
+		LangEN: "Query is nested too deeply",
+		LangID: "Query terlalu dalam",
+	}},
+	CodeQueryTooComplex: {http.StatusBadRequest, map[Lang]string{
+		LangEN: "Query is too complex",
+		LangID: "Query terlalu kompleks",
+	}},
+}
+
+// Status mengembalikan status HTTP untuk kode error
+func (c Code) Status() int {
+	if e, ok := catalog[c]; ok {
+		return e.Status
+	}
+	return http.StatusInternalServerError
+}
+
+// Message mengembalikan pesan kode error dalam bahasa yang diminta
+func (c Code) Message(lang Lang) string {
+	e, ok := catalog[c]
+	if !ok {
+		e = catalog[CodeInternal]
+	}
+	if msg, ok := e.Messages[lang]; ok {
+		return msg
+	}
+	return e.Messages[DefaultLang]
+}
+
+// Codes mengembalikan semua kode yang terdaftar di katalog
+func Codes() []Code {
+	codes := make([]Code, 0, len(catalog))
+	for code := range catalog {
+		codes = append(codes, code)
+	}
+	return codes
+}
@@ -0,0 +1,197 @@
+package apperrors
+
+import (
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+// Lang adalah bahasa pesan error yang didukung
+type Lang string
+
+const (
+	LangEN Lang = "en"
+	LangID Lang = "id"
+
+	DefaultLang = LangEN
+)
+
+// supported harus sama urutannya dengan supportedTags
+var (
+	supported     = []Lang{LangEN, LangID}
+	supportedTags = []language.Tag{language.English, language.Indonesian}
+	matcher       = language.NewMatcher(supportedTags)
+)
+
+// ParseAcceptLanguage memilih bahasa terbaik dari header Accept-Language
+func ParseAcceptLanguage(header string) Lang {
+	if header == "" {
+		return DefaultLang
+	}
+	tags, _, err := language.ParseAcceptLanguage(header)
+	if err != nil || len(tags) == 0 {
+		return DefaultLang
+	}
+	_, index, confidence := matcher.Match(tags...)
+	if confidence == language.No {
+		return DefaultLang
+	}
+	return supported[index]
+}
+
+// fieldMessages adalah pesan error validasi per tag validator
+var fieldMessages = map[string]map[Lang]string{
+	"required": {
+		LangEN: "{field} is required",
+		LangID: "{field} harus diisi",
+	},
+	"email": {
+		LangEN: "{field} must be a valid email address",
+		LangID: "Format email tidak valid",
+	},
+	"min": {
+		LangEN: "{field} must be at least {param} characters",
+		LangID: "{field} minimal {param} karakter",
+	},
+	"max": {
+		LangEN: "{field} must be at most {param} characters",
+		LangID: "{field} maksimal {param} karakter",
+	},
+	"gte": {
+		LangEN: "{field} must be at least {param}",
+		LangID: "{field} minimal {param}",
+	},
+	"lte": {
+		LangEN: "{field} must be at most {param}",
+		LangID: "{field} maksimal {param}",
+	},
+	"uuid": {
+		LangEN: "{field} must be a valid UUID",
+		LangID: "{field} harus berupa UUID yang valid",
+	},
+	"price": {
+		LangEN: "{field} must be a positive decimal amount within the allowed price range",
+		LangID: "{field} harus angka desimal positif dalam rentang harga yang diizinkan",
+	},
+	"price_precision": {
+		LangEN: "{field} can have at most {param} decimal places in this currency",
+		LangID: "{field} maksimal punya {param} angka desimal untuk mata uang ini",
+	},
+	"currency": {
+		LangEN: "{field} must be a supported ISO 4217 currency code",
+		LangID: "{field} harus kode mata uang ISO 4217 yang didukung",
+	},
+	"currency_mismatch": {
+		LangEN: "{field} must be the product currency {param}",
+		LangID: "{field} harus sama dengan mata uang produk {param}",
+	},
+	"exchange_rate": {
+		LangEN: "{field} must be a positive decimal with at most {param} decimal places",
+		LangID: "{field} harus angka desimal positif dengan maksimal {param} angka desimal",
+	},
+	"different": {
+		LangEN: "{field} must be different from {param}",
+		LangID: "{field} harus berbeda dari {param}",
+	},
+	"not_future": {
+		LangEN: "{field} cannot be in the future",
+		LangID: "{field} tidak boleh di masa depan",
+	},
+	"future": {
+		LangEN: "{field} must be in the future",
+		LangID: "{field} harus di masa depan",
+	},
+	"schedule_status": {
+		LangEN: "{field} can only be set when status is one of: {param}",
+		LangID: "{field} hanya bisa diisi jika status salah satu dari: {param}",
+	},
+	"image_type": {
+		LangEN: "{field} must be a JPEG, PNG, GIF or WebP image",
+		LangID: "{field} harus berupa gambar JPEG, PNG, GIF atau WebP",
+	},
+	"max_file_size": {
+		LangEN: "{field} must not be larger than {param} bytes",
+		LangID: "{field} tidak boleh lebih dari {param} byte",
+	},
+	"oneof": {
+		LangEN: "{field} must be one of: {param}",
+		LangID: "{field} harus salah satu dari: {param}",
+	},
+	"exists": {
+		LangEN: "{field} refers to data that does not exist",
+		LangID: "{field} merujuk data yang tidak ada",
+	},
+	"no_cycle": {
+		LangEN: "{field} cannot be the category itself or one of its sub-categories",
+		LangID: "{field} tidak boleh kategori itu sendiri atau sub-kategorinya",
+	},
+	"number": {
+		LangEN: "{field} must be a whole number of at least 0",
+		LangID: "{field} harus bilangan bulat minimal 0",
+	},
+	"boolean": {
+		LangEN: "{field} must be true or false",
+		LangID: "{field} harus true atau false",
+	},
+	"datetime": {
+		LangEN: "{field} must be an RFC 3339 date-time or a YYYY-MM-DD date",
+		LangID: "{field} harus berupa waktu RFC 3339 atau tanggal YYYY-MM-DD",
+	},
+	"gtefield": {
+		LangEN: "{field} must be greater than or equal to {param}",
+		LangID: "{field} harus lebih besar atau sama dengan {param}",
+	},
+	"gtfield": {
+		LangEN: "{field} must be after {param}",
+		LangID: "{field} harus setelah {param}",
+	},
+	"sort_field": {
+		LangEN: "{field} contains unknown field {param}, allowed: name, price, created_at, updated_at, relevance",
+		LangID: "{field} berisi field {param} yang tidak dikenal, yang diizinkan: name, price, created_at, updated_at, relevance",
+	},
+	"required_with": {
+		LangEN: "{field} can only be used together with {param}",
+		LangID: "{field} hanya bisa dipakai bersama {param}",
+	},
+	"duplicate": {
+		LangEN: "{field} contains {param} more than once",
+		LangID: "{field} berisi {param} lebih dari sekali",
+	},
+	"delta_sign": {
+		LangEN: "{field} must be positive for restock and return, and negative for sale and damage",
+		LangID: "{field} harus positif untuk restock dan return, dan negatif untuk sale dan damage",
+	},
+	"max_items": {
+		LangEN: "{field} must contain at most {param} items",
+		LangID: "{field} maksimal berisi {param} item",
+	},
+	"image_order": {
+		LangEN: "{field} must list every image of the product exactly once",
+		LangID: "{field} harus berisi setiap gambar produk tepat satu kali",
+	},
+	"single_primary": {
+		LangEN: "{field} can have only one primary image",
+		LangID: "{field} hanya boleh punya satu gambar utama",
+	},
+	"variant_attributes": {
+		LangEN: "{field} must have exactly one allowed value for each option of the product",
+		LangID: "{field} harus berisi tepat satu nilai yang diizinkan untuk setiap option produk",
+	},
+	"invalid": {
+		LangEN: "{field} is invalid",
+		LangID: "Format tidak valid",
+	},
+}
+
+// FieldMessage membuat pesan error untuk satu field berdasarkan tag validator
+func FieldMessage(lang Lang, tag, field, param string) string {
+	messages, ok := fieldMessages[tag]
+	if !ok {
+		messages = fieldMessages["invalid"]
+	}
+	msg, ok := messages[lang]
+	if !ok {
+		msg = messages[DefaultLang]
+	}
+	return strings.NewReplacer("{field}", field, "{param}", param).Replace(msg)
+}
@@ -0,0 +1,118 @@
+package apperrors
+
+import (
+	"errors"
+	"net/http"
+)
+
+// ContentType adalah media type response error sesuai RFC 7807
+const ContentType = "application/problem+json"
+
+// FieldError adalah error validasi untuk satu field input
+type FieldError struct {
+	Field string `json:"field"`
+	Code  string `json:"code"`
+	Param string `json:"-"`
+}
+
+// Error adalah error aplikasi dengan kode dari katalog.
+// Err berisi penyebab internal yang tidak ditampilkan di production.
+type Error struct {
+	Code   Code
+	Fields []FieldError
+	Err    error
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return string(e.Code) + ": " + e.Err.Error()
+	}
+	return string(e.Code)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// New membuat Error dengan kode dari katalog
+func New(code Code) *Error {
+	return &Error{Code: code}
+}
+
+// Wrap membuat Error dengan penyebab internal
+func Wrap(code Code, err error) *Error {
+	return &Error{Code: code, Err: err}
+}
+
+// Validation membuat Error validation_failed dengan daftar field
+func Validation(fields ...FieldError) *Error {
+	return &Error{Code: CodeValidationFailed, Fields: fields}
+}
+
+// ProblemField adalah error field yang sudah dilokalisasi
+type ProblemField struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// Problem adalah body response error (RFC 7807 problem+json)
+type Problem struct {
+	Type      string         `json:"type"`
+	Title     string         `json:"title"`
+	Status    int            `json:"status"`
+	Detail    string         `json:"detail"`
+	Instance  string         `json:"instance,omitempty"`
+	Code      Code           `json:"code"`
+	RequestID string         `json:"request_id,omitempty"`
+	Errors    []ProblemField `json:"errors,omitempty"`
+	Debug     string         `json:"debug,omitempty"`
+}
+
+// Converter diimplementasikan oleh error dari package lain (misalnya services)
+// yang bisa diubah menjadi *Error
+type Converter interface {
+	AppError() *Error
+}
+
+// From mengubah error apa pun menjadi *Error.
+// Error yang tidak dikenal dianggap internal_error.
+func From(err error) *Error {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+	var converter Converter
+	if errors.As(err, &converter) {
+		return converter.AppError()
+	}
+	return Wrap(CodeInternal, err)
+}
+
+// NewProblem membuat Problem dari error apa pun
+func NewProblem(err error, lang Lang, showInternal bool) Problem {
+	appErr := From(err)
+
+	status := appErr.Code.Status()
+	problem := Problem{
+		Type:   "/problems/" + string(appErr.Code),
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: appErr.Code.Message(lang),
+		Code:   appErr.Code,
+	}
+
+	for _, field := range appErr.Fields {
+		problem.Errors = append(problem.Errors, ProblemField{
+			Field:   field.Field,
+			Code:    field.Code,
+			Message: FieldMessage(lang, field.Code, field.Field, field.Param),
+		})
+	}
+
+	// Detail error internal hanya untuk development
+	if showInternal && appErr.Err != nil {
+		problem.Debug = appErr.Err.Error()
+	}
+	return problem
+}
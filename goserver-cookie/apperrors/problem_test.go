@@ -0,0 +1,71 @@
+package apperrors
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestCatalogHasEveryLanguage(t *testing.T) {
+	for _, code := range Codes() {
+		for _, lang := range supported {
+			if catalog[code].Messages[lang] == "" {
+				t.Errorf("code %s has no %s message", code, lang)
+			}
+		}
+	}
+	for tag, messages := range fieldMessages {
+		for _, lang := range supported {
+			if messages[lang] == "" {
+				t.Errorf("field tag %s has no %s message", tag, lang)
+			}
+		}
+	}
+}
+
+func TestParseAcceptLanguage(t *testing.T) {
+	tests := map[string]Lang{
+		"":                        LangEN,
+		"id":                      LangID,
+		"id-ID,id;q=0.9,en;q=0.8": LangID,
+		"en-US,en;q=0.9,id;q=0.8": LangEN,
+		"fr-FR,id;q=0.5":          LangID,
+		"fr-FR":                   LangEN,
+		"not a language":          LangEN,
+	}
+	for header, want := range tests {
+		if got := ParseAcceptLanguage(header); got != want {
+			t.Errorf("ParseAcceptLanguage(%q) = %s, want %s", header, got, want)
+		}
+	}
+}
+
+type converted struct{}
+
+func (converted) Error() string    { return "converted" }
+func (converted) AppError() *Error { return New(CodeProductNotFound) }
+
+func TestNewProblem(t *testing.T) {
+	problem := NewProblem(errors.New("db is down"), LangID, false)
+	if problem.Code != CodeInternal || problem.Status != 500 || problem.Debug != "" {
+		t.Fatalf("internal error leaked or mis-mapped: %+v", problem)
+	}
+	if problem.Detail != "Terjadi kesalahan pada server" {
+		t.Fatalf("detail not localized: %q", problem.Detail)
+	}
+
+	problem = NewProblem(Wrap(CodeInvalidJSON, errors.New("unexpected EOF")), LangEN, true)
+	if problem.Status != 400 || problem.Debug != "unexpected EOF" || problem.Type != "/problems/invalid_json" {
+		t.Fatalf("unexpected problem: %+v", problem)
+	}
+
+	problem = NewProblem(fmt.Errorf("wrapped: %w", converted{}), LangEN, false)
+	if problem.Code != CodeProductNotFound || problem.Status != 404 {
+		t.Fatalf("converter not used: %+v", problem)
+	}
+
+	problem = NewProblem(Validation(FieldError{Field: "password", Code: "min", Param: "6"}), LangID, false)
+	if len(problem.Errors) != 1 || problem.Errors[0].Message != "password minimal 6 karakter" {
+		t.Fatalf("unexpected field errors: %+v", problem.Errors)
+	}
+}
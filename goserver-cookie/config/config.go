@@ -0,0 +1,401 @@
+package config
+
+import (
+	"crypto/rand"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Tanggal default untuk route lama tanpa prefix /api/v1
+var (
+	DefaultLegacyDeprecation = time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC)
+	DefaultLegacySunset      = time.Date(2027, time.April, 30, 0, 0, 0, 0, time.UTC)
+)
+
+// Config menyimpan seluruh konfigurasi aplikasi yang dibaca dari environment
+type Config struct {
+	AppEnv string
+
+	// Alamat listener HTTP dan HTTPS
+	HTTPAddr  string
+	HTTPSAddr string
+
+	// TLS aktif jika TLSCertFile dan TLSKeyFile diisi
+	TLSCertFile       string
+	TLSKeyFile        string
+	TLSReloadInterval time.Duration
+
+	// RedirectHTTP membuat listener HTTP hanya mengarahkan request ke HTTPS
+	RedirectHTTP bool
+
+	// Server gRPC berjalan di port terpisah, memakai TLS yang sama dengan HTTPS
+	GRPCEnabled bool
+	GRPCAddr    string
+
+	// Koneksi database, DBDriver berisi "mysql" atau "sqlite"
+	DBDriver      string
+	DBDSN         string
+	DBAutoMigrate bool
+
+	// CursorSecret menandatangani cursor pagination. Jika CURSOR_SECRET kosong
+	// dibuat acak saat start, sehingga cursor lama tidak berlaku setelah restart.
+	CursorSecret []byte
+
+	// SearchReindex mengisi ulang index pencarian MySQL dari semua produk saat start,
+	// misalnya setelah tabel product_search pertama kali dibuat. Index SQLite selalu diisi ulang.
+	SearchReindex bool
+
+	// AdminUsernames adalah admin yang boleh mengelola kategori. Role diperiksa dari
+	// daftar ini di setiap request dan tidak disimpan ke database. Username unik dan
+	// tidak bisa diganti menjadi salah satu nama ini, sehingga akun admin harus
+	// didaftarkan dengan username tersebut.
+	AdminUsernames []string
+
+	// UploadDir adalah folder penyimpanan gambar produk
+	UploadDir string
+	// ImageArchiveDir menyimpan gambar yang dilepas dari produk tetapi masih bisa
+	// dipulihkan dengan rollback revisi, tidak dilayani publik
+	ImageArchiveDir string
+
+	// Produk di trash dihapus permanen setelah TrashRetention, dicek setiap
+	// TrashPurgeInterval. TrashRetention 0 mematikan purge otomatis.
+	TrashRetention     time.Duration
+	TrashPurgeInterval time.Duration
+
+	// Reservasi stok yang belum dikonfirmasi kedaluwarsa setelah ReservationTTL,
+	// stoknya dilepas oleh pengecekan setiap ReservationSweepInterval
+	ReservationTTL           time.Duration
+	ReservationSweepInterval time.Duration
+
+	// Jadwal publish_at dan unpublish_at produk dijalankan setiap PublishSweepInterval
+	PublishSweepInterval time.Duration
+
+	// Kurs yang waktunya lebih lama dari ExchangeRateMaxAge dianggap basi,
+	// harga hasil konversinya tetap dikirim dengan peringatan rate_stale
+	ExchangeRateMaxAge time.Duration
+
+	// Import produk dengan paling banyak ImportSyncRows baris selesai di dalam
+	// request, import yang lebih besar dijalankan sebagai job di background
+	ImportSyncRows int
+	// ImportAllowPrivateURLs mengizinkan gambar import diunduh dari alamat
+	// loopback atau jaringan privat, hanya untuk development
+	ImportAllowPrivateURLs bool
+
+	// LegacyRoutes melayani route lama di root (tanpa /api/v1) dengan header
+	// Deprecation dan Sunset sampai client selesai pindah
+	LegacyRoutes      bool
+	LegacyDeprecation time.Time
+	LegacySunset      time.Time
+
+	Cookie CookieConfig
+}
+
+// CookieConfig berisi atribut cookie token yang dipakai oleh Login dan Logout
+type CookieConfig struct {
+	Name       string
+	Domain     string
+	Path       string
+	MaxAge     int
+	Secure     bool
+	HTTPOnly   bool
+	SameSite   http.SameSite
+	HostPrefix bool
+}
+
+// TLSEnabled mengembalikan true jika file sertifikat dan key sudah dikonfigurasi
+func (c *Config) TLSEnabled() bool {
+	return c.TLSCertFile != "" && c.TLSKeyFile != ""
+}
+
+// IsProduction mengembalikan true jika aplikasi berjalan di mode production
+func (c *Config) IsProduction() bool {
+	return c.AppEnv == "production"
+}
+
+// CookieName mengembalikan nama cookie token, termasuk prefix __Host- jika aktif
+func (c CookieConfig) CookieName() string {
+	if c.HostPrefix {
+		return "__Host-" + c.Name
+	}
+	return c.Name
+}
+
+// Load membaca konfigurasi dari environment dan memvalidasinya
+func Load() (*Config, error) {
+	cfg := &Config{
+		AppEnv:          getEnv("APP_ENV", "development"),
+		HTTPAddr:        getEnv("HTTP_ADDR", ":8080"),
+		HTTPSAddr:       getEnv("HTTPS_ADDR", ":8443"),
+		GRPCAddr:        getEnv("GRPC_ADDR", ":9090"),
+		TLSCertFile:     os.Getenv("TLS_CERT_FILE"),
+		TLSKeyFile:      os.Getenv("TLS_KEY_FILE"),
+		DBDriver:        getEnv("DB_DRIVER", "mysql"),
+		DBDSN:           os.Getenv("DB_DSN"),
+		UploadDir:       getEnv("UPLOAD_DIR", "./uploads"),
+		ImageArchiveDir: getEnv("IMAGE_ARCHIVE_DIR", "./uploads-archive"),
+
+		AdminUsernames: getEnvList("ADMIN_USERNAMES"),
+		CursorSecret:   []byte(os.Getenv("CURSOR_SECRET")),
+	}
+
+	var err error
+	if cfg.DBAutoMigrate, err = getEnvBool("DB_AUTO_MIGRATE", false); err != nil {
+		return nil, err
+	}
+	if cfg.TLSReloadInterval, err = getEnvDuration("TLS_RELOAD_INTERVAL", 30*time.Second); err != nil {
+		return nil, err
+	}
+	if cfg.RedirectHTTP, err = getEnvBool("HTTP_REDIRECT", cfg.TLSEnabled()); err != nil {
+		return nil, err
+	}
+	if cfg.SearchReindex, err = getEnvBool("SEARCH_REINDEX", false); err != nil {
+		return nil, err
+	}
+	if cfg.TrashRetention, err = getEnvDuration("TRASH_RETENTION", 30*24*time.Hour); err != nil {
+		return nil, err
+	}
+	if cfg.TrashPurgeInterval, err = getEnvDuration("TRASH_PURGE_INTERVAL", time.Hour); err != nil {
+		return nil, err
+	}
+	if cfg.ReservationTTL, err = getEnvDuration("RESERVATION_TTL", 15*time.Minute); err != nil {
+		return nil, err
+	}
+	if cfg.ReservationSweepInterval, err = getEnvDuration("RESERVATION_SWEEP_INTERVAL", time.Minute); err != nil {
+		return nil, err
+	}
+	if cfg.PublishSweepInterval, err = getEnvDuration("PUBLISH_SWEEP_INTERVAL", time.Minute); err != nil {
+		return nil, err
+	}
+	if cfg.ExchangeRateMaxAge, err = getEnvDuration("EXCHANGE_RATE_MAX_AGE", 24*time.Hour); err != nil {
+		return nil, err
+	}
+	if cfg.ImportSyncRows, err = getEnvInt("IMPORT_SYNC_ROWS", 100); err != nil {
+		return nil, err
+	}
+	if cfg.ImportAllowPrivateURLs, err = getEnvBool("IMPORT_ALLOW_PRIVATE_URLS", false); err != nil {
+		return nil, err
+	}
+	if cfg.GRPCEnabled, err = getEnvBool("GRPC_ENABLED", true); err != nil {
+		return nil, err
+	}
+	if cfg.LegacyRoutes, err = getEnvBool("API_LEGACY_ROUTES", true); err != nil {
+		return nil, err
+	}
+	if cfg.LegacyDeprecation, err = getEnvDate("API_LEGACY_DEPRECATED_AT", DefaultLegacyDeprecation); err != nil {
+		return nil, err
+	}
+	if cfg.LegacySunset, err = getEnvDate("API_LEGACY_SUNSET", DefaultLegacySunset); err != nil {
+		return nil, err
+	}
+
+	// Atribut cookie, default Secure mengikuti status TLS
+	cfg.Cookie = CookieConfig{
+		Name:     getEnv("COOKIE_NAME", "token"),
+		Domain:   os.Getenv("COOKIE_DOMAIN"),
+		Path:     getEnv("COOKIE_PATH", "/"),
+		HTTPOnly: true,
+	}
+	if cfg.Cookie.MaxAge, err = getEnvInt("COOKIE_MAX_AGE", 60*60); err != nil {
+		return nil, err
+	}
+	if cfg.Cookie.Secure, err = getEnvBool("COOKIE_SECURE", cfg.TLSEnabled()); err != nil {
+		return nil, err
+	}
+	if cfg.Cookie.HostPrefix, err = getEnvBool("COOKIE_HOST_PREFIX", false); err != nil {
+		return nil, err
+	}
+	if cfg.Cookie.SameSite, err = parseSameSite(getEnv("COOKIE_SAMESITE", "lax")); err != nil {
+		return nil, err
+	}
+
+	if len(cfg.CursorSecret) == 0 {
+		cfg.CursorSecret = make([]byte, 32)
+		if _, err := rand.Read(cfg.CursorSecret); err != nil {
+			return nil, fmt.Errorf("gagal membuat CURSOR_SECRET: %w", err)
+		}
+		log.Println("⚠️ CURSOR_SECRET kosong, memakai secret acak (cursor tidak berlaku setelah restart)")
+	}
+
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// LoadConfig memuat konfigurasi dan menghentikan aplikasi jika tidak valid
+func LoadConfig() *Config {
+	cfg, err := Load()
+	if err != nil {
+		log.Fatal("❌ Konfigurasi tidak valid:", err)
+	}
+	return cfg
+}
+
+func (c *Config) validate() error {
+	if c.DBDriver == "sqlite" && c.DBDSN == "" {
+		return fmt.Errorf("DB_DRIVER=sqlite membutuhkan DB_DSN")
+	}
+	if (c.TLSCertFile == "") != (c.TLSKeyFile == "") {
+		return fmt.Errorf("TLS_CERT_FILE dan TLS_KEY_FILE harus diisi bersamaan")
+	}
+	if c.RedirectHTTP && !c.TLSEnabled() {
+		return fmt.Errorf("HTTP_REDIRECT membutuhkan TLS_CERT_FILE dan TLS_KEY_FILE")
+	}
+	if c.TLSEnabled() && c.RedirectHTTP && c.HTTPAddr == c.HTTPSAddr {
+		return fmt.Errorf("HTTP_ADDR dan HTTPS_ADDR tidak boleh sama")
+	}
+
+	if c.GRPCEnabled && (c.GRPCAddr == c.HTTPAddr || (c.TLSEnabled() && c.GRPCAddr == c.HTTPSAddr)) {
+		return fmt.Errorf("GRPC_ADDR harus berbeda dari HTTP_ADDR dan HTTPS_ADDR")
+	}
+	if c.TLSEnabled() && c.TLSReloadInterval <= 0 {
+		return fmt.Errorf("TLS_RELOAD_INTERVAL harus lebih dari 0")
+	}
+	// Semua isi UPLOAD_DIR dilayani publik di /uploads
+	if insideDir(c.UploadDir, c.ImageArchiveDir) {
+		return fmt.Errorf("IMAGE_ARCHIVE_DIR tidak boleh berada di dalam UPLOAD_DIR")
+	}
+	if c.TrashRetention < 0 {
+		return fmt.Errorf("TRASH_RETENTION tidak boleh negatif")
+	}
+	if c.TrashRetention > 0 && c.TrashPurgeInterval <= 0 {
+		return fmt.Errorf("TRASH_PURGE_INTERVAL harus lebih dari 0")
+	}
+	if c.ReservationTTL <= 0 {
+		return fmt.Errorf("RESERVATION_TTL harus lebih dari 0")
+	}
+	if c.ReservationSweepInterval <= 0 {
+		return fmt.Errorf("RESERVATION_SWEEP_INTERVAL harus lebih dari 0")
+	}
+	if c.PublishSweepInterval <= 0 {
+		return fmt.Errorf("PUBLISH_SWEEP_INTERVAL harus lebih dari 0")
+	}
+	if c.ExchangeRateMaxAge <= 0 {
+		return fmt.Errorf("EXCHANGE_RATE_MAX_AGE harus lebih dari 0")
+	}
+	if c.ImportSyncRows <= 0 {
+		return fmt.Errorf("IMPORT_SYNC_ROWS harus lebih dari 0")
+	}
+	if c.LegacyRoutes && !c.LegacySunset.After(c.LegacyDeprecation) {
+		return fmt.Errorf("API_LEGACY_SUNSET harus setelah API_LEGACY_DEPRECATED_AT")
+	}
+
+	// Browser menolak cookie SameSite=None tanpa atribut Secure
+	if c.Cookie.SameSite == http.SameSiteNoneMode && !c.Cookie.Secure {
+		return fmt.Errorf("COOKIE_SAMESITE=none membutuhkan COOKIE_SECURE=true")
+	}
+
+	// Aturan prefix __Host-: Secure, Path=/ dan tanpa Domain
+	if c.Cookie.HostPrefix {
+		if !c.Cookie.Secure {
+			return fmt.Errorf("COOKIE_HOST_PREFIX membutuhkan COOKIE_SECURE=true")
+		}
+		if c.Cookie.Path != "/" {
+			return fmt.Errorf("COOKIE_HOST_PREFIX membutuhkan COOKIE_PATH=/")
+		}
+		if c.Cookie.Domain != "" {
+			return fmt.Errorf("COOKIE_HOST_PREFIX tidak boleh memakai COOKIE_DOMAIN")
+		}
+	}
+	return nil
+}
+
+// insideDir memeriksa apakah path sama dengan dir atau berada di dalamnya
+func insideDir(dir, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+	if err != nil {
+		return false
+	}
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+func parseSameSite(value string) (http.SameSite, error) {
+	switch strings.ToLower(value) {
+	case "lax":
+		return http.SameSiteLaxMode, nil
+	case "strict":
+		return http.SameSiteStrictMode, nil
+	case "none":
+		return http.SameSiteNoneMode, nil
+	case "", "default":
+		return http.SameSiteDefaultMode, nil
+	default:
+		return 0, fmt.Errorf("COOKIE_SAMESITE tidak dikenal: %q", value)
+	}
+}
+
+func getEnv(key, fallback string) string {
+	if value, ok := os.LookupEnv(key); ok && value != "" {
+		return value
+	}
+	return fallback
+}
+
+// getEnvList membaca daftar yang dipisah koma, item kosong diabaikan
+func getEnvList(key string) []string {
+	var items []string
+	for _, item := range strings.Split(os.Getenv(key), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func getEnvBool(key string, fallback bool) (bool, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback, nil
+	}
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("%s harus berupa boolean: %w", key, err)
+	}
+	return parsed, nil
+}
+
+func getEnvInt(key string, fallback int) (int, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback, nil
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("%s harus berupa angka: %w", key, err)
+	}
+	return parsed, nil
+}
+
+func getEnvDuration(key string, fallback time.Duration) (time.Duration, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback, nil
+	}
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("%s harus berupa durasi (contoh 30s): %w", key, err)
+	}
+	return parsed, nil
+}
+
+// getEnvDate membaca tanggal dengan format 2006-01-02 atau RFC 3339
+func getEnvDate(key string, fallback time.Time) (time.Time, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback, nil
+	}
+	if parsed, err := time.Parse(time.DateOnly, value); err == nil {
+		return parsed, nil
+	}
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%s harus berupa tanggal (contoh 2027-04-30): %w", key, err)
+	}
+	return parsed, nil
+}
@@ -0,0 +1,184 @@
+package controllers
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"server-cookie/apperrors"
+	"server-cookie/models"
+	"server-cookie/services"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// BulkHandler adalah adapter HTTP untuk BulkService
+type BulkHandler struct {
+	service *services.BulkService
+}
+
+// NewBulkHandler membuat BulkHandler dengan dependency yang diberikan
+func NewBulkHandler(service *services.BulkService) *BulkHandler {
+	return &BulkHandler{service: service}
+}
+
+// ExportProducts mengirim produk milik user yang login, atau semua produk
+// dengan scope=all, sebagai file CSV atau JSON Lines
+func (h *BulkHandler) ExportProducts(c *gin.Context) {
+	actor, ok := currentActor(c)
+	if !ok {
+		return
+	}
+	format := c.DefaultQuery("format", models.FormatCSV)
+	if format != models.FormatCSV && format != models.FormatJSONL {
+		respondError(c, services.Validation(apperrors.FieldError{Field: "format", Code: "oneof", Param: "csv jsonl"}))
+		return
+	}
+	var ownerID *uuid.UUID
+	switch c.DefaultQuery("scope", "mine") {
+	case "mine":
+		ownerID = &actor.UserID
+	case "all":
+	default:
+		respondError(c, services.Validation(apperrors.FieldError{Field: "scope", Code: "oneof", Param: "mine all"}))
+		return
+	}
+
+	contentType := "text/csv; charset=utf-8"
+	if format == models.FormatJSONL {
+		contentType = "application/x-ndjson"
+	}
+	filename := "products-" + time.Now().UTC().Format("20060102-150405") + "." + format
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+	c.Status(http.StatusOK)
+
+	if err := h.service.Export(c.Request.Context(), c.Writer, format, ownerID); err != nil {
+		// Header sudah terkirim jika sebagian file sudah ditulis, export hanya bisa dihentikan
+		if c.Writer.Written() {
+			log.Println("❌ Export produk terhenti:", err)
+			c.Abort()
+			return
+		}
+		c.Writer.Header().Del("Content-Type")
+		c.Writer.Header().Del("Content-Disposition")
+		respondError(c, err)
+	}
+}
+
+// ImportProducts membuat atau mengubah produk milik user yang login dari file
+// CSV atau JSON Lines. Import besar dijalankan di background dan dibalas dengan
+// 202 beserta header Location ke status job.
+func (h *BulkHandler) ImportProducts(c *gin.Context) {
+	actor, ok := currentActor(c)
+	if !ok {
+		return
+	}
+	var form ImportProductsForm
+	if !bindForm(c, &form) {
+		return
+	}
+
+	format := form.Format
+	if format == "" {
+		format = models.FormatCSV
+		if ext := strings.ToLower(filepath.Ext(form.File.Filename)); ext == ".jsonl" || ext == ".ndjson" {
+			format = models.FormatJSONL
+		}
+	}
+	file, err := spoolFormFile(form.File)
+	if err != nil {
+		respondError(c, fmt.Errorf("failed to store import file: %w", err))
+		return
+	}
+	var archive services.ImportFile
+	if form.Images != nil {
+		if archive, err = spoolFormFile(form.Images); err != nil {
+			file.Close()
+			respondError(c, fmt.Errorf("failed to store import archive: %w", err))
+			return
+		}
+	}
+
+	job, err := h.service.Import(c.Request.Context(), actor, services.ImportInput{
+		Format:  format,
+		File:    file,
+		Archive: archive,
+		DryRun:  form.DryRun,
+		Lang:    apperrors.ParseAcceptLanguage(c.GetHeader("Accept-Language")),
+	})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	if job.Status == models.ImportQueued {
+		// Status job ada di prefix versi yang sama dengan route import
+		prefix := strings.TrimSuffix(c.Request.URL.Path, "/products/import")
+		c.Header("Location", prefix+"/imports/"+job.Id)
+		c.JSON(http.StatusAccepted, ImportJobEnvelope{Message: "Import started", Job: job})
+		return
+	}
+	c.JSON(http.StatusOK, ImportJobEnvelope{Message: "Import finished", Job: job})
+}
+
+// GetImportJob menampilkan progres dan hasil job import milik user yang login
+func (h *BulkHandler) GetImportJob(c *gin.Context) {
+	actor, ok := currentActor(c)
+	if !ok {
+		return
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondCode(c, apperrors.CodeInvalidImportID, err)
+		return
+	}
+
+	job, err := h.service.Job(c.Request.Context(), actor, id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, ImportJobEnvelope{Job: job})
+}
+
+// spoolFormFile menyalin file upload ke file sementara di disk. File multipart
+// dihapus setelah request selesai, sedangkan import besar masih membacanya di
+// background. File sementara dihapus saat ditutup.
+func spoolFormFile(header *multipart.FileHeader) (services.ImportFile, error) {
+	src, err := header.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer src.Close()
+
+	file, err := os.CreateTemp("", "import-*")
+	if err != nil {
+		return nil, err
+	}
+	spooled := tempFile{file}
+	if _, err := io.Copy(file, src); err != nil {
+		spooled.Close()
+		return nil, err
+	}
+	return spooled, nil
+}
+
+// tempFile adalah file sementara yang dihapus saat ditutup
+type tempFile struct {
+	*os.File
+}
+
+func (f tempFile) Close() error {
+	err := f.File.Close()
+	if removeErr := os.Remove(f.Name()); err == nil {
+		err = removeErr
+	}
+	return err
+}
@@ -0,0 +1,105 @@
+package controllers
+
+import (
+	"net/http"
+	"server-cookie/apperrors"
+	"server-cookie/services"
+	"server-cookie/validation"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// CategoryHandler adalah adapter HTTP untuk CategoryService
+type CategoryHandler struct {
+	service *services.CategoryService
+}
+
+// NewCategoryHandler membuat CategoryHandler dengan dependency yang diberikan
+func NewCategoryHandler(service *services.CategoryService) *CategoryHandler {
+	return &CategoryHandler{service: service}
+}
+
+// ListCategories menampilkan semua kategori sebagai pohon
+func (h *CategoryHandler) ListCategories(c *gin.Context) {
+	tree, err := h.service.Tree(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, CategoryTreeResponse{Categories: tree})
+}
+
+// CreateCategory membuat kategori baru, khusus admin
+func (h *CategoryHandler) CreateCategory(c *gin.Context) {
+	input, ok := bindCategory(c)
+	if !ok {
+		return
+	}
+
+	category, err := h.service.Create(c.Request.Context(), input)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, CategoryEnvelope{Message: "Category created successfully", Category: category})
+}
+
+// UpdateCategory mengubah nama, slug atau parent kategori, khusus admin
+func (h *CategoryHandler) UpdateCategory(c *gin.Context) {
+	categoryID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondCode(c, apperrors.CodeInvalidCategoryID, err)
+		return
+	}
+	input, ok := bindCategory(c)
+	if !ok {
+		return
+	}
+
+	category, err := h.service.Update(c.Request.Context(), categoryID, input)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, CategoryEnvelope{Message: "Category updated successfully", Category: category})
+}
+
+// DeleteCategory menghapus kategori tanpa sub-kategori, khusus admin
+func (h *CategoryHandler) DeleteCategory(c *gin.Context) {
+	categoryID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondCode(c, apperrors.CodeInvalidCategoryID, err)
+		return
+	}
+
+	if err := h.service.Delete(c.Request.Context(), categoryID); err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, DeleteCategoryResponse{
+		Message:    "Category deleted successfully",
+		CategoryID: categoryID,
+	})
+}
+
+// bindCategory membaca dan memvalidasi body JSON kategori.
+// Jika gagal, error sudah dicatat dan ok bernilai false.
+func bindCategory(c *gin.Context) (services.CategoryInput, bool) {
+	var request CategoryRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondCode(c, apperrors.CodeInvalidJSON, err)
+		return services.CategoryInput{}, false
+	}
+	if err := validation.Struct(request); err != nil {
+		respondError(c, err)
+		return services.CategoryInput{}, false
+	}
+
+	input := services.CategoryInput{Name: request.Name, Slug: request.Slug}
+	if request.ParentID != nil {
+		parentID := uuid.MustParse(*request.ParentID)
+		input.ParentID = &parentID
+	}
+	return input, true
+}
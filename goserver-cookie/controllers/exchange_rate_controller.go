@@ -0,0 +1,84 @@
+package controllers
+
+import (
+	"net/http"
+	"server-cookie/apperrors"
+	"server-cookie/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ExchangeRateHandler adalah adapter HTTP untuk ExchangeRateService
+type ExchangeRateHandler struct {
+	service *services.ExchangeRateService
+}
+
+// NewExchangeRateHandler membuat ExchangeRateHandler dengan dependency yang diberikan
+func NewExchangeRateHandler(service *services.ExchangeRateService) *ExchangeRateHandler {
+	return &ExchangeRateHandler{service: service}
+}
+
+// ListExchangeRates menampilkan semua kurs beserta status basinya
+func (h *ExchangeRateHandler) ListExchangeRates(c *gin.Context) {
+	rates, err := h.service.List(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, ExchangeRateListResponse{Rates: rates})
+}
+
+// SetExchangeRate menyimpan atau mengganti kurs satu pasangan mata uang, khusus admin
+func (h *ExchangeRateHandler) SetExchangeRate(c *gin.Context) {
+	var request ExchangeRateRequest
+	if !bindJSON(c, &request) {
+		return
+	}
+
+	rate, err := h.service.Set(c.Request.Context(), services.ExchangeRateInput{
+		Base:  c.Param("base"),
+		Quote: c.Param("quote"),
+		Rate:  request.Rate,
+		AsOf:  request.AsOf,
+	})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, ExchangeRateEnvelope{Message: "Exchange rate saved successfully", Rate: rate})
+}
+
+// DeleteExchangeRate menghapus kurs satu pasangan mata uang, khusus admin
+func (h *ExchangeRateHandler) DeleteExchangeRate(c *gin.Context) {
+	if err := h.service.Delete(c.Request.Context(), c.Param("base"), c.Param("quote")); err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, MessageResponse{Message: "Exchange rate deleted successfully"})
+}
+
+// ImportExchangeRates menyimpan kurs dari file CSV sekaligus, khusus admin
+func (h *ExchangeRateHandler) ImportExchangeRates(c *gin.Context) {
+	var form ImportExchangeRatesForm
+	if !bindForm(c, &form) {
+		return
+	}
+
+	file, err := form.File.Open()
+	if err != nil {
+		respondCode(c, apperrors.CodeInvalidForm, err)
+		return
+	}
+	defer file.Close()
+
+	rates, err := h.service.Import(c.Request.Context(), file)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, ImportExchangeRatesResponse{
+		Message:  "Exchange rates imported successfully",
+		Imported: len(rates),
+		Rates:    rates,
+	})
+}
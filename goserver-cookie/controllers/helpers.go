@@ -0,0 +1,60 @@
+package controllers
+
+import (
+	"server-cookie/apperrors"
+	"server-cookie/middleware"
+	"server-cookie/services"
+	"server-cookie/validation"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// currentActor mengambil user yang login dari klaim JWT di context.
+// Jika gagal, error sudah dicatat dan ok bernilai false.
+func currentActor(c *gin.Context) (services.Actor, bool) {
+	userID, err := uuid.Parse(c.GetString("user_id"))
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.Wrap(apperrors.CodeTokenInvalid, err))
+		return services.Actor{}, false
+	}
+	return services.Actor{UserID: userID, Username: c.GetString("username")}, true
+}
+
+// bindForm mengisi form dari form-data lalu memvalidasinya.
+// Jika gagal, error sudah dicatat dan hasilnya false.
+func bindForm(c *gin.Context, form any) bool {
+	if err := c.ShouldBind(form); err != nil {
+		respondCode(c, apperrors.CodeInvalidForm, err)
+		return false
+	}
+	if err := validation.Struct(form); err != nil {
+		respondError(c, err)
+		return false
+	}
+	return true
+}
+
+// bindJSON mengisi request dari body JSON lalu memvalidasinya.
+// Jika gagal, error sudah dicatat dan hasilnya false.
+func bindJSON(c *gin.Context, request any) bool {
+	if err := c.ShouldBindJSON(request); err != nil {
+		respondCode(c, apperrors.CodeInvalidJSON, err)
+		return false
+	}
+	if err := validation.Struct(request); err != nil {
+		respondError(c, err)
+		return false
+	}
+	return true
+}
+
+// respondError meneruskan error ke ErrorMiddleware
+func respondError(c *gin.Context, err error) {
+	middleware.AbortWithError(c, err)
+}
+
+// respondCode meneruskan error dengan kode katalog ke ErrorMiddleware
+func respondCode(c *gin.Context, code apperrors.Code, cause error) {
+	middleware.AbortWithError(c, apperrors.Wrap(code, cause))
+}
@@ -0,0 +1,191 @@
+package controllers
+
+import (
+	"net/http"
+	"server-cookie/apperrors"
+	"server-cookie/services"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// InventoryHandler adalah adapter HTTP untuk InventoryService
+type InventoryHandler struct {
+	service *services.InventoryService
+}
+
+// NewInventoryHandler membuat InventoryHandler dengan dependency yang diberikan
+func NewInventoryHandler(service *services.InventoryService) *InventoryHandler {
+	return &InventoryHandler{service: service}
+}
+
+// GetInventory menampilkan stok setiap item produk, khusus pemilik produk
+func (h *InventoryHandler) GetInventory(c *gin.Context) {
+	actor, productID, ok := inventoryParams(c)
+	if !ok {
+		return
+	}
+
+	items, err := h.service.Levels(c.Request.Context(), actor, productID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, InventoryResponse{Items: items})
+}
+
+// AdjustStock menambah atau mengurangi stok dan mencatatnya di ledger
+func (h *InventoryHandler) AdjustStock(c *gin.Context) {
+	actor, productID, ok := inventoryParams(c)
+	if !ok {
+		return
+	}
+	var request AdjustStockRequest
+	if !bindJSON(c, &request) {
+		return
+	}
+
+	stock, err := h.service.Adjust(c.Request.Context(), actor, productID, services.AdjustStockInput{
+		VariantID: parseOptionalID(request.VariantID),
+		Delta:     request.Delta,
+		Reason:    request.Reason,
+		Note:      request.Note,
+	})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, StockLevelEnvelope{Message: "Stock adjusted successfully", Stock: stock})
+}
+
+// SetStockThreshold mengubah batas stok menipis satu item produk
+func (h *InventoryHandler) SetStockThreshold(c *gin.Context) {
+	actor, productID, ok := inventoryParams(c)
+	if !ok {
+		return
+	}
+	var request StockThresholdRequest
+	if !bindJSON(c, &request) {
+		return
+	}
+
+	stock, err := h.service.SetThreshold(c.Request.Context(), actor, productID, parseOptionalID(request.VariantID), *request.LowStockThreshold)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, StockLevelEnvelope{Message: "Low stock threshold updated successfully", Stock: stock})
+}
+
+// ListStockMovements menampilkan ledger stok produk, yang terbaru lebih dulu
+func (h *InventoryHandler) ListStockMovements(c *gin.Context) {
+	actor, productID, ok := inventoryParams(c)
+	if !ok {
+		return
+	}
+
+	input := services.MovementListInput{}
+	input.Page, _ = strconv.Atoi(c.DefaultQuery("page", "1"))
+	input.Limit, _ = strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if value := c.Query("variant_id"); value != "" {
+		variantID, err := uuid.Parse(value)
+		if err != nil {
+			respondCode(c, apperrors.CodeInvalidVariantID, err)
+			return
+		}
+		input.VariantID = &variantID
+	}
+
+	list, err := h.service.Movements(c.Request.Context(), actor, productID, input)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, MovementListResponse{
+		Movements:  list.Movements,
+		Page:       list.Page,
+		Limit:      list.Limit,
+		TotalItems: list.TotalItems,
+		TotalPages: list.TotalPages,
+	})
+}
+
+// ReserveStock menahan stok produk untuk user yang login
+func (h *InventoryHandler) ReserveStock(c *gin.Context) {
+	actor, productID, ok := inventoryParams(c)
+	if !ok {
+		return
+	}
+	var request ReserveStockRequest
+	if !bindJSON(c, &request) {
+		return
+	}
+
+	reservation, err := h.service.Reserve(c.Request.Context(), actor, productID, parseOptionalID(request.VariantID), request.Quantity)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, ReservationEnvelope{Message: "Stock reserved successfully", Reservation: reservation})
+}
+
+// ConfirmReservation mengubah reservasi menjadi penjualan
+func (h *InventoryHandler) ConfirmReservation(c *gin.Context) {
+	actor, reservationID, ok := reservationParams(c)
+	if !ok {
+		return
+	}
+
+	reservation, err := h.service.ConfirmReservation(c.Request.Context(), actor, reservationID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, ReservationEnvelope{Message: "Reservation confirmed successfully", Reservation: reservation})
+}
+
+// ReleaseReservation membatalkan reservasi dan mengembalikan stoknya
+func (h *InventoryHandler) ReleaseReservation(c *gin.Context) {
+	actor, reservationID, ok := reservationParams(c)
+	if !ok {
+		return
+	}
+
+	reservation, err := h.service.ReleaseReservation(c.Request.Context(), actor, reservationID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, ReservationEnvelope{Message: "Reservation released successfully", Reservation: reservation})
+}
+
+// inventoryParams membaca actor dan ID produk dari URL.
+// Jika gagal, error sudah dicatat dan ok bernilai false.
+func inventoryParams(c *gin.Context) (services.Actor, uuid.UUID, bool) {
+	actor, ok := currentActor(c)
+	if !ok {
+		return services.Actor{}, uuid.Nil, false
+	}
+	productID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondCode(c, apperrors.CodeInvalidProductID, err)
+		return services.Actor{}, uuid.Nil, false
+	}
+	return actor, productID, true
+}
+
+// reservationParams membaca actor dan ID reservasi dari URL.
+// Jika gagal, error sudah dicatat dan ok bernilai false.
+func reservationParams(c *gin.Context) (services.Actor, uuid.UUID, bool) {
+	actor, ok := currentActor(c)
+	if !ok {
+		return services.Actor{}, uuid.Nil, false
+	}
+	reservationID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondCode(c, apperrors.CodeInvalidReservationID, err)
+		return services.Actor{}, uuid.Nil, false
+	}
+	return actor, reservationID, true
+}
@@ -1,469 +1,593 @@
 package controllers
 
 import (
-	"fmt"
-	"io"
 	"mime/multipart"
 	"net/http"
-	"os"
-	"path/filepath"
-	"server-cookie/database"
-	"server-cookie/models"
+	"server-cookie/apperrors"
+	"server-cookie/money"
+	"server-cookie/services"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
 
-// DeleteImage menghapus file gambar berdasarkan path
-func DeleteImage(filePath string) error {
-	err := os.Remove(filePath)
-	return err
+// CreateProductForm adalah input form-data untuk membuat produk. price adalah
+// angka desimal dalam currency, misalnya 12.50, currency kosong berarti IDR.
+type CreateProductForm struct {
+	Name        string                  `form:"name" validate:"required,min=2,max=100"`
+	Price       string                  `form:"price" validate:"required,price"`
+	Currency    string                  `form:"currency" validate:"omitempty,currency"`
+	UserID      string                  `form:"user_id" validate:"required,uuid"`
+	Images      []*multipart.FileHeader `form:"image" validate:"required,max_items=10,image_type,max_file_size=5242880"`
+	ImageAlts   []string                `form:"image_alt" validate:"max_items=10,dive,max=255"`
+	CategoryIDs []string                `form:"category_ids" validate:"max=10,dive,omitempty,uuid"`
+	Tags        []string                `form:"tags" validate:"max=20,dive,max=50"`
+	// status kosong berarti published, atau draft jika publish_at diisi
+	Status      string     `form:"status" validate:"omitempty,oneof=draft published"`
+	PublishAt   *time.Time `form:"publish_at"`
+	UnpublishAt *time.Time `form:"unpublish_at"`
 }
 
-func GetAllProducts(c *gin.Context) {
-	var products []models.Product
-
-	// Ambil parameter page, limit, dan search dari query string
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
-	search := c.Query("search")
-
-	if page < 1 {
-		page = 1
-	}
-	if limit < 1 {
-		limit = 10
-	}
+// UpdateProductForm adalah input form-data untuk mengubah produk,
+// field yang kosong tidak diubah. category_ids, tags dan image yang dikirim
+// mengganti seluruh isinya, kirim satu nilai kosong untuk mengosongkan.
+// Mata uang produk tidak bisa diganti, currency hanya boleh sama dengan mata uang produk.
+type UpdateProductForm struct {
+	Name        string                  `form:"name" validate:"omitempty,min=2,max=100"`
+	Price       string                  `form:"price" validate:"omitempty,price"`
+	Currency    string                  `form:"currency" validate:"omitempty,currency"`
+	UserID      string                  `form:"user_id" validate:"required,uuid"`
+	Images      []*multipart.FileHeader `form:"image" validate:"omitempty,max_items=10,image_type,max_file_size=5242880"`
+	ImageAlts   []string                `form:"image_alt" validate:"max_items=10,dive,max=255"`
+	CategoryIDs []string                `form:"category_ids" validate:"max=10,dive,omitempty,uuid"`
+	Tags        []string                `form:"tags" validate:"max=20,dive,max=50"`
+}
 
-	// Hitung offset
-	offset := (page - 1) * limit
+// AddImagesForm adalah input form-data untuk menambah gambar produk.
+// image_alt ke-n adalah teks alternatif untuk image ke-n.
+type AddImagesForm struct {
+	Images    []*multipart.FileHeader `form:"image" validate:"required,max_items=10,image_type,max_file_size=5242880"`
+	ImageAlts []string                `form:"image_alt" validate:"max_items=10,dive,max=255"`
+}
 
-	db := database.GetDB()
+// ProductHandler adalah adapter HTTP untuk ProductService
+type ProductHandler struct {
+	service *services.ProductService
+	rates   *services.ExchangeRateService
+}
 
-	// Query dasar dengan preload user dan sorting
-	query := db.Preload("User").Order("created_at DESC")
+// NewProductHandler membuat ProductHandler dengan dependency yang diberikan.
+// rates dipakai untuk mengonversi harga di list dan detail produk.
+func NewProductHandler(service *services.ProductService, rates *services.ExchangeRateService) *ProductHandler {
+	return &ProductHandler{service: service, rates: rates}
+}
 
-	// Jika ada parameter search, tambahkan filter (case-insensitive untuk MariaDB)
-	if search != "" {
-		query = query.Where("LOWER(name) LIKE LOWER(?)", "%"+search+"%")
+// priceConverter menyiapkan konversi harga ke mata uang dari query currency atau
+// pilihan user. Jika gagal, error sudah dicatat dan ok bernilai false.
+func (h *ProductHandler) priceConverter(c *gin.Context) (*services.PriceConverter, bool) {
+	actor, ok := currentActor(c)
+	if !ok {
+		return nil, false
+	}
+	converter, err := h.rates.Converter(c.Request.Context(), actor, c.Query("currency"))
+	if err != nil {
+		respondError(c, err)
+		return nil, false
 	}
+	return converter, true
+}
 
-	// Hitung total produk setelah filter
-	var totalItems int64
-	if err := query.Model(&models.Product{}).Count(&totalItems).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count products", "details": err.Error()})
+func (h *ProductHandler) GetAllProducts(c *gin.Context) {
+	// Baca pagination, filter dan sort dari query string lewat query builder
+	input, err := services.ParseProductQuery(c.Request.URL.Query())
+	if err != nil {
+		respondError(c, err)
 		return
 	}
 
-	// Ambil produk dengan pagination
-	if err := query.Limit(limit).Offset(offset).Find(&products).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve products", "details": err.Error()})
+	actor, ok := currentActor(c)
+	if !ok {
 		return
 	}
-
-	// Ubah ke format ProductResponse
-	var productResponses []models.ProductResponse
-	for _, product := range products {
-		productResponses = append(productResponses, models.ProductResponse{
-			Id:    product.Id.String(),
-			Name:  product.Name,
-			Price: product.Price,
-			Image: product.Image,
-			User: models.UserMinimal{
-				Id:       product.User.Id.String(),
-				Username: product.User.Username,
-			},
-		})
+	converter, ok := h.priceConverter(c)
+	if !ok {
+		return
 	}
+	// Produk yang belum published hanya bisa difilter oleh pemiliknya
+	input.ViewerID = actor.UserID
 
-	// Hitung total halaman
-	totalPages := int((totalItems + int64(limit) - 1) / int64(limit))
+	result, err := h.service.List(c.Request.Context(), input)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	for i := range result.Products {
+		converter.Apply(&result.Products[i])
+	}
 
 	// Kirim response dengan metadata pagination
-	c.JSON(http.StatusOK, gin.H{
-		"products":    productResponses,
-		"page":        page,
-		"limit":       limit,
-		"totalItems":  totalItems,
-		"totalPages":  totalPages,
-		"hasNextPage": page < totalPages,
-	})
+	c.JSON(http.StatusOK, newProductListResponse(result))
 }
 
+// ListTags menampilkan semua tag beserta jumlah produk aktifnya
+func (h *ProductHandler) ListTags(c *gin.Context) {
+	counts, err := h.service.TagCounts(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, TagListResponse{Tags: counts})
+}
 
+func (h *ProductHandler) CreateProduct(c *gin.Context) {
+	actor, ok := currentActor(c)
+	if !ok {
+		return
+	}
 
-
-// func GetAllProducts(c *gin.Context) {
-// 	var products []models.Product
-
-// 	// Ambil parameter page & limit dari query string
-// 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-// 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
-
-// 	if page < 1 {
-// 		page = 1
-// 	}
-// 	if limit < 1 {
-// 		limit = 10
-// 	}
-
-// 	// Hitung offset
-// 	offset := (page - 1) * limit
-
-// 	db := database.GetDB()
-
-// 	// Hitung total produk untuk pagination
-// 	var totalItems int64
-// 	if err := db.Model(&models.Product{}).Count(&totalItems).Error; err != nil {
-// 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count products", "details": err.Error()})
-// 		return
-// 	}
-
-// 	// Ambil produk dengan pagination, preload user, dan urutkan berdasarkan created_at
-// 	if err := db.Preload("User").Order("created_at DESC").Limit(limit).Offset(offset).Find(&products).Error; err != nil {
-// 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve products", "details": err.Error()})
-// 		return
-// 	}
-
-// 	// Ubah ke format ProductResponse
-// 	var productResponses []models.ProductResponse
-// 	for _, product := range products {
-// 		productResponses = append(productResponses, models.ProductResponse{
-// 			Id:    product.Id.String(),
-// 			Name:  product.Name,
-// 			Price: product.Price,
-// 			Image: product.Image,
-// 			User: models.UserMinimal{
-// 				Id:       product.User.Id.String(),
-// 				Username: product.User.Username,
-// 			},
-// 		})
-// 	}
-
-// 	// Hitung total halaman
-// 	totalPages := int((totalItems + int64(limit) - 1) / int64(limit))
-
-// 	// Kirim response dengan metadata pagination
-// 	c.JSON(http.StatusOK, gin.H{
-// 		"products":    productResponses,
-// 		"page":        page,
-// 		"limit":       limit,
-// 		"totalItems":  totalItems,
-// 		"totalPages":  totalPages,
-// 		"hasNextPage": page < totalPages,
-// 	})
-// }
-
-
-// func GetAllProducts(c *gin.Context) {
-// 	var products []models.Product
-
-// 	// Ambil data produk dengan user terkaitnya
-// 	db := database.GetDB()
-// 	if err := db.Preload("User").Order("created_at DESC").Find(&products).Error; err != nil {
-// 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve products", "details": err.Error()})
-// 		return
-// 	}
-
-// 	// Ubah ke format ProductResponse agar hanya menampilkan id & username user
-// 	var productResponses []models.ProductResponse
-// 	for _, product := range products {
-// 		productResponses = append(productResponses, models.ProductResponse{
-// 			Id:    product.Id.String(),
-// 			Name:  product.Name,
-// 			Price: product.Price,
-// 			Image: product.Image,
-// 			User: models.UserMinimal{
-// 				Id:       product.User.Id.String(),
-// 				Username: product.User.Username,
-// 			},
-// 		})
-// 	}
-
-// 	// Kirim response
-// 	c.JSON(http.StatusOK, gin.H{"products": productResponses})
-// }
-
-func UploadImage(file *multipart.FileHeader) (string, error) {
-	uploadDir := "./uploads"
-
-	//cek apakah directory upload ada apa tidak
-	if _, err := os.Stat(uploadDir); os.IsNotExist(err) {
-		os.MkdirAll(uploadDir, os.ModePerm)
-	}
-
-	//memberi name file
-	ext := filepath.Ext(file.Filename)
-	uniqueFilename := uuid.New().String() + ext
-	filePath := filepath.Join(uploadDir, uniqueFilename)
-
-	//buka file yang diunggah file masih disimpan di memory tmp
-	src, err := file.Open()
-	if err != nil {
-		return "", fmt.Errorf("failed to create destination file: %w", err)
+	// Ambil dan validasi data dari form-data
+	var form CreateProductForm
+	if !bindForm(c, &form) {
+		return
 	}
-	defer src.Close()
 
-	dst, err := os.Create(filePath)
+	//handle upload image
+	images, closeImages, err := openUploads(form.Images, form.ImageAlts)
 	if err != nil {
-		return "", fmt.Errorf("failed to create destination file: %w", err)
+		respondCode(c, apperrors.CodeImageUploadFailed, err)
+		return
 	}
-	defer dst.Close()
-
-	// Salin isi file ke lokasi tujuan
-	if _, err := io.Copy(dst, src); err != nil {
-		return "", fmt.Errorf("failed to copy file content: %w", err)
+	defer closeImages()
+
+	response, err := h.service.Create(c.Request.Context(), actor, services.CreateProductInput{
+		Name:        form.Name,
+		Price:       money.Input{Amount: form.Price, Currency: form.Currency},
+		OwnerID:     uuid.MustParse(form.UserID),
+		Images:      images,
+		CategoryIDs: parseIDs(form.CategoryIDs),
+		Tags:        form.Tags,
+		Status:      form.Status,
+		PublishAt:   form.PublishAt,
+		UnpublishAt: form.UnpublishAt,
+	})
+	if err != nil {
+		respondError(c, err)
+		return
 	}
 
-	return filePath, nil
+	c.JSON(http.StatusOK, ProductEnvelope{Message: "Product created successfully", Product: response})
 }
 
-func CreateProduct(c *gin.Context) {
-	var product models.Product
-
-	// Ambil data dari form-data
-	product.Name = c.Request.FormValue("name")
+func (h *ProductHandler) GetProductDetail(c *gin.Context) {
+	// Ambil ID produk dari parameter URL
+	productID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondCode(c, apperrors.CodeInvalidProductID, err)
+		return
+	}
 
-	priceStr := c.Request.FormValue("price")
-	if priceStr == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Price is required"})
+	actor, ok := currentActor(c)
+	if !ok {
+		return
+	}
+	converter, ok := h.priceConverter(c)
+	if !ok {
 		return
 	}
 
-	priceInt, err := strconv.ParseInt(priceStr, 10, 64)
+	productDetail, err := h.service.Get(c.Request.Context(), actor, productID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid price format"})
+		respondError(c, err)
 		return
 	}
-	product.Price = priceInt
+	converter.Apply(productDetail)
 
-	userIdStr := c.Request.FormValue("user_id")
+	// Kirim response
+	setETag(c, productDetail.Version)
+	c.JSON(http.StatusOK, ProductEnvelope{Product: productDetail})
+}
+
+func (h *ProductHandler) UpdateProduct(c *gin.Context) {
+	actor, ok := currentActor(c)
+	if !ok {
+		return
+	}
 
-	// Konversi user_id dari string ke uuid.UUID
-	userId, err := uuid.Parse(userIdStr)
+	// Ambil ID produk dari parameter URL
+	productID, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user_id format"})
+		respondCode(c, apperrors.CodeInvalidProductID, err)
 		return
 	}
-	product.UserId = userId
 
-	//handle upload image
-	file, err := c.FormFile("image")
-	if err == nil {
-		filePath, uploadErr := UploadImage(file)
-		if uploadErr != nil {
-			c.JSON(500, gin.H{"error": "Failed to upload image."})
+	// Ambil dan validasi data dari form-data
+	var form UpdateProductForm
+	if !bindForm(c, &form) {
+		return
+	}
+
+	// Hanya update field yang diisi
+	input := services.UpdateProductInput{OwnerID: uuid.MustParse(form.UserID), IfMatch: ifMatch(c)}
+	if form.Name != "" {
+		input.Name = &form.Name
+	}
+	if form.Price != "" {
+		input.Price = &money.Input{Amount: form.Price, Currency: form.Currency}
+	}
+	if formHas(c, "category_ids") {
+		categoryIDs := parseIDs(form.CategoryIDs)
+		input.CategoryIDs = &categoryIDs
+	}
+	if formHas(c, "tags") {
+		input.Tags = &form.Tags
+	}
+
+	// Handle upload image jika ada
+	if len(form.Images) > 0 {
+		images, closeImages, err := openUploads(form.Images, form.ImageAlts)
+		if err != nil {
+			respondCode(c, apperrors.CodeImageUploadFailed, err)
 			return
 		}
-		product.Image = filePath //simpan path ke database
-	} else {
-		product.Image = ""
+		defer closeImages()
+		input.Images = images
 	}
 
-	// Simpan ke database
-	db := database.GetDB()
-	if db == nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database not initialized"})
+	response, err := h.service.Update(c.Request.Context(), actor, productID, input)
+	if err != nil {
+		respondError(c, err)
 		return
 	}
 
-	if err := db.Create(&product).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create product", "details": err.Error()})
+	setETag(c, response.Version)
+	c.JSON(http.StatusOK, ProductEnvelope{Message: "Product updated successfully", Product: response})
+}
+
+func (h *ProductHandler) DeleteProduct(c *gin.Context) {
+	actor, ok := currentActor(c)
+	if !ok {
 		return
 	}
 
-	// Ambil data lengkap dengan User
-	var createdProduct models.Product
-	if err := db.Preload("User").First(&createdProduct, product.Id).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve created product", "details": err.Error()})
+	// Ambil ID produk dari parameter URL
+	productID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondCode(c, apperrors.CodeInvalidProductID, err)
 		return
 	}
 
-	// Format response sesuai struct custom
-	response := models.ProductResponse{
-		Id:    createdProduct.Id.String(),
-		Name:  createdProduct.Name,
-		Price: createdProduct.Price,
-		Image: createdProduct.Image,
-		User: models.UserMinimal{
-			Id:       createdProduct.User.Id.String(),
-			Username: createdProduct.User.Username,
-		},
+	if err := h.service.Delete(c.Request.Context(), actor, productID, ifMatch(c)); err != nil {
+		respondError(c, err)
+		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"message": "Product created successfully",
-		"product": response,
+	// Response sukses
+	c.JSON(http.StatusOK, DeleteProductResponse{
+		Message:   "Product deleted successfully",
+		ProductID: productID,
 	})
-
 }
 
-func GetProductDetail(c *gin.Context) {
-	var product models.Product
+// SetProductStatus mengubah status dan jadwal publikasi produk milik user yang login
+func (h *ProductHandler) SetProductStatus(c *gin.Context) {
+	actor, ok := currentActor(c)
+	if !ok {
+		return
+	}
 
-	// Ambil ID produk dari parameter URL
-	productID := c.Param("id")
+	productID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondCode(c, apperrors.CodeInvalidProductID, err)
+		return
+	}
 
-	// Ambil data produk berdasarkan ID dengan user terkaitnya
-	db := database.GetDB()
-	if err := db.Preload("User").First(&product, "id = ?", productID).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Product not found"})
+	var request ProductStatusRequest
+	if !bindJSON(c, &request) {
 		return
 	}
 
-	// Ubah ke format ProductDetailResponse
-	productDetail := models.ProductResponse{
-		Id:    product.Id.String(),
-		Name:  product.Name,
-		Price: product.Price,
-		Image: product.Image,
-		User: models.UserMinimal{
-			Id:       product.User.Id.String(),
-			Username: product.User.Username,
-		},
+	response, err := h.service.SetStatus(c.Request.Context(), actor, productID, services.ProductStatusInput{
+		Status:      request.Status,
+		PublishAt:   request.PublishAt,
+		UnpublishAt: request.UnpublishAt,
+		IfMatch:     ifMatch(c),
+	})
+	if err != nil {
+		respondError(c, err)
+		return
 	}
 
-	// Kirim response
-	c.JSON(http.StatusOK, gin.H{"product": productDetail})
+	setETag(c, response.Version)
+	c.JSON(http.StatusOK, ProductEnvelope{Message: "Product status updated successfully", Product: response})
 }
 
-func UpdateProduct(c *gin.Context) {
-	var product models.Product
+// BatchProducts menjalankan beberapa operasi update, delete dan categorize
+// sekaligus pada produk milik user yang login
+func (h *ProductHandler) BatchProducts(c *gin.Context) {
+	actor, ok := currentActor(c)
+	if !ok {
+		return
+	}
+	var request BatchProductsRequest
+	if !bindJSON(c, &request) {
+		return
+	}
 
-	// Ambil ID produk dari parameter URL
-	productIDStr := c.Param("id")
-	productID, err := uuid.Parse(productIDStr)
+	mode := request.Mode
+	if mode == "" {
+		mode = "atomic"
+	}
+	result, err := h.service.Batch(c.Request.Context(), actor, request.Operations, mode == "atomic")
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product_id format"})
+		respondError(c, err)
 		return
 	}
 
-	product.Id = productID
+	lang := apperrors.ParseAcceptLanguage(c.GetHeader("Accept-Language"))
+	response := BatchProductsResponse{Mode: mode, Results: make([]BatchItemResponse, len(result.Items))}
+	for i, item := range result.Items {
+		operation := request.Operations[i]
+		response.Results[i] = BatchItemResponse{Index: i, Op: operation.Op, ID: operation.ID, Status: item.Status, Product: item.Product}
+		switch item.Status {
+		case services.BatchApplied:
+			response.Applied++
+		case services.BatchFailed:
+			response.Failed++
+			problem := apperrors.NewProblem(item.Err, lang, false)
+			response.Results[i].Error = &BatchItemError{Code: problem.Code, Message: problem.Detail, Errors: problem.Errors}
+		}
+	}
+	c.JSON(http.StatusOK, response)
+}
 
-	// Cek apakah produk dengan ID tersebut ada di database
-	db := database.GetDB()
-	if err := db.First(&product, "id = ?", productID).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Product not found"})
+// AddProductImages menambah gambar di akhir urutan gambar produk
+func (h *ProductHandler) AddProductImages(c *gin.Context) {
+	actor, ok := currentActor(c)
+	if !ok {
 		return
 	}
 
-	// Ambil data dari form-data (hanya update jika ada nilai baru)
-	name := c.Request.FormValue("name")
-	if name != "" {
-		product.Name = name
+	productID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondCode(c, apperrors.CodeInvalidProductID, err)
+		return
 	}
 
-	priceStr := c.Request.FormValue("price")
-	if priceStr != "" {
-		priceInt, err := strconv.ParseInt(priceStr, 10, 64)
-		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid price format"})
-			return
-		}
-		product.Price = priceInt
+	var form AddImagesForm
+	if !bindForm(c, &form) {
+		return
+	}
+	images, closeImages, err := openUploads(form.Images, form.ImageAlts)
+	if err != nil {
+		respondCode(c, apperrors.CodeImageUploadFailed, err)
+		return
+	}
+	defer closeImages()
+
+	response, err := h.service.AddImages(c.Request.Context(), actor, productID, images)
+	if err != nil {
+		respondError(c, err)
+		return
 	}
 
-	userIdStr := c.Request.FormValue("user_id")
+	c.JSON(http.StatusOK, ProductEnvelope{Message: "Images added successfully", Product: response})
+}
+
+// ReorderProductImages menyusun ulang gambar produk, sekaligus mengubah alt dan gambar utama
+func (h *ProductHandler) ReorderProductImages(c *gin.Context) {
+	actor, ok := currentActor(c)
+	if !ok {
+		return
+	}
 
-	// Konversi user_id dari string ke uuid.UUID
-	userId, err := uuid.Parse(userIdStr)
+	productID, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user_id format"})
+		respondCode(c, apperrors.CodeInvalidProductID, err)
 		return
 	}
-	product.UserId = userId
 
-	// Handle upload image jika ada
-	file, err := c.FormFile("image")
-	if err == nil {
-		// Hapus gambar lama jika ada sebelum menyimpan gambar baru
-		if product.Image != "" {
-			deleteErr := DeleteImage(product.Image)
-			if deleteErr != nil {
-				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete old image"})
-				return
-			}
-		}
+	var request ReorderImagesRequest
+	if !bindJSON(c, &request) {
+		return
+	}
 
-		// Upload gambar baru
-		filePath, uploadErr := UploadImage(file)
-		if uploadErr != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to upload image"})
-			return
-		}
-		product.Image = filePath // Simpan path ke database
+	order := make([]services.ImageOrderItem, 0, len(request.Images))
+	for _, image := range request.Images {
+		order = append(order, services.ImageOrderItem{
+			ID:      uuid.MustParse(image.ID),
+			Alt:     image.Alt,
+			Primary: image.Primary,
+		})
+	}
+
+	response, err := h.service.ReorderImages(c.Request.Context(), actor, productID, order)
+	if err != nil {
+		respondError(c, err)
+		return
 	}
 
-	// Update produk di database
-	if err := db.Save(&product).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update product", "details": err.Error()})
+	c.JSON(http.StatusOK, ProductEnvelope{Message: "Images updated successfully", Product: response})
+}
+
+// DeleteProductImage menghapus satu gambar produk beserta filenya
+func (h *ProductHandler) DeleteProductImage(c *gin.Context) {
+	actor, ok := currentActor(c)
+	if !ok {
+		return
+	}
+
+	productID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondCode(c, apperrors.CodeInvalidProductID, err)
+		return
+	}
+	imageID, err := uuid.Parse(c.Param("imageId"))
+	if err != nil {
+		respondCode(c, apperrors.CodeInvalidImageID, err)
 		return
 	}
 
-	// Ambil data lengkap dengan User setelah update
-	var updatedProduct models.Product
-	if err := db.Preload("User").First(&updatedProduct, productID).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve updated product", "details": err.Error()})
+	response, err := h.service.RemoveImage(c.Request.Context(), actor, productID, imageID)
+	if err != nil {
+		respondError(c, err)
 		return
 	}
 
-	// Format response sesuai struct custom
-	response := models.ProductResponse{
-		Id:    updatedProduct.Id.String(),
-		Name:  updatedProduct.Name,
-		Price: updatedProduct.Price,
-		Image: updatedProduct.Image,
-		User: models.UserMinimal{
-			Id:       updatedProduct.User.Id.String(),
-			Username: updatedProduct.User.Username,
-		},
+	c.JSON(http.StatusOK, ProductEnvelope{Message: "Image deleted successfully", Product: response})
+}
+
+// ListTrash menampilkan produk milik user yang sedang di trash
+func (h *ProductHandler) ListTrash(c *gin.Context) {
+	actor, ok := currentActor(c)
+	if !ok {
+		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"message": "Product updated successfully",
-		"product": response,
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	result, err := h.service.ListTrash(c.Request.Context(), actor, services.ListProductsInput{
+		Page:  page,
+		Limit: limit,
 	})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newProductListResponse(result))
 }
 
-func DeleteProduct(c *gin.Context) {
-	var product models.Product
+// RestoreProduct mengembalikan produk dari trash
+func (h *ProductHandler) RestoreProduct(c *gin.Context) {
+	actor, ok := currentActor(c)
+	if !ok {
+		return
+	}
 
-	// Ambil ID produk dari parameter URL
-	productIDStr := c.Param("id")
-	productID, err := uuid.Parse(productIDStr)
+	productID, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product_id format"})
+		respondCode(c, apperrors.CodeInvalidProductID, err)
 		return
 	}
 
-	product.Id = productID
+	response, err := h.service.Restore(c.Request.Context(), actor, productID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, ProductEnvelope{Message: "Product restored successfully", Product: response})
+}
 
-	// Cek apakah produk dengan ID tersebut ada di database
-	db := database.GetDB()
-	if err := db.First(&product, "id = ?", productID).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Product not found"})
+// PurgeProduct menghapus permanen produk di trash beserta gambarnya
+func (h *ProductHandler) PurgeProduct(c *gin.Context) {
+	actor, ok := currentActor(c)
+	if !ok {
 		return
 	}
 
-	// Hapus gambar terkait jika ada
-	if product.Image != "" {
-		deleteErr := DeleteImage(product.Image)
-		if deleteErr != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete product image"})
-			return
-		}
+	productID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondCode(c, apperrors.CodeInvalidProductID, err)
+		return
 	}
 
-	// Hapus produk dari database
-	if err := db.Delete(&product).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete product", "details": err.Error()})
+	if err := h.service.Purge(c.Request.Context(), actor, productID); err != nil {
+		respondError(c, err)
 		return
 	}
 
-	// Response sukses
-	c.JSON(http.StatusOK, gin.H{
-		"message":    "Product deleted successfully",
-		"product_id": productID,
+	c.JSON(http.StatusOK, DeleteProductResponse{
+		Message:   "Product permanently deleted",
+		ProductID: productID,
 	})
 }
+
+// setETag mengirim versi produk sebagai ETag agar bisa dikirim kembali di If-Match
+func setETag(c *gin.Context, version int64) {
+	c.Header("ETag", strconv.Quote(strconv.FormatInt(version, 10)))
+}
+
+// ifMatch membaca versi produk dari header If-Match. Header kosong atau "*" berarti
+// versi tidak dicek. ETag lemah (W/) dan yang tidak dikenal diabaikan, sehingga
+// header yang tidak berisi versi valid tidak pernah cocok.
+func ifMatch(c *gin.Context) []int64 {
+	header := strings.TrimSpace(c.GetHeader("If-Match"))
+	if header == "" || header == "*" {
+		return nil
+	}
+	versions := make([]int64, 0)
+	for _, tag := range strings.Split(header, ",") {
+		value, err := strconv.Unquote(strings.TrimSpace(tag))
+		if err != nil {
+			continue
+		}
+		if version, err := strconv.ParseInt(value, 10, 64); err == nil {
+			versions = append(versions, version)
+		}
+	}
+	return versions
+}
+
+// formHas mengecek apakah field dikirim di form, walaupun nilainya kosong
+func formHas(c *gin.Context, key string) bool {
+	if form := c.Request.MultipartForm; form != nil {
+		if _, ok := form.Value[key]; ok {
+			return true
+		}
+	}
+	_, ok := c.Request.PostForm[key]
+	return ok
+}
+
+// parseIDs mengubah daftar UUID yang sudah divalidasi, nilai kosong diabaikan
+func parseIDs(values []string) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(values))
+	for _, value := range values {
+		if value != "" {
+			ids = append(ids, uuid.MustParse(value))
+		}
+	}
+	return ids
+}
+
+// parseOptionalID mengubah UUID opsional yang sudah divalidasi, nil tetap nil
+func parseOptionalID(value *string) *uuid.UUID {
+	if value == nil {
+		return nil
+	}
+	id := uuid.MustParse(*value)
+	return &id
+}
+
+// openUploads membuka semua file upload beserta alt-nya sesuai urutan,
+// fungsi close dipanggil setelah service selesai
+func openUploads(files []*multipart.FileHeader, alts []string) ([]services.ImageUpload, func(), error) {
+	uploads := make([]services.ImageUpload, 0, len(files))
+	var opened []multipart.File
+	closeAll := func() {
+		for _, src := range opened {
+			src.Close()
+		}
+	}
+	for i, file := range files {
+		//buka file yang diunggah file masih disimpan di memory tmp
+		src, err := file.Open()
+		if err != nil {
+			closeAll()
+			return nil, func() {}, err
+		}
+		opened = append(opened, src)
+
+		upload := services.ImageUpload{Filename: file.Filename, Content: src}
+		if i < len(alts) {
+			upload.Alt = alts[i]
+		}
+		uploads = append(uploads, upload)
+	}
+	return uploads, closeAll, nil
+}
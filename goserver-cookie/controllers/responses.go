@@ -0,0 +1,304 @@
+package controllers
+
+import (
+	"mime/multipart"
+	"server-cookie/apperrors"
+	"server-cookie/models"
+	"server-cookie/money"
+	"server-cookie/services"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Struct request dan response API, juga dipakai untuk membuat dokumen OpenAPI
+
+// RegisterRequest adalah body JSON untuk registrasi user
+type RegisterRequest struct {
+	Username string `json:"username"`
+	Email    string `json:"email" validate:"required,email"`
+	Password string `json:"password" validate:"required,min=6"`
+}
+
+// LoginRequest adalah body JSON untuk login
+type LoginRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// UpdateProfileRequest adalah body JSON untuk memperbarui profil.
+// currency adalah mata uang tampilan harga, tidak diubah jika tidak dikirim
+// dan dikosongkan dengan string kosong.
+type UpdateProfileRequest struct {
+	Username string  `json:"username"`
+	Email    string  `json:"email" validate:"required,email"`
+	Password string  `json:"password" validate:"omitempty,min=6"`
+	Currency *string `json:"currency,omitempty" validate:"omitnil,currency"`
+}
+
+// CategoryRequest adalah body JSON untuk membuat atau mengubah kategori
+type CategoryRequest struct {
+	Name     string  `json:"name" validate:"required,min=2,max=100"`
+	Slug     string  `json:"slug,omitempty" validate:"omitempty,max=100"`
+	ParentID *string `json:"parent_id,omitempty" validate:"omitnil,uuid"`
+}
+
+// ReorderImagesRequest adalah body JSON untuk menyusun ulang gambar produk.
+// Images berisi setiap gambar produk sesuai urutan baru, alt dan primary boleh dikosongkan.
+type ReorderImagesRequest struct {
+	Images []ImageOrderRequest `json:"images" validate:"required,max_items=10,dive"`
+}
+
+type ImageOrderRequest struct {
+	ID      string  `json:"id" validate:"required,uuid"`
+	Alt     *string `json:"alt,omitempty" validate:"omitnil,max=255"`
+	Primary *bool   `json:"primary,omitempty"`
+}
+
+// ProductOptionsRequest adalah body JSON untuk mengganti option varian produk,
+// kirim daftar kosong untuk menghapus semua option
+type ProductOptionsRequest struct {
+	Options []OptionRequest `json:"options" validate:"required,max_items=3,dive"`
+}
+
+type OptionRequest struct {
+	Name   string   `json:"name" validate:"required,max=50"`
+	Values []string `json:"values" validate:"required,min=1,max_items=20,dive,required,max=50"`
+}
+
+// VariantRequest adalah body JSON varian. price kosong berarti mengikuti harga produk,
+// bisa berupa string desimal ("12.50") atau object {"amount": "12.50", "currency": "IDR"}.
+// attributes berisi satu nilai untuk setiap option produk, misalnya {"size": "M"}.
+type VariantRequest struct {
+	SKU        string            `json:"sku" validate:"required,max=64"`
+	Price      *money.Input      `json:"price,omitempty"`
+	ImageID    *string           `json:"image_id,omitempty" validate:"omitnil,uuid"`
+	Attributes map[string]string `json:"attributes,omitempty"`
+}
+
+// ProductStatusRequest adalah body JSON status produk. publish_at hanya untuk draft,
+// unpublish_at untuk draft atau published. Jadwal yang tidak dikirim dihapus.
+type ProductStatusRequest struct {
+	Status      string     `json:"status" validate:"required,oneof=draft published archived"`
+	PublishAt   *time.Time `json:"publish_at,omitempty"`
+	UnpublishAt *time.Time `json:"unpublish_at,omitempty"`
+}
+
+// ExchangeRateRequest adalah body JSON kurs, 1 base = rate quote.
+// as_of kosong berarti kurs berlaku saat disimpan.
+type ExchangeRateRequest struct {
+	Rate string     `json:"rate" validate:"required"`
+	AsOf *time.Time `json:"as_of,omitempty"`
+}
+
+// ImportExchangeRatesForm adalah file CSV kurs dengan header base,quote,rate dan kolom as_of opsional
+type ImportExchangeRatesForm struct {
+	File *multipart.FileHeader `form:"file" validate:"required,max_file_size=1048576"`
+}
+
+// ImportProductsForm adalah file import produk beserta arsip ZIP gambar opsional.
+// format kosong ditentukan dari ekstensi file, .jsonl atau .ndjson berarti JSON Lines.
+type ImportProductsForm struct {
+	File   *multipart.FileHeader `form:"file" validate:"required,max_file_size=10485760"`
+	Images *multipart.FileHeader `form:"images" validate:"omitnil,max_file_size=104857600"`
+	Format string                `form:"format" validate:"omitempty,oneof=csv jsonl"`
+	DryRun bool                  `form:"dry_run"`
+}
+
+// BatchProductsRequest adalah body JSON untuk menjalankan beberapa operasi produk sekaligus.
+// mode atomic (default) menyimpan semua operasi atau tidak sama sekali, best_effort
+// menyimpan setiap operasi yang valid.
+type BatchProductsRequest struct {
+	Mode       string                    `json:"mode,omitempty" validate:"omitempty,oneof=atomic best_effort"`
+	Operations []services.BatchOperation `json:"operations" validate:"required,min=1,max_items=100"`
+}
+
+// MessageResponse adalah response sukses yang hanya berisi pesan
+type MessageResponse struct {
+	Message string `json:"message"`
+}
+
+// UserEnvelope adalah response yang berisi data user
+type UserEnvelope struct {
+	Message string       `json:"message,omitempty"`
+	User    UserResponse `json:"user"`
+}
+
+// ProductEnvelope adalah response yang berisi satu produk
+type ProductEnvelope struct {
+	Message string                  `json:"message,omitempty"`
+	Product *models.ProductResponse `json:"product"`
+}
+
+// ProductListResponse adalah satu halaman produk beserta metadata pagination.
+// Dengan cursor, page tidak dikirim dan totalItems/totalPages hanya dikirim jika include_total=true.
+type ProductListResponse struct {
+	Products    []models.ProductResponse `json:"products"`
+	Page        *int                     `json:"page,omitempty"`
+	Limit       int                      `json:"limit"`
+	TotalItems  *int64                   `json:"totalItems,omitempty"`
+	TotalPages  *int                     `json:"totalPages,omitempty"`
+	HasNextPage bool                     `json:"hasNextPage"`
+	NextCursor  string                   `json:"next_cursor,omitempty"`
+	PrevCursor  string                   `json:"prev_cursor,omitempty"`
+	// SearchTruncated bernilai true jika search cocok dengan lebih banyak produk
+	// daripada batas hasil pencarian, hanya hasil paling relevan yang ditampilkan
+	SearchTruncated bool `json:"search_truncated,omitempty"`
+}
+
+func newProductListResponse(result *services.ProductList) ProductListResponse {
+	response := ProductListResponse{
+		Products:        result.Products,
+		Limit:           result.Limit,
+		HasNextPage:     result.HasNextPage,
+		NextCursor:      result.NextCursor,
+		PrevCursor:      result.PrevCursor,
+		SearchTruncated: result.SearchTruncated,
+	}
+	if result.Page > 0 {
+		response.Page = &result.Page
+	}
+	if result.Counted {
+		response.TotalItems = &result.TotalItems
+		response.TotalPages = &result.TotalPages
+	}
+	return response
+}
+
+// DeleteProductResponse adalah response setelah produk dihapus
+type DeleteProductResponse struct {
+	Message   string    `json:"message"`
+	ProductID uuid.UUID `json:"product_id"`
+}
+
+// BatchProductsResponse berisi hasil setiap operasi batch sesuai urutan di request
+type BatchProductsResponse struct {
+	Mode    string              `json:"mode"`
+	Applied int                 `json:"applied"`
+	Failed  int                 `json:"failed"`
+	Results []BatchItemResponse `json:"results"`
+}
+
+// BatchItemResponse adalah hasil satu operasi batch. status applied, failed, atau
+// skipped jika operasi valid tetapi batch atomic dibatalkan.
+type BatchItemResponse struct {
+	Index   int                     `json:"index"`
+	Op      string                  `json:"op"`
+	ID      string                  `json:"id"`
+	Status  string                  `json:"status"`
+	Product *models.ProductResponse `json:"product,omitempty"`
+	Error   *BatchItemError         `json:"error,omitempty"`
+}
+
+// BatchItemError adalah error satu operasi batch dengan kode dan pesan yang sama
+// seperti response error API
+type BatchItemError struct {
+	Code    apperrors.Code           `json:"code"`
+	Message string                   `json:"message"`
+	Errors  []apperrors.ProblemField `json:"errors,omitempty"`
+}
+
+// TagListResponse adalah daftar tag beserta jumlah produknya
+type TagListResponse struct {
+	Tags []models.TagCount `json:"tags"`
+}
+
+// CategoryTreeResponse adalah semua kategori sebagai pohon
+type CategoryTreeResponse struct {
+	Categories []models.CategoryNode `json:"categories"`
+}
+
+// CategoryEnvelope adalah response yang berisi satu kategori
+type CategoryEnvelope struct {
+	Message  string               `json:"message,omitempty"`
+	Category *models.CategoryNode `json:"category"`
+}
+
+// DeleteCategoryResponse adalah response setelah kategori dihapus
+type DeleteCategoryResponse struct {
+	Message    string    `json:"message"`
+	CategoryID uuid.UUID `json:"category_id"`
+}
+
+// AdjustStockRequest adalah body JSON perubahan stok manual. variant_id wajib
+// untuk produk yang punya varian, delta negatif mengurangi stok.
+type AdjustStockRequest struct {
+	VariantID *string `json:"variant_id,omitempty" validate:"omitnil,uuid"`
+	Delta     int64   `json:"delta" validate:"required,gte=-1000000,lte=1000000"`
+	Reason    string  `json:"reason" validate:"required,oneof=restock sale return damage correction"`
+	Note      string  `json:"note,omitempty" validate:"max=255"`
+}
+
+// StockThresholdRequest adalah body JSON batas stok menipis, 0 mematikan peringatan
+type StockThresholdRequest struct {
+	VariantID         *string `json:"variant_id,omitempty" validate:"omitnil,uuid"`
+	LowStockThreshold *int64  `json:"low_stock_threshold" validate:"required,gte=0,lte=1000000"`
+}
+
+// ReserveStockRequest adalah body JSON reservasi stok
+type ReserveStockRequest struct {
+	VariantID *string `json:"variant_id,omitempty" validate:"omitnil,uuid"`
+	Quantity  int64   `json:"quantity" validate:"required,gte=1,lte=1000"`
+}
+
+type InventoryResponse struct {
+	Items []models.StockLevelResponse `json:"items"`
+}
+
+type StockLevelEnvelope struct {
+	Message string                     `json:"message,omitempty"`
+	Stock   *models.StockLevelResponse `json:"stock"`
+}
+
+type MovementListResponse struct {
+	Movements  []models.InventoryMovementResponse `json:"movements"`
+	Page       int                                `json:"page"`
+	Limit      int                                `json:"limit"`
+	TotalItems int64                              `json:"totalItems"`
+	TotalPages int                                `json:"totalPages"`
+}
+
+type ReservationEnvelope struct {
+	Message     string                           `json:"message"`
+	Reservation *models.StockReservationResponse `json:"reservation"`
+}
+
+type ExchangeRateListResponse struct {
+	Rates []models.ExchangeRateResponse `json:"rates"`
+}
+
+type ExchangeRateEnvelope struct {
+	Message string                       `json:"message"`
+	Rate    *models.ExchangeRateResponse `json:"rate"`
+}
+
+// ImportExchangeRatesResponse berisi semua kurs yang disimpan dari file CSV
+type ImportExchangeRatesResponse struct {
+	Message  string                        `json:"message"`
+	Imported int                           `json:"imported"`
+	Rates    []models.ExchangeRateResponse `json:"rates"`
+}
+
+// ImportJobEnvelope berisi status dan hasil satu job import produk
+type ImportJobEnvelope struct {
+	Message string                    `json:"message,omitempty"`
+	Job     *models.ImportJobResponse `json:"job"`
+}
+
+type RevisionListResponse struct {
+	Revisions  []models.ProductRevisionResponse `json:"revisions"`
+	Page       int                              `json:"page"`
+	Limit      int                              `json:"limit"`
+	TotalItems int64                            `json:"totalItems"`
+	TotalPages int                              `json:"totalPages"`
+}
+
+type RevisionEnvelope struct {
+	Revision *models.ProductRevisionResponse `json:"revision"`
+}
+
+type RevisionDiffResponse struct {
+	From    int                  `json:"from"`
+	To      int                  `json:"to"`
+	Changes []models.FieldChange `json:"changes"`
+}
@@ -0,0 +1,117 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"server-cookie/apperrors"
+	"server-cookie/services"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ListProductRevisions menampilkan riwayat revisi produk, yang terbaru lebih dulu
+func (h *ProductHandler) ListProductRevisions(c *gin.Context) {
+	actor, productID, ok := inventoryParams(c)
+	if !ok {
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	list, err := h.service.Revisions(c.Request.Context(), actor, productID, page, limit)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, RevisionListResponse{
+		Revisions:  list.Revisions,
+		Page:       list.Page,
+		Limit:      list.Limit,
+		TotalItems: list.TotalItems,
+		TotalPages: list.TotalPages,
+	})
+}
+
+// GetProductRevision menampilkan satu revisi produk beserta snapshot-nya
+func (h *ProductHandler) GetProductRevision(c *gin.Context) {
+	actor, productID, number, ok := revisionParams(c)
+	if !ok {
+		return
+	}
+
+	revision, err := h.service.Revision(c.Request.Context(), actor, productID, number)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, RevisionEnvelope{Revision: revision})
+}
+
+// DiffProductRevisions menampilkan field yang berubah dari revisi from ke revisi to
+func (h *ProductHandler) DiffProductRevisions(c *gin.Context) {
+	actor, productID, ok := inventoryParams(c)
+	if !ok {
+		return
+	}
+	from, err := parseRevision(c.Query("from"))
+	if err != nil {
+		respondCode(c, apperrors.CodeInvalidRevision, err)
+		return
+	}
+	to, err := parseRevision(c.Query("to"))
+	if err != nil {
+		respondCode(c, apperrors.CodeInvalidRevision, err)
+		return
+	}
+
+	diff, err := h.service.DiffRevisions(c.Request.Context(), actor, productID, from, to)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, RevisionDiffResponse{From: diff.From, To: diff.To, Changes: diff.Changes})
+}
+
+// RollbackProduct mengembalikan isi produk ke revisi tertentu
+func (h *ProductHandler) RollbackProduct(c *gin.Context) {
+	actor, productID, number, ok := revisionParams(c)
+	if !ok {
+		return
+	}
+
+	response, err := h.service.Rollback(c.Request.Context(), actor, productID, number)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, ProductEnvelope{Message: "Product rolled back successfully", Product: response})
+}
+
+// revisionParams membaca actor, ID produk dan nomor revisi dari URL.
+// Jika gagal, error sudah dicatat dan ok bernilai false.
+func revisionParams(c *gin.Context) (services.Actor, uuid.UUID, int, bool) {
+	actor, productID, ok := inventoryParams(c)
+	if !ok {
+		return services.Actor{}, uuid.Nil, 0, false
+	}
+	number, err := parseRevision(c.Param("number"))
+	if err != nil {
+		respondCode(c, apperrors.CodeInvalidRevision, err)
+		return services.Actor{}, uuid.Nil, 0, false
+	}
+	return actor, productID, number, true
+}
+
+// parseRevision membaca nomor revisi, bilangan bulat mulai dari 1
+func parseRevision(value string) (int, error) {
+	number, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, err
+	}
+	if number < 1 {
+		return 0, errors.New("revision number must be at least 1")
+	}
+	return number, nil
+}
@@ -1,14 +1,18 @@
 package controllers
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
-	"server-cookie/database"
+	"server-cookie/apperrors"
+	"server-cookie/config"
 	"server-cookie/middleware"
 	"server-cookie/models"
+	"server-cookie/repositories"
+	"server-cookie/validation"
+	"strings"
 
 	"github.com/gin-gonic/gin"
-	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
 )
@@ -16,219 +20,252 @@ import (
 type UserResponse struct {
 	Id       string `json:"id"`
 	Username string `json:"username"`
-	Email string `json:"email"`
+	Email    string `json:"email"`
+	Role     string `json:"role"`
+	// Currency adalah mata uang tampilan harga, kosong berarti harga asli produk
+	Currency string `json:"currency"`
 }
 
-var validate = validator.New()
-
-// Fungsi untuk mengubah error validator ke format yang lebih jelas
-func formatValidationError(err error) map[string]string {
-	errors := make(map[string]string)
-	if validationErrors, ok := err.(validator.ValidationErrors); ok {
-		for _, e := range validationErrors {
-			field := e.Field()
-			switch e.Tag() {
-			case "required":
-				errors[field] = field + " harus diisi"
-			case "email":
-				errors[field] = "Format email tidak valid"
-			case "min":
-				errors[field] = field + " minimal " + e.Param() + " karakter"
-			case "max":
-				errors[field] = field + " maksimal " + e.Param() + " karakter"
-			default:
-				errors[field] = "Format tidak valid"
-			}
-		}
+// UserHandler berisi handler untuk autentikasi dan profil user
+type UserHandler struct {
+	users  repositories.UserRepository
+	cookie config.CookieConfig
+	admins map[string]bool
+}
+
+// NewUserHandler membuat UserHandler dengan dependency yang diberikan.
+// User dengan username di admins mendapat role admin di token, role tidak disimpan.
+func NewUserHandler(users repositories.UserRepository, cookie config.CookieConfig, admins []string) *UserHandler {
+	h := &UserHandler{users: users, cookie: cookie, admins: make(map[string]bool)}
+	for _, username := range admins {
+		h.admins[username] = true
+	}
+	return h
+}
+
+// role menentukan role user dari konfigurasi, bukan dari kolom role di database,
+// sehingga username yang dihapus dari ADMIN_USERNAMES langsung kehilangan akses admin
+func (h *UserHandler) role(user *models.User) string {
+	if h.admins[user.Username] {
+		return models.RoleAdmin
+	}
+	return models.RoleUser
+}
+
+func (h *UserHandler) userResponse(user *models.User) UserResponse {
+	return UserResponse{
+		Id:       user.Id.String(),
+		Username: user.Username,
+		Email:    user.Email,
+		Role:     h.role(user),
+		Currency: user.Currency,
 	}
-	return errors
 }
 
-func Register(c *gin.Context) {
-	var user models.User
-	if err := c.ShouldBindJSON(&user); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+func (h *UserHandler) Register(c *gin.Context) {
+	var input RegisterRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		respondCode(c, apperrors.CodeInvalidJSON, err)
 		return
 	}
 
 	// 🔥 Validasi otomatis dengan library validator
-	if err := validate.Struct(user); err != nil {
-		formattedErrors := formatValidationError(err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": formattedErrors})
+	if err := validation.Struct(input); err != nil {
+		respondError(c, err)
 		return
 	}
 
 	// 🔥 Cek apakah username atau email sudah digunakan
-	var existingUser models.User
-	if err := database.DB.Where("username = ?", user.Username).Or("email = ?", user.Email).First(&existingUser).Error; err == nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Username atau Email sudah digunakan"})
+	exists, err := h.users.ExistsByUsernameOrEmail(c.Request.Context(), input.Username, input.Email)
+	if err != nil {
+		respondError(c, fmt.Errorf("could not check user: %w", err))
+		return
+	}
+	if exists {
+		respondCode(c, apperrors.CodeUserExists, nil)
 		return
 	}
 
 	// Hash password
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(user.Password), bcrypt.DefaultCost)
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(input.Password), bcrypt.DefaultCost)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not hash password"})
+		respondError(c, fmt.Errorf("could not hash password: %w", err))
 		return
 	}
-	user.Password = string(hashedPassword)
+	user := models.User{
+		Username: input.Username,
+		Email:    input.Email,
+		Password: string(hashedPassword),
+		Role:     models.RoleUser,
+	}
 
 	// Save user to database
-	if err := database.DB.Create(&user).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not create user"})
+	if err := h.users.Create(c.Request.Context(), &user); err != nil {
+		if errors.Is(err, repositories.ErrUsernameTaken) {
+			respondCode(c, apperrors.CodeUserExists, nil)
+			return
+		}
+		respondError(c, fmt.Errorf("could not create user: %w", err))
 		return
 	}
-	c.JSON(http.StatusCreated, gin.H{"message": "User registered successfully"})
+	c.JSON(http.StatusCreated, MessageResponse{Message: "User registered successfully"})
 }
 
-func Login(c *gin.Context) {
-	var inputUser models.User
+func (h *UserHandler) Login(c *gin.Context) {
+	var inputUser LoginRequest
 	if err := c.ShouldBindJSON(&inputUser); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondCode(c, apperrors.CodeInvalidJSON, err)
 		return
 	}
 
-	var dbUser models.User
-	if err := database.DB.Where("username = ?", inputUser.Username).First(&dbUser).Error; err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid credential"})
+	dbUser, err := h.users.FindByUsername(c.Request.Context(), inputUser.Username)
+	if errors.Is(err, repositories.ErrNotFound) {
+		respondCode(c, apperrors.CodeInvalidCredentials, nil)
+		return
+	}
+	if err != nil {
+		respondError(c, fmt.Errorf("could not find user: %w", err))
 		return
 	}
 
 	//compare password
 	if err := bcrypt.CompareHashAndPassword([]byte(dbUser.Password), []byte(inputUser.Password)); err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid credential"})
+		respondCode(c, apperrors.CodeInvalidCredentials, nil)
 		return
 	}
 
 	// Gunakan fungsi GenerateToken dari middleware
-	tokenString, err := middleware.GenerateToken(dbUser.Id.String(), dbUser.Username)
+	tokenString, err := middleware.GenerateToken(dbUser.Id.String(), dbUser.Username, h.role(dbUser))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not generate token"})
+		respondError(c, fmt.Errorf("could not generate token: %w", err))
 		return
 	}
 
 	// Set token in cookie
-	expiry := 60 * 60 //24 * 60 * 60 = 24jam
-	c.SetCookie("token", tokenString, expiry, "/", "localhost", false, true)
+	middleware.SetTokenCookie(c, h.cookie, tokenString)
 	// Gunakan struct UserResponse untuk response tanpa password
-	userResponse := UserResponse{
-		Id:       dbUser.Id.String(),
-		Username: dbUser.Username,
-		Email: dbUser.Email,
-	}
-
-	c.JSON(http.StatusOK, gin.H{
-		"message": "Logged in successfully",
-		"user":    userResponse,
-	})
+	c.JSON(http.StatusOK, UserEnvelope{Message: "Logged in successfully", User: h.userResponse(dbUser)})
 }
 
-// // Logout handler
-// func Logout(c *gin.Context) {
-//
-// 	// Hapus token di cookie (expired dengan maxAge -1)
-// 	c.SetCookie("token", "", -1, "/", "localhost", false, true)
-// 	c.JSON(http.StatusOK, gin.H{"message": "Logged out successfully"})
-// }
-
-func Logout(c *gin.Context) {
+// Logout menghapus cookie token milik user
+func (h *UserHandler) Logout(c *gin.Context) {
 	// Periksa apakah cookie ada
-	token, err := c.Cookie("token")
-	if err != nil {
-		fmt.Println("❌ Token not found in cookie:", err)
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Token not found"})
+	if _, err := middleware.GetTokenCookie(c, h.cookie); err != nil {
+		respondCode(c, apperrors.CodeTokenMissing, err)
 		return
 	}
 
-	fmt.Println("✅ Token for logout:", token)
-
 	// Hapus token di cookie (expire segera)
-	c.SetCookie("token", "", -1, "/", "localhost", false, true)
+	middleware.ClearTokenCookie(c, h.cookie)
 
-	c.JSON(http.StatusOK, gin.H{"message": "Logged out successfully"})
+	c.JSON(http.StatusOK, MessageResponse{Message: "Logged out successfully"})
 }
 
 // GetProfile - Mendapatkan profil pengguna berdasarkan ID
-func GetProfile(c *gin.Context) {
+func (h *UserHandler) GetProfile(c *gin.Context) {
 	userId := c.Param("id")
-	var user models.User
 
 	// Validasi UUID
 	parsedUUID, err := uuid.Parse(userId)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		respondCode(c, apperrors.CodeInvalidUserID, err)
 		return
 	}
 
-	if err := database.DB.First(&user, "id = ?", parsedUUID).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+	user, err := h.users.FindByID(c.Request.Context(), parsedUUID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			respondCode(c, apperrors.CodeUserNotFound, nil)
+			return
+		}
+		respondError(c, fmt.Errorf("failed to retrieve user: %w", err))
 		return
 	}
 
-	userResponse := UserResponse{
-		Id:       user.Id.String(),
-		Username: user.Username,
-		Email: user.Email,
-	}
-
-
-
-	c.JSON(http.StatusOK, gin.H{"user": userResponse})
+	c.JSON(http.StatusOK, UserEnvelope{User: h.userResponse(user)})
 }
 
 // UpdateProfile - Memperbarui profil pengguna
-func UpdateProfile(c *gin.Context) {
+func (h *UserHandler) UpdateProfile(c *gin.Context) {
 	userId := c.Param("id")
-	var user models.User
 
 	// Validasi UUID
 	parsedUUID, err := uuid.Parse(userId)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		respondCode(c, apperrors.CodeInvalidUserID, err)
 		return
 	}
 
-	// Cari user
-	if err := database.DB.First(&user, "id = ?", parsedUUID).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+	// User hanya boleh mengubah profil milik sendiri
+	actor, ok := currentActor(c)
+	if !ok {
+		return
+	}
+	if parsedUUID != actor.UserID {
+		respondCode(c, apperrors.CodeNotProfileOwner, nil)
 		return
 	}
 
-	var updateData struct {
-		Username string `json:"username"`
-		Email    string `json:"email" validate:"required,email"`
-		Password string `json:"password" validate:"omitempty,min=6"`
+	// Cari user
+	user, err := h.users.FindByID(c.Request.Context(), parsedUUID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			respondCode(c, apperrors.CodeUserNotFound, nil)
+			return
+		}
+		respondError(c, fmt.Errorf("failed to retrieve user: %w", err))
+		return
 	}
 
+	var updateData UpdateProfileRequest
 	if err := c.ShouldBindJSON(&updateData); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondCode(c, apperrors.CodeInvalidJSON, err)
+		return
+	}
+	// currency kosong menghapus pilihan mata uang, tidak perlu divalidasi
+	clearCurrency := updateData.Currency != nil && strings.TrimSpace(*updateData.Currency) == ""
+	if clearCurrency {
+		updateData.Currency = nil
+	}
+	if err := validation.Struct(updateData); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	// Username admin hanya bisa didapat saat register, bukan dengan mengganti nama,
+	// karena akses admin ditentukan dari username
+	if updateData.Username != user.Username && h.admins[updateData.Username] {
+		respondCode(c, apperrors.CodeUsernameReserved, nil)
 		return
 	}
 
 	// Update username dan email
 	user.Username = updateData.Username
 	user.Email = updateData.Email
+	if clearCurrency {
+		user.Currency = ""
+	} else if updateData.Currency != nil {
+		user.Currency = strings.ToUpper(strings.TrimSpace(*updateData.Currency))
+	}
 
 	// Jika password diisi, hash ulang
 	if updateData.Password != "" {
 		hashedPassword, err := bcrypt.GenerateFromPassword([]byte(updateData.Password), bcrypt.DefaultCost)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not hash password"})
+			respondError(c, fmt.Errorf("could not hash password: %w", err))
 			return
 		}
 		user.Password = string(hashedPassword)
 	}
 
 	// Simpan perubahan
-	if err := database.DB.Save(&user).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update profile"})
+	if err := h.users.Update(c.Request.Context(), user); err != nil {
+		if errors.Is(err, repositories.ErrUsernameTaken) {
+			respondCode(c, apperrors.CodeUserExists, nil)
+			return
+		}
+		respondError(c, fmt.Errorf("failed to update profile: %w", err))
 		return
 	}
-	userResponse := UserResponse{
-		Id:       user.Id.String(),
-		Username: user.Username,
-		Email: user.Email,
-	}
-
-	c.JSON(http.StatusOK, gin.H{"message": "Profile updated successfully", "user":userResponse})
+	c.JSON(http.StatusOK, UserEnvelope{Message: "Profile updated successfully", User: h.userResponse(user)})
 }
@@ -0,0 +1,137 @@
+package controllers
+
+import (
+	"net/http"
+	"server-cookie/apperrors"
+	"server-cookie/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// SetProductOptions mengganti definisi option varian produk
+func (h *ProductHandler) SetProductOptions(c *gin.Context) {
+	actor, ok := currentActor(c)
+	if !ok {
+		return
+	}
+	productID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondCode(c, apperrors.CodeInvalidProductID, err)
+		return
+	}
+
+	var request ProductOptionsRequest
+	if !bindJSON(c, &request) {
+		return
+	}
+	options := make([]services.OptionInput, 0, len(request.Options))
+	for _, option := range request.Options {
+		options = append(options, services.OptionInput{Name: option.Name, Values: option.Values})
+	}
+
+	response, err := h.service.SetOptions(c.Request.Context(), actor, productID, options)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, ProductEnvelope{Message: "Options updated successfully", Product: response})
+}
+
+// CreateProductVariant menambahkan varian produk
+func (h *ProductHandler) CreateProductVariant(c *gin.Context) {
+	actor, ok := currentActor(c)
+	if !ok {
+		return
+	}
+	productID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondCode(c, apperrors.CodeInvalidProductID, err)
+		return
+	}
+	input, ok := bindVariant(c)
+	if !ok {
+		return
+	}
+
+	response, err := h.service.CreateVariant(c.Request.Context(), actor, productID, input)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, ProductEnvelope{Message: "Variant created successfully", Product: response})
+}
+
+// UpdateProductVariant mengganti data varian produk
+func (h *ProductHandler) UpdateProductVariant(c *gin.Context) {
+	actor, ok := currentActor(c)
+	if !ok {
+		return
+	}
+	productID, variantID, ok := variantParams(c)
+	if !ok {
+		return
+	}
+	input, ok := bindVariant(c)
+	if !ok {
+		return
+	}
+
+	response, err := h.service.UpdateVariant(c.Request.Context(), actor, productID, variantID, input)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, ProductEnvelope{Message: "Variant updated successfully", Product: response})
+}
+
+// DeleteProductVariant menghapus varian produk
+func (h *ProductHandler) DeleteProductVariant(c *gin.Context) {
+	actor, ok := currentActor(c)
+	if !ok {
+		return
+	}
+	productID, variantID, ok := variantParams(c)
+	if !ok {
+		return
+	}
+
+	response, err := h.service.DeleteVariant(c.Request.Context(), actor, productID, variantID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, ProductEnvelope{Message: "Variant deleted successfully", Product: response})
+}
+
+// variantParams membaca ID produk dan varian dari URL.
+// Jika gagal, error sudah dicatat dan ok bernilai false.
+func variantParams(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	productID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondCode(c, apperrors.CodeInvalidProductID, err)
+		return uuid.Nil, uuid.Nil, false
+	}
+	variantID, err := uuid.Parse(c.Param("variantId"))
+	if err != nil {
+		respondCode(c, apperrors.CodeInvalidVariantID, err)
+		return uuid.Nil, uuid.Nil, false
+	}
+	return productID, variantID, true
+}
+
+// bindVariant membaca dan memvalidasi body JSON varian.
+// Jika gagal, error sudah dicatat dan ok bernilai false.
+func bindVariant(c *gin.Context) (services.VariantInput, bool) {
+	var request VariantRequest
+	if !bindJSON(c, &request) {
+		return services.VariantInput{}, false
+	}
+
+	return services.VariantInput{
+		SKU:        request.SKU,
+		Price:      request.Price,
+		ImageID:    parseOptionalID(request.ImageID),
+		Attributes: request.Attributes,
+	}, true
+}
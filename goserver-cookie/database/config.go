@@ -3,42 +3,150 @@ package database
 import (
 	"fmt"
 	"log"
+	"math"
 
-	// "server-cookie/models"
+	"server-cookie/models"
+	"server-cookie/money"
+	"server-cookie/search"
 
+	"github.com/glebarez/sqlite"
+	"github.com/google/uuid"
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 )
 
-var DB *gorm.DB
+// DefaultMySQLDSN dipakai jika DB_DSN tidak diisi
+const DefaultMySQLDSN = "root:admin123@tcp(127.0.0.1:3306)/gocookie_db?charset=utf8mb4&parseTime=True&loc=Local"
 
-func ConnectDatabase() {
-	// Ganti dengan kredensial database yang sesuai
-	dsn := "root:admin123@tcp(127.0.0.1:3306)/gocookie_db?charset=utf8mb4&parseTime=True&loc=Local"
+// Open membuka koneksi database sesuai driver ("mysql" atau "sqlite")
+func Open(driver, dsn string, logLevel logger.LogLevel) (*gorm.DB, error) {
+	var dialector gorm.Dialector
+	switch driver {
+	case "", "mysql":
+		if dsn == "" {
+			dsn = DefaultMySQLDSN
+		}
+		dialector = mysql.Open(dsn)
+	case "sqlite":
+		dialector = sqlite.Open(dsn)
+	default:
+		return nil, fmt.Errorf("unknown database driver %q", driver)
+	}
 
-	// Membuka koneksi ke database dengan konfigurasi logger aktif
-	var err error
-	DB, err = gorm.Open(mysql.Open(dsn), &gorm.Config{
-		Logger: logger.Default.LogMode(logger.Info),
+	return gorm.Open(dialector, &gorm.Config{
+		Logger: logger.Default.LogMode(logLevel),
+		// Pelanggaran unique index dilaporkan sebagai gorm.ErrDuplicatedKey
+		TranslateError: true,
+	})
+}
+
+// Migrate menjalankan migrasi otomatis untuk semua model.
+// Tabel pencarian full-text hanya dibuat di MySQL, SQLite memakai index di memory.
+func Migrate(db *gorm.DB) error {
+	if err := db.AutoMigrate(&models.User{}, &models.Category{}, &models.Tag{}, &models.Product{}, &models.ProductImage{},
+		&models.ProductOption{}, &models.ProductVariant{}, &models.StockLevel{}, &models.InventoryMovement{},
+		&models.StockReservation{}, &models.ExchangeRate{}, &models.ProductRevision{}, &models.ImportJob{}); err != nil {
+		return err
+	}
+	if err := migrateLegacyImages(db); err != nil {
+		return fmt.Errorf("failed to migrate product images: %w", err)
+	}
+	if err := migrateLegacyPrices(db); err != nil {
+		return fmt.Errorf("failed to migrate product prices: %w", err)
+	}
+	// Produk lama belum punya rentang harga, harga minimal produk adalah 1 sehingga 0 berarti kosong
+	if err := db.Model(&models.Product{}).Unscoped().
+		Where("price_min = 0").
+		Updates(map[string]any{"price_min": gorm.Expr("price_amount"), "price_max": gorm.Expr("price_amount")}).Error; err != nil {
+		return fmt.Errorf("failed to fill product price ranges: %w", err)
+	}
+	if db.Dialector.Name() == "mysql" {
+		return search.MigrateMySQL(db)
+	}
+	return nil
+}
+
+// migrateLegacyImages memindahkan kolom products.image (satu gambar per produk)
+// ke tabel product_images sebagai gambar utama, lalu menghapus kolom tersebut
+func migrateLegacyImages(db *gorm.DB) error {
+	if !db.Migrator().HasColumn(&models.Product{}, "image") {
+		return nil
+	}
+	return db.Transaction(func(tx *gorm.DB) error {
+		var rows []struct {
+			Id    uuid.UUID
+			Image string
+		}
+		err := tx.Table("products").Select("id, image").
+			Where("image <> '' AND NOT EXISTS (SELECT 1 FROM product_images WHERE product_images.product_id = products.id)").
+			Scan(&rows).Error
+		if err != nil {
+			return err
+		}
+		for _, row := range rows {
+			image := models.ProductImage{ProductId: row.Id, Path: row.Image, Primary: true}
+			if err := tx.Create(&image).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Migrator().DropColumn(&models.Product{}, "image")
 	})
+}
+
+// migrateLegacyPrices memindahkan kolom price lama (bilangan bulat rupiah tanpa
+// mata uang) ke price_amount dalam minor unit models.DefaultCurrency, lalu
+// menghapus kolom tersebut. Rentang harga produk ikut dikonversi.
+func migrateLegacyPrices(db *gorm.DB) error {
+	currency, err := money.LookupCurrency(models.DefaultCurrency)
+	if err != nil {
+		return err
+	}
+	factor := int64(math.Pow10(currency.Exponent))
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		if tx.Migrator().HasColumn(&models.Product{}, "price") {
+			err := tx.Model(&models.Product{}).Unscoped().Where("1 = 1").Updates(map[string]any{
+				"price_amount":   gorm.Expr("price * ?", factor),
+				"price_currency": currency.Code,
+				"price_min":      gorm.Expr("price_min * ?", factor),
+				"price_max":      gorm.Expr("price_max * ?", factor),
+			}).Error
+			if err != nil {
+				return err
+			}
+			if err := tx.Migrator().DropColumn(&models.Product{}, "price"); err != nil {
+				return err
+			}
+		}
+		if tx.Migrator().HasColumn(&models.ProductVariant{}, "price") {
+			err := tx.Model(&models.ProductVariant{}).
+				Where("price IS NOT NULL").
+				Update("price_amount", gorm.Expr("price * ?", factor)).Error
+			if err != nil {
+				return err
+			}
+			return tx.Migrator().DropColumn(&models.ProductVariant{}, "price")
+		}
+		return nil
+	})
+}
+
+// ConnectDatabase membuka koneksi database dan mengembalikan instance GORM
+func ConnectDatabase(driver, dsn string, autoMigrate bool) *gorm.DB {
+	// Membuka koneksi ke database dengan konfigurasi logger aktif
+	db, err := Open(driver, dsn, logger.Info)
 	if err != nil {
 		log.Fatal("❌ Gagal terhubung ke database:", err)
 	}
 
 	// Migrasi otomatis (pastikan model sudah benar)
-	// err = DB.AutoMigrate(&models.Product{}, &models.User{})
-	// if err != nil {
-	// 	log.Fatal("❌ Gagal melakukan migrasi database:", err)
-	// }
+	if autoMigrate {
+		if err := Migrate(db); err != nil {
+			log.Fatal("❌ Gagal melakukan migrasi database:", err)
+		}
+	}
 
 	fmt.Println("✅ Database connected successfully!")
-}
-
-// GetDB mengembalikan instance database
-func GetDB() *gorm.DB {
-	if DB == nil {
-		log.Fatal("❌ Database belum diinisialisasi. Panggil ConnectDatabase() terlebih dahulu.")
-	}
-	return DB
+	return db
 }
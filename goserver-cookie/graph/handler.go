@@ -0,0 +1,246 @@
+// Package graph menyediakan endpoint GraphQL untuk produk dan penjual
+// dengan service yang sama seperti handler REST.
+package graph
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"server-cookie/apperrors"
+	"server-cookie/middleware"
+	"server-cookie/models"
+	"server-cookie/repositories"
+	"server-cookie/services"
+	"server-cookie/validation"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/graph-gophers/graphql-go"
+	gqlerrors "github.com/graph-gophers/graphql-go/errors"
+)
+
+//go:embed schema.graphql
+var Schema string
+
+// Request adalah body request GraphQL
+type Request struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName,omitempty"`
+	Variables     map[string]any `json:"variables,omitempty"`
+}
+
+// Response adalah body response GraphQL
+type Response struct {
+	Data   json.RawMessage         `json:"data,omitempty"`
+	Errors []*gqlerrors.QueryError `json:"errors,omitempty"`
+}
+
+// Upload adalah file dari request multipart sesuai GraphQL multipart request spec
+type Upload struct {
+	Filename string
+	Content  []byte
+}
+
+func (Upload) ImplementsGraphQLType(name string) bool {
+	return name == "Upload"
+}
+
+func (u *Upload) UnmarshalGraphQL(input any) error {
+	upload, ok := input.(*Upload)
+	if !ok {
+		return fmt.Errorf("upload must be sent as a multipart file, got %T", input)
+	}
+	*u = *upload
+	return nil
+}
+
+func (u *Upload) file() *validation.File {
+	if u == nil || u.Filename == "" {
+		return nil
+	}
+	return &validation.File{Name: u.Filename, Content: u.Content}
+}
+
+// Handler menjalankan query GraphQL untuk user yang login lewat cookie
+type Handler struct {
+	schema *graphql.Schema
+	users  repositories.UserRepository
+	limits Limits
+}
+
+// NewHandler membuat Handler dengan dependency yang diberikan
+func NewHandler(products *services.ProductService, users repositories.UserRepository, limits Limits) *Handler {
+	schema := graphql.MustParseSchema(Schema, &resolver{products: products, users: users},
+		graphql.UseStringDescriptions(),
+		graphql.MaxDepth(limits.MaxDepth+introspectionDepth),
+	)
+	return &Handler{schema: schema, users: users, limits: limits}
+}
+
+// introspectionDepth memberi ruang untuk query introspection bawaan GraphiQL/Apollo,
+// kedalaman field biasa tetap dibatasi oleh checkLimits
+const introspectionDepth = 10
+
+// Serve menangani POST /graphql, body JSON atau multipart/form-data
+func (h *Handler) Serve(c *gin.Context) {
+	userID, err := uuid.Parse(c.GetString("user_id"))
+	if err != nil {
+		middleware.AbortWithError(c, apperrors.Wrap(apperrors.CodeTokenInvalid, err))
+		return
+	}
+	actor := services.Actor{UserID: userID, Username: c.GetString("username")}
+
+	req, err := readRequest(c.Request)
+	if err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+	if err := checkLimits(req, h.limits); err != nil {
+		middleware.AbortWithError(c, err)
+		return
+	}
+
+	lang := apperrors.ParseAcceptLanguage(c.GetHeader("Accept-Language"))
+	ctx := context.WithValue(c.Request.Context(), actorKey{}, actor)
+	ctx = context.WithValue(ctx, loaderKey{}, newUserLoader(h.users))
+
+	result := h.schema.Exec(ctx, req.Query, req.OperationName, req.Variables)
+	for _, queryErr := range result.Errors {
+		localizeError(queryErr, lang, c.GetString("request_id"))
+	}
+	c.JSON(http.StatusOK, Response{Data: result.Data, Errors: result.Errors})
+}
+
+// localizeError mengganti pesan error resolver dengan pesan dari katalog
+// dan menambahkan kode error di extensions
+func localizeError(queryErr *gqlerrors.QueryError, lang apperrors.Lang, requestID string) {
+	if queryErr.ResolverError == nil {
+		return
+	}
+	appErr := apperrors.From(queryErr.ResolverError)
+	if appErr.Code == apperrors.CodeInternal {
+		log.Printf("❌ [%s] GraphQL %v: %v", requestID, queryErr.Path, queryErr.ResolverError)
+	}
+
+	queryErr.Message = appErr.Code.Message(lang)
+	extensions := map[string]any{
+		"code":   appErr.Code,
+		"status": appErr.Code.Status(),
+	}
+	if len(appErr.Fields) > 0 {
+		fields := make([]apperrors.ProblemField, 0, len(appErr.Fields))
+		for _, field := range appErr.Fields {
+			fields = append(fields, apperrors.ProblemField{
+				Field:   field.Field,
+				Code:    field.Code,
+				Message: apperrors.FieldMessage(lang, field.Code, field.Field, field.Param),
+			})
+		}
+		extensions["errors"] = fields
+	}
+	queryErr.Extensions = extensions
+}
+
+// readRequest membaca request JSON atau multipart (operations, map dan file)
+func readRequest(r *http.Request) (Request, error) {
+	var req Request
+	if !strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			return req, apperrors.Wrap(apperrors.CodeInvalidJSON, err)
+		}
+		return req, nil
+	}
+
+	if err := r.ParseMultipartForm(32 << 20); err != nil {
+		return req, apperrors.Wrap(apperrors.CodeInvalidForm, err)
+	}
+	if err := json.Unmarshal([]byte(r.FormValue("operations")), &req); err != nil {
+		return req, apperrors.Wrap(apperrors.CodeInvalidForm, fmt.Errorf("operations: %w", err))
+	}
+	var fileMap map[string][]string
+	if err := json.Unmarshal([]byte(r.FormValue("map")), &fileMap); err != nil {
+		return req, apperrors.Wrap(apperrors.CodeInvalidForm, fmt.Errorf("map: %w", err))
+	}
+
+	for key, paths := range fileMap {
+		files := r.MultipartForm.File[key]
+		if len(files) == 0 {
+			return req, apperrors.Wrap(apperrors.CodeInvalidForm, fmt.Errorf("missing file %q", key))
+		}
+		src, err := files[0].Open()
+		if err != nil {
+			return req, apperrors.Wrap(apperrors.CodeInvalidForm, err)
+		}
+		// Dibaca sedikit melebihi batas agar validator max_file_size tetap bisa menolak
+		content, err := io.ReadAll(io.LimitReader(src, models.MaxProductImageSize+1))
+		src.Close()
+		if err != nil {
+			return req, apperrors.Wrap(apperrors.CodeInvalidForm, err)
+		}
+
+		upload := &Upload{Filename: files[0].Filename, Content: content}
+		for _, path := range paths {
+			if err := setVariable(req.Variables, path, upload); err != nil {
+				return req, apperrors.Wrap(apperrors.CodeInvalidForm, err)
+			}
+		}
+	}
+	return req, nil
+}
+
+// setVariable mengisi nilai di path seperti "variables.input.image" atau "variables.files.0"
+func setVariable(variables map[string]any, path string, value any) error {
+	parts := strings.Split(path, ".")
+	if variables == nil || len(parts) < 2 || parts[0] != "variables" {
+		return fmt.Errorf("invalid map path %q", path)
+	}
+
+	var current any = variables
+	for i, part := range parts[1:] {
+		last := i == len(parts)-2
+		switch node := current.(type) {
+		case map[string]any:
+			if last {
+				node[part] = value
+				return nil
+			}
+			current = node[part]
+		case []any:
+			index, err := strconv.Atoi(part)
+			if err != nil || index < 0 || index >= len(node) {
+				return fmt.Errorf("invalid map path %q", path)
+			}
+			if last {
+				node[index] = value
+				return nil
+			}
+			current = node[index]
+		default:
+			return fmt.Errorf("invalid map path %q", path)
+		}
+	}
+	return fmt.Errorf("invalid map path %q", path)
+}
+
+type (
+	actorKey  struct{}
+	loaderKey struct{}
+)
+
+func actorFrom(ctx context.Context) (services.Actor, error) {
+	actor, ok := ctx.Value(actorKey{}).(services.Actor)
+	if !ok {
+		return services.Actor{}, apperrors.New(apperrors.CodeTokenMissing)
+	}
+	return actor, nil
+}
+
+func loaderFrom(ctx context.Context) *userLoader {
+	return ctx.Value(loaderKey{}).(*userLoader)
+}
@@ -0,0 +1,112 @@
+package graph
+
+import (
+	"server-cookie/apperrors"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/parser"
+)
+
+// Limits membatasi query sebelum dieksekusi
+type Limits struct {
+	// MaxDepth adalah kedalaman field maksimal, field introspection (__*) tidak dihitung
+	MaxDepth int
+	// MaxComplexity adalah jumlah field maksimal setelah dikalikan argumen limit
+	MaxComplexity int
+}
+
+// DefaultLimits cukup untuk query products -> nodes -> owner dengan limit 100
+var DefaultLimits = Limits{MaxDepth: 6, MaxComplexity: 1000}
+
+// defaultListLimits adalah nilai default argumen limit per field list
+var defaultListLimits = map[string]int{
+	"products": 10,
+}
+
+// checkLimits menghitung kedalaman dan kompleksitas operasi yang akan dijalankan
+func checkLimits(req Request, limits Limits) error {
+	doc, err := parser.ParseQuery(&ast.Source{Input: req.Query})
+	if err != nil {
+		// Error sintaks dilaporkan oleh eksekutor GraphQL
+		return nil
+	}
+
+	for _, op := range doc.Operations {
+		if req.OperationName != "" && op.Name != req.OperationName {
+			continue
+		}
+		c := cost{fragments: doc.Fragments, variables: req.Variables}
+		complexity, depth := c.selectionSet(op.SelectionSet, 0, map[string]bool{})
+		if depth > limits.MaxDepth {
+			return apperrors.New(apperrors.CodeQueryTooDeep)
+		}
+		if complexity > limits.MaxComplexity {
+			return apperrors.New(apperrors.CodeQueryTooComplex)
+		}
+	}
+	return nil
+}
+
+type cost struct {
+	fragments ast.FragmentDefinitionList
+	variables map[string]any
+}
+
+// selectionSet mengembalikan kompleksitas dan kedalaman maksimal selection set
+func (c cost) selectionSet(set ast.SelectionSet, depth int, visiting map[string]bool) (int, int) {
+	total, maxDepth := 0, depth
+	add := func(complexity, d int) {
+		total += complexity
+		maxDepth = max(maxDepth, d)
+	}
+
+	for _, selection := range set {
+		switch s := selection.(type) {
+		case *ast.Field:
+			if strings.HasPrefix(s.Name, "__") {
+				continue
+			}
+			children, d := c.selectionSet(s.SelectionSet, depth+1, visiting)
+			add(1+c.multiplier(s)*children, d)
+		case *ast.InlineFragment:
+			add(c.selectionSet(s.SelectionSet, depth, visiting))
+		case *ast.FragmentSpread:
+			// Fragment yang rekursif ditolak oleh validasi GraphQL, cukup dilewati di sini
+			fragment := c.fragments.ForName(s.Name)
+			if fragment == nil || visiting[s.Name] {
+				continue
+			}
+			visiting[s.Name] = true
+			add(c.selectionSet(fragment.SelectionSet, depth, visiting))
+			delete(visiting, s.Name)
+		}
+	}
+	return total, maxDepth
+}
+
+// multiplier adalah jumlah item yang diminta field list lewat argumen limit
+func (c cost) multiplier(field *ast.Field) int {
+	n, ok := defaultListLimits[field.Name]
+	if !ok {
+		n = 1
+	}
+	arg := field.Arguments.ForName("limit")
+	if arg == nil {
+		return n
+	}
+
+	var value any
+	if arg.Value.Kind == ast.Variable {
+		value = c.variables[arg.Value.Raw]
+	} else if v, err := arg.Value.Value(nil); err == nil {
+		value = v
+	}
+	switch v := value.(type) {
+	case int64:
+		n = int(v)
+	case float64:
+		n = int(v)
+	}
+	return max(n, 1)
+}
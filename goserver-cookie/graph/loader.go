@@ -0,0 +1,71 @@
+package graph
+
+import (
+	"context"
+	"server-cookie/models"
+	"server-cookie/repositories"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// userLoader memuat user secara batch per request untuk menghindari query N+1.
+// Resolver list mendaftarkan ID lebih dulu lewat Prime, lalu Load pertama
+// mengambil semua ID yang tertunda dalam satu query.
+type userLoader struct {
+	users repositories.UserRepository
+
+	mu      sync.Mutex
+	pending map[uuid.UUID]struct{}
+	loaded  map[uuid.UUID]*models.User
+}
+
+func newUserLoader(users repositories.UserRepository) *userLoader {
+	return &userLoader{
+		users:   users,
+		pending: make(map[uuid.UUID]struct{}),
+		loaded:  make(map[uuid.UUID]*models.User),
+	}
+}
+
+// Prime mendaftarkan ID yang akan dimuat pada batch berikutnya
+func (l *userLoader) Prime(ids ...uuid.UUID) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, id := range ids {
+		if _, ok := l.loaded[id]; !ok {
+			l.pending[id] = struct{}{}
+		}
+	}
+}
+
+// Load mengembalikan user dengan ID tersebut, nil jika tidak ada
+func (l *userLoader) Load(ctx context.Context, id uuid.UUID) (*models.User, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if user, ok := l.loaded[id]; ok {
+		return user, nil
+	}
+
+	l.pending[id] = struct{}{}
+	ids := make([]uuid.UUID, 0, len(l.pending))
+	for pendingID := range l.pending {
+		ids = append(ids, pendingID)
+	}
+	clear(l.pending)
+
+	users, err := l.users.FindByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	// ID yang tidak ditemukan juga disimpan agar tidak di-query ulang
+	for _, id := range ids {
+		l.loaded[id] = nil
+	}
+	for i := range users {
+		l.loaded[users[i].Id] = &users[i]
+	}
+	return l.loaded[id], nil
+}
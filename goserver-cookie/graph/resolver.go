@@ -0,0 +1,374 @@
+package graph
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"server-cookie/apperrors"
+	"server-cookie/models"
+	"server-cookie/money"
+	"server-cookie/repositories"
+	"server-cookie/services"
+	"server-cookie/validation"
+
+	"github.com/google/uuid"
+	"github.com/graph-gophers/graphql-go"
+)
+
+// createProductInput memakai aturan validasi yang sama dengan CreateProductForm
+type createProductInput struct {
+	Name     string           `json:"name" validate:"required,min=2,max=100"`
+	Price    string           `json:"price" validate:"required,price"`
+	Currency *string          `json:"currency" validate:"omitnil,currency"`
+	Image    *validation.File `json:"image" validate:"required,image_type,max_file_size=5242880"`
+}
+
+// updateProductInput memakai aturan validasi yang sama dengan UpdateProductForm
+type updateProductInput struct {
+	Name     *string          `json:"name" validate:"omitnil,min=2,max=100"`
+	Price    *string          `json:"price" validate:"omitnil,price"`
+	Currency *string          `json:"currency" validate:"omitnil,currency"`
+	Image    *validation.File `json:"image" validate:"omitnil,image_type,max_file_size=5242880"`
+}
+
+// resolver adalah root Query dan Mutation
+type resolver struct {
+	products *services.ProductService
+	users    repositories.UserRepository
+}
+
+func (r *resolver) Me(ctx context.Context) (*userResolver, error) {
+	actor, err := actorFrom(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return loadUser(ctx, actor.UserID)
+}
+
+func (r *resolver) User(ctx context.Context, args struct{ ID graphql.ID }) (*userResolver, error) {
+	id, err := uuid.Parse(string(args.ID))
+	if err != nil {
+		return nil, apperrors.Wrap(apperrors.CodeInvalidUserID, err)
+	}
+	user, err := loaderFrom(ctx).Load(ctx, id)
+	if err != nil || user == nil {
+		return nil, err
+	}
+	return &userResolver{user: user}, nil
+}
+
+func (r *resolver) Product(ctx context.Context, args struct{ ID graphql.ID }) (*productResolver, error) {
+	id, err := parseProductID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+	actor, err := actorFrom(ctx)
+	if err != nil {
+		return nil, err
+	}
+	product, err := r.products.Get(ctx, actor, id)
+	if apperrors.From(err).Code == apperrors.CodeProductNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &productResolver{product: product}, nil
+}
+
+func (r *resolver) Products(ctx context.Context, args struct {
+	Page   int32
+	Limit  int32
+	Search *string
+}) (*productConnectionResolver, error) {
+	input := services.ListProductsInput{Page: int(args.Page), Limit: int(args.Limit), WithoutOwner: true}
+	if args.Search != nil {
+		input.Search = *args.Search
+	}
+	result, err := r.products.List(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	// Pemilik semua produk di halaman ini dimuat dalam satu query
+	loader := loaderFrom(ctx)
+	for _, product := range result.Products {
+		if id, err := uuid.Parse(product.User.Id); err == nil {
+			loader.Prime(id)
+		}
+	}
+	return &productConnectionResolver{list: result}, nil
+}
+
+func (r *resolver) CreateProduct(ctx context.Context, args struct {
+	Input struct {
+		Name     string
+		Price    string
+		Currency *string
+		Image    Upload
+	}
+}) (*productResolver, error) {
+	actor, err := actorFrom(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	input := createProductInput{Name: args.Input.Name, Price: args.Input.Price, Currency: args.Input.Currency, Image: args.Input.Image.file()}
+	if err := validation.Struct(input); err != nil {
+		return nil, err
+	}
+
+	product, err := r.products.Create(ctx, actor, services.CreateProductInput{
+		Name:    args.Input.Name,
+		Price:   money.Input{Amount: args.Input.Price, Currency: stringOf(args.Input.Currency)},
+		OwnerID: actor.UserID,
+		Images:  toUploads(input.Image),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &productResolver{product: product}, nil
+}
+
+func (r *resolver) UpdateProduct(ctx context.Context, args struct {
+	ID    graphql.ID
+	Input struct {
+		Name            *string
+		Price           *string
+		Currency        *string
+		Image           *Upload
+		ExpectedVersion *int32
+	}
+}) (*productResolver, error) {
+	actor, err := actorFrom(ctx)
+	if err != nil {
+		return nil, err
+	}
+	id, err := parseProductID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	input := updateProductInput{Name: args.Input.Name, Price: args.Input.Price, Currency: args.Input.Currency}
+	update := services.UpdateProductInput{Name: args.Input.Name, OwnerID: actor.UserID, IfMatch: expectedVersion(args.Input.ExpectedVersion)}
+	if args.Input.Price != nil {
+		update.Price = &money.Input{Amount: *args.Input.Price, Currency: stringOf(args.Input.Currency)}
+	}
+	if args.Input.Image != nil {
+		input.Image = args.Input.Image.file()
+	}
+	if err := validation.Struct(input); err != nil {
+		return nil, err
+	}
+	update.Images = toUploads(input.Image)
+
+	product, err := r.products.Update(ctx, actor, id, update)
+	if err != nil {
+		return nil, err
+	}
+	return &productResolver{product: product}, nil
+}
+
+func (r *resolver) DeleteProduct(ctx context.Context, args struct {
+	ID              graphql.ID
+	ExpectedVersion *int32
+}) (graphql.ID, error) {
+	actor, err := actorFrom(ctx)
+	if err != nil {
+		return "", err
+	}
+	id, err := parseProductID(args.ID)
+	if err != nil {
+		return "", err
+	}
+	if err := r.products.Delete(ctx, actor, id, expectedVersion(args.ExpectedVersion)); err != nil {
+		return "", err
+	}
+	return args.ID, nil
+}
+
+// expectedVersion mengubah argumen expectedVersion menjadi IfMatch, nil berarti tidak dicek
+func expectedVersion(version *int32) []int64 {
+	if version == nil {
+		return nil
+	}
+	return []int64{int64(*version)}
+}
+
+// loadUser memuat user lewat loader request, error jika user tidak ada
+func loadUser(ctx context.Context, id uuid.UUID) (*userResolver, error) {
+	user, err := loaderFrom(ctx).Load(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve user: %w", err)
+	}
+	if user == nil {
+		return nil, apperrors.New(apperrors.CodeUserNotFound)
+	}
+	return &userResolver{user: user}, nil
+}
+
+type userResolver struct {
+	user *models.User
+}
+
+func (r *userResolver) ID() graphql.ID   { return graphql.ID(r.user.Id.String()) }
+func (r *userResolver) Username() string { return r.user.Username }
+func (r *userResolver) Email() string    { return r.user.Email }
+
+type productResolver struct {
+	product *models.ProductResponse
+}
+
+func (r *productResolver) ID() graphql.ID           { return graphql.ID(r.product.Id) }
+func (r *productResolver) Name() string             { return r.product.Name }
+func (r *productResolver) Price() *moneyResolver    { return &moneyResolver{r.product.Price} }
+func (r *productResolver) PriceMin() *moneyResolver { return &moneyResolver{r.product.PriceMin} }
+func (r *productResolver) PriceMax() *moneyResolver { return &moneyResolver{r.product.PriceMax} }
+func (r *productResolver) Image() string            { return r.product.Image }
+func (r *productResolver) Version() int32           { return int32(r.product.Version) }
+func (r *productResolver) CreatedAt() graphql.Time  { return graphql.Time{Time: r.product.CreatedAt} }
+func (r *productResolver) UpdatedAt() graphql.Time  { return graphql.Time{Time: r.product.UpdatedAt} }
+
+func (r *productResolver) Images() []*productImageResolver {
+	images := make([]*productImageResolver, 0, len(r.product.Images))
+	for i := range r.product.Images {
+		images = append(images, &productImageResolver{image: &r.product.Images[i]})
+	}
+	return images
+}
+
+func (r *productResolver) Options() []*productOptionResolver {
+	options := make([]*productOptionResolver, 0, len(r.product.Options))
+	for i := range r.product.Options {
+		options = append(options, &productOptionResolver{option: &r.product.Options[i]})
+	}
+	return options
+}
+
+func (r *productResolver) Variants() []*productVariantResolver {
+	variants := make([]*productVariantResolver, 0, len(r.product.Variants))
+	for i := range r.product.Variants {
+		variants = append(variants, &productVariantResolver{variant: &r.product.Variants[i], options: r.product.Options})
+	}
+	return variants
+}
+
+func (r *productResolver) Owner(ctx context.Context) (*userResolver, error) {
+	id, err := uuid.Parse(r.product.User.Id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid owner id %q: %w", r.product.User.Id, err)
+	}
+	return loadUser(ctx, id)
+}
+
+type productImageResolver struct {
+	image *models.ProductImageResponse
+}
+
+func (r *productImageResolver) ID() graphql.ID  { return graphql.ID(r.image.Id) }
+func (r *productImageResolver) Path() string    { return r.image.Path }
+func (r *productImageResolver) Alt() string     { return r.image.Alt }
+func (r *productImageResolver) Position() int32 { return int32(r.image.Position) }
+func (r *productImageResolver) Primary() bool   { return r.image.Primary }
+
+type productOptionResolver struct {
+	option *models.ProductOptionResponse
+}
+
+func (r *productOptionResolver) ID() graphql.ID   { return graphql.ID(r.option.Id) }
+func (r *productOptionResolver) Name() string     { return r.option.Name }
+func (r *productOptionResolver) Values() []string { return r.option.Values }
+
+type productVariantResolver struct {
+	variant *models.ProductVariantResponse
+	// options dipakai untuk mengurutkan attributes sesuai urutan option
+	options []models.ProductOptionResponse
+}
+
+func (r *productVariantResolver) ID() graphql.ID        { return graphql.ID(r.variant.Id) }
+func (r *productVariantResolver) Sku() string           { return r.variant.SKU }
+func (r *productVariantResolver) Price() *moneyResolver { return &moneyResolver{r.variant.Price} }
+func (r *productVariantResolver) Image() string         { return r.variant.Image }
+
+func (r *productVariantResolver) PriceOverride() *moneyResolver {
+	if r.variant.PriceOverride == nil {
+		return nil
+	}
+	return &moneyResolver{*r.variant.PriceOverride}
+}
+
+func (r *productVariantResolver) Attributes() []*variantAttributeResolver {
+	attributes := make([]*variantAttributeResolver, 0, len(r.options))
+	for _, option := range r.options {
+		if value, ok := r.variant.Attributes[option.Name]; ok {
+			attributes = append(attributes, &variantAttributeResolver{name: option.Name, value: value})
+		}
+	}
+	return attributes
+}
+
+type variantAttributeResolver struct {
+	name, value string
+}
+
+func (r *variantAttributeResolver) Name() string  { return r.name }
+func (r *variantAttributeResolver) Value() string { return r.value }
+
+type productConnectionResolver struct {
+	list *services.ProductList
+}
+
+func (r *productConnectionResolver) Nodes() []*productResolver {
+	nodes := make([]*productResolver, 0, len(r.list.Products))
+	for i := range r.list.Products {
+		nodes = append(nodes, &productResolver{product: &r.list.Products[i]})
+	}
+	return nodes
+}
+
+func (r *productConnectionResolver) PageInfo() *pageInfoResolver {
+	return &pageInfoResolver{list: r.list}
+}
+
+type pageInfoResolver struct {
+	list *services.ProductList
+}
+
+func (r *pageInfoResolver) Page() int32           { return int32(r.list.Page) }
+func (r *pageInfoResolver) Limit() int32          { return int32(r.list.Limit) }
+func (r *pageInfoResolver) TotalItems() int32     { return int32(r.list.TotalItems) }
+func (r *pageInfoResolver) TotalPages() int32     { return int32(r.list.TotalPages) }
+func (r *pageInfoResolver) HasNextPage() bool     { return r.list.HasNextPage }
+func (r *pageInfoResolver) SearchTruncated() bool { return r.list.SearchTruncated }
+
+func parseProductID(id graphql.ID) (uuid.UUID, error) {
+	parsed, err := uuid.Parse(string(id))
+	if err != nil {
+		return uuid.Nil, apperrors.Wrap(apperrors.CodeInvalidProductID, err)
+	}
+	return parsed, nil
+}
+
+// toUploads mengubah satu file GraphQL menjadi daftar gambar service, nil jika tidak ada file
+func toUploads(file *validation.File) []services.ImageUpload {
+	if file == nil {
+		return nil
+	}
+	return []services.ImageUpload{{Filename: file.Name, Content: bytes.NewReader(file.Content)}}
+}
+
+type moneyResolver struct {
+	money money.Money
+}
+
+func (r *moneyResolver) Amount() string      { return r.money.Decimal() }
+func (r *moneyResolver) Currency() string    { return r.money.Currency }
+func (r *moneyResolver) MinorUnits() float64 { return float64(r.money.Amount) }
+
+// stringOf mengembalikan isi pointer string, kosong jika nil
+func stringOf(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,91 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"server-cookie/apperrors"
+	"server-cookie/middleware"
+	"server-cookie/services"
+	"strings"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+)
+
+// AuthorizationKey adalah key metadata untuk token, isinya "Bearer <jwt>"
+const AuthorizationKey = "authorization"
+
+// publicServices bisa dipanggil tanpa token
+var publicServices = []string{
+	healthpb.Health_ServiceDesc.ServiceName,
+	"grpc.reflection.v1.ServerReflection",
+	"grpc.reflection.v1alpha.ServerReflection",
+}
+
+type actorKey struct{}
+
+func unaryAuthInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	ctx, err := authenticate(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func streamAuthInterceptor(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := authenticate(ss.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+	return handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+}
+
+// authenticate memvalidasi JWT dari metadata dan menyimpan actor di context
+func authenticate(ctx context.Context, fullMethod string) (context.Context, error) {
+	for _, service := range publicServices {
+		if strings.HasPrefix(fullMethod, "/"+service+"/") {
+			return ctx, nil
+		}
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get(AuthorizationKey)
+	if len(values) == 0 {
+		return nil, apperrors.New(apperrors.CodeTokenMissing)
+	}
+	token, ok := strings.CutPrefix(values[0], "Bearer ")
+	if !ok {
+		return nil, apperrors.Wrap(apperrors.CodeTokenInvalid, errors.New("authorization must use the Bearer scheme"))
+	}
+
+	claims, err := middleware.ParseToken(token)
+	if err != nil {
+		return nil, apperrors.Wrap(apperrors.CodeTokenInvalid, err)
+	}
+	userID, err := uuid.Parse(claims.UserId)
+	if err != nil {
+		return nil, apperrors.Wrap(apperrors.CodeTokenInvalid, err)
+	}
+	return context.WithValue(ctx, actorKey{}, services.Actor{UserID: userID, Username: claims.Username}), nil
+}
+
+// actorFrom mengambil user yang login dari context
+func actorFrom(ctx context.Context) (services.Actor, error) {
+	actor, ok := ctx.Value(actorKey{}).(services.Actor)
+	if !ok {
+		return services.Actor{}, apperrors.New(apperrors.CodeTokenMissing)
+	}
+	return actor, nil
+}
+
+// contextStream mengganti context milik stream
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
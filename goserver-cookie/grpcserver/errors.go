@@ -0,0 +1,92 @@
+package grpcserver
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"server-cookie/apperrors"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+)
+
+// ErrorDomain adalah domain di ErrorInfo, reason berisi kode apperrors
+const ErrorDomain = "server-cookie"
+
+func unaryErrorInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	resp, err := handler(ctx, req)
+	if err != nil {
+		return nil, toStatus(ctx, info.FullMethod, err)
+	}
+	return resp, nil
+}
+
+func streamErrorInterceptor(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := handler(srv, ss); err != nil {
+		return toStatus(ss.Context(), info.FullMethod, err)
+	}
+	return nil
+}
+
+// toStatus mengubah error aplikasi menjadi status gRPC dengan pesan yang sama seperti
+// problem+json: ErrorInfo berisi kode error dan BadRequest berisi error per field
+func toStatus(ctx context.Context, fullMethod string, err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	appErr := apperrors.From(err)
+	if appErr.Code == apperrors.CodeInternal {
+		log.Printf("❌ gRPC %s: %v", fullMethod, err)
+	}
+
+	lang := apperrors.DefaultLang
+	if md, ok := metadata.FromIncomingContext(ctx); ok && len(md.Get("accept-language")) > 0 {
+		lang = apperrors.ParseAcceptLanguage(md.Get("accept-language")[0])
+	}
+
+	details := []protoadapt.MessageV1{&errdetails.ErrorInfo{Reason: string(appErr.Code), Domain: ErrorDomain}}
+	if len(appErr.Fields) > 0 {
+		badRequest := &errdetails.BadRequest{}
+		for _, field := range appErr.Fields {
+			badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+				Field:       field.Field,
+				Reason:      field.Code,
+				Description: apperrors.FieldMessage(lang, field.Code, field.Field, field.Param),
+			})
+		}
+		details = append(details, badRequest)
+	}
+
+	st := status.New(grpcCode(appErr.Code.Status()), appErr.Code.Message(lang))
+	if withDetails, err := st.WithDetails(details...); err == nil {
+		st = withDetails
+	}
+	return st.Err()
+}
+
+// grpcCode memetakan status HTTP dari katalog error ke kode gRPC
+func grpcCode(httpStatus int) codes.Code {
+	switch httpStatus {
+	case http.StatusBadRequest:
+		return codes.InvalidArgument
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusConflict:
+		return codes.AlreadyExists
+	case http.StatusPreconditionFailed:
+		return codes.FailedPrecondition
+	case http.StatusMethodNotAllowed:
+		return codes.Unimplemented
+	default:
+		return codes.Internal
+	}
+}
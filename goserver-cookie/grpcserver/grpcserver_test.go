@@ -0,0 +1,238 @@
+package grpcserver_test
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"server-cookie/apperrors"
+	cookiev1 "server-cookie/gen/cookie/v1"
+	"server-cookie/grpcserver"
+	"server-cookie/middleware"
+	"server-cookie/models"
+	"server-cookie/repositories"
+	"server-cookie/search"
+	"server-cookie/services"
+	"server-cookie/storage"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+var jpeg = []byte("\xff\xd8\xff\xe0 fake jpeg content")
+
+type testServer struct {
+	conn  *grpc.ClientConn
+	users *repositories.MemoryUserRepository
+}
+
+func newTestServer(t *testing.T) *testServer {
+	t.Helper()
+	users := repositories.NewMemoryUserRepository()
+	categories := repositories.NewMemoryCategoryRepository()
+	revisions := repositories.NewMemoryRevisionRepository()
+	inventory := repositories.NewMemoryInventoryRepository()
+	products := services.NewProductService(repositories.NewMemoryProductRepository(users, categories, inventory, revisions), categories, inventory, revisions, storage.NewLocalImageStore(t.TempDir(), t.TempDir()), services.NewCursorSigner([]byte("test-cursor-secret")), search.NewMemoryIndex())
+
+	listener := bufconn.Listen(1 << 20)
+	server := grpcserver.New(grpcserver.Services{Products: products, Users: users})
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return &testServer{conn: conn, users: users}
+}
+
+// login membuat user dan mengembalikan context dengan token di metadata
+func (s *testServer) login(t *testing.T, username string) (context.Context, models.User) {
+	t.Helper()
+	user := models.User{Username: username, Email: username + "@example.com", Password: "x"}
+	if err := s.users.Create(context.Background(), &user); err != nil {
+		t.Fatal(err)
+	}
+	token, err := middleware.GenerateToken(user.Id.String(), username, user.Role)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return metadata.AppendToOutgoingContext(context.Background(), grpcserver.AuthorizationKey, "Bearer "+token), user
+}
+
+// expectStatus memastikan error adalah status gRPC dengan kode dan reason yang diharapkan
+func expectStatus(t *testing.T, err error, want codes.Code, reason apperrors.Code) *status.Status {
+	t.Helper()
+	st, ok := status.FromError(err)
+	if !ok || st.Code() != want {
+		t.Fatalf("error = %v, want code %s", err, want)
+	}
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok {
+			if info.Reason != string(reason) {
+				t.Fatalf("reason = %s, want %s", info.Reason, reason)
+			}
+			return st
+		}
+	}
+	t.Fatalf("status %v has no ErrorInfo", st)
+	return nil
+}
+
+func TestHealthAndReflectionArePublic(t *testing.T) {
+	s := newTestServer(t)
+	ctx := context.Background()
+
+	resp, err := healthpb.NewHealthClient(s.conn).Check(ctx, &healthpb.HealthCheckRequest{Service: "cookie.v1.ProductService"})
+	if err != nil || resp.Status != healthpb.HealthCheckResponse_SERVING {
+		t.Fatalf("health = %v, %v", resp, err)
+	}
+
+	stream, err := reflectionpb.NewServerReflectionClient(s.conn).ServerReflectionInfo(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := stream.Send(&reflectionpb.ServerReflectionRequest{MessageRequest: &reflectionpb.ServerReflectionRequest_ListServices{}}); err != nil {
+		t.Fatal(err)
+	}
+	reply, err := stream.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := map[string]bool{}
+	for _, service := range reply.GetListServicesResponse().GetService() {
+		found[service.Name] = true
+	}
+	if !found["cookie.v1.ProductService"] || !found["cookie.v1.UserService"] {
+		t.Fatalf("reflection services = %v", found)
+	}
+}
+
+func TestAuthentication(t *testing.T) {
+	s := newTestServer(t)
+	client := cookiev1.NewProductServiceClient(s.conn)
+
+	_, err := client.ListProducts(context.Background(), &cookiev1.ListProductsRequest{})
+	expectStatus(t, err, codes.Unauthenticated, apperrors.CodeTokenMissing)
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), grpcserver.AuthorizationKey, "Bearer forged")
+	_, err = client.ListProducts(ctx, &cookiev1.ListProductsRequest{})
+	expectStatus(t, err, codes.Unauthenticated, apperrors.CodeTokenInvalid)
+}
+
+func TestProductLifecycle(t *testing.T) {
+	s := newTestServer(t)
+	client := cookiev1.NewProductServiceClient(s.conn)
+	ctx, alice := s.login(t, "alice")
+
+	// Validasi memakai aturan dan kode field yang sama dengan REST
+	_, err := client.CreateProduct(ctx, &cookiev1.CreateProductRequest{Name: "X"})
+	st := expectStatus(t, err, codes.InvalidArgument, apperrors.CodeValidationFailed)
+	fields := map[string]string{}
+	for _, detail := range st.Details() {
+		if badRequest, ok := detail.(*errdetails.BadRequest); ok {
+			for _, violation := range badRequest.FieldViolations {
+				fields[violation.Field] = violation.Reason
+			}
+		}
+	}
+	if fields["name"] != "min" || fields["price"] != "required" || fields["image"] != "required" {
+		t.Fatalf("field violations = %v", fields)
+	}
+
+	_, err = client.CreateProduct(ctx, &cookiev1.CreateProductRequest{Name: "Cookie", Price: &cookiev1.Money{Amount: "1000"}, Image: &cookiev1.Image{Filename: "photo.png", Content: jpeg}})
+	expectStatus(t, err, codes.InvalidArgument, apperrors.CodeValidationFailed)
+
+	created, err := client.CreateProduct(ctx, &cookiev1.CreateProductRequest{Name: "Cookie", Price: &cookiev1.Money{Amount: "1000"}, Image: &cookiev1.Image{Filename: "photo.jpg", Content: jpeg}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	product := created.Product
+	if product.Owner.GetId() != alice.Id.String() || product.Image == "" || product.CreateTime == nil || product.Price.GetMinorUnits() != 100000 {
+		t.Fatalf("created product = %v", product)
+	}
+
+	got, err := client.GetProduct(ctx, &cookiev1.GetProductRequest{Id: product.Id})
+	if err != nil || got.Product.Name != "Cookie" {
+		t.Fatalf("get = %v, %v", got, err)
+	}
+	_, err = client.GetProduct(ctx, &cookiev1.GetProductRequest{Id: "not-a-uuid"})
+	expectStatus(t, err, codes.InvalidArgument, apperrors.CodeInvalidProductID)
+
+	list, err := client.ListProducts(ctx, &cookiev1.ListProductsRequest{Search: "cook"})
+	if err != nil || list.TotalItems != 1 || list.Page != 1 || list.Limit != 10 {
+		t.Fatalf("list = %v, %v", list, err)
+	}
+
+	// Hanya harga yang diubah, nama tetap
+	updated, err := client.UpdateProduct(ctx, &cookiev1.UpdateProductRequest{Id: product.Id, Price: &cookiev1.Money{MinorUnits: 250000}})
+	if err != nil || updated.Product.Price.GetAmount() != "2500.00" || updated.Product.Price.GetCurrencyCode() != "IDR" || updated.Product.Name != "Cookie" {
+		t.Fatalf("update = %v, %v", updated, err)
+	}
+	_, err = client.UpdateProduct(ctx, &cookiev1.UpdateProductRequest{Id: product.Id, Price: &cookiev1.Money{Amount: "10", CurrencyCode: "USD"}})
+	expectStatus(t, err, codes.InvalidArgument, apperrors.CodeValidationFailed)
+	empty := ""
+	_, err = client.UpdateProduct(ctx, &cookiev1.UpdateProductRequest{Id: product.Id, Name: &empty})
+	expectStatus(t, err, codes.InvalidArgument, apperrors.CodeValidationFailed)
+
+	// expected_version lama ditolak, versi terbaru diterima
+	stale := product.Version
+	name := "Chocolate Cookie"
+	_, err = client.UpdateProduct(ctx, &cookiev1.UpdateProductRequest{Id: product.Id, Name: &name, ExpectedVersion: &stale})
+	expectStatus(t, err, codes.FailedPrecondition, apperrors.CodeVersionMismatch)
+	_, err = client.DeleteProduct(ctx, &cookiev1.DeleteProductRequest{Id: product.Id, ExpectedVersion: &stale})
+	expectStatus(t, err, codes.FailedPrecondition, apperrors.CodeVersionMismatch)
+	renamed, err := client.UpdateProduct(ctx, &cookiev1.UpdateProductRequest{Id: product.Id, Name: &name, ExpectedVersion: &updated.Product.Version})
+	if err != nil || renamed.Product.Version != updated.Product.Version+1 {
+		t.Fatalf("update with current version = %v, %v", renamed, err)
+	}
+
+	other, _ := s.login(t, "mallory")
+	_, err = client.DeleteProduct(other, &cookiev1.DeleteProductRequest{Id: product.Id})
+	expectStatus(t, err, codes.PermissionDenied, apperrors.CodeNotProductOwner)
+
+	if _, err := client.DeleteProduct(ctx, &cookiev1.DeleteProductRequest{Id: product.Id}); err != nil {
+		t.Fatal(err)
+	}
+	_, err = client.GetProduct(ctx, &cookiev1.GetProductRequest{Id: product.Id})
+	expectStatus(t, err, codes.NotFound, apperrors.CodeProductNotFound)
+}
+
+func TestGetUser(t *testing.T) {
+	s := newTestServer(t)
+	client := cookiev1.NewUserServiceClient(s.conn)
+	ctx, alice := s.login(t, "alice")
+
+	byID, err := client.GetUser(ctx, &cookiev1.GetUserRequest{Lookup: &cookiev1.GetUserRequest_Id{Id: alice.Id.String()}})
+	if err != nil || byID.User.Username != "alice" || byID.User.Email != "alice@example.com" {
+		t.Fatalf("by id = %v, %v", byID, err)
+	}
+	byName, err := client.GetUser(ctx, &cookiev1.GetUserRequest{Lookup: &cookiev1.GetUserRequest_Username{Username: "alice"}})
+	if err != nil || byName.User.Id != alice.Id.String() {
+		t.Fatalf("by username = %v, %v", byName, err)
+	}
+
+	_, err = client.GetUser(ctx, &cookiev1.GetUserRequest{Lookup: &cookiev1.GetUserRequest_Username{Username: "nobody"}})
+	expectStatus(t, err, codes.NotFound, apperrors.CodeUserNotFound)
+	_, err = client.GetUser(ctx, &cookiev1.GetUserRequest{Lookup: &cookiev1.GetUserRequest_Id{Id: "x"}})
+	expectStatus(t, err, codes.InvalidArgument, apperrors.CodeInvalidUserID)
+
+	// Pesan error mengikuti metadata accept-language
+	idCtx := metadata.AppendToOutgoingContext(ctx, "accept-language", "id")
+	_, err = client.GetUser(idCtx, &cookiev1.GetUserRequest{})
+	st := expectStatus(t, err, codes.InvalidArgument, apperrors.CodeValidationFailed)
+	if st.Message() != apperrors.CodeValidationFailed.Message(apperrors.LangID) {
+		t.Fatalf("message = %q", st.Message())
+	}
+}
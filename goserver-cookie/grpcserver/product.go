@@ -0,0 +1,209 @@
+package grpcserver
+
+import (
+	"bytes"
+	"context"
+	"server-cookie/apperrors"
+	cookiev1 "server-cookie/gen/cookie/v1"
+	"server-cookie/models"
+	"server-cookie/money"
+	"server-cookie/services"
+	"server-cookie/validation"
+
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// createProductInput memakai aturan validasi yang sama dengan CreateProductForm,
+// jumlah dan mata uang price diperiksa oleh service
+type createProductInput struct {
+	Name  string           `json:"name" validate:"required,min=2,max=100"`
+	Price *money.Input     `json:"price" validate:"required"`
+	Image *validation.File `json:"image" validate:"required,image_type,max_file_size=5242880"`
+}
+
+// updateProductInput memakai aturan validasi yang sama dengan UpdateProductForm,
+// field nil tidak diubah
+type updateProductInput struct {
+	Name  *string          `json:"name" validate:"omitnil,min=2,max=100"`
+	Image *validation.File `json:"image" validate:"omitnil,image_type,max_file_size=5242880"`
+}
+
+// productServer adalah adapter gRPC untuk ProductService
+type productServer struct {
+	cookiev1.UnimplementedProductServiceServer
+	products *services.ProductService
+}
+
+func (s *productServer) ListProducts(ctx context.Context, req *cookiev1.ListProductsRequest) (*cookiev1.ListProductsResponse, error) {
+	result, err := s.products.List(ctx, services.ListProductsInput{
+		Page:   int(req.GetPage()),
+		Limit:  int(req.GetLimit()),
+		Search: req.GetSearch(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	products := make([]*cookiev1.Product, 0, len(result.Products))
+	for _, product := range result.Products {
+		products = append(products, toProto(&product))
+	}
+	return &cookiev1.ListProductsResponse{
+		Products:        products,
+		Page:            int32(result.Page),
+		Limit:           int32(result.Limit),
+		TotalItems:      result.TotalItems,
+		TotalPages:      int32(result.TotalPages),
+		HasNextPage:     result.HasNextPage,
+		SearchTruncated: result.SearchTruncated,
+	}, nil
+}
+
+func (s *productServer) GetProduct(ctx context.Context, req *cookiev1.GetProductRequest) (*cookiev1.GetProductResponse, error) {
+	id, err := parseProductID(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	actor, err := actorFrom(ctx)
+	if err != nil {
+		return nil, err
+	}
+	product, err := s.products.Get(ctx, actor, id)
+	if err != nil {
+		return nil, err
+	}
+	return &cookiev1.GetProductResponse{Product: toProto(product)}, nil
+}
+
+func (s *productServer) CreateProduct(ctx context.Context, req *cookiev1.CreateProductRequest) (*cookiev1.CreateProductResponse, error) {
+	actor, err := actorFrom(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	input := createProductInput{Name: req.GetName(), Price: toMoneyInput(req.GetPrice()), Image: toFile(req.GetImage())}
+	if err := validation.Struct(input); err != nil {
+		return nil, err
+	}
+
+	// Produk selalu dimiliki oleh user yang login
+	product, err := s.products.Create(ctx, actor, services.CreateProductInput{
+		Name:    req.GetName(),
+		Price:   *input.Price,
+		OwnerID: actor.UserID,
+		Images:  toUploads(input.Image),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &cookiev1.CreateProductResponse{Product: toProto(product)}, nil
+}
+
+func (s *productServer) UpdateProduct(ctx context.Context, req *cookiev1.UpdateProductRequest) (*cookiev1.UpdateProductResponse, error) {
+	actor, err := actorFrom(ctx)
+	if err != nil {
+		return nil, err
+	}
+	id, err := parseProductID(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	input := updateProductInput{Name: req.Name, Image: toFile(req.GetImage())}
+	if err := validation.Struct(input); err != nil {
+		return nil, err
+	}
+
+	product, err := s.products.Update(ctx, actor, id, services.UpdateProductInput{
+		Name:    req.Name,
+		Price:   toMoneyInput(req.GetPrice()),
+		OwnerID: actor.UserID,
+		Images:  toUploads(input.Image),
+		IfMatch: expectedVersion(req.ExpectedVersion),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &cookiev1.UpdateProductResponse{Product: toProto(product)}, nil
+}
+
+func (s *productServer) DeleteProduct(ctx context.Context, req *cookiev1.DeleteProductRequest) (*cookiev1.DeleteProductResponse, error) {
+	actor, err := actorFrom(ctx)
+	if err != nil {
+		return nil, err
+	}
+	id, err := parseProductID(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	if err := s.products.Delete(ctx, actor, id, expectedVersion(req.ExpectedVersion)); err != nil {
+		return nil, err
+	}
+	return &cookiev1.DeleteProductResponse{}, nil
+}
+
+// expectedVersion mengubah expected_version menjadi IfMatch, nil berarti tidak dicek
+func expectedVersion(version *int64) []int64 {
+	if version == nil {
+		return nil
+	}
+	return []int64{*version}
+}
+
+func parseProductID(id string) (uuid.UUID, error) {
+	parsed, err := uuid.Parse(id)
+	if err != nil {
+		return uuid.Nil, apperrors.Wrap(apperrors.CodeInvalidProductID, err)
+	}
+	return parsed, nil
+}
+
+func toFile(image *cookiev1.Image) *validation.File {
+	if image == nil {
+		return nil
+	}
+	return &validation.File{Name: image.GetFilename(), Content: image.GetContent()}
+}
+
+// toUploads mengubah satu file gRPC menjadi daftar gambar service, nil jika tidak ada file
+func toUploads(file *validation.File) []services.ImageUpload {
+	if file == nil {
+		return nil
+	}
+	return []services.ImageUpload{{Filename: file.Name, Content: bytes.NewReader(file.Content)}}
+}
+
+// toMoneyInput mengubah harga gRPC menjadi input service, nil jika tidak diisi.
+// amount dipakai jika diisi, selain itu minor_units.
+func toMoneyInput(price *cookiev1.Money) *money.Input {
+	if price == nil {
+		return nil
+	}
+	if price.GetAmount() != "" {
+		return &money.Input{Amount: price.GetAmount(), Currency: price.GetCurrencyCode()}
+	}
+	minorUnits := price.GetMinorUnits()
+	return &money.Input{MinorUnits: &minorUnits, Currency: price.GetCurrencyCode()}
+}
+
+// toProto mengubah ProductResponse menjadi message protobuf
+func toProto(product *models.ProductResponse) *cookiev1.Product {
+	return &cookiev1.Product{
+		Id:   product.Id,
+		Name: product.Name,
+		Price: &cookiev1.Money{
+			CurrencyCode: product.Price.Currency,
+			MinorUnits:   product.Price.Amount,
+			Amount:       product.Price.Decimal(),
+		},
+		Image: product.Image,
+		Owner: &cookiev1.UserSummary{
+			Id:       product.User.Id,
+			Username: product.User.Username,
+		},
+		CreateTime: timestamppb.New(product.CreatedAt),
+		UpdateTime: timestamppb.New(product.UpdatedAt),
+		Version:    product.Version,
+	}
+}
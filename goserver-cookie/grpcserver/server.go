@@ -0,0 +1,45 @@
+// Package grpcserver adalah adapter gRPC untuk service yang sama dengan handler gin.
+// Kode protobuf ada di gen/cookie/v1 dan dibuat ulang dengan `buf generate`.
+package grpcserver
+
+//go:generate sh -c "cd .. && buf generate"
+
+import (
+	cookiev1 "server-cookie/gen/cookie/v1"
+	"server-cookie/repositories"
+	"server-cookie/services"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+)
+
+// Services berisi dependency yang dipakai server gRPC
+type Services struct {
+	Products *services.ProductService
+	Users    repositories.UserRepository
+}
+
+// New membuat server gRPC dengan service produk dan user, health check dan reflection.
+// Semua RPC kecuali health dan reflection membutuhkan JWT di metadata authorization.
+func New(deps Services, opts ...grpc.ServerOption) *grpc.Server {
+	opts = append(opts,
+		grpc.ChainUnaryInterceptor(unaryErrorInterceptor, unaryAuthInterceptor),
+		grpc.ChainStreamInterceptor(streamErrorInterceptor, streamAuthInterceptor),
+	)
+	s := grpc.NewServer(opts...)
+
+	cookiev1.RegisterProductServiceServer(s, &productServer{products: deps.Products})
+	cookiev1.RegisterUserServiceServer(s, &userServer{users: deps.Users})
+
+	// Status "" adalah status server secara keseluruhan
+	healthServer := health.NewServer()
+	for _, name := range []string{"", cookiev1.ProductService_ServiceDesc.ServiceName, cookiev1.UserService_ServiceDesc.ServiceName} {
+		healthServer.SetServingStatus(name, healthpb.HealthCheckResponse_SERVING)
+	}
+	healthpb.RegisterHealthServer(s, healthServer)
+
+	reflection.Register(s)
+	return s
+}
@@ -0,0 +1,52 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"server-cookie/apperrors"
+	cookiev1 "server-cookie/gen/cookie/v1"
+	"server-cookie/models"
+	"server-cookie/repositories"
+
+	"github.com/google/uuid"
+)
+
+// userServer adalah adapter gRPC untuk pencarian user
+type userServer struct {
+	cookiev1.UnimplementedUserServiceServer
+	users repositories.UserRepository
+}
+
+func (s *userServer) GetUser(ctx context.Context, req *cookiev1.GetUserRequest) (*cookiev1.GetUserResponse, error) {
+	var (
+		user *models.User
+		err  error
+	)
+	switch lookup := req.GetLookup().(type) {
+	case *cookiev1.GetUserRequest_Id:
+		id, parseErr := uuid.Parse(lookup.Id)
+		if parseErr != nil {
+			return nil, apperrors.Wrap(apperrors.CodeInvalidUserID, parseErr)
+		}
+		user, err = s.users.FindByID(ctx, id)
+	case *cookiev1.GetUserRequest_Username:
+		user, err = s.users.FindByUsername(ctx, lookup.Username)
+	default:
+		return nil, apperrors.Validation(apperrors.FieldError{Field: "lookup", Code: "required"})
+	}
+
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil, apperrors.New(apperrors.CodeUserNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve user: %w", err)
+	}
+
+	// Password tidak pernah dikirim, sama seperti UserResponse di REST
+	return &cookiev1.GetUserResponse{User: &cookiev1.User{
+		Id:       user.Id.String(),
+		Username: user.Username,
+		Email:    user.Email,
+	}}, nil
+}
@@ -1,53 +1,90 @@
 package main
 
 import (
-	"net/http"
+	"context"
+	"log"
+	"server-cookie/config"
 	"server-cookie/controllers"
 	"server-cookie/database"
-	"server-cookie/middleware"
+	"server-cookie/graph"
+	"server-cookie/grpcserver"
+	"server-cookie/repositories"
+	"server-cookie/routes"
+	"server-cookie/search"
+	"server-cookie/server"
+	"server-cookie/services"
+	"server-cookie/storage"
 
-	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
 )
 
-func CORSMiddleware() gin.HandlerFunc {
-	return func(c *gin.Context) {
-		c.Writer.Header().Set("Access-Control-Allow-Origin", "http://localhost:3000") // Ganti * dengan domain tertentu jika perlu
-		c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, Cookie")
-		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
-
-		// Jika method OPTIONS, langsung response 200 OK
-		if c.Request.Method == "OPTIONS" {
-			c.AbortWithStatus(http.StatusOK)
-			return
+func main() {
+	cfg := config.LoadConfig()
+	db := database.ConnectDatabase(cfg.DBDriver, cfg.DBDSN, cfg.DBAutoMigrate)
+
+	// Repository, service dan handler
+	userRepo := repositories.NewGormUserRepository(db)
+	productRepo := repositories.NewGormProductRepository(db)
+	categoryRepo := repositories.NewGormCategoryRepository(db)
+	inventoryRepo := repositories.NewGormInventoryRepository(db)
+	exchangeRateRepo := repositories.NewGormExchangeRateRepository(db)
+	revisionRepo := repositories.NewGormRevisionRepository(db)
+	importJobRepo := repositories.NewGormImportJobRepository(db)
+
+	// Pencarian memakai FULLTEXT MySQL, atau index di memory untuk SQLite
+	// yang harus diisi ulang setiap start
+	var searchIndex search.Index = search.NewMySQLIndex(db)
+	reindex := cfg.SearchReindex
+	if cfg.DBDriver == "sqlite" {
+		searchIndex = search.NewMemoryIndex()
+		reindex = true
+	}
+	productService := services.NewProductService(productRepo, categoryRepo, inventoryRepo, revisionRepo, storage.NewLocalImageStore(cfg.UploadDir, cfg.ImageArchiveDir), services.NewCursorSigner(cfg.CursorSecret), searchIndex)
+	if reindex {
+		indexed, err := productService.Reindex(context.Background())
+		if err != nil {
+			log.Fatal("❌ Gagal mengisi index pencarian:", err)
 		}
+		log.Printf("🔎 %d produk diindeks untuk pencarian", indexed)
+	}
+
+	inventoryService := services.NewInventoryService(inventoryRepo, productRepo, cfg.ReservationTTL)
+	exchangeRateService := services.NewExchangeRateService(exchangeRateRepo, userRepo, cfg.ExchangeRateMaxAge)
 
-		c.Next()
+	// Job import yang masih berjalan saat server berhenti tidak akan dilanjutkan
+	bulkService := services.NewBulkService(productService, importJobRepo, services.NewImageClient(cfg.ImportAllowPrivateURLs), cfg.ImportSyncRows)
+	if failed, err := bulkService.FailInterrupted(context.Background()); err != nil {
+		log.Fatal("❌ Gagal memperbarui job import:", err)
+	} else if failed > 0 {
+		log.Printf("⚠️ %d job import terputus ditandai gagal", failed)
 	}
-}
 
-func main() {
-	database.ConnectDatabase()
-
-	r := gin.Default()
-	r.Use(CORSMiddleware())
-	r.Static("/uploads", "./uploads")
-	r.POST("/register", controllers.Register)
-	r.POST("/login", controllers.Login)
-	// Protected routes
-	protectedRoutes := r.Group("/")
-	protectedRoutes.Use(middleware.AuthMiddleware())
-	{
-		protectedRoutes.GET("/logout", controllers.Logout)
-		protectedRoutes.GET("/products", controllers.GetAllProducts)
-		protectedRoutes.GET("/products/:id", controllers.GetProductDetail)
-		protectedRoutes.DELETE("/products/:id", controllers.DeleteProduct)
-		protectedRoutes.PUT("/products/:id", controllers.UpdateProduct)
-		protectedRoutes.POST("/products", controllers.CreateProduct)
-		protectedRoutes.GET("/profile/:id", controllers.GetProfile)
-		protectedRoutes.PUT("/profile/:id", controllers.UpdateProfile)
+	r := routes.SetupRouter(cfg, routes.Handlers{
+		User:         controllers.NewUserHandler(userRepo, cfg.Cookie, cfg.AdminUsernames),
+		Product:      controllers.NewProductHandler(productService, exchangeRateService),
+		Category:     controllers.NewCategoryHandler(services.NewCategoryService(categoryRepo)),
+		Inventory:    controllers.NewInventoryHandler(inventoryService),
+		ExchangeRate: controllers.NewExchangeRateHandler(exchangeRateService),
+		Bulk:         controllers.NewBulkHandler(bulkService),
+		GraphQL:      graph.NewHandler(productService, userRepo, graph.DefaultLimits),
+	})
+
+	// Purge otomatis produk yang terlalu lama di trash
+	if cfg.TrashRetention > 0 {
+		go productService.WatchTrash(context.Background(), cfg.TrashRetention, cfg.TrashPurgeInterval)
 	}
+	// Lepas stok reservasi yang tidak dikonfirmasi sampai kedaluwarsa
+	go inventoryService.WatchReservations(context.Background(), cfg.ReservationSweepInterval)
+	// Publikasikan dan arsipkan produk sesuai jadwalnya
+	go productService.WatchSchedule(context.Background(), cfg.PublishSweepInterval)
 
-	r.Run(":8080")
+	var grpcServer *grpc.Server
+	if cfg.GRPCEnabled {
+		grpcServer = grpcserver.New(grpcserver.Services{Products: productService, Users: userRepo})
+	}
+
+	if err := server.Run(cfg, r, grpcServer); err != nil {
+		log.Fatal("❌ Server berhenti:", err)
+	}
 
 }
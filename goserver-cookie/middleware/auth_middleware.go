@@ -1,35 +1,50 @@
 package middleware
 
 import (
-	"github.com/dgrijalva/jwt-go"
+	"server-cookie/apperrors"
+	"server-cookie/config"
+	"server-cookie/models"
+
 	"github.com/gin-gonic/gin"
-	"net/http"
 )
 
-func AuthMiddleware() gin.HandlerFunc {
+func AuthMiddleware(cookieCfg config.CookieConfig) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Get the token from the cookie
-		tokenString, err := c.Cookie("token")
+		tokenString, err := GetTokenCookie(c, cookieCfg)
 		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Token not found"})
-			c.Abort()
+			AbortWithError(c, apperrors.Wrap(apperrors.CodeTokenMissing, err))
 			return
 		}
 
 		// Parse and validate the token
-		claims := &Claims{}
-		token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-			return jwtKey, nil
-		})
-
-		if err != nil || !token.Valid {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
-			c.Abort()
+		claims, err := ParseToken(tokenString)
+		if err != nil {
+			AbortWithError(c, apperrors.Wrap(apperrors.CodeTokenInvalid, err))
 			return
 		}
 
 		// Attach the claims to the context for further use
+		c.Set("user_id", claims.UserId)
 		c.Set("username", claims.Username)
+		c.Set("role", claims.Role)
+		c.Next()
+	}
+}
+
+// RequireAdmin menolak request dari user yang bukan admin, dipasang setelah
+// AuthMiddleware. Username diperiksa lagi ke daftar admins di setiap request
+// agar token lama tidak tetap admin setelah username dihapus dari konfigurasi.
+func RequireAdmin(admins []string) gin.HandlerFunc {
+	allowed := make(map[string]bool, len(admins))
+	for _, username := range admins {
+		allowed[username] = true
+	}
+	return func(c *gin.Context) {
+		if c.GetString("role") != models.RoleAdmin || !allowed[c.GetString("username")] {
+			AbortWithError(c, apperrors.New(apperrors.CodeAdminRequired))
+			return
+		}
 		c.Next()
 	}
 }
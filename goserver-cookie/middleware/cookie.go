@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"net/http"
+	"server-cookie/config"
+
+	"github.com/gin-gonic/gin"
+)
+
+// newTokenCookie membuat cookie token dengan atribut dari konfigurasi
+func newTokenCookie(cfg config.CookieConfig, value string, maxAge int) *http.Cookie {
+	cookie := &http.Cookie{
+		Name:     cfg.CookieName(),
+		Value:    value,
+		Path:     cfg.Path,
+		Domain:   cfg.Domain,
+		MaxAge:   maxAge,
+		Secure:   cfg.Secure,
+		HttpOnly: cfg.HTTPOnly,
+		SameSite: cfg.SameSite,
+	}
+
+	// Prefix __Host- wajib Path=/ dan tanpa Domain
+	if cfg.HostPrefix {
+		cookie.Path = "/"
+		cookie.Domain = ""
+	}
+	return cookie
+}
+
+// SetTokenCookie menyimpan token JWT ke cookie
+func SetTokenCookie(c *gin.Context, cfg config.CookieConfig, token string) {
+	http.SetCookie(c.Writer, newTokenCookie(cfg, token, cfg.MaxAge))
+}
+
+// ClearTokenCookie menghapus cookie token (expire segera)
+func ClearTokenCookie(c *gin.Context, cfg config.CookieConfig) {
+	http.SetCookie(c.Writer, newTokenCookie(cfg, "", -1))
+}
+
+// GetTokenCookie membaca token JWT dari cookie
+func GetTokenCookie(c *gin.Context, cfg config.CookieConfig) (string, error) {
+	return c.Cookie(cfg.CookieName())
+}
@@ -0,0 +1,25 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func CORSMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Writer.Header().Set("Access-Control-Allow-Origin", "http://localhost:3000") // Ganti * dengan domain tertentu jika perlu
+		c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
+		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, Cookie, X-Request-ID, If-Match")
+		c.Writer.Header().Set("Access-Control-Expose-Headers", "X-Request-ID, Deprecation, Sunset, Link, ETag, Location, Content-Disposition")
+		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
+
+		// Jika method OPTIONS, langsung response 200 OK
+		if c.Request.Method == "OPTIONS" {
+			c.AbortWithStatus(http.StatusOK)
+			return
+		}
+
+		c.Next()
+	}
+}
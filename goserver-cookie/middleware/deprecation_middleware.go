@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DeprecationMiddleware menandai route lama dengan header Deprecation (RFC 9745),
+// Sunset (RFC 8594) dan Link ke route pengganti di bawah successorPrefix
+func DeprecationMiddleware(deprecatedAt, sunset time.Time, successorPrefix string) gin.HandlerFunc {
+	deprecation := "@" + strconv.FormatInt(deprecatedAt.Unix(), 10)
+	sunsetValue := sunset.UTC().Format(http.TimeFormat)
+
+	return func(c *gin.Context) {
+		header := c.Writer.Header()
+		header.Set("Deprecation", deprecation)
+		if !sunset.IsZero() {
+			header.Set("Sunset", sunsetValue)
+		}
+		header.Add("Link", fmt.Sprintf(`<%s%s>; rel="successor-version"`, successorPrefix, c.Request.URL.Path))
+		c.Next()
+	}
+}
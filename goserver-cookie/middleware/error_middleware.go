@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"log"
+	"server-cookie/apperrors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ErrorMiddleware mengirim error yang dicatat handler lewat c.Error
+// sebagai response problem+json. Detail internal disembunyikan jika production.
+func ErrorMiddleware(production bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+
+		err := c.Errors.Last().Err
+		problem := apperrors.NewProblem(err, apperrors.ParseAcceptLanguage(c.GetHeader("Accept-Language")), !production)
+		problem.Instance = c.Request.URL.Path
+		problem.RequestID = c.GetString("request_id")
+
+		if problem.Code == apperrors.CodeInternal {
+			log.Printf("❌ [%s] %s %s: %v", problem.RequestID, c.Request.Method, c.Request.URL.Path, err)
+		}
+
+		c.Header("Content-Type", apperrors.ContentType)
+		c.JSON(problem.Status, problem)
+	}
+}
+
+// AbortWithError mencatat error untuk ErrorMiddleware dan menghentikan handler berikutnya
+func AbortWithError(c *gin.Context, err error) {
+	c.Error(err)
+	c.Abort()
+}
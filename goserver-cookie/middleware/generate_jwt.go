@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"errors"
 	"time"
 
 	"github.com/dgrijalva/jwt-go"
@@ -13,16 +14,18 @@ var jwtKey = []byte("my_secret_key")
 type Claims struct {
 	UserId   string `json:"user_id"`
 	Username string `json:"username"`
+	Role     string `json:"role,omitempty"`
 	jwt.StandardClaims
 }
 
 // GenerateToken membuat token JWT untuk user yang berhasil login
-func GenerateToken(userId string, username string) (string, error) {
+func GenerateToken(userId string, username string, role string) (string, error) {
 	expirationTime := time.Now().Add(24 * time.Hour) // Token berlaku selama 24 jam
 
 	claims := &Claims{
 		UserId:   userId,
 		Username: username,
+		Role:     role,
 		StandardClaims: jwt.StandardClaims{
 			ExpiresAt: expirationTime.Unix(),
 		},
@@ -39,3 +42,18 @@ func GenerateToken(userId string, username string) (string, error) {
 
 	return tokenString, nil
 }
+
+// ParseToken memvalidasi token JWT dan mengembalikan klaimnya
+func ParseToken(tokenString string) (*Claims, error) {
+	claims := &Claims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		return jwtKey, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !token.Valid {
+		return nil, errors.New("token is not valid")
+	}
+	return claims, nil
+}
@@ -0,0 +1,23 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// RequestIDHeader adalah header untuk ID request
+const RequestIDHeader = "X-Request-ID"
+
+// RequestIDMiddleware memakai X-Request-ID dari client atau membuat yang baru
+func RequestIDMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if requestID == "" || len(requestID) > 128 {
+			requestID = uuid.New().String()
+		}
+
+		c.Set("request_id", requestID)
+		c.Writer.Header().Set(RequestIDHeader, requestID)
+		c.Next()
+	}
+}
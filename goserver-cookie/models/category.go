@@ -0,0 +1,56 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Category adalah kategori produk yang dikelola admin, bisa bertingkat lewat ParentId
+type Category struct {
+	Id        uuid.UUID  `gorm:"type:char(36);primaryKey" json:"id"`
+	Name      string     `gorm:"type:varchar(100)" json:"name"`
+	Slug      string     `gorm:"type:varchar(100);uniqueIndex" json:"slug"`
+	ParentId  *uuid.UUID `gorm:"type:char(36);index" json:"parent_id"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (c *Category) BeforeCreate(tx *gorm.DB) (err error) {
+	c.Id = uuid.New()
+	return
+}
+
+// Tag adalah label bebas pada produk, disimpan dengan nama yang sudah dinormalisasi
+type Tag struct {
+	Name string `gorm:"type:varchar(50);primaryKey" json:"name"`
+}
+
+// CategoryMinimal adalah data kategori yang ditampilkan di response produk
+type CategoryMinimal struct {
+	Id   string `json:"id"`
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+}
+
+// CategoryNode adalah satu kategori beserta sub-kategorinya
+type CategoryNode struct {
+	Id       string         `json:"id"`
+	Name     string         `json:"name"`
+	Slug     string         `json:"slug"`
+	ParentId *string        `json:"parent_id"`
+	Children []CategoryNode `json:"children"`
+}
+
+// TagCount adalah jumlah produk aktif yang memakai satu tag
+type TagCount struct {
+	Name  string `json:"name"`
+	Count int64  `json:"count"`
+}
+
+// Batas jumlah kategori dan tag per produk
+const (
+	MaxProductCategories = 10
+	MaxProductTags       = 20
+)
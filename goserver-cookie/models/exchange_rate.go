@@ -0,0 +1,63 @@
+package models
+
+import (
+	"server-cookie/money"
+	"time"
+)
+
+// ExchangeRate adalah kurs satu arah yang dikelola admin: 1 Base = Rate Quote.
+// Kurs kebalikannya tidak dihitung otomatis, setiap arah harus dicatat sendiri.
+type ExchangeRate struct {
+	Base  string `gorm:"type:char(3);primaryKey"`
+	Quote string `gorm:"type:char(3);primaryKey"`
+	// Rate disimpan sebagai string desimal agar tidak kehilangan presisi
+	Rate string `gorm:"type:varchar(32)"`
+	// AsOf adalah waktu kurs menurut sumbernya, dipakai untuk menentukan kurs basi
+	AsOf      time.Time
+	Source    string `gorm:"type:varchar(20)"`
+	UpdatedAt time.Time
+}
+
+// Sumber kurs di ExchangeRate
+const (
+	RateSourceManual = "manual"
+	RateSourceImport = "import"
+)
+
+// Batas import kurs dari CSV
+const MaxExchangeRateImportRows = 1000
+
+// ExchangeRateResponse adalah kurs yang ditampilkan di API.
+// Stale bernilai true jika AsOf lebih lama dari batas umur kurs.
+type ExchangeRateResponse struct {
+	Base      string    `json:"base"`
+	Quote     string    `json:"quote"`
+	Rate      string    `json:"rate"`
+	AsOf      time.Time `json:"as_of"`
+	Source    string    `json:"source"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Stale     bool      `json:"stale"`
+}
+
+// Peringatan di PriceConversion
+const (
+	WarningRateStale       = "rate_stale"
+	WarningRateUnavailable = "rate_unavailable"
+)
+
+// PriceConversion adalah harga produk dalam mata uang pilihan pembeli.
+// Harga asli tetap ada di ProductResponse.Price, PriceMin dan PriceMax.
+type PriceConversion struct {
+	Currency string `json:"currency"`
+	// Price, PriceMin dan PriceMax nil jika kurs tidak tersedia
+	Price    *money.Money `json:"price"`
+	PriceMin *money.Money `json:"price_min"`
+	PriceMax *money.Money `json:"price_max"`
+	// Rate adalah kurs yang dipakai, 1 mata uang produk = Rate Currency
+	Rate     string     `json:"rate,omitempty"`
+	RateAsOf *time.Time `json:"rate_as_of,omitempty"`
+	// Rounding adalah cara hasil konversi dibulatkan ke minor unit
+	Rounding string `json:"rounding"`
+	// Warning diisi rate_stale jika kurs basi, atau rate_unavailable jika kurs tidak ada
+	Warning string `json:"warning,omitempty"`
+}
@@ -0,0 +1,122 @@
+package models
+
+import (
+	"server-cookie/apperrors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Format file import dan export produk
+const (
+	FormatCSV   = "csv"
+	FormatJSONL = "jsonl"
+)
+
+// Status ImportJob. Job selesai jika statusnya succeeded atau failed.
+const (
+	ImportQueued    = "queued"
+	ImportRunning   = "running"
+	ImportSucceeded = "succeeded"
+	ImportFailed    = "failed"
+)
+
+// Batas import produk
+const (
+	MaxImportRows = 10000
+	// MaxImportFileSize dan MaxImportArchiveSize dalam byte, untuk file baris dan arsip ZIP gambar
+	MaxImportFileSize    = 10 << 20
+	MaxImportArchiveSize = 100 << 20
+	// MaxImportRowErrors adalah jumlah error baris yang disimpan, sisanya hanya dihitung di Failed
+	MaxImportRowErrors = 1000
+)
+
+// ImportJob adalah satu proses import produk dari file beserta progresnya.
+// Import kecil selesai di dalam request, yang besar dijalankan di background.
+type ImportJob struct {
+	Id     uuid.UUID `gorm:"type:char(36);primaryKey"`
+	UserId uuid.UUID `gorm:"type:char(36);index"`
+	Format string    `gorm:"type:varchar(10)"`
+	// DryRun hanya memvalidasi baris tanpa menyimpan produk
+	DryRun bool
+	Status string `gorm:"type:varchar(20);index"`
+
+	// TotalRows adalah jumlah baris data, ProcessedRows yang sudah diproses.
+	// Created, Updated dan Failed adalah hasil per baris, pada dry-run berarti
+	// produk yang akan dibuat atau diubah.
+	TotalRows     int
+	ProcessedRows int
+	Created       int
+	Updated       int
+	Failed        int
+	Errors        []ImportRowError `gorm:"type:text;serializer:json"`
+	// ErrorCode diisi jika job berhenti sebelum semua baris diproses
+	ErrorCode apperrors.Code `gorm:"type:varchar(50)"`
+
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	FinishedAt *time.Time
+}
+
+func (j *ImportJob) BeforeCreate(tx *gorm.DB) (err error) {
+	if j.Id == uuid.Nil {
+		j.Id = uuid.New()
+	}
+	return
+}
+
+// Finished mengembalikan true jika job tidak diproses lagi
+func (j ImportJob) Finished() bool {
+	return j.Status == ImportSucceeded || j.Status == ImportFailed
+}
+
+// ImportRowError adalah error satu baris import. Row adalah nomor baris di file,
+// header CSV dihitung sebagai baris 1.
+type ImportRowError struct {
+	Row     int                      `json:"row"`
+	Code    apperrors.Code           `json:"code"`
+	Message string                   `json:"message"`
+	Errors  []apperrors.ProblemField `json:"errors,omitempty"`
+}
+
+// ImportJobResponse adalah ImportJob yang ditampilkan di API
+type ImportJobResponse struct {
+	Id            string           `json:"id"`
+	Format        string           `json:"format"`
+	DryRun        bool             `json:"dry_run"`
+	Status        string           `json:"status"`
+	TotalRows     int              `json:"total_rows"`
+	ProcessedRows int              `json:"processed_rows"`
+	Created       int              `json:"created"`
+	Updated       int              `json:"updated"`
+	Failed        int              `json:"failed"`
+	Errors        []ImportRowError `json:"errors"`
+	ErrorCode     apperrors.Code   `json:"error_code,omitempty"`
+	CreatedAt     time.Time        `json:"created_at"`
+	UpdatedAt     time.Time        `json:"updated_at"`
+	FinishedAt    *time.Time       `json:"finished_at"`
+}
+
+func NewImportJobResponse(job ImportJob) ImportJobResponse {
+	errors := job.Errors
+	if errors == nil {
+		errors = []ImportRowError{}
+	}
+	return ImportJobResponse{
+		Id:            job.Id.String(),
+		Format:        job.Format,
+		DryRun:        job.DryRun,
+		Status:        job.Status,
+		TotalRows:     job.TotalRows,
+		ProcessedRows: job.ProcessedRows,
+		Created:       job.Created,
+		Updated:       job.Updated,
+		Failed:        job.Failed,
+		Errors:        errors,
+		ErrorCode:     job.ErrorCode,
+		CreatedAt:     job.CreatedAt,
+		UpdatedAt:     job.UpdatedAt,
+		FinishedAt:    job.FinishedAt,
+	}
+}
@@ -0,0 +1,204 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// StockLevel adalah stok satu produk tanpa varian (VariantId uuid.Nil) atau satu varian.
+// Reserved adalah jumlah yang ditahan reservasi pending, belum dikurangi dari OnHand.
+type StockLevel struct {
+	Id        uuid.UUID `gorm:"type:char(36);primaryKey"`
+	ProductId uuid.UUID `gorm:"type:char(36);uniqueIndex:idx_stock_level_item"`
+	VariantId uuid.UUID `gorm:"type:char(36);uniqueIndex:idx_stock_level_item"`
+	OnHand    int64
+	Reserved  int64
+	// LowStockThreshold 0 berarti stok tidak pernah dianggap menipis
+	LowStockThreshold int64
+	UpdatedAt         time.Time
+}
+
+func (s *StockLevel) BeforeCreate(tx *gorm.DB) (err error) {
+	if s.Id == uuid.Nil {
+		s.Id = uuid.New()
+	}
+	return
+}
+
+// Available adalah stok yang masih bisa dipesan
+func (s StockLevel) Available() int64 {
+	return s.OnHand - s.Reserved
+}
+
+// Low mengembalikan true jika stok yang tersedia sudah mencapai batas stok menipis
+func (s StockLevel) Low() bool {
+	return s.LowStockThreshold > 0 && s.Available() <= s.LowStockThreshold
+}
+
+// Alasan perubahan stok di InventoryMovement
+const (
+	ReasonRestock    = "restock"
+	ReasonSale       = "sale"
+	ReasonReturn     = "return"
+	ReasonDamage     = "damage"
+	ReasonCorrection = "correction"
+)
+
+// InventoryMovement adalah satu baris ledger perubahan OnHand.
+// Balance adalah OnHand setelah perubahan, ReservationId diisi untuk penjualan dari reservasi.
+type InventoryMovement struct {
+	Id            uuid.UUID `gorm:"type:char(36);primaryKey"`
+	ProductId     uuid.UUID `gorm:"type:char(36);index"`
+	VariantId     uuid.UUID `gorm:"type:char(36)"`
+	Delta         int64
+	Balance       int64
+	Reason        string     `gorm:"type:varchar(20)"`
+	Note          string     `gorm:"type:varchar(255)"`
+	ReservationId *uuid.UUID `gorm:"type:char(36)"`
+	UserId        uuid.UUID  `gorm:"type:char(36)"`
+	CreatedAt     time.Time  `gorm:"index"`
+}
+
+func (m *InventoryMovement) BeforeCreate(tx *gorm.DB) (err error) {
+	if m.Id == uuid.Nil {
+		m.Id = uuid.New()
+	}
+	return
+}
+
+// Status StockReservation. Hanya reservasi pending yang menahan stok.
+const (
+	ReservationPending   = "pending"
+	ReservationConfirmed = "confirmed"
+	ReservationReleased  = "released"
+	ReservationExpired   = "expired"
+)
+
+// StockReservation menahan Quantity stok untuk UserId sampai dikonfirmasi,
+// dilepas, atau melewati ExpiresAt
+type StockReservation struct {
+	Id        uuid.UUID `gorm:"type:char(36);primaryKey"`
+	ProductId uuid.UUID `gorm:"type:char(36);index"`
+	VariantId uuid.UUID `gorm:"type:char(36)"`
+	UserId    uuid.UUID `gorm:"type:char(36);index"`
+	Quantity  int64
+	Status    string    `gorm:"type:varchar(20);index:idx_stock_reservation_expiry"`
+	ExpiresAt time.Time `gorm:"index:idx_stock_reservation_expiry"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (r *StockReservation) BeforeCreate(tx *gorm.DB) (err error) {
+	if r.Id == uuid.Nil {
+		r.Id = uuid.New()
+	}
+	return
+}
+
+// StockResponse adalah stok di response produk dan varian. Stok produk dengan varian
+// adalah jumlah stok semua varian, LowStock true jika salah satu varian menipis.
+type StockResponse struct {
+	OnHand    int64 `json:"on_hand"`
+	Reserved  int64 `json:"reserved"`
+	Available int64 `json:"available"`
+	LowStock  bool  `json:"low_stock"`
+}
+
+// StockLevelResponse adalah stok satu item di response inventory.
+// VariantId dan SKU kosong untuk produk tanpa varian.
+type StockLevelResponse struct {
+	VariantId         *string   `json:"variant_id"`
+	SKU               string    `json:"sku"`
+	Tracked           bool      `json:"tracked"`
+	OnHand            int64     `json:"on_hand"`
+	Reserved          int64     `json:"reserved"`
+	Available         int64     `json:"available"`
+	LowStockThreshold int64     `json:"low_stock_threshold"`
+	LowStock          bool      `json:"low_stock"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+type InventoryMovementResponse struct {
+	Id            string    `json:"id"`
+	VariantId     *string   `json:"variant_id"`
+	Delta         int64     `json:"delta"`
+	Balance       int64     `json:"balance"`
+	Reason        string    `json:"reason"`
+	Note          string    `json:"note"`
+	ReservationId *string   `json:"reservation_id"`
+	UserId        string    `json:"user_id"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+type StockReservationResponse struct {
+	Id        string    `json:"id"`
+	ProductId string    `json:"product_id"`
+	VariantId *string   `json:"variant_id"`
+	Quantity  int64     `json:"quantity"`
+	Status    string    `json:"status"`
+	ExpiresAt time.Time `json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// NewStockLevelResponse mengubah StockLevel menjadi format response API.
+// level nil berarti stok item belum pernah dicatat.
+func NewStockLevelResponse(variant *ProductVariant, level *StockLevel) StockLevelResponse {
+	var response StockLevelResponse
+	if variant != nil {
+		response.VariantId = variantIdOf(variant.Id)
+		response.SKU = variant.SKU
+	}
+	if level != nil {
+		response.Tracked = true
+		response.OnHand = level.OnHand
+		response.Reserved = level.Reserved
+		response.Available = level.Available()
+		response.LowStockThreshold = level.LowStockThreshold
+		response.LowStock = level.Low()
+		response.UpdatedAt = level.UpdatedAt
+	}
+	return response
+}
+
+// NewInventoryMovementResponse mengubah InventoryMovement menjadi format response API
+func NewInventoryMovementResponse(movement InventoryMovement) InventoryMovementResponse {
+	response := InventoryMovementResponse{
+		Id:        movement.Id.String(),
+		VariantId: variantIdOf(movement.VariantId),
+		Delta:     movement.Delta,
+		Balance:   movement.Balance,
+		Reason:    movement.Reason,
+		Note:      movement.Note,
+		UserId:    movement.UserId.String(),
+		CreatedAt: movement.CreatedAt,
+	}
+	if movement.ReservationId != nil {
+		id := movement.ReservationId.String()
+		response.ReservationId = &id
+	}
+	return response
+}
+
+// NewStockReservationResponse mengubah StockReservation menjadi format response API
+func NewStockReservationResponse(reservation StockReservation) StockReservationResponse {
+	return StockReservationResponse{
+		Id:        reservation.Id.String(),
+		ProductId: reservation.ProductId.String(),
+		VariantId: variantIdOf(reservation.VariantId),
+		Quantity:  reservation.Quantity,
+		Status:    reservation.Status,
+		ExpiresAt: reservation.ExpiresAt,
+		CreatedAt: reservation.CreatedAt,
+	}
+}
+
+// variantIdOf mengembalikan nil untuk uuid.Nil, yaitu stok produk tanpa varian
+func variantIdOf(id uuid.UUID) *string {
+	if id == uuid.Nil {
+		return nil
+	}
+	s := id.String()
+	return &s
+}
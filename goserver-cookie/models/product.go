@@ -1,6 +1,7 @@
 package models
 
 import (
+	"server-cookie/money"
 	"time"
 
 	"github.com/google/uuid"
@@ -8,24 +9,217 @@ import (
 )
 
 type Product struct {
-	Id        uuid.UUID `gorm:"type:char(36);primaryKey" json:"id"`
-	Name      string    `gorm:"type:varchar(255)" json:"name"`
-	Price     int64     `gorm:"type:int" json:"price"`
-	Image     string    `gorm:"type:varchar(255)" json:"image"`
-	UserId    uuid.UUID `gorm:"type:uuid;index" json:"user_id"`
-	User      User      `gorm:"foreignKey:UserId"` // Menyatakan relasi dengan model User
+	Id   uuid.UUID `gorm:"type:char(36);primaryKey" json:"id"`
+	Name string    `gorm:"type:varchar(255)" json:"name"`
+	// Price disimpan di kolom price_amount (minor unit) dan price_currency.
+	// Mata uang produk juga berlaku untuk harga semua variannya.
+	Price money.Money `gorm:"embedded;embeddedPrefix:price_" json:"price"`
+	// PriceMin dan PriceMax adalah rentang harga semua varian dalam minor unit, sama dengan
+	// Price jika produk tidak punya varian. Diisi ulang oleh RefreshPriceRange.
+	PriceMin int64     `gorm:"type:bigint;index"`
+	PriceMax int64     `gorm:"type:bigint"`
+	UserId   uuid.UUID `gorm:"type:uuid;index;uniqueIndex:idx_products_user_external" json:"user_id"`
+	User     User      `gorm:"foreignKey:UserId"` // Menyatakan relasi dengan model User
+	// ExternalId adalah ID produk di sistem penjual, unik per pemilik (termasuk produk
+	// di trash) dan dipakai untuk mencocokkan baris import dengan produk yang sudah ada
+	ExternalId *string `gorm:"type:varchar(100);uniqueIndex:idx_products_user_external" json:"external_id"`
+	// Status menentukan siapa yang bisa melihat produk, hanya ProductPublished yang tampil
+	// untuk semua user. PublishAt dan UnpublishAt adalah jadwal perubahan status yang
+	// dijalankan worker, nil berarti tidak dijadwalkan.
+	Status      string     `gorm:"type:varchar(20);not null;default:published;index" json:"status"`
+	PublishAt   *time.Time `gorm:"index" json:"publish_at"`
+	UnpublishAt *time.Time `gorm:"index" json:"unpublish_at"`
+	// Images diurutkan berdasarkan Position, paling banyak satu yang Primary
+	Images []ProductImage `gorm:"foreignKey:ProductId"`
+	// Options adalah definisi pilihan (misalnya ukuran dan warna), Variants kombinasinya
+	Options  []ProductOption  `gorm:"foreignKey:ProductId"`
+	Variants []ProductVariant `gorm:"foreignKey:ProductId"`
+	// Stock diisi service dari InventoryRepository, tidak disimpan bersama produk
+	Stock []StockLevel `gorm:"-"`
+	// Relasi many-to-many, disimpan di tabel product_categories dan product_tags
+	Categories []Category `gorm:"many2many:product_categories"`
+	Tags       []Tag      `gorm:"many2many:product_tags"`
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	// Version dimulai dari 1 dan naik setiap kali produk disimpan, dipindah ke trash
+	// atau di-restore. Dipakai sebagai ETag untuk mencegah perubahan saling menimpa.
+	Version int64 `gorm:"not null;default:1" json:"version"`
+
+	// DeletedAt diisi saat produk dipindah ke trash (soft delete)
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// ProductImage adalah satu gambar produk, disimpan di tabel product_images
+type ProductImage struct {
+	Id        uuid.UUID `gorm:"type:char(36);primaryKey"`
+	ProductId uuid.UUID `gorm:"type:char(36);index"`
+	Path      string    `gorm:"type:varchar(255)"`
+	Alt       string    `gorm:"type:varchar(255)"`
+	Position  int
+	Primary   bool `gorm:"column:is_primary"`
 	CreatedAt time.Time
-	UpdatedAt time.Time
+}
+
+func (i *ProductImage) BeforeCreate(tx *gorm.DB) (err error) {
+	// Id dipertahankan saat gambar disimpan ulang bersama produk
+	if i.Id == uuid.Nil {
+		i.Id = uuid.New()
+	}
+	return
+}
+
+// ProductOption adalah satu pilihan varian beserta nilai yang diizinkan, misalnya size: S, M, L
+type ProductOption struct {
+	Id        uuid.UUID `gorm:"type:char(36);primaryKey"`
+	ProductId uuid.UUID `gorm:"type:char(36);index"`
+	Name      string    `gorm:"type:varchar(50)"`
+	Values    []string  `gorm:"type:text;serializer:json"`
+	Position  int
+}
+
+func (o *ProductOption) BeforeCreate(tx *gorm.DB) (err error) {
+	if o.Id == uuid.Nil {
+		o.Id = uuid.New()
+	}
+	return
+}
+
+// ProductVariant adalah satu kombinasi nilai option yang bisa dibeli, dengan SKU sendiri.
+// Combination adalah Attributes dalam bentuk kanonik agar kombinasi yang sama ditolak database.
+type ProductVariant struct {
+	Id          uuid.UUID         `gorm:"type:char(36);primaryKey"`
+	ProductId   uuid.UUID         `gorm:"type:char(36);uniqueIndex:idx_product_variant_combination"`
+	SKU         string            `gorm:"column:sku;type:varchar(64);uniqueIndex"`
+	Price       *int64            `gorm:"column:price_amount;type:bigint"` // minor unit dalam mata uang produk, nil berarti mengikuti harga produk
+	ImageId     *uuid.UUID        `gorm:"type:char(36)"`
+	Attributes  map[string]string `gorm:"type:text;serializer:json"`
+	Combination string            `gorm:"type:varchar(400);uniqueIndex:idx_product_variant_combination"`
+	Position    int
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+func (v *ProductVariant) BeforeCreate(tx *gorm.DB) (err error) {
+	if v.Id == uuid.Nil {
+		v.Id = uuid.New()
+	}
+	return
+}
+
+// EffectivePrice mengembalikan harga varian, atau base jika varian tidak punya harga sendiri
+func (v ProductVariant) EffectivePrice(base int64) int64 {
+	if v.Price != nil {
+		return *v.Price
+	}
+	return base
+}
+
+// RefreshPriceRange mengisi PriceMin dan PriceMax dari harga semua varian
+func (p *Product) RefreshPriceRange() {
+	p.PriceMin, p.PriceMax = p.Price.Amount, p.Price.Amount
+	for i, variant := range p.Variants {
+		price := variant.EffectivePrice(p.Price.Amount)
+		if i == 0 || price < p.PriceMin {
+			p.PriceMin = price
+		}
+		if i == 0 || price > p.PriceMax {
+			p.PriceMax = price
+		}
+	}
+}
+
+// priceOf mengubah jumlah minor unit menjadi Money dalam mata uang produk
+func (p Product) priceOf(amount int64) money.Money {
+	return money.Money{Amount: amount, Currency: p.Price.Currency}
+}
+
+// StockOf mengembalikan stok varian, atau stok produk untuk uuid.Nil.
+// Hasilnya nil jika stok item tersebut belum pernah dicatat.
+func (p Product) StockOf(variantID uuid.UUID) *StockLevel {
+	for i := range p.Stock {
+		if p.Stock[i].VariantId == variantID {
+			return &p.Stock[i]
+		}
+	}
+	return nil
+}
+
+// PrimaryImage mengembalikan path gambar utama, kosong jika produk tidak punya gambar
+func (p Product) PrimaryImage() string {
+	for _, image := range p.Images {
+		if image.Primary {
+			return image.Path
+		}
+	}
+	return ""
 }
 
 type ProductResponse struct {
-	Id        string      `json:"id"`
-	Name      string      `json:"name"`
-	Price     int64       `json:"price"`
-	Image     string      `json:"image"`
-	User      UserMinimal `json:"user"`
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	Id         string  `json:"id"`
+	ExternalId *string `json:"external_id,omitempty"`
+	Name       string  `json:"name"`
+	// Status beserta jadwalnya, jadwal yang kosong tidak dikirim
+	Status      string      `json:"status"`
+	PublishAt   *time.Time  `json:"publish_at,omitempty"`
+	UnpublishAt *time.Time  `json:"unpublish_at,omitempty"`
+	Price       money.Money `json:"price"`
+	// PriceMin dan PriceMax adalah harga yang ditampilkan, rentang harga semua varian
+	PriceMin money.Money              `json:"price_min"`
+	PriceMax money.Money              `json:"price_max"`
+	Options  []ProductOptionResponse  `json:"options"`
+	Variants []ProductVariantResponse `json:"variants"`
+	// Stock nil berarti stok produk belum pernah dicatat
+	Stock *StockResponse `json:"stock"`
+	// Image adalah path gambar utama, sama dengan Images yang Primary
+	Image      string                 `json:"image"`
+	Images     []ProductImageResponse `json:"images"`
+	User       UserMinimal            `json:"user"`
+	Categories []CategoryMinimal      `json:"categories"`
+	Tags       []string               `json:"tags"`
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	// Version sama dengan isi header ETag, dikirim kembali di If-Match saat mengubah produk
+	Version   int64      `json:"version"`
+	DeletedAt *time.Time `json:",omitempty"`
+	// Match hanya diisi pada hasil pencarian
+	Match *SearchMatch `json:"match,omitempty"`
+	// ConvertedPrice hanya diisi jika pembeli meminta mata uang tampilan
+	ConvertedPrice *PriceConversion `json:"converted_price,omitempty"`
+}
+
+// SearchMatch adalah skor relevansi dan potongan teks dengan kata yang cocok
+// dibungkus <mark>, teks lainnya sudah di-escape sebagai HTML
+type SearchMatch struct {
+	Score     float64 `json:"score"`
+	Highlight string  `json:"highlight"`
+}
+
+type ProductImageResponse struct {
+	Id       string `json:"id"`
+	Path     string `json:"path"`
+	Alt      string `json:"alt"`
+	Position int    `json:"position"`
+	Primary  bool   `json:"primary"`
+}
+
+type ProductOptionResponse struct {
+	Id     string   `json:"id"`
+	Name   string   `json:"name"`
+	Values []string `json:"values"`
+}
+
+// ProductVariantResponse berisi harga efektif varian, PriceOverride nil
+// berarti harga mengikuti harga produk
+type ProductVariantResponse struct {
+	Id            string            `json:"id"`
+	SKU           string            `json:"sku"`
+	Price         money.Money       `json:"price"`
+	PriceOverride *money.Money      `json:"price_override"`
+	ImageId       *string           `json:"image_id"`
+	Image         string            `json:"image"`
+	Attributes    map[string]string `json:"attributes"`
+	// Stock nil berarti stok varian belum pernah dicatat
+	Stock *StockResponse `json:"stock"`
 }
 
 type UserMinimal struct {
@@ -35,5 +229,161 @@ type UserMinimal struct {
 
 func (u *Product) BeforeCreate(tx *gorm.DB) (err error) {
 	u.Id = uuid.New()
+	u.Version = 1
+	if u.Status == "" {
+		u.Status = ProductPublished
+	}
 	return
 }
+
+// VisibleTo menentukan apakah user boleh melihat produk, produk yang belum atau
+// tidak lagi dipublikasikan hanya terlihat oleh pemiliknya
+func (p Product) VisibleTo(userID uuid.UUID) bool {
+	return p.Status == ProductPublished || p.UserId == userID
+}
+
+// NewProductResponse mengubah Product menjadi format response API
+func NewProductResponse(product Product) ProductResponse {
+	response := ProductResponse{
+		Id:          product.Id.String(),
+		ExternalId:  product.ExternalId,
+		Name:        product.Name,
+		Status:      product.Status,
+		PublishAt:   product.PublishAt,
+		UnpublishAt: product.UnpublishAt,
+		Price:       product.Price,
+		PriceMin:    product.priceOf(product.PriceMin),
+		PriceMax:    product.priceOf(product.PriceMax),
+		Image:       product.PrimaryImage(),
+		User: UserMinimal{
+			Id:       product.UserId.String(),
+			Username: product.User.Username,
+		},
+		Images:     make([]ProductImageResponse, 0, len(product.Images)),
+		Options:    make([]ProductOptionResponse, 0, len(product.Options)),
+		Variants:   make([]ProductVariantResponse, 0, len(product.Variants)),
+		Categories: make([]CategoryMinimal, 0, len(product.Categories)),
+		Tags:       make([]string, 0, len(product.Tags)),
+		CreatedAt:  product.CreatedAt,
+		UpdatedAt:  product.UpdatedAt,
+		Version:    product.Version,
+	}
+	for _, image := range product.Images {
+		response.Images = append(response.Images, ProductImageResponse{
+			Id:       image.Id.String(),
+			Path:     image.Path,
+			Alt:      image.Alt,
+			Position: image.Position,
+			Primary:  image.Primary,
+		})
+	}
+	for _, option := range product.Options {
+		response.Options = append(response.Options, ProductOptionResponse{
+			Id:     option.Id.String(),
+			Name:   option.Name,
+			Values: option.Values,
+		})
+	}
+	images := make(map[uuid.UUID]string, len(product.Images))
+	for _, image := range product.Images {
+		images[image.Id] = image.Path
+	}
+	for _, variant := range product.Variants {
+		item := ProductVariantResponse{
+			Id:         variant.Id.String(),
+			SKU:        variant.SKU,
+			Price:      product.priceOf(variant.EffectivePrice(product.Price.Amount)),
+			Attributes: variant.Attributes,
+		}
+		if variant.Price != nil {
+			price := product.priceOf(*variant.Price)
+			item.PriceOverride = &price
+		}
+		if variant.ImageId != nil {
+			id := variant.ImageId.String()
+			item.ImageId = &id
+			item.Image = images[*variant.ImageId]
+		}
+		if level := product.StockOf(variant.Id); level != nil {
+			item.Stock = newStockResponse(*level)
+			response.Stock = addStock(response.Stock, *item.Stock)
+		}
+		response.Variants = append(response.Variants, item)
+	}
+	if level := product.StockOf(uuid.Nil); level != nil && len(product.Variants) == 0 {
+		response.Stock = newStockResponse(*level)
+	}
+	for _, category := range product.Categories {
+		response.Categories = append(response.Categories, CategoryMinimal{
+			Id:   category.Id.String(),
+			Name: category.Name,
+			Slug: category.Slug,
+		})
+	}
+	for _, tag := range product.Tags {
+		response.Tags = append(response.Tags, tag.Name)
+	}
+	if product.DeletedAt.Valid {
+		response.DeletedAt = &product.DeletedAt.Time
+	}
+	return response
+}
+
+func newStockResponse(level StockLevel) *StockResponse {
+	return &StockResponse{
+		OnHand:    level.OnHand,
+		Reserved:  level.Reserved,
+		Available: level.Available(),
+		LowStock:  level.Low(),
+	}
+}
+
+// addStock menjumlahkan stok varian menjadi stok produk
+func addStock(total *StockResponse, stock StockResponse) *StockResponse {
+	if total == nil {
+		return &stock
+	}
+	total.OnHand += stock.OnHand
+	total.Reserved += stock.Reserved
+	total.Available += stock.Available
+	total.LowStock = total.LowStock || stock.LowStock
+	return total
+}
+
+// Status produk. Perubahan yang diizinkan ada di ProductTransitions.
+const (
+	ProductDraft     = "draft"
+	ProductPublished = "published"
+	ProductArchived  = "archived"
+)
+
+// ProductTransitions berisi status tujuan yang boleh dipilih dari setiap status.
+// Produk yang diarsipkan harus dijadikan draft lagi sebelum dipublikasikan ulang.
+var ProductTransitions = map[string][]string{
+	ProductDraft:     {ProductPublished, ProductArchived},
+	ProductPublished: {ProductDraft, ProductArchived},
+	ProductArchived:  {ProductDraft},
+}
+
+// Batas nilai produk yang diterima API
+const (
+	// Harga dalam minor unit. Batas atas masih bisa dibaca tepat sebagai number JavaScript.
+	MinProductPrice = 1
+	MaxProductPrice = 999_999_999_999_999
+	DefaultCurrency = "IDR"
+
+	MaxProductImageSize = 5 << 20 // 5 MB
+	MaxProductImages    = 10
+	MaxProductOptions   = 3
+	MaxOptionValues     = 20
+	MaxProductVariants  = 100
+)
+
+// AllowedImageTypes adalah tipe gambar yang boleh diunggah, per ekstensi file
+var AllowedImageTypes = map[string]string{
+	".jpg":  "image/jpeg",
+	".jpeg": "image/jpeg",
+	".png":  "image/png",
+	".gif":  "image/gif",
+	".webp": "image/webp",
+}
@@ -0,0 +1,133 @@
+package models
+
+import (
+	"server-cookie/money"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Aksi yang menghasilkan ProductRevision
+const (
+	RevisionCreate   = "create"
+	RevisionUpdate   = "update"
+	RevisionDelete   = "delete"
+	RevisionRestore  = "restore"
+	RevisionRollback = "rollback"
+	// RevisionStatus adalah perubahan status atau jadwal publikasi, termasuk oleh worker
+	RevisionStatus = "status"
+)
+
+// ProductRevision adalah catatan isi produk setelah satu perubahan. Revisi tidak
+// pernah diubah, hanya ikut terhapus saat produknya di-purge. Number dimulai dari 1
+// dan naik untuk setiap revisi produk yang sama.
+type ProductRevision struct {
+	Id        uuid.UUID `gorm:"type:char(36);primaryKey"`
+	ProductId uuid.UUID `gorm:"type:char(36);uniqueIndex:idx_product_revision_number"`
+	Number    int       `gorm:"uniqueIndex:idx_product_revision_number"`
+	Action    string    `gorm:"type:varchar(20)"`
+	// ActorId dan ActorUsername diambil dari klaim JWT, kosong untuk aksi sistem
+	ActorId       uuid.UUID `gorm:"type:char(36)"`
+	ActorUsername string    `gorm:"type:varchar(100)"`
+	// RollbackOf adalah nomor revisi yang dipulihkan oleh aksi rollback
+	RollbackOf *int
+	Snapshot   ProductSnapshot `gorm:"type:text;serializer:json"`
+	CreatedAt  time.Time
+}
+
+func (r *ProductRevision) BeforeCreate(tx *gorm.DB) (err error) {
+	if r.Id == uuid.Nil {
+		r.Id = uuid.New()
+	}
+	return
+}
+
+// ProductSnapshot adalah isi produk yang dicatat di revisi. Stok tidak termasuk
+// karena perubahannya sudah dicatat di ledger inventory.
+type ProductSnapshot struct {
+	Name string `json:"name"`
+	// Status dan jadwal publikasi tidak dipulihkan oleh rollback
+	Status      string                  `json:"status,omitempty"`
+	PublishAt   *time.Time              `json:"publish_at,omitempty"`
+	UnpublishAt *time.Time              `json:"unpublish_at,omitempty"`
+	Price       money.Money             `json:"price"`
+	Categories  []CategoryMinimal       `json:"categories"`
+	Tags        []string                `json:"tags"`
+	Images      []ProductImageResponse  `json:"images"`
+	Options     []ProductOptionResponse `json:"options"`
+	Variants    []VariantSnapshot       `json:"variants"`
+}
+
+// VariantSnapshot adalah varian di ProductSnapshot, Price nil berarti mengikuti harga produk
+type VariantSnapshot struct {
+	Id         string            `json:"id"`
+	SKU        string            `json:"sku"`
+	Price      *money.Money      `json:"price"`
+	ImageId    *string           `json:"image_id"`
+	Attributes map[string]string `json:"attributes"`
+}
+
+// NewProductSnapshot mengambil isi produk untuk dicatat di revisi
+func NewProductSnapshot(product Product) ProductSnapshot {
+	response := NewProductResponse(product)
+	snapshot := ProductSnapshot{
+		Name:        response.Name,
+		Status:      response.Status,
+		PublishAt:   response.PublishAt,
+		UnpublishAt: response.UnpublishAt,
+		Price:       response.Price,
+		Categories:  response.Categories,
+		Tags:        response.Tags,
+		Images:      response.Images,
+		Options:     response.Options,
+		Variants:    make([]VariantSnapshot, 0, len(response.Variants)),
+	}
+	for _, variant := range response.Variants {
+		snapshot.Variants = append(snapshot.Variants, VariantSnapshot{
+			Id:         variant.Id,
+			SKU:        variant.SKU,
+			Price:      variant.PriceOverride,
+			ImageId:    variant.ImageId,
+			Attributes: variant.Attributes,
+		})
+	}
+	return snapshot
+}
+
+// ProductRevisionResponse adalah revisi di API, Snapshot hanya diisi pada detail revisi
+type ProductRevisionResponse struct {
+	Number     int              `json:"number"`
+	Action     string           `json:"action"`
+	Actor      *UserMinimal     `json:"actor"`
+	RollbackOf *int             `json:"rollback_of,omitempty"`
+	CreatedAt  time.Time        `json:"created_at"`
+	Snapshot   *ProductSnapshot `json:"snapshot,omitempty"`
+}
+
+// NewProductRevisionResponse mengubah ProductRevision menjadi format response API
+func NewProductRevisionResponse(revision ProductRevision, withSnapshot bool) ProductRevisionResponse {
+	response := ProductRevisionResponse{
+		Number:     revision.Number,
+		Action:     revision.Action,
+		RollbackOf: revision.RollbackOf,
+		CreatedAt:  revision.CreatedAt,
+	}
+	if revision.ActorId != uuid.Nil {
+		response.Actor = &UserMinimal{Id: revision.ActorId.String(), Username: revision.ActorUsername}
+	}
+	if withSnapshot {
+		snapshot := revision.Snapshot
+		response.Snapshot = &snapshot
+	}
+	return response
+}
+
+// FieldChange adalah satu field yang berbeda di antara dua revisi. Field berupa path
+// seperti name, images[<id>].alt atau variants[<id>].price. From nil berarti field
+// baru ada, To nil berarti field sudah tidak ada.
+type FieldChange struct {
+	Field string `json:"field"`
+	From  any    `json:"from"`
+	To    any    `json:"to"`
+}
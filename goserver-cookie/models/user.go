@@ -8,14 +8,23 @@ import (
 )
 
 type User struct {
-	Id        uuid.UUID `gorm:"type:char(36);primaryKey" json:"id"`
-	Username  string    `gorm:"type:varchar(100)" json:"username"`
-	Email     string    `gorm:"type:varchar(100)" json:"email" validate:"required,email"`
-	Password  string    `gorm:"type:varchar(255)" json:"password" validate:"required,min=6"`
+	Id       uuid.UUID `gorm:"type:char(36);primaryKey" json:"id"`
+	Username string    `gorm:"type:varchar(100);uniqueIndex" json:"username"`
+	Email    string    `gorm:"type:varchar(100)" json:"email" validate:"required,email"`
+	Password string    `gorm:"type:varchar(255)" json:"password" validate:"required,min=6"`
+	Role     string    `gorm:"type:varchar(20);default:user" json:"role"`
+	// Currency adalah mata uang tampilan harga pilihan user, kosong berarti harga asli
+	Currency  string `gorm:"type:char(3)" json:"currency"`
 	CreatedAt time.Time
 	UpdatedAt time.Time
 }
 
+// Role user, admin boleh mengelola kategori
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
 // Fungsi BeforeCreate untuk menghasilkan UUID sebelum penyimpanan ke database
 func (u *User) BeforeCreate(tx *gorm.DB) (err error) {
 	u.Id = uuid.New()
@@ -0,0 +1,38 @@
+package money
+
+import (
+	"maps"
+	"slices"
+	"strings"
+)
+
+// Currency adalah mata uang ISO 4217 beserta jumlah digit minor unit-nya,
+// misalnya IDR dan USD punya 2 digit, JPY 0 digit dan KWD 3 digit
+type Currency struct {
+	Code     string
+	Exponent int
+}
+
+// currencies adalah mata uang yang diterima, dengan exponent sesuai ISO 4217
+var currencies = map[string]int{
+	"AED": 2, "AUD": 2, "BHD": 3, "BND": 2, "BRL": 2, "CAD": 2, "CHF": 2, "CNY": 2,
+	"CZK": 2, "DKK": 2, "EUR": 2, "GBP": 2, "HKD": 2, "IDR": 2, "INR": 2, "JOD": 3,
+	"JPY": 0, "KRW": 0, "KWD": 3, "MXN": 2, "MYR": 2, "NOK": 2, "NZD": 2, "OMR": 3,
+	"PHP": 2, "PLN": 2, "SAR": 2, "SEK": 2, "SGD": 2, "THB": 2, "TND": 3, "TRY": 2,
+	"TWD": 2, "USD": 2, "VND": 0, "ZAR": 2,
+}
+
+// LookupCurrency mencari mata uang berdasarkan kodenya tanpa membedakan huruf besar/kecil
+func LookupCurrency(code string) (Currency, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	exponent, ok := currencies[code]
+	if !ok {
+		return Currency{}, ErrUnknownCurrency
+	}
+	return Currency{Code: code, Exponent: exponent}, nil
+}
+
+// Currencies mengembalikan kode semua mata uang yang diterima, urut abjad
+func Currencies() []string {
+	return slices.Sorted(maps.Keys(currencies))
+}
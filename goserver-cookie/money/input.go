@@ -0,0 +1,71 @@
+package money
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+)
+
+// Input adalah harga dari request yang belum dibaca sesuai mata uangnya.
+// Di JSON bisa dikirim sebagai string desimal ("12.50"), angka (12.50), atau
+// object {"amount": "12.50", "currency": "USD"} maupun {"minor_units": 1250, "currency": "USD"}.
+type Input struct {
+	// Amount adalah jumlah desimal, dipakai jika MinorUnits nil
+	Amount     string
+	MinorUnits *int64
+	// Currency kosong berarti mengikuti mata uang yang diberikan ke Resolve
+	Currency string
+}
+
+// UnmarshalJSON membaca salah satu bentuk Input. Angka dibaca dari teks aslinya
+// sehingga 12.50 tidak pernah melewati float. Nilai yang bukan harga tidak
+// ditolak di sini, tetapi oleh Resolve agar bisa dilaporkan sebagai error field.
+func (in *Input) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 || data[0] != '{' {
+		*in = Input{Amount: decimalOf(data)}
+		return nil
+	}
+
+	var object struct {
+		Amount     json.RawMessage `json:"amount"`
+		MinorUnits *int64          `json:"minor_units"`
+		Currency   string          `json:"currency"`
+	}
+	if err := json.Unmarshal(data, &object); err != nil {
+		return err
+	}
+	*in = Input{Currency: object.Currency}
+	// Hanya salah satu dari amount dan minor_units yang boleh diisi
+	if object.Amount == nil {
+		in.MinorUnits = object.MinorUnits
+	} else if object.MinorUnits == nil {
+		in.Amount = decimalOf(object.Amount)
+	}
+	return nil
+}
+
+// Resolve mengubah Input menjadi Money. Mata uang Input dipakai jika diisi,
+// selain itu currency.
+func (in Input) Resolve(currency string) (Money, error) {
+	if strings.TrimSpace(in.Currency) != "" {
+		currency = in.Currency
+	}
+	if in.MinorUnits == nil {
+		return Parse(in.Amount, currency)
+	}
+	c, err := LookupCurrency(currency)
+	if err != nil {
+		return Money{}, err
+	}
+	return Money{Amount: *in.MinorUnits, Currency: c.Code}, nil
+}
+
+// decimalOf mengambil teks angka dari string JSON maupun number JSON
+func decimalOf(data json.RawMessage) string {
+	var amount string
+	if err := json.Unmarshal(data, &amount); err == nil {
+		return amount
+	}
+	return string(data)
+}
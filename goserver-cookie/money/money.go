@@ -0,0 +1,306 @@
+// Package money menyimpan jumlah uang secara tepat sebagai bilangan bulat
+// dalam satuan terkecil mata uangnya, tanpa float.
+package money
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"strings"
+)
+
+// Error yang dikembalikan saat membaca atau menghitung Money
+var (
+	ErrInvalidAmount    = errors.New("money: invalid decimal amount")
+	ErrTooPrecise       = errors.New("money: more decimal places than the currency allows")
+	ErrOverflow         = errors.New("money: amount out of range")
+	ErrUnknownCurrency  = errors.New("money: unknown currency")
+	ErrCurrencyMismatch = errors.New("money: currency mismatch")
+	ErrInvalidRate      = errors.New("money: invalid exchange rate")
+)
+
+// MaxRateDecimals adalah jumlah digit desimal maksimal pada kurs
+const MaxRateDecimals = 12
+
+// Money adalah jumlah uang dalam minor unit mata uangnya, misalnya IDR 5000.00
+// adalah Money{Amount: 500000, Currency: "IDR"}
+type Money struct {
+	Amount   int64  `gorm:"type:bigint"`
+	Currency string `gorm:"type:char(3)"`
+}
+
+// RoundingMode menentukan cara membulatkan hasil yang jatuh di antara dua minor unit
+type RoundingMode int
+
+const (
+	// RoundHalfEven membulatkan .5 ke angka genap terdekat (banker's rounding)
+	RoundHalfEven RoundingMode = iota
+	// RoundHalfUp membulatkan .5 menjauhi nol
+	RoundHalfUp
+	// RoundDown membuang sisa pecahan ke arah nol
+	RoundDown
+)
+
+// String mengembalikan nama mode pembulatan, misalnya "half_even"
+func (r RoundingMode) String() string {
+	switch r {
+	case RoundHalfEven:
+		return "half_even"
+	case RoundHalfUp:
+		return "half_up"
+	case RoundDown:
+		return "down"
+	}
+	return "unknown"
+}
+
+// New membuat Money dari jumlah minor unit
+func New(amount int64, currency string) Money {
+	return Money{Amount: amount, Currency: strings.ToUpper(strings.TrimSpace(currency))}
+}
+
+// Parse membaca string desimal seperti "12.50" atau "-3" dalam currency. Digit
+// desimal yang melebihi exponent mata uang ditolak kecuali semuanya nol,
+// Parse tidak pernah membulatkan.
+func Parse(amount, currency string) (Money, error) {
+	c, err := LookupCurrency(currency)
+	if err != nil {
+		return Money{}, err
+	}
+	minor, err := parseMinor(strings.TrimSpace(amount), c.Exponent)
+	if err != nil {
+		return Money{}, err
+	}
+	return Money{Amount: minor, Currency: c.Code}, nil
+}
+
+// IsDecimal memeriksa format string desimal tanpa memperhatikan mata uangnya
+func IsDecimal(amount string) bool {
+	_, _, ok := splitDecimal(strings.TrimSpace(amount))
+	return ok
+}
+
+// Decimal menulis jumlah dalam satuan utama, misalnya "5000.00" atau "-0.05"
+func (m Money) Decimal() string {
+	exponent := m.exponent()
+	digits := new(big.Int).Abs(big.NewInt(m.Amount)).String()
+	if len(digits) <= exponent {
+		digits = strings.Repeat("0", exponent-len(digits)+1) + digits
+	}
+	if exponent > 0 {
+		digits = digits[:len(digits)-exponent] + "." + digits[len(digits)-exponent:]
+	}
+	if m.Amount < 0 {
+		return "-" + digits
+	}
+	return digits
+}
+
+// String menulis jumlah beserta kode mata uangnya, misalnya "5000.00 IDR"
+func (m Money) String() string {
+	return m.Decimal() + " " + m.Currency
+}
+
+// Add menjumlahkan dua Money dengan mata uang yang sama
+func (m Money) Add(other Money) (Money, error) {
+	if m.Currency != other.Currency {
+		return Money{}, ErrCurrencyMismatch
+	}
+	sum := new(big.Int).Add(big.NewInt(m.Amount), big.NewInt(other.Amount))
+	return fromBig(sum, m.Currency)
+}
+
+// Sub mengurangi m dengan other yang mata uangnya sama
+func (m Money) Sub(other Money) (Money, error) {
+	if m.Currency != other.Currency {
+		return Money{}, ErrCurrencyMismatch
+	}
+	difference := new(big.Int).Sub(big.NewInt(m.Amount), big.NewInt(other.Amount))
+	return fromBig(difference, m.Currency)
+}
+
+// Mul mengalikan jumlah dengan bilangan bulat, misalnya harga dengan kuantitas
+func (m Money) Mul(n int64) (Money, error) {
+	product := new(big.Int).Mul(big.NewInt(m.Amount), big.NewInt(n))
+	return fromBig(product, m.Currency)
+}
+
+// MulRat mengalikan jumlah dengan pecahan, misalnya diskon 15/100,
+// lalu membulatkannya ke minor unit dengan mode yang diberikan
+func (m Money) MulRat(r *big.Rat, mode RoundingMode) (Money, error) {
+	numerator := new(big.Int).Mul(big.NewInt(m.Amount), r.Num())
+	return fromBig(Round(numerator, r.Denom(), mode), m.Currency)
+}
+
+// Convert mengubah m ke mata uang to dengan kurs rate (1 unit utama m = rate unit utama to).
+// Perbedaan jumlah digit desimal kedua mata uang ikut dihitung, hasilnya dibulatkan dengan mode.
+func (m Money) Convert(to string, rate *big.Rat, mode RoundingMode) (Money, error) {
+	from, err := LookupCurrency(m.Currency)
+	if err != nil {
+		return Money{}, err
+	}
+	target, err := LookupCurrency(to)
+	if err != nil {
+		return Money{}, err
+	}
+	factor := new(big.Rat).Mul(rate, new(big.Rat).SetFrac(pow10(target.Exponent), pow10(from.Exponent)))
+	converted, err := m.MulRat(factor, mode)
+	if err != nil {
+		return Money{}, err
+	}
+	converted.Currency = target.Code
+	return converted, nil
+}
+
+// ParseRate membaca kurs desimal positif seperti "15500.25" tanpa kehilangan presisi
+func ParseRate(rate string) (*big.Rat, error) {
+	rate = strings.TrimSpace(rate)
+	whole, fraction, ok := splitDecimal(rate)
+	if !ok || strings.HasPrefix(rate, "-") || len(whole)+len(fraction) > 30 || len(fraction) > MaxRateDecimals {
+		return nil, ErrInvalidRate
+	}
+	r, ok := new(big.Rat).SetString(rate)
+	if !ok || r.Sign() <= 0 {
+		return nil, ErrInvalidRate
+	}
+	return r, nil
+}
+
+// Cmp membandingkan dua Money dengan mata uang yang sama, hasilnya -1, 0 atau 1
+func (m Money) Cmp(other Money) (int, error) {
+	if m.Currency != other.Currency {
+		return 0, ErrCurrencyMismatch
+	}
+	return big.NewInt(m.Amount).Cmp(big.NewInt(other.Amount)), nil
+}
+
+// IsZero bernilai true jika jumlahnya nol
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
+
+// MarshalJSON menulis Money dalam bentuk desimal dan minor unit sekaligus,
+// misalnya {"amount": "5000.00", "currency": "IDR", "minor_units": 500000}
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(moneyJSON{Amount: m.Decimal(), Currency: m.Currency, MinorUnits: m.Amount})
+}
+
+// UnmarshalJSON menerima bentuk yang sama dengan Input, mata uang wajib diisi.
+// Hasil MarshalJSON juga diterima selama amount dan minor_units-nya sama.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	var full struct {
+		Amount     *string `json:"amount"`
+		Currency   string  `json:"currency"`
+		MinorUnits *int64  `json:"minor_units"`
+	}
+	if json.Unmarshal(data, &full) == nil && full.Amount != nil && full.MinorUnits != nil {
+		c, err := LookupCurrency(full.Currency)
+		if err != nil {
+			return err
+		}
+		parsed := Money{Amount: *full.MinorUnits, Currency: c.Code}
+		if parsed.Decimal() != *full.Amount {
+			return ErrInvalidAmount
+		}
+		*m = parsed
+		return nil
+	}
+
+	var input Input
+	if err := input.UnmarshalJSON(data); err != nil {
+		return err
+	}
+	parsed, err := input.Resolve("")
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+type moneyJSON struct {
+	Amount     string `json:"amount"`
+	Currency   string `json:"currency"`
+	MinorUnits int64  `json:"minor_units"`
+}
+
+// Round membagi numerator dengan denominator dan membulatkan hasilnya ke bilangan bulat
+func Round(numerator, denominator *big.Int, mode RoundingMode) *big.Int {
+	quotient, remainder := new(big.Int).QuoRem(numerator, denominator, new(big.Int))
+	if remainder.Sign() == 0 || mode == RoundDown {
+		return quotient
+	}
+
+	// Bandingkan sisa dengan setengah pembagi
+	twice := new(big.Int).Lsh(new(big.Int).Abs(remainder), 1)
+	half := twice.Cmp(new(big.Int).Abs(denominator))
+	if half > 0 || (half == 0 && (mode == RoundHalfUp || quotient.Bit(0) == 1)) {
+		if numerator.Sign()*denominator.Sign() < 0 {
+			return quotient.Sub(quotient, big.NewInt(1))
+		}
+		return quotient.Add(quotient, big.NewInt(1))
+	}
+	return quotient
+}
+
+func (m Money) exponent() int {
+	c, err := LookupCurrency(m.Currency)
+	if err != nil {
+		return 0
+	}
+	return c.Exponent
+}
+
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+func fromBig(amount *big.Int, currency string) (Money, error) {
+	if !amount.IsInt64() {
+		return Money{}, ErrOverflow
+	}
+	return Money{Amount: amount.Int64(), Currency: currency}, nil
+}
+
+// parseMinor mengubah string desimal menjadi minor unit dengan exponent digit desimal
+func parseMinor(amount string, exponent int) (int64, error) {
+	whole, fraction, ok := splitDecimal(amount)
+	if !ok {
+		return 0, ErrInvalidAmount
+	}
+	if len(fraction) > exponent {
+		if strings.Trim(fraction[exponent:], "0") != "" {
+			return 0, ErrTooPrecise
+		}
+		fraction = fraction[:exponent]
+	}
+	fraction += strings.Repeat("0", exponent-len(fraction))
+
+	value, _ := new(big.Int).SetString(whole+fraction, 10)
+	if strings.HasPrefix(amount, "-") {
+		value.Neg(value)
+	}
+	if !value.IsInt64() {
+		return 0, ErrOverflow
+	}
+	return value.Int64(), nil
+}
+
+// splitDecimal memisahkan "-12.50" menjadi "12" dan "50". Tanda minus boleh ada,
+// titik desimal harus diapit digit dan tidak boleh ada eksponen.
+func splitDecimal(amount string) (string, string, bool) {
+	whole, fraction, hasPoint := strings.Cut(strings.TrimPrefix(amount, "-"), ".")
+	if whole == "" || (hasPoint && fraction == "") || !isDigits(whole) || !isDigits(fraction) {
+		return "", "", false
+	}
+	return whole, fraction, true
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,167 @@
+package money_test
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"math/big"
+	"server-cookie/money"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		amount, currency string
+		want             int64
+		err              error
+	}{
+		{"5000", "IDR", 500000, nil},
+		{"12.5", "usd", 1250, nil},
+		{"12.500", "USD", 1250, nil},
+		{"-0.05", "USD", -5, nil},
+		{"1500", "JPY", 1500, nil},
+		{"1.234", "KWD", 1234, nil},
+		{"12.345", "USD", 0, money.ErrTooPrecise},
+		{"1.5", "JPY", 0, money.ErrTooPrecise},
+		{"1e3", "USD", 0, money.ErrInvalidAmount},
+		{"12.", "USD", 0, money.ErrInvalidAmount},
+		{".5", "USD", 0, money.ErrInvalidAmount},
+		{"", "USD", 0, money.ErrInvalidAmount},
+		{"92233720368547758.08", "USD", 0, money.ErrOverflow},
+		{"10", "XXX", 0, money.ErrUnknownCurrency},
+	}
+	for _, tt := range tests {
+		got, err := money.Parse(tt.amount, tt.currency)
+		if !errors.Is(err, tt.err) {
+			t.Errorf("Parse(%q, %q) error = %v, want %v", tt.amount, tt.currency, err, tt.err)
+			continue
+		}
+		if err == nil && got.Amount != tt.want {
+			t.Errorf("Parse(%q, %q) = %d, want %d", tt.amount, tt.currency, got.Amount, tt.want)
+		}
+	}
+}
+
+func TestDecimal(t *testing.T) {
+	tests := map[string]money.Money{
+		"5000.00": money.New(500000, "IDR"),
+		"0.05":    money.New(5, "USD"),
+		"-1.50":   money.New(-150, "USD"),
+		"1500":    money.New(1500, "JPY"),
+		"0.001":   money.New(1, "KWD"),
+	}
+	for want, m := range tests {
+		if got := m.Decimal(); got != want {
+			t.Errorf("%d %s Decimal() = %q, want %q", m.Amount, m.Currency, got, want)
+		}
+	}
+}
+
+func TestArithmetic(t *testing.T) {
+	price := money.New(1999, "USD")
+	total, err := price.Mul(3)
+	if err != nil || total.Amount != 5997 {
+		t.Fatalf("Mul = %v, %v", total, err)
+	}
+	if _, err := price.Add(money.New(1, "IDR")); !errors.Is(err, money.ErrCurrencyMismatch) {
+		t.Fatalf("Add across currencies = %v, want ErrCurrencyMismatch", err)
+	}
+	if _, err := money.New(math.MaxInt64, "USD").Add(money.New(1, "USD")); !errors.Is(err, money.ErrOverflow) {
+		t.Fatalf("Add overflow = %v, want ErrOverflow", err)
+	}
+	if _, err := money.New(math.MinInt64, "USD").Sub(money.New(1, "USD")); !errors.Is(err, money.ErrOverflow) {
+		t.Fatalf("Sub overflow = %v, want ErrOverflow", err)
+	}
+
+	half := big.NewRat(1, 2)
+	rounding := []struct {
+		amount int64
+		mode   money.RoundingMode
+		want   int64
+	}{
+		{5, money.RoundHalfEven, 2},
+		{7, money.RoundHalfEven, 4},
+		{5, money.RoundHalfUp, 3},
+		{-5, money.RoundHalfUp, -3},
+		{-5, money.RoundHalfEven, -2},
+		{7, money.RoundDown, 3},
+	}
+	for _, tt := range rounding {
+		got, err := money.New(tt.amount, "USD").MulRat(half, tt.mode)
+		if err != nil || got.Amount != tt.want {
+			t.Errorf("%d * 1/2 with mode %d = %d, %v; want %d", tt.amount, tt.mode, got.Amount, err, tt.want)
+		}
+	}
+}
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		from money.Money
+		to   string
+		rate string
+		want int64
+	}{
+		{money.New(1250, "USD"), "IDR", "15500.25", 19375312},
+		{money.New(1500, "JPY"), "USD", "0.0067", 1005},
+		{money.New(100, "USD"), "KWD", "0.3075", 308},
+		{money.New(1000000, "IDR"), "JPY", "0.0095", 95},
+	}
+	for _, tt := range tests {
+		rate, err := money.ParseRate(tt.rate)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := tt.from.Convert(tt.to, rate, money.RoundHalfEven)
+		if err != nil || got != money.New(tt.want, tt.to) {
+			t.Errorf("%v to %s at %s = %v, %v; want %d", tt.from, tt.to, tt.rate, got, err, tt.want)
+		}
+	}
+
+	for _, rate := range []string{"0", "-1.5", "abc", "1e3", "0.0000000000001"} {
+		if _, err := money.ParseRate(rate); !errors.Is(err, money.ErrInvalidRate) {
+			t.Errorf("ParseRate(%q) error = %v, want ErrInvalidRate", rate, err)
+		}
+	}
+}
+
+func TestInputJSON(t *testing.T) {
+	tests := map[string]money.Money{
+		`"12.50"`:                              money.New(1250, "IDR"),
+		`12.50`:                                money.New(1250, "IDR"),
+		`{"amount": "3.5", "currency": "usd"}`: money.New(350, "USD"),
+		`{"minor_units": 1250, "currency": "JPY"}`: money.New(1250, "JPY"),
+	}
+	for data, want := range tests {
+		var input money.Input
+		if err := json.Unmarshal([]byte(data), &input); err != nil {
+			t.Errorf("Unmarshal(%s) error = %v", data, err)
+			continue
+		}
+		got, err := input.Resolve("IDR")
+		if err != nil || got != want {
+			t.Errorf("Resolve(%s) = %v, %v; want %v", data, got, err, want)
+		}
+	}
+
+	for _, data := range []string{`{"amount": "1", "minor_units": 100}`, `true`, `"1e3"`} {
+		var input money.Input
+		if err := json.Unmarshal([]byte(data), &input); err != nil {
+			t.Fatalf("Unmarshal(%s) error = %v", data, err)
+		}
+		if _, err := input.Resolve("IDR"); !errors.Is(err, money.ErrInvalidAmount) {
+			t.Errorf("Resolve(%s) error = %v, want ErrInvalidAmount", data, err)
+		}
+	}
+
+	encoded, _ := json.Marshal(money.New(500000, "IDR"))
+	if string(encoded) != `{"amount":"5000.00","currency":"IDR","minor_units":500000}` {
+		t.Fatalf("unexpected JSON: %s", encoded)
+	}
+	var decoded money.Money
+	if err := json.Unmarshal(encoded, &decoded); err != nil || decoded != money.New(500000, "IDR") {
+		t.Fatalf("Unmarshal(%s) = %v, %v", encoded, decoded, err)
+	}
+	if err := json.Unmarshal([]byte(`{"amount":"1.00","currency":"IDR","minor_units":500}`), &decoded); !errors.Is(err, money.ErrInvalidAmount) {
+		t.Fatalf("expected mismatched amounts to be rejected, got %v", err)
+	}
+}
@@ -0,0 +1,280 @@
+package openapi
+
+import (
+	"net/http"
+	"reflect"
+	"regexp"
+	"server-cookie/apperrors"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Version adalah versi spesifikasi OpenAPI yang dihasilkan
+const Version = "3.1.0"
+
+// CookieAuth adalah nama security scheme untuk token di cookie
+const CookieAuth = "cookieAuth"
+
+// Document adalah dokumen OpenAPI 3.1
+type Document struct {
+	OpenAPI    string               `json:"openapi"`
+	Info       Info                 `json:"info"`
+	Paths      map[string]*PathItem `json:"paths"`
+	Components Components           `json:"components"`
+}
+
+// Info berisi judul dan versi API
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+// PathItem berisi operasi per method HTTP (huruf kecil) untuk satu path
+type PathItem map[string]*Operation
+
+// Operation adalah satu endpoint API
+type Operation struct {
+	Summary     string                `json:"summary,omitempty"`
+	OperationID string                `json:"operationId,omitempty"`
+	Tags        []string              `json:"tags,omitempty"`
+	Parameters  []Parameter           `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]*Response  `json:"responses"`
+	Security    []map[string][]string `json:"security,omitempty"`
+	Deprecated  bool                  `json:"deprecated,omitempty"`
+}
+
+// Parameter adalah parameter path atau query
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Required    bool    `json:"required,omitempty"`
+	Description string  `json:"description,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+// RequestBody adalah body request per media type
+type RequestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+// Response adalah satu response per status HTTP
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+// MediaType berisi schema untuk satu media type
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Components berisi schema dan security scheme yang dipakai ulang
+type Components struct {
+	Schemas         map[string]*Schema         `json:"schemas"`
+	SecuritySchemes map[string]*SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+// SecurityScheme mendeskripsikan cara autentikasi
+type SecurityScheme struct {
+	Type        string `json:"type"`
+	In          string `json:"in,omitempty"`
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// Endpoint adalah deskripsi singkat satu route yang diubah menjadi Operation.
+// Path memakai format gin (:id), parameter path dibuat otomatis.
+type Endpoint struct {
+	Method  string
+	Path    string
+	Summary string
+	Tag     string
+	Auth    bool
+	Query   []Parameter
+	Headers []Parameter
+
+	// Salah satu dari JSONBody atau FormBody (multipart/form-data)
+	JSONBody any
+	FormBody any
+
+	Status   int
+	Response any
+	// Produces adalah media type response sukses yang bukan JSON, misalnya file export
+	Produces []string
+	Errors   []apperrors.Code
+
+	Deprecated bool
+}
+
+// New membuat dokumen kosong dengan schema Problem untuk response error
+func New(title, version string) *Document {
+	d := &Document{
+		OpenAPI: Version,
+		Info:    Info{Title: title, Version: version},
+		Paths:   map[string]*PathItem{},
+		Components: Components{
+			Schemas: map[string]*Schema{},
+		},
+	}
+
+	// Daftar kode error diambil dari katalog agar selalu sinkron
+	d.SchemaOf(apperrors.Problem{}, "json")
+	codes := apperrors.Codes()
+	sort.Slice(codes, func(i, j int) bool { return codes[i] < codes[j] })
+	code := d.Components.Schemas["Problem"].Properties["code"]
+	for _, c := range codes {
+		code.Enum = append(code.Enum, string(c))
+	}
+	return d
+}
+
+// UseCookieAuth mendaftarkan security scheme token JWT di cookie
+func (d *Document) UseCookieAuth(cookieName string) {
+	if d.Components.SecuritySchemes == nil {
+		d.Components.SecuritySchemes = map[string]*SecurityScheme{}
+	}
+	d.Components.SecuritySchemes[CookieAuth] = &SecurityScheme{
+		Type:        "apiKey",
+		In:          "cookie",
+		Name:        cookieName,
+		Description: "JWT yang dikirim server lewat Set-Cookie saat login",
+	}
+}
+
+var pathParam = regexp.MustCompile(`:([^/]+)`)
+
+// Path mengubah path gin (/products/:id) menjadi path OpenAPI (/products/{id})
+func Path(ginPath string) string {
+	return pathParam.ReplaceAllString(ginPath, "{$1}")
+}
+
+// Add mendaftarkan operasi untuk method dan path OpenAPI
+func (d *Document) Add(method, path string, op *Operation) {
+	item, ok := d.Paths[path]
+	if !ok {
+		item = &PathItem{}
+		d.Paths[path] = item
+	}
+	(*item)[strings.ToLower(method)] = op
+}
+
+// AddEndpoint mengubah Endpoint menjadi Operation lalu mendaftarkannya
+func (d *Document) AddEndpoint(e Endpoint) {
+	op := &Operation{
+		Summary:     e.Summary,
+		OperationID: operationID(e.Method, e.Path),
+		Responses:   map[string]*Response{},
+		Deprecated:  e.Deprecated,
+	}
+	if e.Tag != "" {
+		op.Tags = []string{e.Tag}
+	}
+
+	for _, match := range pathParam.FindAllStringSubmatch(e.Path, -1) {
+		op.Parameters = append(op.Parameters, Parameter{
+			Name:     match[1],
+			In:       "path",
+			Required: true,
+			Schema:   &Schema{Type: "string", Format: "uuid"},
+		})
+	}
+	op.Parameters = append(op.Parameters, e.Query...)
+	op.Parameters = append(op.Parameters, e.Headers...)
+
+	switch {
+	case e.JSONBody != nil:
+		op.RequestBody = &RequestBody{Required: true, Content: map[string]MediaType{
+			"application/json": {Schema: d.SchemaOf(e.JSONBody, "json")},
+		}}
+	case e.FormBody != nil:
+		op.RequestBody = &RequestBody{Required: true, Content: map[string]MediaType{
+			"multipart/form-data": {Schema: d.SchemaOf(e.FormBody, "form")},
+		}}
+	}
+
+	status := e.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	success := &Response{Description: http.StatusText(status)}
+	if e.Response != nil {
+		success.Content = map[string]MediaType{
+			"application/json": {Schema: d.SchemaOf(e.Response, "json")},
+		}
+	}
+	for _, mediaType := range e.Produces {
+		if success.Content == nil {
+			success.Content = map[string]MediaType{}
+		}
+		success.Content[mediaType] = MediaType{Schema: &Schema{Type: "string"}}
+	}
+	op.Responses[strconv.Itoa(status)] = success
+
+	errs := append([]apperrors.Code{}, e.Errors...)
+	if e.Auth {
+		op.Security = []map[string][]string{{CookieAuth: {}}}
+		errs = append(errs, apperrors.CodeTokenMissing, apperrors.CodeTokenInvalid)
+	}
+	errs = append(errs, apperrors.CodeInternal)
+	d.addErrorResponses(op, errs)
+
+	d.Add(e.Method, Path(e.Path), op)
+}
+
+// addErrorResponses mengelompokkan kode error per status HTTP
+func (d *Document) addErrorResponses(op *Operation, codes []apperrors.Code) {
+	byStatus := map[int][]string{}
+	for _, code := range codes {
+		status := code.Status()
+		if !contains(byStatus[status], string(code)) {
+			byStatus[status] = append(byStatus[status], string(code))
+		}
+	}
+	for status, codes := range byStatus {
+		op.Responses[strconv.Itoa(status)] = &Response{
+			Description: http.StatusText(status) + ": " + strings.Join(codes, ", "),
+			Content: map[string]MediaType{
+				apperrors.ContentType: {Schema: Ref("Problem")},
+			},
+		}
+	}
+}
+
+// operationID membuat id operasi yang stabil, misalnya getProductsById
+func operationID(method, path string) string {
+	var b strings.Builder
+	b.WriteString(strings.ToLower(method))
+	for _, part := range strings.Split(path, "/") {
+		if part == "" {
+			continue
+		}
+		if strings.HasPrefix(part, ":") {
+			b.WriteString("By")
+			part = part[1:]
+		}
+		b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return b.String()
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// typeOf mengembalikan tipe dasar dari value, pointer dilepas
+func typeOf(v any) reflect.Type {
+	t := reflect.TypeOf(v)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
@@ -0,0 +1,62 @@
+package openapi
+
+import (
+	"fmt"
+	"io/fs"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	swaggerFiles "github.com/swaggo/files/v2"
+)
+
+// Handler mengirim dokumen OpenAPI sebagai JSON
+func Handler(doc *Document) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, doc)
+	}
+}
+
+// swaggerInitializer menggantikan konfigurasi bawaan Swagger UI (petstore)
+const swaggerInitializer = `window.onload = function() {
+  window.ui = SwaggerUIBundle({
+    url: %q,
+    dom_id: '#swagger-ui',
+    deepLinking: true,
+    withCredentials: true,
+    presets: [
+      SwaggerUIBundle.presets.apis,
+      SwaggerUIStandalonePreset
+    ],
+    plugins: [
+      SwaggerUIBundle.plugins.DownloadUrl
+    ],
+    layout: "StandaloneLayout"
+  });
+};
+`
+
+// SwaggerUI menyajikan Swagger UI bawaan yang membaca dokumen dari specURL.
+// Route harus didaftarkan dengan wildcard *filepath.
+func SwaggerUI(specURL string) gin.HandlerFunc {
+	initializer := fmt.Sprintf(swaggerInitializer, specURL)
+	index, _ := fs.ReadFile(swaggerFiles.FS, "index.html")
+	fileServer := http.FileServer(http.FS(swaggerFiles.FS))
+
+	return func(c *gin.Context) {
+		file := strings.TrimPrefix(c.Param("filepath"), "/")
+		switch file {
+		case "", "index.html":
+			// Disajikan langsung karena http.FileServer mengalihkan /index.html ke ./
+			c.Data(http.StatusOK, "text/html; charset=utf-8", index)
+			return
+		case "swagger-initializer.js":
+			c.Data(http.StatusOK, "application/javascript; charset=utf-8", []byte(initializer))
+			return
+		}
+
+		req := c.Request.Clone(c.Request.Context())
+		req.URL.Path = "/" + file
+		fileServer.ServeHTTP(c.Writer, req)
+	}
+}
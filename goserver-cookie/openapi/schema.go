@@ -0,0 +1,264 @@
+package openapi
+
+import (
+	"encoding/json"
+	"mime/multipart"
+	"reflect"
+	"server-cookie/models"
+	"server-cookie/money"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Schema adalah JSON Schema (subset yang dipakai OpenAPI 3.1)
+type Schema struct {
+	Ref         string             `json:"$ref,omitempty"`
+	Type        string             `json:"type,omitempty"`
+	Format      string             `json:"format,omitempty"`
+	Description string             `json:"description,omitempty"`
+	Properties  map[string]*Schema `json:"properties,omitempty"`
+	Required    []string           `json:"required,omitempty"`
+	Items       *Schema            `json:"items,omitempty"`
+	Enum        []string           `json:"enum,omitempty"`
+	MinLength   *int               `json:"minLength,omitempty"`
+	MaxLength   *int               `json:"maxLength,omitempty"`
+	MinItems    *int               `json:"minItems,omitempty"`
+	MaxItems    *int               `json:"maxItems,omitempty"`
+	Minimum     *int               `json:"minimum,omitempty"`
+	Maximum     *int               `json:"maximum,omitempty"`
+	Pattern     string             `json:"pattern,omitempty"`
+	OneOf       []*Schema          `json:"oneOf,omitempty"`
+}
+
+// Ref membuat referensi ke schema di components
+func Ref(name string) *Schema {
+	return &Schema{Ref: "#/components/schemas/" + name}
+}
+
+var (
+	timeType = reflect.TypeOf(time.Time{})
+	uuidType = reflect.TypeOf(uuid.UUID{})
+	fileType = reflect.TypeOf(multipart.FileHeader{})
+	rawType  = reflect.TypeOf(json.RawMessage{})
+
+	moneyType      = reflect.TypeOf(money.Money{})
+	moneyInputType = reflect.TypeOf(money.Input{})
+)
+
+// decimalPattern adalah format jumlah uang desimal, misalnya 12.50
+const decimalPattern = `^-?[0-9]+(\.[0-9]+)?$`
+
+// SchemaOf membuat schema dari tipe Go. Struct bernama didaftarkan ke components
+// dan dikembalikan sebagai $ref. tagName adalah "json" atau "form".
+func (d *Document) SchemaOf(v any, tagName string) *Schema {
+	return d.schemaFor(typeOf(v), tagName)
+}
+
+func (d *Document) schemaFor(t reflect.Type, tagName string) *Schema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch t {
+	case timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case uuidType:
+		return &Schema{Type: "string", Format: "uuid"}
+	case fileType:
+		return &Schema{Type: "string", Format: "binary"}
+	case rawType:
+		// JSON bebas, misalnya data hasil query GraphQL
+		return &Schema{}
+	case moneyType:
+		return d.named("Money", moneySchema)
+	case moneyInputType:
+		return d.named("MoneyInput", moneyInputSchema)
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: d.schemaFor(t.Elem(), tagName)}
+	case reflect.Map:
+		return &Schema{Type: "object"}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return d.structSchema(t, tagName)
+		}
+		if _, ok := d.Components.Schemas[t.Name()]; !ok {
+			// Daftarkan dulu agar struct rekursif tidak berulang tanpa henti
+			d.Components.Schemas[t.Name()] = &Schema{}
+			*d.Components.Schemas[t.Name()] = *d.structSchema(t, tagName)
+		}
+		return Ref(t.Name())
+	}
+	return &Schema{}
+}
+
+// named mendaftarkan schema buatan tangan ke components dan mengembalikan $ref-nya
+func (d *Document) named(name string, build func() *Schema) *Schema {
+	if _, ok := d.Components.Schemas[name]; !ok {
+		d.Components.Schemas[name] = build()
+	}
+	return Ref(name)
+}
+
+// moneySchema adalah bentuk JSON money.Money
+func moneySchema() *Schema {
+	return &Schema{
+		Type:        "object",
+		Description: "Exact amount of money. amount and minor_units are the same value, e.g. 5000.00 IDR is 500000 minor units.",
+		Properties: map[string]*Schema{
+			"amount":      {Type: "string", Pattern: decimalPattern, Description: "Decimal amount in the major unit"},
+			"currency":    {Type: "string", Enum: money.Currencies(), Description: "ISO 4217 currency code"},
+			"minor_units": {Type: "integer", Description: "Amount in the smallest unit of the currency"},
+		},
+		Required: []string{"amount", "currency", "minor_units"},
+	}
+}
+
+// moneyInputSchema adalah bentuk JSON yang diterima money.Input
+func moneyInputSchema() *Schema {
+	return &Schema{
+		Description: "A decimal string such as \"12.50\", or an object with either amount or minor_units. The currency defaults to the product currency.",
+		OneOf: []*Schema{
+			{Type: "string", Pattern: decimalPattern},
+			{
+				Type: "object",
+				Properties: map[string]*Schema{
+					"amount":      {Type: "string", Pattern: decimalPattern},
+					"minor_units": {Type: "integer"},
+					"currency":    {Type: "string", Enum: money.Currencies()},
+				},
+			},
+		},
+	}
+}
+
+// structSchema membuat schema object dari field struct yang diekspor
+func (d *Document) structSchema(t reflect.Type, tagName string) *Schema {
+	schema := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, omitempty := fieldName(field, tagName)
+		if name == "-" {
+			continue
+		}
+
+		prop := d.schemaFor(field.Type, tagName)
+		required := applyValidateTag(prop, field.Tag.Get("validate"))
+		if field.Tag.Get("validate") == "" {
+			// Tanpa tag validate, field JSON wajib kecuali omitempty
+			required = tagName == "json" && !omitempty
+		}
+
+		schema.Properties[name] = prop
+		if required {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+	return schema
+}
+
+// fieldName mengikuti aturan encoding/json dan binding form gin
+func fieldName(field reflect.StructField, tagName string) (string, bool) {
+	parts := strings.Split(field.Tag.Get(tagName), ",")
+	name := parts[0]
+	if name == "" {
+		name = field.Name
+	}
+	omitempty := false
+	for _, opt := range parts[1:] {
+		if opt == "omitempty" {
+			omitempty = true
+		}
+	}
+	return name, omitempty
+}
+
+// applyValidateTag menerjemahkan tag validator ke batasan schema
+// dan mengembalikan true jika field wajib diisi. Aturan setelah dive
+// berlaku untuk isi slice sehingga tidak dipakai.
+func applyValidateTag(schema *Schema, tag string) bool {
+	required := false
+	for _, rule := range strings.Split(tag, ",") {
+		name, param, _ := strings.Cut(rule, "=")
+		n, err := strconv.Atoi(param)
+		switch name {
+		case "dive":
+			return required
+		case "required":
+			required = true
+		case "email":
+			schema.Format = "email"
+		case "uuid":
+			schema.Format = "uuid"
+		case "min", "max":
+			if err != nil {
+				continue
+			}
+			// min dan max berarti nilai untuk angka, jumlah item untuk array dan panjang untuk string
+			switch {
+			case schema.Type == "integer" && name == "min":
+				schema.Minimum = &n
+			case schema.Type == "integer":
+				schema.Maximum = &n
+			case schema.Type == "array" && name == "min":
+				schema.MinItems = &n
+			case schema.Type == "array":
+				schema.MaxItems = &n
+			case name == "min":
+				schema.MinLength = &n
+			default:
+				schema.MaxLength = &n
+			}
+		case "gte":
+			if err == nil {
+				schema.Minimum = &n
+			}
+		case "lte":
+			if err == nil {
+				schema.Maximum = &n
+			}
+		case "oneof":
+			schema.Enum = strings.Fields(param)
+		case "max_items":
+			if err == nil {
+				schema.MaxItems = &n
+			}
+		case "price":
+			schema.Pattern = `^[0-9]+(\.[0-9]+)?$`
+			schema.Description = appendDescription(schema.Description, "Positive decimal amount with at most as many decimal places as the currency allows, e.g. 12.50")
+		case "currency":
+			schema.Enum = money.Currencies()
+			schema.Description = appendDescription(schema.Description, "ISO 4217 currency code, defaults to "+models.DefaultCurrency)
+		case "image_type":
+			schema.Description = appendDescription(schema.Description, "JPEG, PNG, GIF or WebP image")
+		case "max_file_size":
+			schema.Description = appendDescription(schema.Description, "At most "+param+" bytes")
+		}
+	}
+	return required
+}
+
+func appendDescription(description, s string) string {
+	if description == "" {
+		return s
+	}
+	return description + ". " + s
+}
@@ -0,0 +1,60 @@
+package repositories
+
+import (
+	"context"
+	"server-cookie/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// GormCategoryRepository adalah implementasi CategoryRepository dengan GORM
+type GormCategoryRepository struct {
+	db *gorm.DB
+}
+
+var _ CategoryRepository = (*GormCategoryRepository)(nil)
+
+// NewGormCategoryRepository membuat CategoryRepository berbasis GORM
+func NewGormCategoryRepository(db *gorm.DB) *GormCategoryRepository {
+	return &GormCategoryRepository{db: db}
+}
+
+func (r *GormCategoryRepository) List(ctx context.Context) ([]models.Category, error) {
+	var categories []models.Category
+	err := r.db.WithContext(ctx).Order("name").Find(&categories).Error
+	return categories, err
+}
+
+func (r *GormCategoryRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.Category, error) {
+	var category models.Category
+	if err := r.db.WithContext(ctx).First(&category, "id = ?", id).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &category, nil
+}
+
+func (r *GormCategoryRepository) FindBySlug(ctx context.Context, slug string) (*models.Category, error) {
+	var category models.Category
+	if err := r.db.WithContext(ctx).First(&category, "slug = ?", slug).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &category, nil
+}
+
+func (r *GormCategoryRepository) Create(ctx context.Context, category *models.Category) error {
+	return r.db.WithContext(ctx).Create(category).Error
+}
+
+func (r *GormCategoryRepository) Update(ctx context.Context, category *models.Category) error {
+	return r.db.WithContext(ctx).Save(category).Error
+}
+
+func (r *GormCategoryRepository) Delete(ctx context.Context, category *models.Category) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Table("product_categories").Where("category_id = ?", category.Id).Delete(nil).Error; err != nil {
+			return err
+		}
+		return tx.Delete(category).Error
+	})
+}
@@ -0,0 +1,47 @@
+package repositories
+
+import (
+	"context"
+	"server-cookie/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GormExchangeRateRepository adalah implementasi ExchangeRateRepository dengan GORM
+type GormExchangeRateRepository struct {
+	db *gorm.DB
+}
+
+var _ ExchangeRateRepository = (*GormExchangeRateRepository)(nil)
+
+// NewGormExchangeRateRepository membuat ExchangeRateRepository berbasis GORM
+func NewGormExchangeRateRepository(db *gorm.DB) *GormExchangeRateRepository {
+	return &GormExchangeRateRepository{db: db}
+}
+
+func (r *GormExchangeRateRepository) List(ctx context.Context) ([]models.ExchangeRate, error) {
+	var rates []models.ExchangeRate
+	err := r.db.WithContext(ctx).Order("base").Order("quote").Find(&rates).Error
+	return rates, err
+}
+
+func (r *GormExchangeRateRepository) Save(ctx context.Context, rates []models.ExchangeRate) error {
+	if len(rates) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return tx.Clauses(clause.OnConflict{UpdateAll: true}).CreateInBatches(rates, 100).Error
+	})
+}
+
+func (r *GormExchangeRateRepository) Delete(ctx context.Context, base, quote string) error {
+	result := r.db.WithContext(ctx).Delete(&models.ExchangeRate{}, "base = ? AND quote = ?", base, quote)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
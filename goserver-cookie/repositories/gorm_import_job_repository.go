@@ -0,0 +1,47 @@
+package repositories
+
+import (
+	"context"
+	"server-cookie/apperrors"
+	"server-cookie/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// GormImportJobRepository adalah implementasi ImportJobRepository dengan GORM
+type GormImportJobRepository struct {
+	db *gorm.DB
+}
+
+var _ ImportJobRepository = (*GormImportJobRepository)(nil)
+
+// NewGormImportJobRepository membuat ImportJobRepository berbasis GORM
+func NewGormImportJobRepository(db *gorm.DB) *GormImportJobRepository {
+	return &GormImportJobRepository{db: db}
+}
+
+func (r *GormImportJobRepository) Create(ctx context.Context, job *models.ImportJob) error {
+	return r.db.WithContext(ctx).Create(job).Error
+}
+
+func (r *GormImportJobRepository) Update(ctx context.Context, job *models.ImportJob) error {
+	return r.db.WithContext(ctx).Save(job).Error
+}
+
+func (r *GormImportJobRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.ImportJob, error) {
+	var job models.ImportJob
+	if err := r.db.WithContext(ctx).First(&job, "id = ?", id).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &job, nil
+}
+
+func (r *GormImportJobRepository) FailUnfinished(ctx context.Context, code apperrors.Code) (int64, error) {
+	now := time.Now()
+	result := r.db.WithContext(ctx).Model(&models.ImportJob{}).
+		Where("status IN ?", []string{models.ImportQueued, models.ImportRunning}).
+		Updates(map[string]any{"status": models.ImportFailed, "error_code": code, "finished_at": now, "updated_at": now})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,245 @@
+package repositories
+
+import (
+	"context"
+	"server-cookie/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GormInventoryRepository adalah implementasi InventoryRepository dengan GORM
+type GormInventoryRepository struct {
+	db *gorm.DB
+}
+
+var _ InventoryRepository = (*GormInventoryRepository)(nil)
+
+// NewGormInventoryRepository membuat InventoryRepository berbasis GORM
+func NewGormInventoryRepository(db *gorm.DB) *GormInventoryRepository {
+	return &GormInventoryRepository{db: db}
+}
+
+func (r *GormInventoryRepository) Levels(ctx context.Context, productIDs []uuid.UUID) ([]models.StockLevel, error) {
+	var levels []models.StockLevel
+	err := r.db.WithContext(ctx).Where("product_id IN ?", productIDs).Find(&levels).Error
+	return levels, err
+}
+
+func (r *GormInventoryRepository) LowStockProductIDs(ctx context.Context) ([]uuid.UUID, error) {
+	ids := []uuid.UUID{}
+	err := r.db.WithContext(ctx).Model(&models.StockLevel{}).
+		Where("low_stock_threshold > 0 AND on_hand - reserved <= low_stock_threshold").
+		Distinct().Pluck("product_id", &ids).Error
+	return ids, err
+}
+
+func (r *GormInventoryRepository) SetThreshold(ctx context.Context, item StockItem, threshold int64) (*models.StockLevel, error) {
+	var level models.StockLevel
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := ensureLevel(tx, item); err != nil {
+			return err
+		}
+		if err := levelOf(tx, item).Update("low_stock_threshold", threshold).Error; err != nil {
+			return err
+		}
+		return levelOf(tx, item).First(&level).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &level, nil
+}
+
+func (r *GormInventoryRepository) Adjust(ctx context.Context, movement *models.InventoryMovement) (*models.StockLevel, error) {
+	item := StockItem{ProductID: movement.ProductId, VariantID: movement.VariantId}
+	var level models.StockLevel
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := ensureLevel(tx, item); err != nil {
+			return err
+		}
+		// Syarat di WHERE membuat pengecekan dan perubahan terjadi dalam satu statement
+		result := levelOf(tx, item).
+			Where("on_hand + ? >= reserved", movement.Delta).
+			Update("on_hand", gorm.Expr("on_hand + ?", movement.Delta))
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrInsufficientStock
+		}
+		if err := levelOf(tx, item).First(&level).Error; err != nil {
+			return err
+		}
+		movement.Balance = level.OnHand
+		return tx.Create(movement).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &level, nil
+}
+
+func (r *GormInventoryRepository) Movements(ctx context.Context, productID uuid.UUID, variantID *uuid.UUID, page, limit int) ([]models.InventoryMovement, int64, error) {
+	query := func() *gorm.DB {
+		query := r.db.WithContext(ctx).Model(&models.InventoryMovement{}).Where("product_id = ?", productID)
+		if variantID != nil {
+			query = query.Where("variant_id = ?", *variantID)
+		}
+		return query
+	}
+
+	var total int64
+	if err := query().Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var movements []models.InventoryMovement
+	err := query().Order("created_at DESC").Order("id").
+		Limit(limit).Offset((page - 1) * limit).
+		Find(&movements).Error
+	return movements, total, err
+}
+
+func (r *GormInventoryRepository) Prune(ctx context.Context, productID uuid.UUID, keep []uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&models.StockReservation{}).
+			Where("product_id = ? AND status = ? AND variant_id NOT IN ?", productID, models.ReservationPending, keep).
+			Update("status", models.ReservationReleased).Error
+		if err != nil {
+			return err
+		}
+		return tx.Where("product_id = ? AND variant_id NOT IN ?", productID, keep).Delete(&models.StockLevel{}).Error
+	})
+}
+
+// purgeStock menghapus semua stok, ledger dan reservasi produk di dalam transaksi
+// tx, dipanggil GormProductRepository.Purge bersama penghapusan produknya
+func purgeStock(tx *gorm.DB, productID uuid.UUID) error {
+	for _, model := range []any{&models.StockReservation{}, &models.InventoryMovement{}, &models.StockLevel{}} {
+		if err := tx.Where("product_id = ?", productID).Delete(model).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *GormInventoryRepository) Reserve(ctx context.Context, reservation *models.StockReservation) error {
+	item := StockItem{ProductID: reservation.ProductId, VariantID: reservation.VariantId}
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := levelOf(tx, item).
+			Where("on_hand - reserved >= ?", reservation.Quantity).
+			Update("reserved", gorm.Expr("reserved + ?", reservation.Quantity))
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrInsufficientStock
+		}
+		reservation.Status = models.ReservationPending
+		return tx.Create(reservation).Error
+	})
+}
+
+func (r *GormInventoryRepository) FindReservation(ctx context.Context, id uuid.UUID) (*models.StockReservation, error) {
+	var reservation models.StockReservation
+	if err := r.db.WithContext(ctx).First(&reservation, "id = ?", id).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &reservation, nil
+}
+
+func (r *GormInventoryRepository) Confirm(ctx context.Context, reservation *models.StockReservation, now time.Time) error {
+	item := StockItem{ProductID: reservation.ProductId, VariantID: reservation.VariantId}
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Hanya satu request yang bisa mengubah status dari pending
+		result := tx.Model(&models.StockReservation{}).
+			Where("id = ? AND status = ? AND expires_at > ?", reservation.Id, models.ReservationPending, now).
+			Update("status", models.ReservationConfirmed)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrReservationClosed
+		}
+
+		result = levelOf(tx, item).Updates(map[string]any{
+			"on_hand":  gorm.Expr("on_hand - ?", reservation.Quantity),
+			"reserved": gorm.Expr("reserved - ?", reservation.Quantity),
+		})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrReservationClosed
+		}
+		var level models.StockLevel
+		if err := levelOf(tx, item).First(&level).Error; err != nil {
+			return err
+		}
+		movement := saleMovement(*reservation, level.OnHand)
+		if err := tx.Create(&movement).Error; err != nil {
+			return err
+		}
+		reservation.Status = models.ReservationConfirmed
+		return nil
+	})
+}
+
+func (r *GormInventoryRepository) Release(ctx context.Context, reservation *models.StockReservation, status string) error {
+	item := StockItem{ProductID: reservation.ProductId, VariantID: reservation.VariantId}
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.StockReservation{}).
+			Where("id = ? AND status = ?", reservation.Id, models.ReservationPending).
+			Update("status", status)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrReservationClosed
+		}
+		err := levelOf(tx, item).
+			Update("reserved", gorm.Expr("reserved - ?", reservation.Quantity)).Error
+		if err != nil {
+			return err
+		}
+		reservation.Status = status
+		return nil
+	})
+}
+
+func (r *GormInventoryRepository) ListExpired(ctx context.Context, now time.Time, limit int) ([]models.StockReservation, error) {
+	var reservations []models.StockReservation
+	err := r.db.WithContext(ctx).
+		Where("status = ? AND expires_at <= ?", models.ReservationPending, now).
+		Order("expires_at").
+		Limit(limit).
+		Find(&reservations).Error
+	return reservations, err
+}
+
+// levelOf membuat query untuk baris stok satu item
+func levelOf(tx *gorm.DB, item StockItem) *gorm.DB {
+	return tx.Model(&models.StockLevel{}).Where("product_id = ? AND variant_id = ?", item.ProductID, item.VariantID)
+}
+
+// ensureLevel mencatat stok 0 untuk item yang belum punya baris stok.
+// Jika request lain mencatatnya lebih dulu, unique index membuat insert ini diabaikan.
+func ensureLevel(tx *gorm.DB, item StockItem) error {
+	level := models.StockLevel{ProductId: item.ProductID, VariantId: item.VariantID}
+	return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&level).Error
+}
+
+// saleMovement adalah baris ledger untuk reservasi yang dikonfirmasi
+func saleMovement(reservation models.StockReservation, balance int64) models.InventoryMovement {
+	return models.InventoryMovement{
+		ProductId:     reservation.ProductId,
+		VariantId:     reservation.VariantId,
+		Delta:         -reservation.Quantity,
+		Balance:       balance,
+		Reason:        models.ReasonSale,
+		ReservationId: &reservation.Id,
+		UserId:        reservation.UserId,
+	}
+}
@@ -0,0 +1,587 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"server-cookie/models"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GormProductRepository adalah implementasi ProductRepository dengan GORM
+type GormProductRepository struct {
+	db *gorm.DB
+}
+
+var _ ProductRepository = (*GormProductRepository)(nil)
+
+// NewGormProductRepository membuat ProductRepository berbasis GORM
+func NewGormProductRepository(db *gorm.DB) *GormProductRepository {
+	return &GormProductRepository{db: db}
+}
+
+func (r *GormProductRepository) List(ctx context.Context, params ProductListParams) ([]models.Product, int64, error) {
+	// Query dasar dengan filter hasil pencarian, kategori dan tag
+	filtered := func() *gorm.DB {
+		query := r.db.WithContext(ctx).Model(&models.Product{})
+		if params.Ranking != nil {
+			query = query.Where("id IN ?", params.Ranking)
+		}
+		if params.IDs != nil {
+			query = query.Where("id IN ?", params.IDs)
+		}
+		if len(params.CategoryIDs) > 0 {
+			query = query.Where("id IN (?)", r.db.Table("product_categories").
+				Select("product_id").
+				Where("category_id IN ?", params.CategoryIDs))
+		}
+		if len(params.Tags) > 0 {
+			tagged := r.db.Table("product_tags").
+				Select("product_id").
+				Where("tag_name IN ?", params.Tags)
+			if params.AllTags {
+				tagged = tagged.Group("product_id").Having("COUNT(*) = ?", len(params.Tags))
+			}
+			query = query.Where("id IN (?)", tagged)
+		}
+		return filterProducts(query, params)
+	}
+
+	// Hitung total produk setelah filter, kecuali diminta tidak
+	var totalItems int64
+	if !params.SkipCount {
+		if err := filtered().Count(&totalItems).Error; err != nil {
+			return nil, 0, err
+		}
+	}
+
+	// Ambil produk dengan pagination, preload user dan sorting
+	query := preloadRelations(filtered())
+	if !params.WithoutUser {
+		query = query.Preload("User")
+	}
+	sort := params.sortOrDefault()
+	if params.Cursor != nil {
+		// Keyset: lanjut dari posisi cursor, untuk mundur urutannya dibalik
+		query = afterKey(query, sort, params.Ranking, *params.Cursor)
+		if params.Cursor.Backward {
+			sort = reverseSort(sort)
+		}
+	} else {
+		query = query.Offset(params.Offset())
+	}
+
+	var products []models.Product
+	err := orderProducts(query, sort, params.Ranking, params.Cursor != nil && params.Cursor.Backward).
+		Limit(params.fetchLimit()).
+		Find(&products).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	if params.Cursor != nil && params.Cursor.Backward {
+		slices.Reverse(products)
+	}
+	return products, totalItems, nil
+}
+
+// filterProducts menambahkan filter harga, pemilik, status, waktu dibuat dan gambar
+func filterProducts(query *gorm.DB, params ProductListParams) *gorm.DB {
+	hasImages := query.Session(&gorm.Session{NewDB: true}).Table("product_images").
+		Select("1").
+		Where("product_images.product_id = products.id")
+	if params.Currency != "" {
+		query = query.Where("price_currency = ?", params.Currency)
+	}
+	// Produk cocok jika rentang harga variannya beririsan dengan filter
+	if params.MinPrice != nil {
+		query = query.Where("price_max >= ?", *params.MinPrice)
+	}
+	if params.MaxPrice != nil {
+		query = query.Where("price_min <= ?", *params.MaxPrice)
+	}
+	if params.OwnerID != nil {
+		query = query.Where("user_id = ?", *params.OwnerID)
+	}
+	if params.Statuses != nil {
+		query = query.Where("status IN ?", params.Statuses)
+	}
+	if params.CreatedAfter != nil {
+		query = query.Where("created_at >= ?", *params.CreatedAfter)
+	}
+	if params.CreatedBefore != nil {
+		query = query.Where("created_at < ?", *params.CreatedBefore)
+	}
+	if params.HasImage != nil {
+		if *params.HasImage {
+			query = query.Where("EXISTS (?)", hasImages)
+		} else {
+			query = query.Where("NOT EXISTS (?)", hasImages)
+		}
+	}
+	return query
+}
+
+// productSortColumns memetakan field sort ke ekspresi kolom,
+// nama diurutkan tanpa membedakan huruf besar/kecil
+var productSortColumns = map[ProductSortField]string{
+	SortByName:      "LOWER(name)",
+	SortByPrice:     "price_min",
+	SortByCreatedAt: "created_at",
+	SortByUpdatedAt: "updated_at",
+}
+
+// sortColumn mengembalikan ekspresi kolom untuk field sort. Relevansi adalah posisi
+// di ranking; ID berupa UUID (hanya hex dan tanda hubung) sehingga aman ditulis langsung.
+func sortColumn(field ProductSortField, ranking []uuid.UUID) (string, bool) {
+	if field != SortByRelevance {
+		column, ok := productSortColumns[field]
+		return column, ok
+	}
+	// Tanpa hasil pencarian semua rank sama, CASE tanpa WHEN juga tidak valid
+	if len(ranking) == 0 {
+		return "", false
+	}
+	var b strings.Builder
+	b.WriteString("(CASE id")
+	for rank, id := range ranking {
+		fmt.Fprintf(&b, " WHEN '%s' THEN %d", id, rank)
+	}
+	fmt.Fprintf(&b, " ELSE %d END)", len(ranking))
+	return b.String(), true
+}
+
+// orderProducts menambahkan ORDER BY sesuai sort, diakhiri id agar stabil
+func orderProducts(query *gorm.DB, sort []ProductSort, ranking []uuid.UUID, idDesc bool) *gorm.DB {
+	for _, key := range sort {
+		column, ok := sortColumn(key.Field, ranking)
+		if !ok {
+			continue
+		}
+		if key.Desc {
+			column += " DESC"
+		}
+		query = query.Order(column)
+	}
+	if idDesc {
+		return query.Order("id DESC")
+	}
+	return query.Order("id")
+}
+
+// afterKey membatasi produk yang letaknya setelah (atau sebelum) kunci cursor,
+// misalnya untuk sort -price: price < ? OR (price = ? AND id > ?)
+func afterKey(query *gorm.DB, sort []ProductSort, ranking []uuid.UUID, cursor ProductCursor) *gorm.DB {
+	var (
+		conditions []string
+		args       []any
+		equal      string
+		equalArgs  []any
+	)
+	add := func(column string, desc bool, value any) {
+		op := ">"
+		if desc != cursor.Backward {
+			op = "<"
+		}
+		conditions = append(conditions, equal+column+" "+op+" ?")
+		args = append(append(args, equalArgs...), value)
+		equal += column + " = ? AND "
+		equalArgs = append(equalArgs, value)
+	}
+	for _, key := range sort {
+		column, ok := sortColumn(key.Field, ranking)
+		if !ok {
+			continue
+		}
+		add(column, key.Desc, keyValue(cursor.Key, key.Field))
+	}
+	add("id", false, cursor.Key.Id)
+	return query.Where("("+strings.Join(conditions, ") OR (")+")", args...)
+}
+
+// keyValue mengambil nilai kolom sort dari kunci, sama seperti productSortColumns
+func keyValue(key ProductKey, field ProductSortField) any {
+	switch field {
+	case SortByName:
+		return strings.ToLower(key.Name)
+	case SortByPrice:
+		return key.Price
+	case SortByCreatedAt:
+		return key.CreatedAt
+	case SortByUpdatedAt:
+		return key.UpdatedAt
+	case SortByRelevance:
+		return key.Rank
+	}
+	return nil
+}
+
+func (r *GormProductRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.Product, error) {
+	var product models.Product
+	if err := preloadRelations(r.db.WithContext(ctx)).Preload("User").First(&product, "id = ?", id).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &product, nil
+}
+
+func (r *GormProductRepository) Create(ctx context.Context, product *models.Product, revision *models.ProductRevision) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Create(product).Error; err != nil {
+			return translateProductError(err)
+		}
+		if err := saveRelations(tx, product); err != nil {
+			return err
+		}
+		if err := loadRelations(tx, product); err != nil {
+			return err
+		}
+		return saveRevision(tx, product, revision)
+	})
+}
+
+func (r *GormProductRepository) Update(ctx context.Context, product *models.Product, revision *models.ProductRevision) error {
+	version := product.Version
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := updateProduct(tx, product); err != nil {
+			return err
+		}
+		if err := loadRelations(tx, product); err != nil {
+			return err
+		}
+		return saveRevision(tx, product, revision)
+	})
+	if err != nil {
+		product.Version = version
+	}
+	return err
+}
+
+func (r *GormProductRepository) Delete(ctx context.Context, product *models.Product, revision *models.ProductRevision) error {
+	saved := *product
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := deleteProduct(tx, product, time.Now()); err != nil {
+			return err
+		}
+		return saveRevision(tx, product, revision)
+	})
+	if err != nil {
+		product.Version, product.DeletedAt = saved.Version, saved.DeletedAt
+	}
+	return err
+}
+
+func (r *GormProductRepository) SaveBatch(ctx context.Context, changes []ProductChange) error {
+	// Versi dan deleted_at dikembalikan jika transaksi dibatalkan
+	saved := make([]models.Product, len(changes))
+	for i, change := range changes {
+		saved[i] = *change.Product
+	}
+
+	deletedAt := time.Now()
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for i, change := range changes {
+			var err error
+			if change.Delete {
+				err = deleteProduct(tx, change.Product, deletedAt)
+			} else if err = updateProduct(tx, change.Product); err == nil {
+				err = loadRelations(tx, change.Product)
+			}
+			if err == nil {
+				err = saveRevision(tx, change.Product, change.Revision)
+			}
+			if err != nil {
+				return &BatchError{Index: i, Err: err}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		for i, change := range changes {
+			change.Product.Version, change.Product.DeletedAt = saved[i].Version, saved[i].DeletedAt
+		}
+	}
+	return err
+}
+
+// updateProduct menyimpan produk beserta relasinya di dalam transaksi tx
+func updateProduct(tx *gorm.DB, product *models.Product) error {
+	version := product.Version
+	product.Version = version + 1
+	// Update bersyarat versi agar perubahan request lain tidak tertimpa
+	result := tx.Model(product).Select("*").Omit(clause.Associations).
+		Where("version = ?", version).
+		Updates(product)
+	err := translateProductError(result.Error)
+	if err == nil && result.RowsAffected == 0 {
+		err = ErrVersionConflict
+	}
+	if err == nil {
+		err = saveRelations(tx, product)
+	}
+	if err != nil {
+		product.Version = version
+	}
+	return err
+}
+
+// translateProductError memetakan pelanggaran unique index pada tabel products.
+// Selain primary key, satu-satunya unique index di tabel itu adalah
+// idx_products_user_external, SKU ganda dilaporkan dari tabel product_variants.
+func translateProductError(err error) error {
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return ErrExternalIDTaken
+	}
+	return err
+}
+
+// deleteProduct memindahkan produk ke trash dengan update bersyarat versi
+func deleteProduct(db *gorm.DB, product *models.Product, deletedAt time.Time) error {
+	result := db.Model(&models.Product{}).
+		Where("id = ? AND version = ?", product.Id, product.Version).
+		Updates(map[string]any{"deleted_at": deletedAt, "version": product.Version + 1})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrVersionConflict
+	}
+	product.Version++
+	product.DeletedAt = gorm.DeletedAt{Time: deletedAt, Valid: true}
+	return nil
+}
+
+func (r *GormProductRepository) ListTrashed(ctx context.Context, ownerID uuid.UUID, params ProductListParams) ([]models.Product, int64, error) {
+	trashed := func() *gorm.DB {
+		return r.db.WithContext(ctx).Unscoped().Model(&models.Product{}).
+			Where("user_id = ? AND deleted_at IS NOT NULL", ownerID)
+	}
+
+	var totalItems int64
+	if err := trashed().Count(&totalItems).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var products []models.Product
+	err := preloadRelations(trashed()).Preload("User").
+		Order("deleted_at DESC").
+		Limit(params.Limit).Offset(params.Offset()).
+		Find(&products).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return products, totalItems, nil
+}
+
+func (r *GormProductRepository) FindTrashedByID(ctx context.Context, id uuid.UUID) (*models.Product, error) {
+	var product models.Product
+	err := preloadRelations(r.db.WithContext(ctx).Unscoped()).Preload("User").
+		Where("deleted_at IS NOT NULL").
+		First(&product, "id = ?", id).Error
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return &product, nil
+}
+
+func (r *GormProductRepository) Restore(ctx context.Context, product *models.Product, revision *models.ProductRevision) error {
+	saved := *product
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Unscoped().Model(&models.Product{}).
+			Where("id = ? AND deleted_at IS NOT NULL", product.Id).
+			Updates(map[string]any{"deleted_at": nil, "version": gorm.Expr("version + 1")})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrNotFound
+		}
+		product.Version++
+		product.DeletedAt = gorm.DeletedAt{}
+		return saveRevision(tx, product, revision)
+	})
+	if err != nil {
+		product.Version, product.DeletedAt = saved.Version, saved.DeletedAt
+	}
+	return err
+}
+
+func (r *GormProductRepository) Purge(ctx context.Context, product *models.Product) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, table := range []string{"product_categories", "product_tags", "product_images", "product_options", "product_variants"} {
+			if err := tx.Table(table).Where("product_id = ?", product.Id).Delete(nil).Error; err != nil {
+				return err
+			}
+		}
+		if err := purgeStock(tx, product.Id); err != nil {
+			return err
+		}
+		if err := purgeRevisions(tx, product.Id); err != nil {
+			return err
+		}
+		return tx.Unscoped().Delete(product).Error
+	})
+}
+
+func (r *GormProductRepository) ListTrashedBefore(ctx context.Context, before time.Time, limit int) ([]models.Product, error) {
+	var products []models.Product
+	// Gambar ikut dimuat karena filenya dihapus setelah produk di-purge
+	err := r.db.WithContext(ctx).Unscoped().Preload("Images").
+		Where("deleted_at IS NOT NULL AND deleted_at < ?", before).
+		Order("deleted_at").
+		Limit(limit).
+		Find(&products).Error
+	return products, err
+}
+
+func (r *GormProductRepository) ListScheduledBefore(ctx context.Context, before time.Time, limit int) ([]models.Product, error) {
+	var products []models.Product
+	// Relasi ikut dimuat karena produk disimpan ulang setelah statusnya diubah
+	err := preloadRelations(r.db.WithContext(ctx)).Preload("User").
+		Where("(status = ? AND publish_at < ?) OR (status = ? AND unpublish_at < ?)",
+			models.ProductDraft, before, models.ProductPublished, before).
+		Order("created_at, id").
+		Limit(limit).
+		Find(&products).Error
+	return products, err
+}
+
+func (r *GormProductRepository) TagCounts(ctx context.Context) ([]models.TagCount, error) {
+	var counts []models.TagCount
+	err := r.db.WithContext(ctx).Table("product_tags").
+		Select("product_tags.tag_name AS name, COUNT(*) AS count").
+		Joins("JOIN products ON products.id = product_tags.product_id AND products.deleted_at IS NULL AND products.status = ?", models.ProductPublished).
+		Group("product_tags.tag_name").
+		Order("count DESC, name").
+		Scan(&counts).Error
+	return counts, err
+}
+
+// loadRelations mengisi data User pemilik, kategori dan tag produk setelah create/update
+func loadRelations(db *gorm.DB, product *models.Product) error {
+	product.User = models.User{}
+	if err := db.Where("id = ?", product.UserId).Limit(1).Find(&product.User).Error; err != nil {
+		return err
+	}
+
+	var loaded models.Product
+	if err := preloadRelations(db.Unscoped()).First(&loaded, "id = ?", product.Id).Error; err != nil {
+		return translateError(err)
+	}
+	product.Categories = loaded.Categories
+	product.Tags = loaded.Tags
+	product.Images = loaded.Images
+	product.Options = loaded.Options
+	product.Variants = loaded.Variants
+	return nil
+}
+
+// saveRevision menyimpan revisi dengan snapshot produk setelah perubahan di dalam
+// transaksi tx, sehingga perubahan dan revisinya tersimpan bersama atau tidak sama
+// sekali. Tidak melakukan apa-apa jika revision nil.
+func saveRevision(tx *gorm.DB, product *models.Product, revision *models.ProductRevision) error {
+	if revision == nil {
+		return nil
+	}
+	revision.ProductId = product.Id
+	revision.Snapshot = models.NewProductSnapshot(*product)
+	return createRevision(tx, revision)
+}
+
+// preloadRelations memuat kategori dan tag produk diurutkan berdasarkan nama,
+// serta gambar, option dan varian sesuai posisinya
+func preloadRelations(db *gorm.DB) *gorm.DB {
+	byName := func(db *gorm.DB) *gorm.DB { return db.Order("name") }
+	byPosition := func(db *gorm.DB) *gorm.DB { return db.Order("position") }
+	return db.Preload("Categories", byName).Preload("Tags", byName).
+		Preload("Images", byPosition).Preload("Options", byPosition).Preload("Variants", byPosition)
+}
+
+// saveRelations mengganti isi tabel product_categories, product_tags, product_images,
+// product_options dan product_variants. Tabel relasi ditulis langsung agar GORM
+// tidak ikut menyimpan ulang data kategori.
+func saveRelations(tx *gorm.DB, product *models.Product) error {
+	// Gambar, option dan varian ditulis ulang dengan ID yang sama, sehingga urutan ikut tersimpan
+	for i := range product.Images {
+		product.Images[i].ProductId = product.Id
+	}
+	if err := replaceRows(tx, product.Id, &product.Images); err != nil {
+		return err
+	}
+	for i := range product.Options {
+		product.Options[i].ProductId = product.Id
+	}
+	if err := replaceRows(tx, product.Id, &product.Options); err != nil {
+		return err
+	}
+	for i := range product.Variants {
+		product.Variants[i].ProductId = product.Id
+	}
+	if err := replaceRows(tx, product.Id, &product.Variants); err != nil {
+		return err
+	}
+
+	if err := tx.Table("product_categories").Where("product_id = ?", product.Id).Delete(nil).Error; err != nil {
+		return err
+	}
+	if len(product.Categories) > 0 {
+		rows := make([]map[string]any, 0, len(product.Categories))
+		for _, category := range product.Categories {
+			rows = append(rows, map[string]any{"product_id": product.Id, "category_id": category.Id})
+		}
+		if err := tx.Table("product_categories").Create(rows).Error; err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Table("product_tags").Where("product_id = ?", product.Id).Delete(nil).Error; err != nil {
+		return err
+	}
+	if len(product.Tags) > 0 {
+		// Tag baru dibuat, tag yang sudah ada dibiarkan
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&product.Tags).Error; err != nil {
+			return err
+		}
+		rows := make([]map[string]any, 0, len(product.Tags))
+		for _, tag := range product.Tags {
+			rows = append(rows, map[string]any{"product_id": product.Id, "tag_name": tag.Name})
+		}
+		if err := tx.Table("product_tags").Create(rows).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// replaceRows menghapus semua baris milik produk di tabel rows, lalu menyimpan isi rows
+func replaceRows[T any](tx *gorm.DB, productID uuid.UUID, rows *[]T) error {
+	if err := tx.Where("product_id = ?", productID).Delete(new(T)).Error; err != nil {
+		return err
+	}
+	if len(*rows) == 0 {
+		return nil
+	}
+	return tx.Create(rows).Error
+}
+
+func (r *GormProductRepository) FindByExternalID(ctx context.Context, ownerID uuid.UUID, externalID string) (*models.Product, error) {
+	var product models.Product
+	err := preloadRelations(r.db.WithContext(ctx).Unscoped()).Preload("User").
+		First(&product, "user_id = ? AND external_id = ?", ownerID, externalID).Error
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return &product, nil
+}
+
+func (r *GormProductRepository) FindVariantBySKU(ctx context.Context, sku string) (*models.ProductVariant, error) {
+	var variant models.ProductVariant
+	if err := r.db.WithContext(ctx).First(&variant, "sku = ?", sku).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &variant, nil
+}
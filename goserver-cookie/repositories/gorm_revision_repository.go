@@ -0,0 +1,92 @@
+package repositories
+
+import (
+	"context"
+	"server-cookie/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// GormRevisionRepository adalah implementasi ProductRevisionRepository dengan GORM
+type GormRevisionRepository struct {
+	db *gorm.DB
+}
+
+var _ ProductRevisionRepository = (*GormRevisionRepository)(nil)
+
+// NewGormRevisionRepository membuat ProductRevisionRepository berbasis GORM
+func NewGormRevisionRepository(db *gorm.DB) *GormRevisionRepository {
+	return &GormRevisionRepository{db: db}
+}
+
+func (r *GormRevisionRepository) List(ctx context.Context, productID uuid.UUID, page, limit int) ([]models.ProductRevision, int64, error) {
+	query := func() *gorm.DB {
+		return r.db.WithContext(ctx).Model(&models.ProductRevision{}).Where("product_id = ?", productID)
+	}
+
+	var total int64
+	if err := query().Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var revisions []models.ProductRevision
+	err := query().Omit("snapshot").Order("number DESC").
+		Limit(limit).Offset((page - 1) * limit).
+		Find(&revisions).Error
+	return revisions, total, err
+}
+
+func (r *GormRevisionRepository) Find(ctx context.Context, productID uuid.UUID, number int) (*models.ProductRevision, error) {
+	var revision models.ProductRevision
+	err := r.db.WithContext(ctx).First(&revision, "product_id = ? AND number = ?", productID, number).Error
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return &revision, nil
+}
+
+func (r *GormRevisionRepository) ImagePaths(ctx context.Context, productID uuid.UUID) ([]string, error) {
+	var revisions []models.ProductRevision
+	err := r.db.WithContext(ctx).Select("snapshot").Where("product_id = ?", productID).Find(&revisions).Error
+	if err != nil {
+		return nil, err
+	}
+	return revisionImagePaths(revisions), nil
+}
+
+// purgeRevisions menghapus semua revisi produk di dalam transaksi tx,
+// dipanggil GormProductRepository.Purge bersama penghapusan produknya
+func purgeRevisions(tx *gorm.DB, productID uuid.UUID) error {
+	return tx.Where("product_id = ?", productID).Delete(&models.ProductRevision{}).Error
+}
+
+// revisionImagePaths mengambil path gambar yang tercatat di revisi, tanpa path ganda
+func revisionImagePaths(revisions []models.ProductRevision) []string {
+	var paths []string
+	seen := make(map[string]bool)
+	for _, revision := range revisions {
+		for _, image := range revision.Snapshot.Images {
+			if image.Path != "" && !seen[image.Path] {
+				seen[image.Path] = true
+				paths = append(paths, image.Path)
+			}
+		}
+	}
+	return paths
+}
+
+// createRevision menyimpan revisi dengan Number satu setelah revisi terakhir produknya.
+// Dipanggil di transaksi yang sudah mengubah baris produk, sehingga request lain yang
+// mengubah produk yang sama menunggu transaksi ini selesai sebelum mengambil nomor.
+func createRevision(tx *gorm.DB, revision *models.ProductRevision) error {
+	var last int
+	err := tx.Model(&models.ProductRevision{}).
+		Where("product_id = ?", revision.ProductId).
+		Select("COALESCE(MAX(number), 0)").Scan(&last).Error
+	if err != nil {
+		return err
+	}
+	revision.Id = uuid.Nil
+	revision.Number = last + 1
+	return tx.Create(revision).Error
+}
@@ -0,0 +1,82 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"server-cookie/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// GormUserRepository adalah implementasi UserRepository dengan GORM
+type GormUserRepository struct {
+	db *gorm.DB
+}
+
+var _ UserRepository = (*GormUserRepository)(nil)
+
+// NewGormUserRepository membuat UserRepository berbasis GORM
+func NewGormUserRepository(db *gorm.DB) *GormUserRepository {
+	return &GormUserRepository{db: db}
+}
+
+func (r *GormUserRepository) Create(ctx context.Context, user *models.User) error {
+	return translateUserError(r.db.WithContext(ctx).Create(user).Error)
+}
+
+func (r *GormUserRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.User, error) {
+	var user models.User
+	if err := r.db.WithContext(ctx).First(&user, "id = ?", id).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &user, nil
+}
+
+func (r *GormUserRepository) FindByIDs(ctx context.Context, ids []uuid.UUID) ([]models.User, error) {
+	var users []models.User
+	if len(ids) == 0 {
+		return users, nil
+	}
+	if err := r.db.WithContext(ctx).Where("id IN ?", ids).Find(&users).Error; err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+func (r *GormUserRepository) FindByUsername(ctx context.Context, username string) (*models.User, error) {
+	var user models.User
+	if err := r.db.WithContext(ctx).Where("username = ?", username).First(&user).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &user, nil
+}
+
+func (r *GormUserRepository) ExistsByUsernameOrEmail(ctx context.Context, username, email string) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.User{}).
+		Where("username = ?", username).Or("email = ?", email).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r *GormUserRepository) Update(ctx context.Context, user *models.User) error {
+	return translateUserError(r.db.WithContext(ctx).Save(user).Error)
+}
+
+// translateUserError memetakan pelanggaran unique index username, satu-satunya
+// unique index di tabel users selain primary key
+func translateUserError(err error) error {
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return ErrUsernameTaken
+	}
+	return err
+}
+
+// translateError mengubah error GORM menjadi error repository
+func translateError(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrNotFound
+	}
+	return err
+}
@@ -0,0 +1,96 @@
+package repositories
+
+import (
+	"context"
+	"server-cookie/models"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MemoryCategoryRepository adalah implementasi CategoryRepository di memory.
+// Relasi ke produk disimpan di MemoryProductRepository dan otomatis hilang
+// karena produk hanya memuat kategori yang masih ada.
+type MemoryCategoryRepository struct {
+	mu         sync.RWMutex
+	categories map[uuid.UUID]models.Category
+}
+
+var _ CategoryRepository = (*MemoryCategoryRepository)(nil)
+
+// NewMemoryCategoryRepository membuat CategoryRepository kosong di memory
+func NewMemoryCategoryRepository() *MemoryCategoryRepository {
+	return &MemoryCategoryRepository{categories: make(map[uuid.UUID]models.Category)}
+}
+
+func (r *MemoryCategoryRepository) List(ctx context.Context) ([]models.Category, error) {
+	r.mu.RLock()
+	categories := make([]models.Category, 0, len(r.categories))
+	for _, category := range r.categories {
+		categories = append(categories, category)
+	}
+	r.mu.RUnlock()
+
+	sort.Slice(categories, func(i, j int) bool {
+		return categories[i].Name < categories[j].Name
+	})
+	return categories, nil
+}
+
+func (r *MemoryCategoryRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.Category, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	category, ok := r.categories[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &category, nil
+}
+
+func (r *MemoryCategoryRepository) FindBySlug(ctx context.Context, slug string) (*models.Category, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, category := range r.categories {
+		if category.Slug == slug {
+			return &category, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (r *MemoryCategoryRepository) Create(ctx context.Context, category *models.Category) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Sama seperti hook BeforeCreate pada GORM
+	category.Id = uuid.New()
+	now := time.Now()
+	category.CreatedAt = now
+	category.UpdatedAt = now
+	r.categories[category.Id] = *category
+	return nil
+}
+
+func (r *MemoryCategoryRepository) Update(ctx context.Context, category *models.Category) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.categories[category.Id]; !ok {
+		return ErrNotFound
+	}
+	category.UpdatedAt = time.Now()
+	r.categories[category.Id] = *category
+	return nil
+}
+
+func (r *MemoryCategoryRepository) Delete(ctx context.Context, category *models.Category) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.categories, category.Id)
+	return nil
+}
@@ -0,0 +1,63 @@
+package repositories
+
+import (
+	"context"
+	"server-cookie/models"
+	"sort"
+	"sync"
+	"time"
+)
+
+// MemoryExchangeRateRepository adalah implementasi ExchangeRateRepository di memory
+type MemoryExchangeRateRepository struct {
+	mu    sync.RWMutex
+	rates map[[2]string]models.ExchangeRate
+}
+
+var _ ExchangeRateRepository = (*MemoryExchangeRateRepository)(nil)
+
+// NewMemoryExchangeRateRepository membuat ExchangeRateRepository kosong di memory
+func NewMemoryExchangeRateRepository() *MemoryExchangeRateRepository {
+	return &MemoryExchangeRateRepository{rates: make(map[[2]string]models.ExchangeRate)}
+}
+
+func (r *MemoryExchangeRateRepository) List(ctx context.Context) ([]models.ExchangeRate, error) {
+	r.mu.RLock()
+	rates := make([]models.ExchangeRate, 0, len(r.rates))
+	for _, rate := range r.rates {
+		rates = append(rates, rate)
+	}
+	r.mu.RUnlock()
+
+	sort.Slice(rates, func(i, j int) bool {
+		if rates[i].Base != rates[j].Base {
+			return rates[i].Base < rates[j].Base
+		}
+		return rates[i].Quote < rates[j].Quote
+	})
+	return rates, nil
+}
+
+func (r *MemoryExchangeRateRepository) Save(ctx context.Context, rates []models.ExchangeRate) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	for i := range rates {
+		rates[i].UpdatedAt = now
+		r.rates[[2]string{rates[i].Base, rates[i].Quote}] = rates[i]
+	}
+	return nil
+}
+
+func (r *MemoryExchangeRateRepository) Delete(ctx context.Context, base, quote string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := [2]string{base, quote}
+	if _, ok := r.rates[key]; !ok {
+		return ErrNotFound
+	}
+	delete(r.rates, key)
+	return nil
+}
@@ -0,0 +1,82 @@
+package repositories
+
+import (
+	"context"
+	"server-cookie/apperrors"
+	"server-cookie/models"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MemoryImportJobRepository adalah implementasi ImportJobRepository di memory
+type MemoryImportJobRepository struct {
+	mu   sync.RWMutex
+	jobs map[uuid.UUID]models.ImportJob
+}
+
+var _ ImportJobRepository = (*MemoryImportJobRepository)(nil)
+
+// NewMemoryImportJobRepository membuat ImportJobRepository kosong di memory
+func NewMemoryImportJobRepository() *MemoryImportJobRepository {
+	return &MemoryImportJobRepository{jobs: make(map[uuid.UUID]models.ImportJob)}
+}
+
+func (r *MemoryImportJobRepository) Create(ctx context.Context, job *models.ImportJob) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Sama seperti hook BeforeCreate pada GORM
+	job.Id = uuid.New()
+	now := time.Now()
+	job.CreatedAt, job.UpdatedAt = now, now
+	r.jobs[job.Id] = cloneJob(*job)
+	return nil
+}
+
+func (r *MemoryImportJobRepository) Update(ctx context.Context, job *models.ImportJob) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	job.UpdatedAt = time.Now()
+	r.jobs[job.Id] = cloneJob(*job)
+	return nil
+}
+
+func (r *MemoryImportJobRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.ImportJob, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	job, ok := r.jobs[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	job = cloneJob(job)
+	return &job, nil
+}
+
+func (r *MemoryImportJobRepository) FailUnfinished(ctx context.Context, code apperrors.Code) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	var failed int64
+	for id, job := range r.jobs {
+		if job.Finished() {
+			continue
+		}
+		job.Status, job.ErrorCode = models.ImportFailed, code
+		job.FinishedAt, job.UpdatedAt = &now, now
+		r.jobs[id] = job
+		failed++
+	}
+	return failed, nil
+}
+
+// cloneJob menyalin daftar error agar job yang disimpan tidak ikut berubah
+func cloneJob(job models.ImportJob) models.ImportJob {
+	job.Errors = slices.Clone(job.Errors)
+	return job
+}
@@ -0,0 +1,265 @@
+package repositories
+
+import (
+	"context"
+	"server-cookie/models"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MemoryInventoryRepository adalah implementasi InventoryRepository di memory.
+// Semua perubahan dilakukan di bawah satu lock, setara dengan update bersyarat di database.
+type MemoryInventoryRepository struct {
+	mu           sync.Mutex
+	levels       map[StockItem]models.StockLevel
+	movements    []models.InventoryMovement
+	reservations map[uuid.UUID]models.StockReservation
+}
+
+var _ InventoryRepository = (*MemoryInventoryRepository)(nil)
+
+// NewMemoryInventoryRepository membuat InventoryRepository kosong di memory
+func NewMemoryInventoryRepository() *MemoryInventoryRepository {
+	return &MemoryInventoryRepository{
+		levels:       make(map[StockItem]models.StockLevel),
+		reservations: make(map[uuid.UUID]models.StockReservation),
+	}
+}
+
+func (r *MemoryInventoryRepository) Levels(ctx context.Context, productIDs []uuid.UUID) ([]models.StockLevel, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var levels []models.StockLevel
+	for item, level := range r.levels {
+		if slices.Contains(productIDs, item.ProductID) {
+			levels = append(levels, level)
+		}
+	}
+	return levels, nil
+}
+
+func (r *MemoryInventoryRepository) LowStockProductIDs(ctx context.Context) ([]uuid.UUID, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	ids := []uuid.UUID{}
+	for item, level := range r.levels {
+		if level.Low() && !slices.Contains(ids, item.ProductID) {
+			ids = append(ids, item.ProductID)
+		}
+	}
+	return ids, nil
+}
+
+func (r *MemoryInventoryRepository) SetThreshold(ctx context.Context, item StockItem, threshold int64) (*models.StockLevel, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	level := r.levelOf(item)
+	level.LowStockThreshold = threshold
+	level.UpdatedAt = time.Now()
+	r.levels[item] = level
+	return &level, nil
+}
+
+func (r *MemoryInventoryRepository) Adjust(ctx context.Context, movement *models.InventoryMovement) (*models.StockLevel, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	item := StockItem{ProductID: movement.ProductId, VariantID: movement.VariantId}
+	level := r.levelOf(item)
+	if level.OnHand+movement.Delta < level.Reserved {
+		return nil, ErrInsufficientStock
+	}
+	level.OnHand += movement.Delta
+	level.UpdatedAt = time.Now()
+	r.levels[item] = level
+
+	movement.Balance = level.OnHand
+	r.addMovement(movement)
+	return &level, nil
+}
+
+func (r *MemoryInventoryRepository) Movements(ctx context.Context, productID uuid.UUID, variantID *uuid.UUID, page, limit int) ([]models.InventoryMovement, int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Ledger disimpan urut waktu, dibalik agar yang terbaru lebih dulu
+	var matched []models.InventoryMovement
+	for i := len(r.movements) - 1; i >= 0; i-- {
+		movement := r.movements[i]
+		if movement.ProductId != productID || (variantID != nil && movement.VariantId != *variantID) {
+			continue
+		}
+		matched = append(matched, movement)
+	}
+
+	start := min((page-1)*limit, len(matched))
+	end := min(start+limit, len(matched))
+	return matched[start:end], int64(len(matched)), nil
+}
+
+func (r *MemoryInventoryRepository) Prune(ctx context.Context, productID uuid.UUID, keep []uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for id, reservation := range r.reservations {
+		if reservation.ProductId == productID && reservation.Status == models.ReservationPending && !slices.Contains(keep, reservation.VariantId) {
+			reservation.Status = models.ReservationReleased
+			reservation.UpdatedAt = time.Now()
+			r.reservations[id] = reservation
+		}
+	}
+	for item := range r.levels {
+		if item.ProductID == productID && !slices.Contains(keep, item.VariantID) {
+			delete(r.levels, item)
+		}
+	}
+	return nil
+}
+
+// purge menghapus semua stok, ledger dan reservasi produk, dipanggil
+// MemoryProductRepository saat produknya di-purge
+func (r *MemoryInventoryRepository) purge(productID uuid.UUID) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for id, reservation := range r.reservations {
+		if reservation.ProductId == productID {
+			delete(r.reservations, id)
+		}
+	}
+	r.movements = slices.DeleteFunc(r.movements, func(movement models.InventoryMovement) bool {
+		return movement.ProductId == productID
+	})
+	for item := range r.levels {
+		if item.ProductID == productID {
+			delete(r.levels, item)
+		}
+	}
+}
+
+func (r *MemoryInventoryRepository) Reserve(ctx context.Context, reservation *models.StockReservation) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	item := StockItem{ProductID: reservation.ProductId, VariantID: reservation.VariantId}
+	level, ok := r.levels[item]
+	if !ok || level.Available() < reservation.Quantity {
+		return ErrInsufficientStock
+	}
+	level.Reserved += reservation.Quantity
+	level.UpdatedAt = time.Now()
+	r.levels[item] = level
+
+	// Sama seperti hook BeforeCreate pada GORM
+	if reservation.Id == uuid.Nil {
+		reservation.Id = uuid.New()
+	}
+	reservation.Status = models.ReservationPending
+	reservation.CreatedAt = time.Now()
+	reservation.UpdatedAt = reservation.CreatedAt
+	r.reservations[reservation.Id] = *reservation
+	return nil
+}
+
+func (r *MemoryInventoryRepository) FindReservation(ctx context.Context, id uuid.UUID) (*models.StockReservation, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	reservation, ok := r.reservations[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &reservation, nil
+}
+
+func (r *MemoryInventoryRepository) Confirm(ctx context.Context, reservation *models.StockReservation, now time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.reservations[reservation.Id]
+	if !ok || stored.Status != models.ReservationPending || !stored.ExpiresAt.After(now) {
+		return ErrReservationClosed
+	}
+	item := StockItem{ProductID: stored.ProductId, VariantID: stored.VariantId}
+	level, ok := r.levels[item]
+	if !ok {
+		return ErrReservationClosed
+	}
+	level.OnHand -= stored.Quantity
+	level.Reserved -= stored.Quantity
+	level.UpdatedAt = time.Now()
+	r.levels[item] = level
+
+	stored.Status = models.ReservationConfirmed
+	stored.UpdatedAt = time.Now()
+	r.reservations[stored.Id] = stored
+	movement := saleMovement(stored, level.OnHand)
+	r.addMovement(&movement)
+
+	reservation.Status = stored.Status
+	return nil
+}
+
+func (r *MemoryInventoryRepository) Release(ctx context.Context, reservation *models.StockReservation, status string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.reservations[reservation.Id]
+	if !ok || stored.Status != models.ReservationPending {
+		return ErrReservationClosed
+	}
+	item := StockItem{ProductID: stored.ProductId, VariantID: stored.VariantId}
+	if level, ok := r.levels[item]; ok {
+		level.Reserved -= stored.Quantity
+		level.UpdatedAt = time.Now()
+		r.levels[item] = level
+	}
+
+	stored.Status = status
+	stored.UpdatedAt = time.Now()
+	r.reservations[stored.Id] = stored
+	reservation.Status = status
+	return nil
+}
+
+func (r *MemoryInventoryRepository) ListExpired(ctx context.Context, now time.Time, limit int) ([]models.StockReservation, error) {
+	r.mu.Lock()
+	var matched []models.StockReservation
+	for _, reservation := range r.reservations {
+		if reservation.Status == models.ReservationPending && !reservation.ExpiresAt.After(now) {
+			matched = append(matched, reservation)
+		}
+	}
+	r.mu.Unlock()
+
+	sort.Slice(matched, func(i, j int) bool {
+		return matched[i].ExpiresAt.Before(matched[j].ExpiresAt)
+	})
+	return matched[:min(limit, len(matched))], nil
+}
+
+// levelOf mengambil stok item, atau stok 0 yang baru jika belum tercatat.
+// Pemanggil harus memegang lock.
+func (r *MemoryInventoryRepository) levelOf(item StockItem) models.StockLevel {
+	if level, ok := r.levels[item]; ok {
+		return level
+	}
+	return models.StockLevel{Id: uuid.New(), ProductId: item.ProductID, VariantId: item.VariantID, UpdatedAt: time.Now()}
+}
+
+// addMovement menyimpan baris ledger, pemanggil harus memegang lock
+func (r *MemoryInventoryRepository) addMovement(movement *models.InventoryMovement) {
+	// Sama seperti hook BeforeCreate pada GORM
+	if movement.Id == uuid.Nil {
+		movement.Id = uuid.New()
+	}
+	movement.CreatedAt = time.Now()
+	r.movements = append(r.movements, *movement)
+}
@@ -0,0 +1,601 @@
+package repositories
+
+import (
+	"cmp"
+	"context"
+	"maps"
+	"server-cookie/models"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// MemoryProductRepository adalah implementasi ProductRepository di memory.
+// Data User pemilik dan kategori diambil dari repository yang diberikan,
+// revisi disimpan ke revisions selama r.mu masih dipegang.
+type MemoryProductRepository struct {
+	mu         sync.RWMutex
+	products   map[uuid.UUID]models.Product
+	users      UserRepository
+	categories CategoryRepository
+	inventory  *MemoryInventoryRepository
+	revisions  *MemoryRevisionRepository
+}
+
+var _ ProductRepository = (*MemoryProductRepository)(nil)
+
+// NewMemoryProductRepository membuat ProductRepository kosong di memory. Stok di
+// inventory dan revisi di revisions ikut dihapus saat produk di-purge.
+func NewMemoryProductRepository(users UserRepository, categories CategoryRepository, inventory *MemoryInventoryRepository, revisions *MemoryRevisionRepository) *MemoryProductRepository {
+	return &MemoryProductRepository{
+		products:   make(map[uuid.UUID]models.Product),
+		users:      users,
+		categories: categories,
+		inventory:  inventory,
+		revisions:  revisions,
+	}
+}
+
+func (r *MemoryProductRepository) List(ctx context.Context, params ProductListParams) ([]models.Product, int64, error) {
+	r.mu.RLock()
+	var matched []models.Product
+	ranks := make(map[uuid.UUID]int, len(params.Ranking))
+	for rank, id := range params.Ranking {
+		ranks[id] = rank
+	}
+	ids := make(map[uuid.UUID]bool, len(params.IDs))
+	for _, id := range params.IDs {
+		ids[id] = true
+	}
+	for _, product := range r.products {
+		if product.DeletedAt.Valid {
+			continue
+		}
+		if _, ranked := ranks[product.Id]; params.Ranking != nil && !ranked {
+			continue
+		}
+		if params.IDs != nil && !ids[product.Id] {
+			continue
+		}
+		if len(params.CategoryIDs) > 0 && !hasAnyCategory(product, params.CategoryIDs) {
+			continue
+		}
+		if len(params.Tags) > 0 && !hasTags(product, params.Tags, params.AllTags) {
+			continue
+		}
+		if !matchesFilters(product, params) {
+			continue
+		}
+		matched = append(matched, product)
+	}
+	r.mu.RUnlock()
+
+	// Urutkan sesuai params.Sort, sama seperti orderProducts pada GORM
+	keys := params.sortOrDefault()
+	keyOf := func(product models.Product) ProductKey {
+		key := KeyOf(product, nil)
+		key.Rank = ranks[product.Id]
+		return key
+	}
+	sort.Slice(matched, func(i, j int) bool {
+		return lessKey(keyOf(matched[i]), keyOf(matched[j]), keys)
+	})
+
+	var total int64
+	if !params.SkipCount {
+		total = int64(len(matched))
+	}
+
+	start := min(params.Offset(), len(matched))
+	end := min(start+params.fetchLimit(), len(matched))
+	if params.Cursor != nil {
+		// Keyset: cari posisi kunci cursor di hasil yang sudah terurut
+		key := params.Cursor.Key
+		if params.Cursor.Backward {
+			end = sort.Search(len(matched), func(i int) bool { return !lessKey(keyOf(matched[i]), key, keys) })
+			start = max(end-params.fetchLimit(), 0)
+		} else {
+			start = sort.Search(len(matched), func(i int) bool { return lessKey(key, keyOf(matched[i]), keys) })
+			end = min(start+params.fetchLimit(), len(matched))
+		}
+	}
+
+	page := matched[start:end]
+	for i := range page {
+		r.loadRelations(ctx, &page[i], !params.WithoutUser)
+	}
+	return page, total, nil
+}
+
+func (r *MemoryProductRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.Product, error) {
+	r.mu.RLock()
+	product, ok := r.products[id]
+	r.mu.RUnlock()
+
+	if !ok || product.DeletedAt.Valid {
+		return nil, ErrNotFound
+	}
+	r.loadRelations(ctx, &product, true)
+	return &product, nil
+}
+
+func (r *MemoryProductRepository) Create(ctx context.Context, product *models.Product, revision *models.ProductRevision) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.externalIDTaken(*product) {
+		return ErrExternalIDTaken
+	}
+	// Sama seperti hook BeforeCreate pada GORM
+	product.Id = uuid.New()
+	product.Version = 1
+	if product.Status == "" {
+		product.Status = models.ProductPublished
+	}
+	now := time.Now()
+	product.CreatedAt = now
+	product.UpdatedAt = now
+	stored := r.stripRelations(*product)
+	r.products[product.Id] = stored
+	product.Images, product.Options, product.Variants = stored.Images, stored.Options, stored.Variants
+
+	r.loadRelations(ctx, product, true)
+	r.addRevision(*product, revision)
+	return nil
+}
+
+func (r *MemoryProductRepository) Update(ctx context.Context, product *models.Product, revision *models.ProductRevision) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.current(*product) {
+		return ErrVersionConflict
+	}
+	if r.externalIDTaken(*product) {
+		return ErrExternalIDTaken
+	}
+	r.update(product, time.Now())
+	r.loadRelations(ctx, product, true)
+	r.addRevision(*product, revision)
+	return nil
+}
+
+func (r *MemoryProductRepository) Delete(ctx context.Context, product *models.Product, revision *models.ProductRevision) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.current(*product) {
+		return ErrVersionConflict
+	}
+	r.delete(product, time.Now())
+	r.addRevision(*product, revision)
+	return nil
+}
+
+func (r *MemoryProductRepository) SaveBatch(ctx context.Context, changes []ProductChange) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Semua versi dicek sebelum ada yang disimpan, sama seperti rollback transaksi
+	for i, change := range changes {
+		if !r.current(*change.Product) {
+			return &BatchError{Index: i, Err: ErrVersionConflict}
+		}
+		if !change.Delete && r.externalIDTaken(*change.Product) {
+			return &BatchError{Index: i, Err: ErrExternalIDTaken}
+		}
+	}
+	now := time.Now()
+	for _, change := range changes {
+		if change.Delete {
+			r.delete(change.Product, now)
+		} else {
+			r.update(change.Product, now)
+			r.loadRelations(ctx, change.Product, true)
+		}
+		r.addRevision(*change.Product, change.Revision)
+	}
+	return nil
+}
+
+// externalIDTaken meniru unique index idx_products_user_external: produk lain
+// milik pemilik yang sama, termasuk yang di trash, tidak boleh memakai ExternalId
+// yang sama. Pemanggil harus memegang r.mu.
+func (r *MemoryProductRepository) externalIDTaken(product models.Product) bool {
+	if product.ExternalId == nil {
+		return false
+	}
+	for _, other := range r.products {
+		if other.Id != product.Id && other.UserId == product.UserId && other.ExternalId != nil && *other.ExternalId == *product.ExternalId {
+			return true
+		}
+	}
+	return false
+}
+
+// addRevision menyimpan revisi dengan snapshot produk setelah perubahan.
+// Pemanggil harus memegang r.mu agar revisi tersimpan bersama perubahannya.
+func (r *MemoryProductRepository) addRevision(product models.Product, revision *models.ProductRevision) {
+	if revision == nil {
+		return
+	}
+	revision.ProductId = product.Id
+	revision.Snapshot = models.NewProductSnapshot(product)
+	r.revisions.add(revision)
+}
+
+// current mengembalikan true jika produk aktif dan versinya masih sama dengan yang tersimpan.
+// Pemanggil harus memegang r.mu.
+func (r *MemoryProductRepository) current(product models.Product) bool {
+	stored, ok := r.products[product.Id]
+	return ok && !stored.DeletedAt.Valid && stored.Version == product.Version
+}
+
+// update menyimpan produk dan menaikkan versinya, pemanggil harus memegang r.mu
+func (r *MemoryProductRepository) update(product *models.Product, now time.Time) {
+	product.Version++
+	product.UpdatedAt = now
+	stored := r.stripRelations(*product)
+	r.products[product.Id] = stored
+	product.Images, product.Options, product.Variants = stored.Images, stored.Options, stored.Variants
+}
+
+// delete memindahkan produk ke trash, pemanggil harus memegang r.mu
+func (r *MemoryProductRepository) delete(product *models.Product, now time.Time) {
+	stored := r.products[product.Id]
+	stored.DeletedAt = gorm.DeletedAt{Time: now, Valid: true}
+	stored.Version++
+	r.products[product.Id] = stored
+	product.DeletedAt, product.Version = stored.DeletedAt, stored.Version
+}
+
+func (r *MemoryProductRepository) ListTrashed(ctx context.Context, ownerID uuid.UUID, params ProductListParams) ([]models.Product, int64, error) {
+	r.mu.RLock()
+	var matched []models.Product
+	for _, product := range r.products {
+		if product.DeletedAt.Valid && product.UserId == ownerID {
+			matched = append(matched, product)
+		}
+	}
+	r.mu.RUnlock()
+
+	// Urutkan berdasarkan deleted_at DESC
+	sort.Slice(matched, func(i, j int) bool {
+		return matched[i].DeletedAt.Time.After(matched[j].DeletedAt.Time)
+	})
+
+	total := int64(len(matched))
+	start := min(params.Offset(), len(matched))
+	end := min(start+params.Limit, len(matched))
+
+	page := matched[start:end]
+	for i := range page {
+		r.loadRelations(ctx, &page[i], true)
+	}
+	return page, total, nil
+}
+
+func (r *MemoryProductRepository) FindTrashedByID(ctx context.Context, id uuid.UUID) (*models.Product, error) {
+	r.mu.RLock()
+	product, ok := r.products[id]
+	r.mu.RUnlock()
+
+	if !ok || !product.DeletedAt.Valid {
+		return nil, ErrNotFound
+	}
+	r.loadRelations(ctx, &product, true)
+	return &product, nil
+}
+
+func (r *MemoryProductRepository) Restore(ctx context.Context, product *models.Product, revision *models.ProductRevision) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.products[product.Id]
+	if !ok || !stored.DeletedAt.Valid {
+		return ErrNotFound
+	}
+	stored.DeletedAt = gorm.DeletedAt{}
+	stored.UpdatedAt = time.Now()
+	stored.Version++
+	r.products[product.Id] = stored
+	product.DeletedAt, product.Version = stored.DeletedAt, stored.Version
+	product.UpdatedAt = stored.UpdatedAt
+	r.addRevision(*product, revision)
+	return nil
+}
+
+func (r *MemoryProductRepository) Purge(ctx context.Context, product *models.Product) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.products, product.Id)
+	r.inventory.purge(product.Id)
+	r.revisions.purge(product.Id)
+	return nil
+}
+
+func (r *MemoryProductRepository) ListTrashedBefore(ctx context.Context, before time.Time, limit int) ([]models.Product, error) {
+	r.mu.RLock()
+	var matched []models.Product
+	for _, product := range r.products {
+		if product.DeletedAt.Valid && product.DeletedAt.Time.Before(before) {
+			matched = append(matched, product)
+		}
+	}
+	r.mu.RUnlock()
+
+	sort.Slice(matched, func(i, j int) bool {
+		return matched[i].DeletedAt.Time.Before(matched[j].DeletedAt.Time)
+	})
+	return matched[:min(limit, len(matched))], nil
+}
+
+func (r *MemoryProductRepository) ListScheduledBefore(ctx context.Context, before time.Time, limit int) ([]models.Product, error) {
+	due := func(at *time.Time) bool { return at != nil && at.Before(before) }
+	r.mu.RLock()
+	var matched []models.Product
+	for _, product := range r.products {
+		if product.DeletedAt.Valid {
+			continue
+		}
+		if (product.Status == models.ProductDraft && due(product.PublishAt)) ||
+			(product.Status == models.ProductPublished && due(product.UnpublishAt)) {
+			matched = append(matched, product)
+		}
+	}
+	r.mu.RUnlock()
+
+	sort.Slice(matched, func(i, j int) bool {
+		return lessKey(KeyOf(matched[i], nil), KeyOf(matched[j], nil), []ProductSort{{Field: SortByCreatedAt}})
+	})
+	matched = matched[:min(limit, len(matched))]
+	for i := range matched {
+		r.loadRelations(ctx, &matched[i], true)
+	}
+	return matched, nil
+}
+
+func (r *MemoryProductRepository) FindByExternalID(ctx context.Context, ownerID uuid.UUID, externalID string) (*models.Product, error) {
+	r.mu.RLock()
+	var found *models.Product
+	for _, product := range r.products {
+		if product.UserId == ownerID && product.ExternalId != nil && *product.ExternalId == externalID {
+			found = &product
+			break
+		}
+	}
+	r.mu.RUnlock()
+
+	if found == nil {
+		return nil, ErrNotFound
+	}
+	r.loadRelations(ctx, found, true)
+	return found, nil
+}
+
+func (r *MemoryProductRepository) FindVariantBySKU(ctx context.Context, sku string) (*models.ProductVariant, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, product := range r.products {
+		for _, variant := range product.Variants {
+			if variant.SKU == sku {
+				return &variant, nil
+			}
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (r *MemoryProductRepository) TagCounts(ctx context.Context) ([]models.TagCount, error) {
+	r.mu.RLock()
+	counts := make(map[string]int64)
+	for _, product := range r.products {
+		if product.DeletedAt.Valid || product.Status != models.ProductPublished {
+			continue
+		}
+		for _, tag := range product.Tags {
+			counts[tag.Name]++
+		}
+	}
+	r.mu.RUnlock()
+
+	result := make([]models.TagCount, 0, len(counts))
+	for name, count := range counts {
+		result = append(result, models.TagCount{Name: name, Count: count})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Count != result[j].Count {
+			return result[i].Count > result[j].Count
+		}
+		return result[i].Name < result[j].Name
+	})
+	return result, nil
+}
+
+// stripRelations hanya menyimpan ID kategori serta salinan relasi lainnya agar data yang disimpan tidak basi.
+// Stok tidak disimpan karena dikelola InventoryRepository.
+func (r *MemoryProductRepository) stripRelations(product models.Product) models.Product {
+	product.User = models.User{}
+	product.Stock = nil
+	categories := make([]models.Category, 0, len(product.Categories))
+	for _, category := range product.Categories {
+		categories = append(categories, models.Category{Id: category.Id})
+	}
+	product.Categories = categories
+	product.Tags = append([]models.Tag(nil), product.Tags...)
+	images := make([]models.ProductImage, len(product.Images))
+	for i, image := range product.Images {
+		// Sama seperti hook BeforeCreate pada GORM
+		if image.Id == uuid.Nil {
+			image.Id = uuid.New()
+		}
+		if image.CreatedAt.IsZero() {
+			image.CreatedAt = time.Now()
+		}
+		image.ProductId = product.Id
+		images[i] = image
+	}
+	product.Images = images
+
+	options := make([]models.ProductOption, len(product.Options))
+	for i, option := range product.Options {
+		if option.Id == uuid.Nil {
+			option.Id = uuid.New()
+		}
+		option.ProductId = product.Id
+		option.Values = slices.Clone(option.Values)
+		options[i] = option
+	}
+	product.Options = options
+
+	variants := make([]models.ProductVariant, len(product.Variants))
+	for i, variant := range product.Variants {
+		if variant.Id == uuid.Nil {
+			variant.Id = uuid.New()
+		}
+		if variant.CreatedAt.IsZero() {
+			variant.CreatedAt = time.Now()
+			variant.UpdatedAt = variant.CreatedAt
+		}
+		variant.ProductId = product.Id
+		variant.Attributes = maps.Clone(variant.Attributes)
+		variants[i] = variant
+	}
+	product.Variants = variants
+	return product
+}
+
+// loadRelations mengisi data User pemilik, kategori, tag, gambar, option dan varian produk, mirip Preload.
+// Kategori yang sudah dihapus tidak ikut dimuat.
+func (r *MemoryProductRepository) loadRelations(ctx context.Context, product *models.Product, withUser bool) {
+	product.User = models.User{}
+	if withUser {
+		if user, err := r.users.FindByID(ctx, product.UserId); err == nil {
+			product.User = *user
+		}
+	}
+
+	categories := make([]models.Category, 0, len(product.Categories))
+	for _, category := range product.Categories {
+		if loaded, err := r.categories.FindByID(ctx, category.Id); err == nil {
+			categories = append(categories, *loaded)
+		}
+	}
+	sort.Slice(categories, func(i, j int) bool {
+		return categories[i].Name < categories[j].Name
+	})
+	product.Categories = categories
+
+	tags := append([]models.Tag(nil), product.Tags...)
+	sort.Slice(tags, func(i, j int) bool {
+		return tags[i].Name < tags[j].Name
+	})
+	product.Tags = tags
+
+	images := append([]models.ProductImage(nil), product.Images...)
+	sort.Slice(images, func(i, j int) bool {
+		return images[i].Position < images[j].Position
+	})
+	product.Images = images
+
+	options := append([]models.ProductOption(nil), product.Options...)
+	sort.Slice(options, func(i, j int) bool {
+		return options[i].Position < options[j].Position
+	})
+	product.Options = options
+
+	variants := append([]models.ProductVariant(nil), product.Variants...)
+	sort.Slice(variants, func(i, j int) bool {
+		return variants[i].Position < variants[j].Position
+	})
+	product.Variants = variants
+}
+
+func hasAnyCategory(product models.Product, ids []uuid.UUID) bool {
+	for _, category := range product.Categories {
+		for _, id := range ids {
+			if category.Id == id {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// hasTags mengecek apakah produk punya salah satu tag, atau semua tag jika all
+func hasTags(product models.Product, tags []string, all bool) bool {
+	found := 0
+	for _, want := range tags {
+		for _, tag := range product.Tags {
+			if tag.Name == want {
+				found++
+				break
+			}
+		}
+	}
+	if all {
+		return found == len(tags)
+	}
+	return found > 0
+}
+
+// matchesFilters sama seperti filterProducts pada GORM
+func matchesFilters(product models.Product, params ProductListParams) bool {
+	switch {
+	case params.Currency != "" && product.Price.Currency != params.Currency:
+		return false
+	case params.MinPrice != nil && product.PriceMax < *params.MinPrice:
+		return false
+	case params.MaxPrice != nil && product.PriceMin > *params.MaxPrice:
+		return false
+	case params.OwnerID != nil && product.UserId != *params.OwnerID:
+		return false
+	case params.Statuses != nil && !slices.Contains(params.Statuses, product.Status):
+		return false
+	case params.CreatedAfter != nil && product.CreatedAt.Before(*params.CreatedAfter):
+		return false
+	case params.CreatedBefore != nil && !product.CreatedAt.Before(*params.CreatedBefore):
+		return false
+	case params.HasImage != nil && (len(product.Images) > 0) != *params.HasImage:
+		return false
+	}
+	return true
+}
+
+// lessKey membandingkan dua kunci produk berdasarkan kunci sort, lalu Id
+func lessKey(a, b ProductKey, keys []ProductSort) bool {
+	for _, key := range keys {
+		order := compareKeys(a, b, key.Field)
+		if order == 0 {
+			continue
+		}
+		if key.Desc {
+			return order > 0
+		}
+		return order < 0
+	}
+	return a.Id.String() < b.Id.String()
+}
+
+func compareKeys(a, b ProductKey, field ProductSortField) int {
+	switch field {
+	case SortByName:
+		return strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+	case SortByPrice:
+		return cmp.Compare(a.Price, b.Price)
+	case SortByCreatedAt:
+		return a.CreatedAt.Compare(b.CreatedAt)
+	case SortByUpdatedAt:
+		return a.UpdatedAt.Compare(b.UpdatedAt)
+	case SortByRelevance:
+		return cmp.Compare(a.Rank, b.Rank)
+	}
+	return 0
+}
@@ -0,0 +1,77 @@
+package repositories
+
+import (
+	"context"
+	"server-cookie/models"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MemoryRevisionRepository adalah implementasi ProductRevisionRepository di memory
+type MemoryRevisionRepository struct {
+	mu sync.RWMutex
+	// revisions per produk, urut berdasarkan Number
+	revisions map[uuid.UUID][]models.ProductRevision
+}
+
+var _ ProductRevisionRepository = (*MemoryRevisionRepository)(nil)
+
+// NewMemoryRevisionRepository membuat ProductRevisionRepository kosong di memory
+func NewMemoryRevisionRepository() *MemoryRevisionRepository {
+	return &MemoryRevisionRepository{revisions: make(map[uuid.UUID][]models.ProductRevision)}
+}
+
+func (r *MemoryRevisionRepository) List(ctx context.Context, productID uuid.UUID, page, limit int) ([]models.ProductRevision, int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	all := r.revisions[productID]
+	revisions := make([]models.ProductRevision, 0, limit)
+	for i := len(all) - 1 - (page-1)*limit; i >= 0 && len(revisions) < limit; i-- {
+		revision := all[i]
+		revision.Snapshot = models.ProductSnapshot{}
+		revisions = append(revisions, revision)
+	}
+	return revisions, int64(len(all)), nil
+}
+
+func (r *MemoryRevisionRepository) Find(ctx context.Context, productID uuid.UUID, number int) (*models.ProductRevision, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	revisions := r.revisions[productID]
+	if number < 1 || number > len(revisions) {
+		return nil, ErrNotFound
+	}
+	revision := revisions[number-1]
+	return &revision, nil
+}
+
+func (r *MemoryRevisionRepository) ImagePaths(ctx context.Context, productID uuid.UUID) ([]string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return revisionImagePaths(r.revisions[productID]), nil
+}
+
+// purge menghapus semua revisi produk, dipanggil MemoryProductRepository
+// saat produknya di-purge
+func (r *MemoryRevisionRepository) purge(productID uuid.UUID) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.revisions, productID)
+}
+
+// add menyimpan revisi dengan Number satu setelah revisi terakhir produknya,
+// dipanggil MemoryProductRepository bersama perubahan produknya
+func (r *MemoryRevisionRepository) add(revision *models.ProductRevision) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	revisions := r.revisions[revision.ProductId]
+	revision.Id = uuid.New()
+	revision.Number = len(revisions) + 1
+	revision.CreatedAt = time.Now()
+	r.revisions[revision.ProductId] = append(revisions, *revision)
+}
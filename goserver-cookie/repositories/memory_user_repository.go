@@ -0,0 +1,117 @@
+package repositories
+
+import (
+	"context"
+	"server-cookie/models"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MemoryUserRepository adalah implementasi UserRepository di memory,
+// dipakai untuk testing dan pengembangan tanpa database
+type MemoryUserRepository struct {
+	mu    sync.RWMutex
+	users map[uuid.UUID]models.User
+}
+
+var _ UserRepository = (*MemoryUserRepository)(nil)
+
+// NewMemoryUserRepository membuat UserRepository kosong di memory
+func NewMemoryUserRepository() *MemoryUserRepository {
+	return &MemoryUserRepository{users: make(map[uuid.UUID]models.User)}
+}
+
+func (r *MemoryUserRepository) Create(ctx context.Context, user *models.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.usernameTaken(*user) {
+		return ErrUsernameTaken
+	}
+	// Sama seperti hook BeforeCreate pada GORM
+	user.Id = uuid.New()
+	now := time.Now()
+	user.CreatedAt = now
+	user.UpdatedAt = now
+	// Sama seperti default kolom role
+	if user.Role == "" {
+		user.Role = models.RoleUser
+	}
+	r.users[user.Id] = *user
+	return nil
+}
+
+func (r *MemoryUserRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	user, ok := r.users[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &user, nil
+}
+
+func (r *MemoryUserRepository) FindByIDs(ctx context.Context, ids []uuid.UUID) ([]models.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	users := make([]models.User, 0, len(ids))
+	for _, id := range ids {
+		if user, ok := r.users[id]; ok {
+			users = append(users, user)
+		}
+	}
+	return users, nil
+}
+
+func (r *MemoryUserRepository) FindByUsername(ctx context.Context, username string) (*models.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, user := range r.users {
+		if user.Username == username {
+			return &user, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (r *MemoryUserRepository) ExistsByUsernameOrEmail(ctx context.Context, username, email string) (bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, user := range r.users {
+		if user.Username == username || user.Email == email {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *MemoryUserRepository) Update(ctx context.Context, user *models.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.users[user.Id]; !ok {
+		return ErrNotFound
+	}
+	if r.usernameTaken(*user) {
+		return ErrUsernameTaken
+	}
+	user.UpdatedAt = time.Now()
+	r.users[user.Id] = *user
+	return nil
+}
+
+// usernameTaken meniru unique index username. Pemanggil harus memegang r.mu.
+func (r *MemoryUserRepository) usernameTaken(user models.User) bool {
+	for _, other := range r.users {
+		if other.Id != user.Id && other.Username == user.Username {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,107 @@
+package repositories
+
+import (
+	"server-cookie/models"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ProductSortField adalah field produk yang boleh dipakai untuk mengurutkan list
+type ProductSortField string
+
+const (
+	SortByName      ProductSortField = "name"
+	SortByPrice     ProductSortField = "price"
+	SortByCreatedAt ProductSortField = "created_at"
+	SortByUpdatedAt ProductSortField = "updated_at"
+	// SortByRelevance mengikuti ProductListParams.Ranking, hanya bisa dipakai saat mencari
+	SortByRelevance ProductSortField = "relevance"
+)
+
+// ProductSortFields adalah whitelist field untuk parameter sort
+var ProductSortFields = []ProductSortField{SortByName, SortByPrice, SortByCreatedAt, SortByUpdatedAt, SortByRelevance}
+
+// Valid memeriksa apakah field ada di whitelist
+func (f ProductSortField) Valid() bool {
+	for _, field := range ProductSortFields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// ProductSort adalah satu kunci pengurutan produk
+type ProductSort struct {
+	Field ProductSortField
+	Desc  bool
+}
+
+// DefaultProductSort dipakai jika ProductListParams.Sort kosong, produk terbaru lebih dulu
+var DefaultProductSort = []ProductSort{{Field: SortByCreatedAt, Desc: true}}
+
+// sortOrDefault mengembalikan urutan yang dipakai untuk query
+func (p ProductListParams) sortOrDefault() []ProductSort {
+	if len(p.Sort) == 0 {
+		return DefaultProductSort
+	}
+	return p.Sort
+}
+
+// ProductKey adalah nilai kunci sort satu produk, dipakai sebagai posisi keyset
+type ProductKey struct {
+	Id        uuid.UUID `json:"id"`
+	Name      string    `json:"name,omitempty"`
+	Price     int64     `json:"price,omitempty"` // PriceMin, sama seperti sort price
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	// Rank adalah posisi produk di Ranking, hanya dipakai untuk SortByRelevance
+	Rank int `json:"rank,omitempty"`
+}
+
+// KeyOf mengambil kunci sort dari produk, ranking boleh nil jika tidak mencari
+func KeyOf(product models.Product, ranking []uuid.UUID) ProductKey {
+	return ProductKey{
+		Id:        product.Id,
+		Name:      product.Name,
+		Price:     product.PriceMin,
+		CreatedAt: product.CreatedAt,
+		UpdatedAt: product.UpdatedAt,
+		Rank:      rankOf(ranking, product.Id),
+	}
+}
+
+// rankOf mengembalikan posisi id di ranking, atau len(ranking) jika tidak ada
+func rankOf(ranking []uuid.UUID, id uuid.UUID) int {
+	if rank := slices.Index(ranking, id); rank >= 0 {
+		return rank
+	}
+	return len(ranking)
+}
+
+// ProductCursor adalah posisi keyset: hasil dimulai tepat setelah Key sesuai urutan Sort,
+// atau tepat sebelum Key jika Backward. Hasil tetap dikembalikan dalam urutan Sort,
+// sehingga dengan Backward dan LookAhead produk tambahan ada di awal hasil.
+type ProductCursor struct {
+	Key      ProductKey
+	Backward bool
+}
+
+// fetchLimit adalah jumlah baris yang diambil, termasuk satu baris tambahan jika LookAhead
+func (p ProductListParams) fetchLimit() int {
+	if p.LookAhead {
+		return p.Limit + 1
+	}
+	return p.Limit
+}
+
+// reverseSort membalik arah setiap kunci sort, untuk mengambil halaman sebelumnya
+func reverseSort(sort []ProductSort) []ProductSort {
+	reversed := make([]ProductSort, len(sort))
+	for i, key := range sort {
+		reversed[i] = ProductSort{Field: key.Field, Desc: !key.Desc}
+	}
+	return reversed
+}
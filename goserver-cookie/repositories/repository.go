@@ -0,0 +1,246 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"server-cookie/apperrors"
+	"server-cookie/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Error penyimpanan yang dicek dengan errors.Is
+var (
+	// ErrNotFound dikembalikan jika data tidak ditemukan di penyimpanan
+	ErrNotFound = errors.New("record not found")
+	// ErrInsufficientStock dikembalikan jika stok tidak cukup untuk perubahan yang diminta
+	ErrInsufficientStock = errors.New("insufficient stock")
+	// ErrReservationClosed dikembalikan jika reservasi sudah tidak pending atau sudah kedaluwarsa
+	ErrReservationClosed = errors.New("reservation is not pending")
+	// ErrVersionConflict dikembalikan jika data sudah diubah request lain sejak dibaca
+	ErrVersionConflict = errors.New("version conflict")
+	// ErrUsernameTaken dikembalikan jika username sudah dipakai user lain
+	ErrUsernameTaken = errors.New("username already used by another user")
+	// ErrExternalIDTaken dikembalikan jika pemilik sudah punya produk lain (termasuk
+	// di trash) dengan ExternalId yang sama
+	ErrExternalIDTaken = errors.New("external id already used by another product")
+)
+
+// UserRepository adalah akses data untuk models.User
+type UserRepository interface {
+	Create(ctx context.Context, user *models.User) error
+	FindByID(ctx context.Context, id uuid.UUID) (*models.User, error)
+	FindByIDs(ctx context.Context, ids []uuid.UUID) ([]models.User, error)
+	FindByUsername(ctx context.Context, username string) (*models.User, error)
+	ExistsByUsernameOrEmail(ctx context.Context, username, email string) (bool, error)
+	Update(ctx context.Context, user *models.User) error
+}
+
+// ProductListParams adalah parameter pagination dan pencarian produk
+type ProductListParams struct {
+	Page  int
+	Limit int
+
+	// Ranking membatasi produk pada ID hasil pencarian full-text jika tidak nil.
+	// Urutannya (paling relevan lebih dulu) dipakai untuk sort SortByRelevance.
+	Ranking []uuid.UUID
+
+	// IDs membatasi produk pada ID ini jika tidak nil, misalnya produk dengan stok menipis
+	IDs []uuid.UUID
+
+	// CategoryIDs membatasi produk yang punya salah satu kategori ini
+	CategoryIDs []uuid.UUID
+	// Tags membatasi produk yang punya salah satu tag, atau semuanya jika AllTags
+	Tags    []string
+	AllTags bool
+
+	// Filter mata uang, harga (inklusif), pemilik, waktu dibuat dan ada tidaknya gambar.
+	// Filter harga dalam minor unit dan cocok dengan produk yang rentang harga variannya
+	// beririsan. CreatedAfter inklusif sedangkan CreatedBefore eksklusif.
+	Currency      string
+	MinPrice      *int64
+	MaxPrice      *int64
+	OwnerID       *uuid.UUID
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	HasImage      *bool
+	// Statuses membatasi produk pada status ini, nil berarti semua status
+	Statuses []string
+
+	// Sort adalah urutan hasil, DefaultProductSort jika kosong.
+	// Id selalu dipakai sebagai kunci terakhir agar urutan stabil antar halaman.
+	Sort []ProductSort
+
+	// Cursor mengganti pagination offset dengan keyset, Page diabaikan jika diisi
+	Cursor *ProductCursor
+	// LookAhead mengambil satu produk lebih dari Limit untuk mengetahui ada halaman berikutnya
+	LookAhead bool
+	// SkipCount tidak menghitung total produk, total yang dikembalikan 0
+	SkipCount bool
+
+	// WithoutUser tidak memuat User pemilik, untuk pemanggil yang memuatnya sendiri secara batch
+	WithoutUser bool
+}
+
+// Offset menghitung offset dari page dan limit
+func (p ProductListParams) Offset() int {
+	return (p.Page - 1) * p.Limit
+}
+
+// ProductRepository adalah akses data untuk models.Product.
+// Produk yang dikembalikan sudah berisi data User pemiliknya,
+// kecuali List dengan ProductListParams.WithoutUser, serta kategori, tag, gambar,
+// option dan variannya. Create dan Update menyimpan relasi kategori (cukup Id)
+// dan relasi lainnya sesuai isi produk.
+//
+// Delete hanya memindahkan produk ke trash (soft delete). List dan FindByID
+// tidak mengembalikan produk di trash, gunakan method *Trashed untuk itu.
+//
+// Update dan Delete hanya berhasil jika Version produk masih sama dengan yang
+// tersimpan, lalu menaikkan Version. ErrVersionConflict jika produk sudah diubah,
+// dipindah ke trash atau di-restore sejak dibaca.
+//
+// Create, Update, Delete, Restore dan SaveBatch menyimpan revision (jika tidak nil)
+// dalam transaksi yang sama dengan perubahannya. ProductId, Number dan Snapshot
+// revisi diisi dari produk setelah perubahan.
+type ProductRepository interface {
+	List(ctx context.Context, params ProductListParams) ([]models.Product, int64, error)
+	FindByID(ctx context.Context, id uuid.UUID) (*models.Product, error)
+	Create(ctx context.Context, product *models.Product, revision *models.ProductRevision) error
+	Update(ctx context.Context, product *models.Product, revision *models.ProductRevision) error
+	Delete(ctx context.Context, product *models.Product, revision *models.ProductRevision) error
+	// SaveBatch menjalankan Update atau Delete untuk setiap change dalam satu transaksi,
+	// semua tersimpan atau tidak sama sekali. Error change dibungkus *BatchError.
+	SaveBatch(ctx context.Context, changes []ProductChange) error
+
+	// ListTrashed mengambil produk di trash milik ownerID, yang terakhir dihapus lebih dulu
+	ListTrashed(ctx context.Context, ownerID uuid.UUID, params ProductListParams) ([]models.Product, int64, error)
+	FindTrashedByID(ctx context.Context, id uuid.UUID) (*models.Product, error)
+	Restore(ctx context.Context, product *models.Product, revision *models.ProductRevision) error
+	// Purge menghapus produk secara permanen, baik di trash maupun tidak, beserta
+	// stok, ledger, reservasi dan revisinya dalam satu transaksi
+	Purge(ctx context.Context, product *models.Product) error
+	// ListTrashedBefore mengambil paling banyak limit produk yang masuk trash sebelum waktu before
+	ListTrashedBefore(ctx context.Context, before time.Time, limit int) ([]models.Product, error)
+
+	// ListScheduledBefore mengambil paling banyak limit produk yang jadwalnya jatuh sebelum
+	// waktu before: draft dengan PublishAt atau published dengan UnpublishAt
+	ListScheduledBefore(ctx context.Context, before time.Time, limit int) ([]models.Product, error)
+
+	// TagCounts menghitung jumlah produk published per tag, terbanyak lebih dulu
+	TagCounts(ctx context.Context) ([]models.TagCount, error)
+	// FindVariantBySKU mencari varian dengan SKU tersebut, termasuk varian produk di trash
+	FindVariantBySKU(ctx context.Context, sku string) (*models.ProductVariant, error)
+	// FindByExternalID mengambil produk milik ownerID dengan ExternalId tersebut,
+	// termasuk produk di trash karena ExternalId tetap unik selama produk belum di-purge
+	FindByExternalID(ctx context.Context, ownerID uuid.UUID, externalID string) (*models.Product, error)
+}
+
+// ProductChange adalah satu perubahan di SaveBatch
+type ProductChange struct {
+	Product *models.Product
+	// Delete memindahkan produk ke trash, selain itu produk di-Update
+	Delete bool
+	// Revision dicatat bersama perubahan jika tidak nil
+	Revision *models.ProductRevision
+}
+
+// BatchError menunjuk change SaveBatch yang membatalkan transaksi
+type BatchError struct {
+	Index int
+	Err   error
+}
+
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("change %d: %v", e.Index, e.Err)
+}
+
+func (e *BatchError) Unwrap() error {
+	return e.Err
+}
+
+// CategoryRepository adalah akses data untuk models.Category
+type CategoryRepository interface {
+	// List mengambil semua kategori, diurutkan berdasarkan nama
+	List(ctx context.Context) ([]models.Category, error)
+	FindByID(ctx context.Context, id uuid.UUID) (*models.Category, error)
+	FindBySlug(ctx context.Context, slug string) (*models.Category, error)
+	Create(ctx context.Context, category *models.Category) error
+	Update(ctx context.Context, category *models.Category) error
+	// Delete menghapus kategori beserta relasinya ke produk
+	Delete(ctx context.Context, category *models.Category) error
+}
+
+// StockItem menunjuk stok satu varian, atau stok produk tanpa varian jika VariantID uuid.Nil
+type StockItem struct {
+	ProductID uuid.UUID
+	VariantID uuid.UUID
+}
+
+// InventoryRepository adalah akses data stok, ledger perubahan stok dan reservasi.
+// Stok diubah dengan update bersyarat yang atomik, sehingga request yang berjalan
+// bersamaan tidak bisa membuat stok tersedia (OnHand - Reserved) menjadi minus.
+type InventoryRepository interface {
+	// Levels mengambil stok yang sudah dicatat untuk produk-produk ini
+	Levels(ctx context.Context, productIDs []uuid.UUID) ([]models.StockLevel, error)
+	// LowStockProductIDs mengambil ID produk yang punya stok menipis
+	LowStockProductIDs(ctx context.Context) ([]uuid.UUID, error)
+	// SetThreshold mengubah batas stok menipis, stok dicatat dengan jumlah 0 jika belum ada
+	SetThreshold(ctx context.Context, item StockItem, threshold int64) (*models.StockLevel, error)
+	// Adjust menambah OnHand sebesar movement.Delta lalu mencatatnya di ledger.
+	// ErrInsufficientStock jika OnHand akan lebih kecil dari Reserved.
+	Adjust(ctx context.Context, movement *models.InventoryMovement) (*models.StockLevel, error)
+	// Movements mengambil ledger produk, yang terbaru lebih dulu. variantID nil berarti semua item.
+	Movements(ctx context.Context, productID uuid.UUID, variantID *uuid.UUID, page, limit int) ([]models.InventoryMovement, int64, error)
+	// Prune menghapus stok produk selain item dengan VariantID di keep,
+	// reservasi pending untuk stok yang dihapus ikut dilepas. Ledger tetap disimpan.
+	Prune(ctx context.Context, productID uuid.UUID, keep []uuid.UUID) error
+
+	// Reserve menahan stok untuk reservasi pending yang baru.
+	// ErrInsufficientStock jika stok tersedia kurang dari Quantity.
+	Reserve(ctx context.Context, reservation *models.StockReservation) error
+	FindReservation(ctx context.Context, id uuid.UUID) (*models.StockReservation, error)
+	// Confirm mengurangi OnHand dan Reserved sebesar Quantity lalu mencatat penjualan di ledger.
+	// ErrReservationClosed jika reservasi sudah tidak pending atau ExpiresAt sudah lewat now.
+	Confirm(ctx context.Context, reservation *models.StockReservation, now time.Time) error
+	// Release melepas stok reservasi pending dengan status baru (released atau expired).
+	// ErrReservationClosed jika reservasi sudah tidak pending.
+	Release(ctx context.Context, reservation *models.StockReservation, status string) error
+	// ListExpired mengambil paling banyak limit reservasi pending yang ExpiresAt-nya tidak setelah now
+	ListExpired(ctx context.Context, now time.Time, limit int) ([]models.StockReservation, error)
+}
+
+// ExchangeRateRepository adalah akses data untuk models.ExchangeRate
+type ExchangeRateRepository interface {
+	// List mengambil semua kurs, diurutkan berdasarkan Base lalu Quote
+	List(ctx context.Context) ([]models.ExchangeRate, error)
+	// Save menyimpan kurs dalam satu transaksi, kurs pasangan mata uang yang sudah ada diganti
+	Save(ctx context.Context, rates []models.ExchangeRate) error
+	// Delete menghapus kurs satu pasangan mata uang, ErrNotFound jika tidak ada
+	Delete(ctx context.Context, base, quote string) error
+}
+
+// ImportJobRepository adalah akses data untuk models.ImportJob
+type ImportJobRepository interface {
+	Create(ctx context.Context, job *models.ImportJob) error
+	// Update menyimpan progres dan hasil job
+	Update(ctx context.Context, job *models.ImportJob) error
+	// FindByID mengambil job, ErrNotFound jika tidak ada
+	FindByID(ctx context.Context, id uuid.UUID) (*models.ImportJob, error)
+	// FailUnfinished menandai job yang belum selesai sebagai failed dengan code,
+	// dipakai saat start karena job di background hilang ketika server berhenti
+	FailUnfinished(ctx context.Context, code apperrors.Code) (int64, error)
+}
+
+// ProductRevisionRepository adalah akses data untuk models.ProductRevision.
+// Revisi hanya ditambah oleh ProductRepository bersama perubahan produknya, tidak pernah diubah.
+type ProductRevisionRepository interface {
+	// List mengambil revisi produk tanpa Snapshot, yang terbaru lebih dulu
+	List(ctx context.Context, productID uuid.UUID, page, limit int) ([]models.ProductRevision, int64, error)
+	// Find mengambil satu revisi produk, ErrNotFound jika tidak ada
+	Find(ctx context.Context, productID uuid.UUID, number int) (*models.ProductRevision, error)
+	// ImagePaths mengambil path semua gambar yang tercatat di revisi produk
+	ImagePaths(ctx context.Context, productID uuid.UUID) ([]string, error)
+}
@@ -0,0 +1,147 @@
+package routes_test
+
+import (
+	"net/http"
+	"server-cookie/apperrors"
+	"testing"
+)
+
+// batchResults mengembalikan status setiap operasi batch, ditambah kode error jika gagal
+func batchResults(t *testing.T, body map[string]any) []string {
+	t.Helper()
+	items, ok := body["results"].([]any)
+	if !ok {
+		t.Fatalf("response has no results: %v", body)
+	}
+	statuses := make([]string, 0, len(items))
+	for _, item := range items {
+		result := item.(map[string]any)
+		status := result["status"].(string)
+		if problem, ok := result["error"].(map[string]any); ok {
+			status += ":" + problem["code"].(string)
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+func expectBatch(t *testing.T, body map[string]any, want ...string) {
+	t.Helper()
+	got := batchResults(t, body)
+	if len(got) != len(want) {
+		t.Fatalf("expected results %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("expected results %v, got %v", want, got)
+		}
+	}
+}
+
+func TestProductBatch(t *testing.T) {
+	for _, b := range backends {
+		t.Run(b.name, func(t *testing.T) {
+			app := newTestApp(t, b)
+			admin := signIn(t, app, "admin")
+			alice := signIn(t, app, "alice")
+			bob := signIn(t, app, "bob")
+
+			status, body := admin.json(http.MethodPost, api+"/categories", map[string]any{"name": "Cookies"})
+			expectStatus(t, status, http.StatusCreated, body)
+			category := body["category"].(map[string]any)["id"].(string)
+
+			create := func(session *testApp, username, name string) map[string]any {
+				t.Helper()
+				_, body := session.json(http.MethodPost, api+"/login", map[string]string{"username": username, "password": "secret123"})
+				userID := body["user"].(map[string]any)["id"].(string)
+				status, body := session.multipart(http.MethodPost, api+"/products", map[string]string{
+					"name": name, "price": "1000", "user_id": userID,
+				}, []byte("\xff\xd8\xff\xe0 fake jpeg"))
+				expectStatus(t, status, http.StatusOK, body)
+				return productOf(t, body)
+			}
+			first := create(alice, "alice", "First")
+			second := create(alice, "alice", "Second")
+			third := create(alice, "alice", "Third")
+			fourth := create(alice, "alice", "Fourth")
+			foreign := create(bob, "bob", "Foreign")
+			firstID, secondID, thirdID := first["id"].(string), second["id"].(string), third["id"].(string)
+
+			operations := []map[string]any{
+				{"op": "update", "id": firstID, "price": "2000", "tags": []string{"sale"}},
+				{"op": "delete", "id": secondID},
+				{"op": "categorize", "id": thirdID, "add_category_ids": []string{category}},
+			}
+
+			// Satu operasi gagal membatalkan seluruh batch atomic
+			status, body = alice.json(http.MethodPost, api+"/products/batch", map[string]any{
+				"operations": append(operations, map[string]any{"op": "delete", "id": foreign["id"]}),
+			})
+			expectStatus(t, status, http.StatusOK, body)
+			expectBatch(t, body, "skipped", "skipped", "skipped", "failed:"+string(apperrors.CodeNotProductOwner))
+			if body["mode"] != "atomic" || body["applied"] != float64(0) || body["failed"] != float64(1) {
+				t.Fatalf("unexpected batch summary: %v", body)
+			}
+			status, body = alice.json(http.MethodGet, api+"/products/"+secondID, nil)
+			expectStatus(t, status, http.StatusOK, body)
+
+			status, body = alice.json(http.MethodPost, api+"/products/batch", map[string]any{"operations": operations})
+			expectStatus(t, status, http.StatusOK, body)
+			expectBatch(t, body, "applied", "applied", "applied")
+			results := body["results"].([]any)
+			updated := results[0].(map[string]any)["product"].(map[string]any)
+			if amountOf(t, updated["price"]) != "2000.00" || updated["version"] != float64(2) {
+				t.Fatalf("unexpected updated product: %v", updated)
+			}
+			status, body = alice.json(http.MethodGet, api+"/products/"+secondID, nil)
+			expectStatus(t, status, http.StatusNotFound, body)
+			status, body = alice.json(http.MethodGet, api+"/products/"+thirdID, nil)
+			expectStatus(t, status, http.StatusOK, body)
+			if categories := productOf(t, body)["categories"].([]any); len(categories) != 1 {
+				t.Fatalf("expected category to be added, got %v", categories)
+			}
+			image := productImages(t, body)[0]["id"].(string)
+
+			// Best effort menyimpan operasi yang valid, yang lain dilaporkan per operasi
+			status, body = alice.json(http.MethodPost, api+"/products/batch", map[string]any{
+				"mode": "best_effort",
+				"operations": []map[string]any{
+					{"op": "update", "id": firstID, "expected_version": 1, "name": "Stale"},
+					{"op": "update", "id": thirdID, "name": "X"},
+					{"op": "update", "id": thirdID, "remove_image_ids": []string{image}},
+					{"op": "categorize", "id": fourth["id"], "add_category_ids": []string{category}},
+					{"op": "rename", "id": secondID},
+				},
+			})
+			expectStatus(t, status, http.StatusOK, body)
+			expectBatch(t, body,
+				"failed:"+string(apperrors.CodeVersionMismatch),
+				"failed:"+string(apperrors.CodeValidationFailed),
+				"failed:"+string(apperrors.CodeValidationFailed),
+				"applied",
+				"failed:"+string(apperrors.CodeValidationFailed),
+			)
+			if body["applied"] != float64(1) || body["failed"] != float64(4) {
+				t.Fatalf("unexpected batch summary: %v", body)
+			}
+
+			status, body = alice.json(http.MethodPost, api+"/products/batch", map[string]any{
+				"mode":       "best_effort",
+				"operations": []map[string]any{{"op": "update", "id": thirdID, "remove_image_ids": []string{image}}},
+			})
+			expectStatus(t, status, http.StatusOK, body)
+			expectBatch(t, body, "applied")
+			status, body = alice.json(http.MethodGet, api+"/products/"+thirdID, nil)
+			expectStatus(t, status, http.StatusOK, body)
+			if images := productImages(t, body); len(images) != 0 {
+				t.Fatalf("expected image to be removed, got %v", images)
+			}
+
+			status, body = alice.json(http.MethodPost, api+"/products/batch", map[string]any{"mode": "fast", "operations": operations})
+			expectStatus(t, status, http.StatusBadRequest, body)
+			expectError(t, body, apperrors.CodeValidationFailed)
+			status, body = alice.json(http.MethodPost, api+"/products/batch", map[string]any{"operations": []any{}})
+			expectStatus(t, status, http.StatusBadRequest, body)
+		})
+	}
+}
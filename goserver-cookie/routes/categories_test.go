@@ -0,0 +1,270 @@
+package routes_test
+
+import (
+	"bytes"
+	"context"
+	"mime/multipart"
+	"net/http"
+	"server-cookie/apperrors"
+	"server-cookie/models"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// multipartValues mengirim form-data dengan field yang boleh diulang dan gambar photo.jpg
+func (a *testApp) multipartValues(method, path string, fields map[string][]string, image []byte) (int, map[string]any) {
+	a.t.Helper()
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	for key, values := range fields {
+		for _, value := range values {
+			w.WriteField(key, value)
+		}
+	}
+	if image != nil {
+		part, _ := w.CreateFormFile("image", "photo.jpg")
+		part.Write(image)
+	}
+	w.Close()
+	return a.do(method, path, &buf, w.FormDataContentType())
+}
+
+// productNames mengembalikan nama produk di response list, diurutkan
+func productNames(t *testing.T, body map[string]any) []string {
+	t.Helper()
+	items, ok := body["products"].([]any)
+	if !ok {
+		t.Fatalf("response has no products: %v", body)
+	}
+	names := make([]string, 0, len(items))
+	for _, item := range items {
+		names = append(names, item.(map[string]any)["name"].(string))
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestCategoriesAndTags(t *testing.T) {
+	for _, b := range backends {
+		t.Run(b.name, func(t *testing.T) {
+			app := newTestApp(t, b)
+			admin := signIn(t, app, "admin")
+			seller := signIn(t, app, "seller")
+			image := []byte("\xff\xd8\xff\xe0 fake jpeg content")
+
+			_, body := seller.json(http.MethodPost, api+"/login", map[string]string{"username": "seller", "password": "secret123"})
+			sellerID := body["user"].(map[string]any)["id"].(string)
+
+			createCategory := func(t *testing.T, name string, parent any) string {
+				t.Helper()
+				status, body := admin.json(http.MethodPost, api+"/categories", map[string]any{"name": name, "parent_id": parent})
+				expectStatus(t, status, http.StatusCreated, body)
+				return body["category"].(map[string]any)["id"].(string)
+			}
+
+			var food, cookies, chocolate, drinks string
+			t.Run("only admins manage categories", func(t *testing.T) {
+				status, body := seller.json(http.MethodPost, api+"/categories", map[string]any{"name": "Food"})
+				expectStatus(t, status, http.StatusForbidden, body)
+				expectError(t, body, apperrors.CodeAdminRequired)
+
+				food = createCategory(t, "Food", nil)
+				cookies = createCategory(t, "Cookies", food)
+				chocolate = createCategory(t, "Chocolate Chip", cookies)
+				drinks = createCategory(t, "Drinks", nil)
+			})
+
+			t.Run("category tree", func(t *testing.T) {
+				status, body := seller.json(http.MethodGet, api+"/categories", nil)
+				expectStatus(t, status, http.StatusOK, body)
+				roots := body["categories"].([]any)
+				if len(roots) != 2 {
+					t.Fatalf("expected 2 root categories, got %v", roots)
+				}
+				foodNode := roots[1].(map[string]any)
+				child := foodNode["children"].([]any)[0].(map[string]any)
+				grandchild := child["children"].([]any)[0].(map[string]any)
+				if foodNode["name"] != "Food" || child["slug"] != "cookies" || grandchild["slug"] != "chocolate-chip" {
+					t.Fatalf("unexpected tree: %v", roots)
+				}
+			})
+
+			t.Run("category validation", func(t *testing.T) {
+				status, body := admin.json(http.MethodPost, api+"/categories", map[string]any{"name": "Cookies!"})
+				expectStatus(t, status, http.StatusConflict, body)
+				expectError(t, body, apperrors.CodeCategoryExists)
+
+				// Kategori tidak boleh dipindah ke bawah turunannya sendiri
+				status, body = admin.json(http.MethodPut, api+"/categories/"+food, map[string]any{"name": "Food", "parent_id": chocolate})
+				expectStatus(t, status, http.StatusBadRequest, body)
+				if fields := fieldErrors(t, body); fields["parent_id"] != "no_cycle" {
+					t.Fatalf("expected parent_id no_cycle, got %v", body)
+				}
+
+				status, body = admin.json(http.MethodDelete, api+"/categories/"+cookies, nil)
+				expectStatus(t, status, http.StatusConflict, body)
+				expectError(t, body, apperrors.CodeCategoryHasChildren)
+			})
+
+			t.Run("products carry categories and normalized tags", func(t *testing.T) {
+				products := []map[string][]string{
+					{"name": {"Choco Cookie"}, "category_ids": {chocolate}, "tags": {"Vegan", " gluten  free "}},
+					{"name": {"Oat Cookie"}, "category_ids": {cookies}, "tags": {"vegan"}},
+					{"name": {"Lemonade"}, "category_ids": {drinks}, "tags": {"gluten free", "vegan", "VEGAN"}},
+					{"name": {"Plain Bread"}},
+				}
+				for _, fields := range products {
+					fields["price"] = []string{"1000"}
+					fields["user_id"] = []string{sellerID}
+					status, body := seller.multipartValues(http.MethodPost, api+"/products", fields, image)
+					expectStatus(t, status, http.StatusOK, body)
+				}
+
+				status, body := seller.multipartValues(http.MethodPost, api+"/products", map[string][]string{
+					"name": {"Bad"}, "price": {"1000"}, "user_id": {sellerID}, "category_ids": {"not-a-uuid"},
+				}, image)
+				expectStatus(t, status, http.StatusBadRequest, body)
+
+				status, body = seller.json(http.MethodGet, api+"/products?search=lemonade", nil)
+				expectStatus(t, status, http.StatusOK, body)
+				lemonade := body["products"].([]any)[0].(map[string]any)
+				if tags := lemonade["tags"].([]any); len(tags) != 2 || tags[0] != "gluten free" || tags[1] != "vegan" {
+					t.Fatalf("unexpected tags: %v", lemonade)
+				}
+				if categories := lemonade["categories"].([]any); len(categories) != 1 || categories[0].(map[string]any)["slug"] != "drinks" {
+					t.Fatalf("unexpected categories: %v", lemonade)
+				}
+			})
+
+			t.Run("filter by category includes sub-categories", func(t *testing.T) {
+				status, body := seller.json(http.MethodGet, api+"/products?category="+food, nil)
+				expectStatus(t, status, http.StatusOK, body)
+				if names := productNames(t, body); strings.Join(names, ",") != "Choco Cookie,Oat Cookie" {
+					t.Fatalf("unexpected products in Food: %v", names)
+				}
+
+				status, body = seller.json(http.MethodGet, api+"/products?category="+chocolate, nil)
+				expectStatus(t, status, http.StatusOK, body)
+				if names := productNames(t, body); strings.Join(names, ",") != "Choco Cookie" {
+					t.Fatalf("unexpected products in Chocolate Chip: %v", names)
+				}
+
+				status, body = seller.json(http.MethodGet, api+"/products?category=not-a-uuid", nil)
+				expectStatus(t, status, http.StatusBadRequest, body)
+				expectError(t, body, apperrors.CodeInvalidCategoryID)
+			})
+
+			t.Run("filter by any or all tags", func(t *testing.T) {
+				status, body := seller.json(http.MethodGet, api+"/products?tags=vegan,gluten+free", nil)
+				expectStatus(t, status, http.StatusOK, body)
+				if names := productNames(t, body); len(names) != 3 {
+					t.Fatalf("expected 3 products with any tag, got %v", names)
+				}
+
+				status, body = seller.json(http.MethodGet, api+"/products?tags=vegan&tags=Gluten+Free&tag_mode=all", nil)
+				expectStatus(t, status, http.StatusOK, body)
+				if names := productNames(t, body); strings.Join(names, ",") != "Choco Cookie,Lemonade" {
+					t.Fatalf("unexpected products with all tags: %v", names)
+				}
+
+				status, body = seller.json(http.MethodGet, api+"/products?tags=vegan&tag_mode=some", nil)
+				expectStatus(t, status, http.StatusBadRequest, body)
+				if fields := fieldErrors(t, body); fields["tag_mode"] != "oneof" {
+					t.Fatalf("expected tag_mode oneof, got %v", body)
+				}
+			})
+
+			t.Run("tag counts", func(t *testing.T) {
+				status, body := seller.json(http.MethodGet, api+"/tags", nil)
+				expectStatus(t, status, http.StatusOK, body)
+				tags := body["tags"].([]any)
+				if len(tags) != 2 {
+					t.Fatalf("unexpected tags: %v", tags)
+				}
+				first, second := tags[0].(map[string]any), tags[1].(map[string]any)
+				if first["name"] != "vegan" || first["count"] != float64(3) || second["name"] != "gluten free" || second["count"] != float64(2) {
+					t.Fatalf("unexpected tag counts: %v", tags)
+				}
+			})
+
+			t.Run("update replaces tags only when sent", func(t *testing.T) {
+				_, body := seller.json(http.MethodGet, api+"/products?search=oat", nil)
+				oatID := body["products"].([]any)[0].(map[string]any)["id"].(string)
+
+				status, body := seller.multipartValues(http.MethodPut, api+"/products/"+oatID, map[string][]string{
+					"user_id": {sellerID}, "price": {"1500"},
+				}, nil)
+				expectStatus(t, status, http.StatusOK, body)
+				if product := productOf(t, body); len(product["tags"].([]any)) != 1 || len(product["categories"].([]any)) != 1 {
+					t.Fatalf("relations changed without being sent: %v", product)
+				}
+
+				status, body = seller.multipartValues(http.MethodPut, api+"/products/"+oatID, map[string][]string{
+					"user_id": {sellerID}, "tags": {""}, "category_ids": {drinks},
+				}, nil)
+				expectStatus(t, status, http.StatusOK, body)
+				product := productOf(t, body)
+				if len(product["tags"].([]any)) != 0 || product["categories"].([]any)[0].(map[string]any)["id"] != drinks {
+					t.Fatalf("relations not replaced: %v", product)
+				}
+			})
+
+			t.Run("deleting a category unlinks its products", func(t *testing.T) {
+				status, body := admin.json(http.MethodDelete, api+"/categories/"+drinks, nil)
+				expectStatus(t, status, http.StatusOK, body)
+
+				status, body = seller.json(http.MethodGet, api+"/products?search=lemonade", nil)
+				expectStatus(t, status, http.StatusOK, body)
+				lemonade := body["products"].([]any)[0].(map[string]any)
+				if categories := lemonade["categories"].([]any); len(categories) != 0 {
+					t.Fatalf("deleted category still linked: %v", lemonade)
+				}
+
+				status, body = seller.json(http.MethodGet, api+"/products?category="+drinks, nil)
+				expectStatus(t, status, http.StatusNotFound, body)
+				expectError(t, body, apperrors.CodeCategoryNotFound)
+			})
+		})
+	}
+}
+
+func TestAdminRoleFromConfig(t *testing.T) {
+	repos := newMemoryRepos(t)
+	app := newTestApp(t, backend{name: "memory", repos: func(*testing.T) testRepos { return repos }})
+	admin := signIn(t, app, "admin")
+	mallory := signIn(t, app, "mallory")
+	ctx := context.Background()
+
+	login := func(session *testApp, username string) map[string]any {
+		t.Helper()
+		status, body := session.json(http.MethodPost, api+"/login", map[string]string{"username": username, "password": "secret123"})
+		expectStatus(t, status, http.StatusOK, body)
+		return body["user"].(map[string]any)
+	}
+
+	// Role admin dari konfigurasi tidak disimpan ke database
+	if user := login(admin, "admin"); user["role"] != models.RoleAdmin {
+		t.Fatalf("expected admin role, got %v", user)
+	}
+	stored, err := repos.users.FindByUsername(ctx, "admin")
+	if err != nil || stored.Role != models.RoleUser {
+		t.Fatalf("expected stored role user, got %v (%v)", stored, err)
+	}
+
+	// Role admin yang tersimpan tanpa ada di konfigurasi diabaikan
+	stored, _ = repos.users.FindByUsername(ctx, "mallory")
+	stored.Role = models.RoleAdmin
+	if err := repos.users.Update(ctx, stored); err != nil {
+		t.Fatalf("update role: %v", err)
+	}
+	if user := login(mallory, "mallory"); user["role"] != models.RoleUser {
+		t.Fatalf("expected user role, got %v", user)
+	}
+	status, body := mallory.json(http.MethodPost, api+"/categories", map[string]any{"name": "Food"})
+	expectStatus(t, status, http.StatusForbidden, body)
+	expectError(t, body, apperrors.CodeAdminRequired)
+
+	status, body = admin.json(http.MethodPost, api+"/categories", map[string]any{"name": "Food"})
+	expectStatus(t, status, http.StatusCreated, body)
+}
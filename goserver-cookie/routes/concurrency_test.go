@@ -0,0 +1,95 @@
+package routes_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"server-cookie/apperrors"
+	"testing"
+)
+
+// conditional mengirim form-data dengan header If-Match opsional, lalu
+// mengembalikan status, header ETag dan body JSON
+func (a *testApp) conditional(method, path, ifMatch string, fields map[string]string) (int, string, map[string]any) {
+	a.t.Helper()
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	for key, value := range fields {
+		w.WriteField(key, value)
+	}
+	w.Close()
+
+	req, _ := http.NewRequest(method, a.server.URL+path, &buf)
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	if ifMatch != "" {
+		req.Header.Set("If-Match", ifMatch)
+	}
+	resp, err := a.client.Do(req)
+	if err != nil {
+		a.t.Fatalf("%s %s: %v", method, path, err)
+	}
+	defer resp.Body.Close()
+
+	var decoded map[string]any
+	raw, _ := io.ReadAll(resp.Body)
+	if len(raw) > 0 {
+		json.Unmarshal(raw, &decoded)
+	}
+	return resp.StatusCode, resp.Header.Get("ETag"), decoded
+}
+
+func TestProductIfMatch(t *testing.T) {
+	for _, b := range backends {
+		t.Run(b.name, func(t *testing.T) {
+			app := newTestApp(t, b)
+			alice := signIn(t, app, "alice")
+
+			_, body := alice.json(http.MethodPost, api+"/login", map[string]string{"username": "alice", "password": "secret123"})
+			aliceID := body["user"].(map[string]any)["id"].(string)
+			status, body := alice.multipart(http.MethodPost, api+"/products", map[string]string{
+				"name": "Cookie", "price": "1000", "user_id": aliceID,
+			}, []byte("\xff\xd8\xff\xe0 fake jpeg"))
+			expectStatus(t, status, http.StatusOK, body)
+			product := api + "/products/" + productOf(t, body)["id"].(string)
+
+			status, etag, body := alice.conditional(http.MethodGet, product, "", nil)
+			expectStatus(t, status, http.StatusOK, body)
+			if etag != `"1"` || productOf(t, body)["version"] != float64(1) {
+				t.Fatalf("expected version 1 as ETag, got %q and %v", etag, body)
+			}
+
+			// Editor pertama menyimpan dengan ETag yang masih berlaku
+			status, newTag, body := alice.conditional(http.MethodPut, product, etag, map[string]string{"name": "First Edit", "user_id": aliceID})
+			expectStatus(t, status, http.StatusOK, body)
+			if newTag != `"2"` || productOf(t, body)["version"] != float64(2) {
+				t.Fatalf("expected version 2 after update, got %q and %v", newTag, body)
+			}
+
+			// Editor kedua masih memegang ETag lama
+			status, _, body = alice.conditional(http.MethodPut, product, etag, map[string]string{"name": "Second Edit", "user_id": aliceID})
+			expectStatus(t, status, http.StatusPreconditionFailed, body)
+			expectError(t, body, apperrors.CodeVersionMismatch)
+			status, _, body = alice.conditional(http.MethodDelete, product, etag, nil)
+			expectStatus(t, status, http.StatusPreconditionFailed, body)
+			expectError(t, body, apperrors.CodeVersionMismatch)
+			status, _, body = alice.conditional(http.MethodPut, product, `W/"2", "abc"`, map[string]string{"name": "Second Edit", "user_id": aliceID})
+			expectStatus(t, status, http.StatusPreconditionFailed, body)
+
+			status, _, body = alice.conditional(http.MethodGet, product, "", nil)
+			expectStatus(t, status, http.StatusOK, body)
+			if name := productOf(t, body)["name"]; name != "First Edit" {
+				t.Fatalf("stale update was applied: %v", name)
+			}
+
+			// Salah satu ETag di daftar cocok, tanpa If-Match versi tidak dicek
+			status, _, body = alice.conditional(http.MethodPut, product, `"1", "2"`, map[string]string{"price": "1500", "user_id": aliceID})
+			expectStatus(t, status, http.StatusOK, body)
+			status, _, body = alice.conditional(http.MethodPut, product, "", map[string]string{"price": "2000", "user_id": aliceID})
+			expectStatus(t, status, http.StatusOK, body)
+			status, _, body = alice.conditional(http.MethodDelete, product, `"4"`, nil)
+			expectStatus(t, status, http.StatusOK, body)
+		})
+	}
+}
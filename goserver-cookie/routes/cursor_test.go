@@ -0,0 +1,124 @@
+package routes_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"server-cookie/apperrors"
+	"strings"
+	"testing"
+)
+
+func TestCursorPagination(t *testing.T) {
+	for _, b := range backends {
+		t.Run(b.name, func(t *testing.T) {
+			app := newTestApp(t, b)
+			alice := signIn(t, app, "alice")
+			image := []byte("\xff\xd8\xff\xe0 fake jpeg content")
+
+			_, body := alice.json(http.MethodPost, api+"/login", map[string]string{"username": "alice", "password": "secret123"})
+			aliceID := body["user"].(map[string]any)["id"].(string)
+
+			create := func(name, price string) {
+				t.Helper()
+				status, body := alice.multipartValues(http.MethodPost, api+"/products", map[string][]string{
+					"name": {name}, "price": {price}, "user_id": {aliceID},
+				}, image)
+				expectStatus(t, status, http.StatusOK, body)
+			}
+			// Harga sengaja ada yang sama agar urutan juga bergantung pada name dan id
+			for i, price := range []string{"3000", "1000", "3000", "2000", "3000", "1000", "2000"} {
+				create(fmt.Sprintf("p%d", i), price)
+			}
+
+			list := func(t *testing.T, query url.Values) map[string]any {
+				t.Helper()
+				status, body := alice.json(http.MethodGet, api+"/products?"+query.Encode(), nil)
+				expectStatus(t, status, http.StatusOK, body)
+				return body
+			}
+
+			t.Run("walks forward and backward with the sort in use", func(t *testing.T) {
+				query := url.Values{"sort": {"-price,name"}, "limit": {"3"}}
+				first := list(t, query)
+				if first["next_cursor"] == nil || first["prev_cursor"] != nil || first["totalItems"] != float64(7) {
+					t.Fatalf("unexpected first page: %v", first)
+				}
+
+				var pages []string
+				page := first
+				for {
+					pages = append(pages, listedNames(t, page))
+					next, _ := page["next_cursor"].(string)
+					if next == "" {
+						break
+					}
+					query.Set("cursor", next)
+					page = list(t, query)
+					if _, ok := page["totalItems"]; ok {
+						t.Fatalf("cursor page counted without include_total: %v", page)
+					}
+				}
+				if got := strings.Join(pages, "|"); got != "p0,p2,p4|p3,p6,p1|p5" {
+					t.Fatalf("unexpected pages: %q", got)
+				}
+
+				// Dari halaman terakhir mundur ke halaman pertama
+				var backward []string
+				for {
+					prev, _ := page["prev_cursor"].(string)
+					if prev == "" {
+						break
+					}
+					query.Set("cursor", prev)
+					page = list(t, query)
+					backward = append(backward, listedNames(t, page))
+				}
+				if got := strings.Join(backward, "|"); got != "p3,p6,p1|p0,p2,p4" {
+					t.Fatalf("unexpected pages going back: %q", got)
+				}
+			})
+
+			t.Run("new products do not shift later pages", func(t *testing.T) {
+				first := list(t, url.Values{"limit": {"4"}})
+				if got := listedNames(t, first); got != "p6,p5,p4,p3" {
+					t.Fatalf("unexpected first page: %q", got)
+				}
+				create("p7", "500")
+
+				second := list(t, url.Values{"limit": {"4"}, "cursor": {first["next_cursor"].(string)}, "include_total": {"true"}})
+				if got := listedNames(t, second); got != "p2,p1,p0" {
+					t.Fatalf("unexpected second page: %q", got)
+				}
+				if second["hasNextPage"] != false || second["next_cursor"] != nil || second["totalItems"] != float64(8) {
+					t.Fatalf("unexpected second page metadata: %v", second)
+				}
+
+				// Offset tetap berjalan seperti sebelumnya
+				offset := list(t, url.Values{"limit": {"4"}, "page": {"2"}})
+				if got := listedNames(t, offset); got != "p3,p2,p1,p0" || offset["page"] != float64(2) || offset["totalPages"] != float64(2) {
+					t.Fatalf("unexpected offset page: %v", offset)
+				}
+			})
+
+			t.Run("rejects tampered or mismatched cursors", func(t *testing.T) {
+				cursor := list(t, url.Values{"limit": {"2"}})["next_cursor"].(string)
+				payload, signature, _ := strings.Cut(cursor, ".")
+
+				for name, query := range map[string]url.Values{
+					"tampered":     {"limit": {"2"}, "cursor": {payload + "x." + signature}},
+					"garbage":      {"limit": {"2"}, "cursor": {"not-a-cursor"}},
+					"other sort":   {"limit": {"2"}, "cursor": {cursor}, "sort": {"price"}},
+					"other filter": {"limit": {"2"}, "cursor": {cursor}, "min_price": {"1000"}},
+				} {
+					status, body := alice.json(http.MethodGet, api+"/products?"+query.Encode(), nil)
+					if status != http.StatusBadRequest {
+						t.Errorf("%s: status %d, body %v", name, status, body)
+						continue
+					}
+					expectError(t, body, apperrors.CodeInvalidCursor)
+				}
+			})
+		})
+	}
+}
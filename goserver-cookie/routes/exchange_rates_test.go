@@ -0,0 +1,174 @@
+package routes_test
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"server-cookie/apperrors"
+	"testing"
+)
+
+// csvUpload mengirim file CSV sebagai field file di form-data
+func (a *testApp) csvUpload(path, content string) (int, map[string]any) {
+	a.t.Helper()
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	part, _ := w.CreateFormFile("file", "rates.csv")
+	part.Write([]byte(content))
+	w.Close()
+	return a.do(http.MethodPost, path, &buf, w.FormDataContentType())
+}
+
+// convertedOf mengembalikan converted_price produk di response, nil jika tidak ada
+func convertedOf(t *testing.T, product map[string]any) map[string]any {
+	t.Helper()
+	converted, _ := product["converted_price"].(map[string]any)
+	return converted
+}
+
+func TestExchangeRates(t *testing.T) {
+	for _, b := range backends {
+		t.Run(b.name, func(t *testing.T) {
+			app := newTestApp(t, b)
+			admin := signIn(t, app, "admin")
+			alice := signIn(t, app, "alice")
+
+			_, body := alice.json(http.MethodPost, api+"/login", map[string]string{"username": "alice", "password": "secret123"})
+			aliceID := body["user"].(map[string]any)["id"].(string)
+
+			t.Run("admins manage rates", func(t *testing.T) {
+				status, body := alice.json(http.MethodPut, api+"/exchange-rates/USD/IDR", map[string]any{"rate": "15500.25"})
+				expectStatus(t, status, http.StatusForbidden, body)
+				expectError(t, body, apperrors.CodeAdminRequired)
+
+				status, body = admin.json(http.MethodPut, api+"/exchange-rates/usd/idr", map[string]any{"rate": "15500.25"})
+				expectStatus(t, status, http.StatusOK, body)
+				rate := body["rate"].(map[string]any)
+				if rate["base"] != "USD" || rate["quote"] != "IDR" || rate["rate"] != "15500.25" || rate["source"] != "manual" || rate["stale"] != false {
+					t.Fatalf("unexpected rate: %v", rate)
+				}
+
+				status, body = admin.json(http.MethodPut, api+"/exchange-rates/USD/USD", map[string]any{"rate": "0"})
+				expectStatus(t, status, http.StatusBadRequest, body)
+				if fields := fieldErrors(t, body); fields["quote"] != "different" || fields["rate"] != "exchange_rate" {
+					t.Fatalf("expected quote different and rate exchange_rate, got %v", fields)
+				}
+			})
+
+			t.Run("rates are imported from CSV all or nothing", func(t *testing.T) {
+				status, body := admin.csvUpload(api+"/exchange-rates/import", "base,quote,rate\nUSD,IDR,abc\nEUR,XYZ,1\nJPY,IDR,105\n")
+				expectStatus(t, status, http.StatusBadRequest, body)
+				if fields := fieldErrors(t, body); fields["rows[2].rate"] != "exchange_rate" || fields["rows[3].quote"] != "currency" {
+					t.Fatalf("expected row errors, got %v", fields)
+				}
+
+				status, body = admin.csvUpload(api+"/exchange-rates/import", "from,to,rate\nUSD,IDR,1\n")
+				expectStatus(t, status, http.StatusBadRequest, body)
+				expectError(t, body, apperrors.CodeInvalidCSV)
+
+				status, body = admin.csvUpload(api+"/exchange-rates/import", "base,quote,rate,as_of\nJPY,IDR,105.5,2020-01-01\nUSD,JPY,150,\n")
+				expectStatus(t, status, http.StatusOK, body)
+				if body["imported"] != float64(2) {
+					t.Fatalf("expected 2 imported rates, got %v", body)
+				}
+
+				status, body = alice.json(http.MethodGet, api+"/exchange-rates", nil)
+				expectStatus(t, status, http.StatusOK, body)
+				rates := body["rates"].([]any)
+				if len(rates) != 3 {
+					t.Fatalf("expected 3 rates, got %v", rates)
+				}
+				if jpy := rates[0].(map[string]any); jpy["base"] != "JPY" || jpy["stale"] != true || jpy["source"] != "import" {
+					t.Fatalf("expected a stale imported JPY/IDR rate first, got %v", jpy)
+				}
+			})
+
+			create := func(name, price, currency string) string {
+				status, body := alice.multipartImages(http.MethodPost, api+"/products", map[string][]string{
+					"name": {name}, "price": {price}, "currency": {currency}, "user_id": {aliceID},
+				}, []byte("\xff\xd8\xff\xe0 fake jpeg "+name))
+				expectStatus(t, status, http.StatusOK, body)
+				return productOf(t, body)["id"].(string)
+			}
+			cookieID := create("Cookie", "12.50", "USD")
+			create("Mochi", "1500", "JPY")
+			create("Stroopwafel", "10", "EUR")
+
+			t.Run("prices are converted with the original kept", func(t *testing.T) {
+				status, body := alice.json(http.MethodGet, api+"/products?currency=idr&sort=name", nil)
+				expectStatus(t, status, http.StatusOK, body)
+				products := map[string]map[string]any{}
+				for _, item := range body["products"].([]any) {
+					product := item.(map[string]any)
+					products[product["name"].(string)] = product
+				}
+
+				cookie := convertedOf(t, products["Cookie"])
+				if amountOf(t, products["Cookie"]["price"]) != "12.50" {
+					t.Fatalf("original price changed: %v", products["Cookie"]["price"])
+				}
+				// 12.50 * 15500.25 = 193753.125, dibulatkan half-even
+				if amountOf(t, cookie["price"]) != "193753.12" || cookie["rate"] != "15500.25" || cookie["rounding"] != "half_even" || cookie["warning"] != nil {
+					t.Fatalf("unexpected Cookie conversion: %v", cookie)
+				}
+				if mochi := convertedOf(t, products["Mochi"]); amountOf(t, mochi["price"]) != "158250.00" || mochi["warning"] != "rate_stale" || mochi["rate_as_of"] == nil {
+					t.Fatalf("unexpected Mochi conversion: %v", mochi)
+				}
+				if waffle := convertedOf(t, products["Stroopwafel"]); waffle["price"] != nil || waffle["warning"] != "rate_unavailable" {
+					t.Fatalf("unexpected Stroopwafel conversion: %v", waffle)
+				}
+
+				status, body = alice.json(http.MethodGet, api+"/products/"+cookieID+"?currency=USD", nil)
+				expectStatus(t, status, http.StatusOK, body)
+				if converted := convertedOf(t, productOf(t, body)); amountOf(t, converted["price"]) != "12.50" || converted["rate"] != "1" {
+					t.Fatalf("expected the same price in its own currency, got %v", converted)
+				}
+
+				status, body = alice.json(http.MethodGet, api+"/products?currency=XYZ", nil)
+				expectStatus(t, status, http.StatusBadRequest, body)
+				if fields := fieldErrors(t, body); fields["currency"] != "currency" {
+					t.Fatalf("expected currency currency, got %v", fields)
+				}
+			})
+
+			t.Run("the profile currency is the default", func(t *testing.T) {
+				profile := func(currency any) {
+					t.Helper()
+					status, body := alice.json(http.MethodPut, api+"/profile/"+aliceID, map[string]any{"email": "alice@example.com", "currency": currency})
+					expectStatus(t, status, http.StatusOK, body)
+				}
+				detail := func(query string) map[string]any {
+					t.Helper()
+					status, body := alice.json(http.MethodGet, api+"/products/"+cookieID+query, nil)
+					expectStatus(t, status, http.StatusOK, body)
+					return convertedOf(t, productOf(t, body))
+				}
+
+				profile("jpy")
+				if converted := detail(""); converted["currency"] != "JPY" || amountOf(t, converted["price"]) != "1875" {
+					t.Fatalf("expected JPY from the profile, got %v", converted)
+				}
+				if converted := detail("?currency=IDR"); converted["currency"] != "IDR" {
+					t.Fatalf("expected the query to win over the profile, got %v", converted)
+				}
+				status, body := alice.json(http.MethodPut, api+"/profile/"+aliceID, map[string]any{"email": "alice@example.com", "currency": "XYZ"})
+				expectStatus(t, status, http.StatusBadRequest, body)
+				if fields := fieldErrors(t, body); fields["currency"] != "currency" {
+					t.Fatalf("expected currency currency, got %v", fields)
+				}
+				profile("")
+				if converted := detail(""); converted != nil {
+					t.Fatalf("expected no conversion without a preference, got %v", converted)
+				}
+			})
+
+			t.Run("rates can be deleted", func(t *testing.T) {
+				status, body := admin.json(http.MethodDelete, api+"/exchange-rates/USD/IDR", nil)
+				expectStatus(t, status, http.StatusOK, body)
+				status, body = admin.json(http.MethodDelete, api+"/exchange-rates/USD/IDR", nil)
+				expectStatus(t, status, http.StatusNotFound, body)
+				expectError(t, body, apperrors.CodeExchangeRateNotFound)
+			})
+		})
+	}
+}
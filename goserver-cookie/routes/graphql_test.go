@@ -0,0 +1,245 @@
+package routes_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"server-cookie/apperrors"
+	"server-cookie/config"
+	"server-cookie/models"
+	"server-cookie/repositories"
+	"server-cookie/routes"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// countingUsers menghitung pemanggilan repository user untuk memastikan owner dimuat per batch
+type countingUsers struct {
+	repositories.UserRepository
+	findByID  atomic.Int32
+	findByIDs atomic.Int32
+}
+
+func (u *countingUsers) FindByID(ctx context.Context, id uuid.UUID) (*models.User, error) {
+	u.findByID.Add(1)
+	return u.UserRepository.FindByID(ctx, id)
+}
+
+func (u *countingUsers) FindByIDs(ctx context.Context, ids []uuid.UUID) ([]models.User, error) {
+	u.findByIDs.Add(1)
+	return u.UserRepository.FindByIDs(ctx, ids)
+}
+
+func (u *countingUsers) reset() {
+	u.findByID.Store(0)
+	u.findByIDs.Store(0)
+}
+
+// graphql mengirim query GraphQL sebagai JSON
+func (a *testApp) graphql(query string, variables map[string]any) (int, map[string]any) {
+	a.t.Helper()
+	return a.json(http.MethodPost, routes.GraphQLPath, map[string]any{"query": query, "variables": variables})
+}
+
+// graphqlUpload mengirim mutation dengan satu file sesuai GraphQL multipart request spec
+func (a *testApp) graphqlUpload(query string, variables map[string]any, variablePath, filename string, content []byte) (int, map[string]any) {
+	a.t.Helper()
+	operations, _ := json.Marshal(map[string]any{"query": query, "variables": variables})
+	fileMap, _ := json.Marshal(map[string][]string{"0": {variablePath}})
+
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	w.WriteField("operations", string(operations))
+	w.WriteField("map", string(fileMap))
+	part, _ := w.CreateFormFile("0", filename)
+	part.Write(content)
+	w.Close()
+	return a.do(http.MethodPost, routes.GraphQLPath, &buf, w.FormDataContentType())
+}
+
+// graphqlErrorCode mengembalikan extensions.code dari error GraphQL pertama
+func graphqlErrorCode(t *testing.T, body map[string]any) (string, map[string]any) {
+	t.Helper()
+	errs, _ := body["errors"].([]any)
+	if len(errs) == 0 {
+		t.Fatalf("expected GraphQL errors, got %v", body)
+	}
+	first, _ := errs[0].(map[string]any)
+	extensions, _ := first["extensions"].(map[string]any)
+	code, _ := extensions["code"].(string)
+	return code, extensions
+}
+
+func graphqlData(t *testing.T, body map[string]any) map[string]any {
+	t.Helper()
+	if errs, ok := body["errors"]; ok {
+		t.Fatalf("unexpected GraphQL errors: %v", errs)
+	}
+	data, ok := body["data"].(map[string]any)
+	if !ok {
+		t.Fatalf("response has no data: %v", body)
+	}
+	return data
+}
+
+func newGraphQLApp(t *testing.T) (*testApp, *countingUsers) {
+	t.Helper()
+	cfg := &config.Config{
+		AppEnv:          "test",
+		UploadDir:       t.TempDir(),
+		ImageArchiveDir: t.TempDir(),
+		Cookie:          config.CookieConfig{Name: "token", Path: "/", MaxAge: 60 * 60, HTTPOnly: true, SameSite: http.SameSiteLaxMode},
+	}
+	repos := newMemoryRepos(t)
+	users := &countingUsers{UserRepository: repos.users}
+	repos.users = users
+	return newTestAppWith(t, cfg, repos), users
+}
+
+// signIn mendaftarkan dan login user baru di session tersendiri
+func signIn(t *testing.T, app *testApp, username string) *testApp {
+	t.Helper()
+	session := app.newSession()
+	credentials := map[string]string{"username": username, "email": username + "@example.com", "password": "secret123"}
+	status, body := session.json(http.MethodPost, api+"/register", credentials)
+	expectStatus(t, status, http.StatusCreated, body)
+	status, body = session.json(http.MethodPost, api+"/login", credentials)
+	expectStatus(t, status, http.StatusOK, body)
+	return session
+}
+
+func TestGraphQL(t *testing.T) {
+	app, users := newGraphQLApp(t)
+	alice := signIn(t, app, "alice")
+	bob := signIn(t, app, "bob")
+	image := []byte("\xff\xd8\xff\xe0 fake jpeg content")
+
+	const createProduct = `mutation($input: CreateProductInput!) {
+		createProduct(input: $input) { id name price { amount currency } image owner { username } }
+	}`
+
+	t.Run("requires the auth cookie", func(t *testing.T) {
+		status, body := app.newSession().graphql(`{ me { id } }`, nil)
+		expectStatus(t, status, http.StatusUnauthorized, body)
+		expectError(t, body, apperrors.CodeTokenMissing)
+	})
+
+	t.Run("me returns the logged-in user", func(t *testing.T) {
+		status, body := alice.graphql(`{ me { username email } }`, nil)
+		expectStatus(t, status, http.StatusOK, body)
+		me, _ := graphqlData(t, body)["me"].(map[string]any)
+		if me["username"] != "alice" || me["email"] != "alice@example.com" {
+			t.Fatalf("unexpected me: %v", me)
+		}
+	})
+
+	t.Run("createProduct accepts a multipart upload", func(t *testing.T) {
+		for _, session := range []*testApp{alice, alice, bob} {
+			variables := map[string]any{"input": map[string]any{"name": "Cookie", "price": "5000", "image": nil}}
+			status, body := session.graphqlUpload(createProduct, variables, "variables.input.image", "photo.jpg", image)
+			expectStatus(t, status, http.StatusOK, body)
+			product, _ := graphqlData(t, body)["createProduct"].(map[string]any)
+			if image, _ := product["image"].(string); !strings.HasPrefix(image, "uploads/") {
+				t.Fatalf("unexpected product: %v", product)
+			}
+		}
+	})
+
+	t.Run("createProduct reports validation errors in extensions", func(t *testing.T) {
+		variables := map[string]any{"input": map[string]any{"name": "", "price": "-1", "image": nil}}
+		status, body := alice.graphqlUpload(createProduct, variables, "variables.input.image", "notes.txt", []byte("plain text"))
+		expectStatus(t, status, http.StatusOK, body)
+		code, extensions := graphqlErrorCode(t, body)
+		if code != string(apperrors.CodeValidationFailed) {
+			t.Fatalf("code = %q, want %q (body %v)", code, apperrors.CodeValidationFailed, body)
+		}
+		fields := fieldErrors(t, extensions)
+		if fields["name"] != "required" || fields["price"] == "" || fields["image"] != "image_type" {
+			t.Fatalf("unexpected field errors: %v", fields)
+		}
+	})
+
+	t.Run("products loads owners in one batch", func(t *testing.T) {
+		users.reset()
+		status, body := alice.graphql(`{
+			products(limit: 10) {
+				nodes { name owner { username } }
+				pageInfo { totalItems hasNextPage }
+			}
+		}`, nil)
+		expectStatus(t, status, http.StatusOK, body)
+		products, _ := graphqlData(t, body)["products"].(map[string]any)
+		nodes, _ := products["nodes"].([]any)
+		if len(nodes) != 3 {
+			t.Fatalf("expected 3 products, got %v", products)
+		}
+		owners := map[string]int{}
+		for _, node := range nodes {
+			owner, _ := node.(map[string]any)["owner"].(map[string]any)
+			username, _ := owner["username"].(string)
+			owners[username]++
+		}
+		if owners["alice"] != 2 || owners["bob"] != 1 {
+			t.Fatalf("unexpected owners: %v", owners)
+		}
+		if users.findByIDs.Load() != 1 || users.findByID.Load() != 0 {
+			t.Fatalf("owners loaded with %d FindByIDs and %d FindByID calls, want 1 batch", users.findByIDs.Load(), users.findByID.Load())
+		}
+	})
+
+	t.Run("product, update and delete respect ownership", func(t *testing.T) {
+		variables := map[string]any{"input": map[string]any{"name": "Brownie", "price": "7000", "image": nil}}
+		status, body := bob.graphqlUpload(createProduct, variables, "variables.input.image", "photo.jpg", image)
+		expectStatus(t, status, http.StatusOK, body)
+		created, _ := graphqlData(t, body)["createProduct"].(map[string]any)
+		id := created["id"]
+
+		status, body = alice.graphql(`query($id: ID!) { product(id: $id) { name owner { username } } }`, map[string]any{"id": id})
+		expectStatus(t, status, http.StatusOK, body)
+		product, _ := graphqlData(t, body)["product"].(map[string]any)
+		if product["name"] != "Brownie" {
+			t.Fatalf("unexpected product: %v", product)
+		}
+
+		const update = `mutation($id: ID!, $input: UpdateProductInput!) { updateProduct(id: $id, input: $input) { name price { amount minorUnits } } }`
+		status, body = alice.graphql(update, map[string]any{"id": id, "input": map[string]any{"price": "1"}})
+		expectStatus(t, status, http.StatusOK, body)
+		if code, _ := graphqlErrorCode(t, body); code != string(apperrors.CodeNotProductOwner) {
+			t.Fatalf("code = %q, want %q", code, apperrors.CodeNotProductOwner)
+		}
+
+		status, body = bob.graphql(update, map[string]any{"id": id, "input": map[string]any{"price": "8000.50"}})
+		expectStatus(t, status, http.StatusOK, body)
+		updated, _ := graphqlData(t, body)["updateProduct"].(map[string]any)
+		if price, _ := updated["price"].(map[string]any); updated["name"] != "Brownie" || price["amount"] != "8000.50" || price["minorUnits"] != float64(800050) {
+			t.Fatalf("unexpected update: %v", updated)
+		}
+
+		status, body = bob.graphql(`mutation($id: ID!) { deleteProduct(id: $id) }`, map[string]any{"id": id})
+		expectStatus(t, status, http.StatusOK, body)
+		if graphqlData(t, body)["deleteProduct"] != id {
+			t.Fatalf("unexpected delete result: %v", body)
+		}
+
+		status, body = alice.graphql(`query($id: ID!) { product(id: $id) { id } }`, map[string]any{"id": id})
+		expectStatus(t, status, http.StatusOK, body)
+		if data := graphqlData(t, body); data["product"] != nil {
+			t.Fatalf("deleted product still returned: %v", data)
+		}
+	})
+
+	t.Run("rejects queries over the depth and complexity limits", func(t *testing.T) {
+		status, body := alice.graphql(`{ me { a { b { c { d { e { f } } } } } } }`, nil)
+		expectStatus(t, status, http.StatusBadRequest, body)
+		expectError(t, body, apperrors.CodeQueryTooDeep)
+
+		status, body = alice.graphql(`query($limit: Int) { products(limit: $limit) { nodes { id name owner { id username } } } }`, map[string]any{"limit": 500})
+		expectStatus(t, status, http.StatusBadRequest, body)
+		expectError(t, body, apperrors.CodeQueryTooComplex)
+	})
+}
@@ -0,0 +1,184 @@
+package routes_test
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"testing"
+)
+
+// multipartImages mengirim form-data dengan beberapa file di field image
+func (a *testApp) multipartImages(method, path string, fields map[string][]string, images ...[]byte) (int, map[string]any) {
+	a.t.Helper()
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	for key, values := range fields {
+		for _, value := range values {
+			w.WriteField(key, value)
+		}
+	}
+	for _, image := range images {
+		part, _ := w.CreateFormFile("image", "photo.jpg")
+		part.Write(image)
+	}
+	w.Close()
+	return a.do(method, path, &buf, w.FormDataContentType())
+}
+
+// productImages mengembalikan daftar gambar produk di response
+func productImages(t *testing.T, body map[string]any) []map[string]any {
+	t.Helper()
+	items := productOf(t, body)["images"].([]any)
+	images := make([]map[string]any, 0, len(items))
+	for _, item := range items {
+		images = append(images, item.(map[string]any))
+	}
+	return images
+}
+
+func TestProductImages(t *testing.T) {
+	for _, b := range backends {
+		t.Run(b.name, func(t *testing.T) {
+			app := newTestApp(t, b)
+			alice := signIn(t, app, "alice")
+			bob := signIn(t, app, "bob")
+			jpeg := func(name string) []byte { return []byte("\xff\xd8\xff\xe0 fake jpeg " + name) }
+
+			_, body := alice.json(http.MethodPost, api+"/login", map[string]string{"username": "alice", "password": "secret123"})
+			aliceID := body["user"].(map[string]any)["id"].(string)
+
+			status, body := alice.multipartImages(http.MethodPost, api+"/products", map[string][]string{
+				"name": {"Cookie"}, "price": {"1000"}, "user_id": {aliceID}, "image_alt": {"front", "back"},
+			}, jpeg("front"), jpeg("back"))
+			expectStatus(t, status, http.StatusOK, body)
+			product := productOf(t, body)
+			id := product["id"].(string)
+			images := productImages(t, body)
+			if len(images) != 2 || images[0]["alt"] != "front" || images[1]["alt"] != "back" || images[1]["position"] != float64(1) {
+				t.Fatalf("unexpected images: %v", images)
+			}
+			if !images[0]["primary"].(bool) || images[1]["primary"].(bool) || product["image"] != images[0]["path"] {
+				t.Fatalf("first image should be primary: %v", product)
+			}
+			for _, image := range images {
+				if _, err := os.Stat(app.imageFile(image["path"].(string))); err != nil {
+					t.Fatalf("image file not stored: %v", err)
+				}
+			}
+
+			t.Run("add appends images", func(t *testing.T) {
+				status, body := alice.multipartImages(http.MethodPost, api+"/products/"+id+"/images", nil, jpeg("side"))
+				expectStatus(t, status, http.StatusOK, body)
+				if images := productImages(t, body); len(images) != 3 || images[2]["position"] != float64(2) || images[2]["primary"].(bool) {
+					t.Fatalf("image not appended: %v", images)
+				}
+
+				status, body = bob.multipartImages(http.MethodPost, api+"/products/"+id+"/images", nil, jpeg("x"))
+				expectStatus(t, status, http.StatusForbidden, body)
+
+				status, body = alice.multipartImages(http.MethodPost, api+"/products/"+id+"/images", nil, []byte("plain text"))
+				expectStatus(t, status, http.StatusBadRequest, body)
+				if fields := fieldErrors(t, body); fields["image"] != "image_type" {
+					t.Fatalf("expected image image_type, got %v", body)
+				}
+
+				many := make([][]byte, 8)
+				for i := range many {
+					many[i] = jpeg("many")
+				}
+				status, body = alice.multipartImages(http.MethodPost, api+"/products/"+id+"/images", nil, many...)
+				expectStatus(t, status, http.StatusBadRequest, body)
+				if fields := fieldErrors(t, body); fields["image"] != "max_items" {
+					t.Fatalf("expected image max_items, got %v", body)
+				}
+			})
+
+			t.Run("reorder changes order, alt and primary", func(t *testing.T) {
+				_, body := alice.json(http.MethodGet, api+"/products/"+id, nil)
+				images := productImages(t, body)
+
+				status, body := alice.json(http.MethodPut, api+"/products/"+id+"/images", map[string]any{
+					"images": []map[string]any{
+						{"id": images[2]["id"], "alt": "side", "primary": true},
+						{"id": images[0]["id"]},
+						{"id": images[1]["id"]},
+					},
+				})
+				expectStatus(t, status, http.StatusOK, body)
+				reordered := productImages(t, body)
+				if reordered[0]["id"] != images[2]["id"] || reordered[0]["alt"] != "side" || !reordered[0]["primary"].(bool) || reordered[1]["primary"].(bool) {
+					t.Fatalf("unexpected order: %v", reordered)
+				}
+				if reordered[1]["alt"] != "front" || productOf(t, body)["image"] != images[2]["path"] {
+					t.Fatalf("alt or primary image changed unexpectedly: %v", body)
+				}
+
+				status, body = alice.json(http.MethodPut, api+"/products/"+id+"/images", map[string]any{
+					"images": []map[string]any{{"id": images[0]["id"]}, {"id": images[0]["id"]}, {"id": images[1]["id"]}},
+				})
+				expectStatus(t, status, http.StatusBadRequest, body)
+				if fields := fieldErrors(t, body); fields["images"] != "image_order" {
+					t.Fatalf("expected images image_order, got %v", body)
+				}
+
+				status, body = alice.json(http.MethodPut, api+"/products/"+id+"/images", map[string]any{
+					"images": []map[string]any{
+						{"id": images[0]["id"], "primary": true},
+						{"id": images[1]["id"], "primary": true},
+						{"id": images[2]["id"]},
+					},
+				})
+				expectStatus(t, status, http.StatusBadRequest, body)
+				if fields := fieldErrors(t, body); fields["images"] != "single_primary" {
+					t.Fatalf("expected images single_primary, got %v", body)
+				}
+			})
+
+			t.Run("remove keeps the file of the revision and promotes the next image", func(t *testing.T) {
+				_, body := alice.json(http.MethodGet, api+"/products/"+id, nil)
+				primary := productImages(t, body)[0]
+
+				status, body := alice.json(http.MethodDelete, api+"/products/"+id+"/images/"+primary["id"].(string), nil)
+				expectStatus(t, status, http.StatusOK, body)
+				images := productImages(t, body)
+				if len(images) != 2 || !images[0]["primary"].(bool) || images[0]["position"] != float64(0) {
+					t.Fatalf("unexpected images after remove: %v", images)
+				}
+				app.expectArchived(primary["path"].(string))
+
+				status, body = alice.json(http.MethodDelete, api+"/products/"+id+"/images/"+primary["id"].(string), nil)
+				expectStatus(t, status, http.StatusNotFound, body)
+				status, body = alice.json(http.MethodDelete, api+"/products/"+id+"/images/not-a-uuid", nil)
+				expectStatus(t, status, http.StatusBadRequest, body)
+			})
+
+			t.Run("update with images replaces all of them", func(t *testing.T) {
+				_, body := alice.json(http.MethodGet, api+"/products/"+id, nil)
+				old := productImages(t, body)
+
+				status, body := alice.multipartImages(http.MethodPut, api+"/products/"+id, map[string][]string{"user_id": {aliceID}}, jpeg("new"))
+				expectStatus(t, status, http.StatusOK, body)
+				if images := productImages(t, body); len(images) != 1 || !images[0]["primary"].(bool) {
+					t.Fatalf("images not replaced: %v", images)
+				}
+				for _, image := range old {
+					app.expectArchived(image["path"].(string))
+				}
+
+				// Tanpa image, gambar tidak berubah
+				status, body = alice.multipartImages(http.MethodPut, api+"/products/"+id, map[string][]string{"user_id": {aliceID}, "name": {"Cookies"}})
+				expectStatus(t, status, http.StatusOK, body)
+				if len(productImages(t, body)) != 1 {
+					t.Fatalf("images changed without being sent: %v", body)
+				}
+
+				status, body = alice.json(http.MethodGet, api+"/products?has_image=true", nil)
+				expectStatus(t, status, http.StatusOK, body)
+				if got := listedNames(t, body); got != "Cookies" {
+					t.Fatalf("has_image=true listed %q", got)
+				}
+			})
+		})
+	}
+}
@@ -0,0 +1,366 @@
+package routes_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"server-cookie/apperrors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// importFile mengirim file import beserta arsip ZIP opsional, lalu mengembalikan
+// status, header Location dan body JSON
+func (a *testApp) importFile(filename, content string, archive []byte, fields map[string]string) (int, string, map[string]any) {
+	a.t.Helper()
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	for key, value := range fields {
+		w.WriteField(key, value)
+	}
+	part, _ := w.CreateFormFile("file", filename)
+	part.Write([]byte(content))
+	if archive != nil {
+		part, _ = w.CreateFormFile("images", "images.zip")
+		part.Write(archive)
+	}
+	w.Close()
+
+	req, _ := http.NewRequest(http.MethodPost, a.server.URL+api+"/products/import", &buf)
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	resp, err := a.client.Do(req)
+	if err != nil {
+		a.t.Fatalf("import: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var decoded map[string]any
+	raw, _ := io.ReadAll(resp.Body)
+	json.Unmarshal(raw, &decoded)
+	return resp.StatusCode, resp.Header.Get("Location"), decoded
+}
+
+// export mengunduh file export dan mengembalikan status, Content-Type dan isinya
+func (a *testApp) export(query string) (int, string, string) {
+	a.t.Helper()
+	resp, err := a.client.Get(a.server.URL + api + "/products/export" + query)
+	if err != nil {
+		a.t.Fatalf("export: %v", err)
+	}
+	defer resp.Body.Close()
+	raw, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, resp.Header.Get("Content-Type"), string(raw)
+}
+
+// jobOf mengembalikan job import di response dan memastikan hasil per baris sesuai
+func jobOf(t *testing.T, body map[string]any, created, updated, failed int) map[string]any {
+	t.Helper()
+	job, ok := body["job"].(map[string]any)
+	if !ok {
+		t.Fatalf("response has no job: %v", body)
+	}
+	if job["created"] != float64(created) || job["updated"] != float64(updated) || job["failed"] != float64(failed) {
+		t.Fatalf("expected %d created, %d updated, %d failed, got %v", created, updated, failed, job)
+	}
+	return job
+}
+
+// rowErrors mengembalikan kode error per nomor baris
+func rowErrors(t *testing.T, job map[string]any) map[int]string {
+	t.Helper()
+	rows := make(map[int]string)
+	for _, item := range job["errors"].([]any) {
+		row := item.(map[string]any)
+		code := row["code"].(string)
+		if fields, ok := row["errors"].([]any); ok {
+			field := fields[0].(map[string]any)
+			code += ":" + field["field"].(string) + ":" + field["code"].(string)
+		}
+		rows[int(row["row"].(float64))] = code
+	}
+	return rows
+}
+
+func zipArchive(t *testing.T, files map[string][]byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, content := range files {
+		f, _ := w.Create(name)
+		f.Write(content)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("zip: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestProductImportExport(t *testing.T) {
+	jpeg := []byte("\xff\xd8\xff\xe0 fake jpeg")
+	images := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/cookie" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(jpeg)
+	}))
+	defer images.Close()
+
+	for _, b := range backends {
+		t.Run(b.name, func(t *testing.T) {
+			app := newTestApp(t, b)
+			alice := signIn(t, app, "alice")
+			bob := signIn(t, app, "bob")
+
+			// Dry-run memvalidasi setiap baris tanpa menyimpan produk
+			file := "external_id,sku,name,price,currency,tags,images\n" +
+				"ext-1,ck-1,Choco,1500,,sweet|choco,cookie.jpg\n" +
+				"ext-2,,Vanilla,abc,,,\n" +
+				"ext-3,CK-1,Butter,1000,,,\n" +
+				"ext-4,,Oat,1000,,," + images.URL + "/cookie\n" +
+				"ext-5,,,1000,,,\n"
+			archive := zipArchive(t, map[string][]byte{"cookie.jpg": jpeg})
+			status, _, body := alice.importFile("products.csv", file, archive, map[string]string{"dry_run": "true"})
+			expectStatus(t, status, http.StatusOK, body)
+			job := jobOf(t, body, 2, 0, 3)
+			if job["status"] != "succeeded" || job["dry_run"] != true || job["total_rows"] != float64(5) {
+				t.Fatalf("unexpected dry-run job: %v", job)
+			}
+			errors := rowErrors(t, job)
+			want := map[int]string{
+				3: "validation_failed:price:price",
+				4: "validation_failed:sku:duplicate",
+				6: "validation_failed:name:required",
+			}
+			if fmt.Sprint(errors) != fmt.Sprint(want) {
+				t.Fatalf("expected row errors %v, got %v", want, errors)
+			}
+			status, body = alice.json(http.MethodGet, api+"/products", nil)
+			expectStatus(t, status, http.StatusOK, body)
+			if products := body["products"].([]any); len(products) != 0 {
+				t.Fatalf("dry-run saved products: %v", products)
+			}
+
+			// Import sungguhan dengan gambar dari arsip ZIP dan URL
+			file = "external_id,sku,name,price,tags,images\n" +
+				"ext-1,ck-1,Choco,1500,sweet|choco,cookie.jpg\n" +
+				"ext-2,,Oat,1000,," + images.URL + "/cookie\n" +
+				"ext-3,,Missing,1000,," + images.URL + "/missing\n"
+			status, _, body = alice.importFile("products.csv", file, archive, nil)
+			expectStatus(t, status, http.StatusOK, body)
+			job = jobOf(t, body, 2, 0, 1)
+			if errors := rowErrors(t, job); errors[4] != string(apperrors.CodeImageFetchFailed) {
+				t.Fatalf("expected image_fetch_failed on row 4, got %v", errors)
+			}
+
+			status, _, export := alice.export("?format=jsonl")
+			if status != http.StatusOK {
+				t.Fatalf("export: %d %s", status, export)
+			}
+			lines := strings.Split(strings.TrimSpace(export), "\n")
+			if len(lines) != 2 {
+				t.Fatalf("expected 2 exported products, got %q", export)
+			}
+			var choco map[string]any
+			json.Unmarshal([]byte(lines[0]), &choco)
+			if choco["external_id"] != "ext-1" || choco["sku"] != "CK-1" || choco["price"] != "1500.00" || len(choco["images"].([]any)) != 1 {
+				t.Fatalf("unexpected exported product: %v", choco)
+			}
+			productPath := api + "/products/" + choco["id"].(string)
+
+			// Baris dicocokkan dengan sku atau external_id, field yang tidak ada tidak diubah
+			status, _, body = alice.importFile("changes.jsonl",
+				`{"sku": "ck-1", "price": "2000"}`+"\n\n"+
+					`{"external_id": "ext-2", "name": "Oat Cookie"}`+"\n"+
+					`{"external_id": "ext-2", "tags": []}`+"\n"+
+					`{"name": "Broken"`+"\n", nil, nil)
+			expectStatus(t, status, http.StatusOK, body)
+			job = jobOf(t, body, 0, 2, 2)
+			errors = rowErrors(t, job)
+			if errors[4] != "validation_failed:external_id:duplicate" || errors[5] != string(apperrors.CodeInvalidJSON) {
+				t.Fatalf("unexpected row errors: %v", errors)
+			}
+			status, _, body = alice.importFile("changes.jsonl", `{"external_id": "ext-2", "sku": "CK-1", "name": "Choco Chip"}`, nil, nil)
+			expectStatus(t, status, http.StatusOK, body)
+			if errors := rowErrors(t, jobOf(t, body, 0, 0, 1)); errors[1] != string(apperrors.CodeImportKeyMismatch) {
+				t.Fatalf("expected import_key_mismatch, got %v", errors)
+			}
+			status, _, body = alice.importFile("changes.jsonl", `{"external_id": "ext-1", "name": "Choco Chip"}`, nil, nil)
+			expectStatus(t, status, http.StatusOK, body)
+			jobOf(t, body, 0, 1, 0)
+			status, body = alice.json(http.MethodGet, productPath, nil)
+			expectStatus(t, status, http.StatusOK, body)
+			product := productOf(t, body)
+			if product["name"] != "Choco Chip" || amountOf(t, product["price"]) != "2000.00" || len(productImages(t, body)) != 1 {
+				t.Fatalf("unexpected product after update: %v", product)
+			}
+
+			// File export bisa diimpor kembali tanpa perubahan
+			status, _, export = alice.export("")
+			if status != http.StatusOK || !strings.HasPrefix(export, "id,external_id,sku,name,price,currency,category_ids,tags,images\n") {
+				t.Fatalf("unexpected CSV export: %d %s", status, export)
+			}
+			status, _, body = alice.importFile("export.csv", export, nil, nil)
+			expectStatus(t, status, http.StatusOK, body)
+			jobOf(t, body, 0, 2, 0)
+			status, body = alice.json(http.MethodGet, productPath, nil)
+			expectStatus(t, status, http.StatusOK, body)
+			if images := productImages(t, body); len(images) != 1 {
+				t.Fatalf("round trip changed images: %v", images)
+			}
+
+			// SKU milik user lain tidak bisa dipakai, export user lain kosong kecuali scope=all
+			status, _, body = bob.importFile("bob.csv", "sku,name,price\nck-1,Copy,1000\n", nil, nil)
+			expectStatus(t, status, http.StatusOK, body)
+			if errors := rowErrors(t, jobOf(t, body, 0, 0, 1)); errors[2] != string(apperrors.CodeSKUTaken) {
+				t.Fatalf("expected sku_taken, got %v", errors)
+			}
+			_, contentType, export := bob.export("")
+			if contentType != "text/csv; charset=utf-8" || strings.Count(export, "\n") != 1 {
+				t.Fatalf("expected header-only export for bob, got %q %q", contentType, export)
+			}
+			_, contentType, export = bob.export("?scope=all&format=jsonl")
+			if contentType != "application/x-ndjson" || strings.Count(export, "\n") != 2 {
+				t.Fatalf("expected all products, got %q %q", contentType, export)
+			}
+
+			// external_id unik per pemilik, produk di trash dipulihkan saat diimpor ulang
+			status, _, body = bob.importFile("bob.csv", "external_id,name,price\next-1,Bob Choco,1000\n", nil, nil)
+			expectStatus(t, status, http.StatusOK, body)
+			jobOf(t, body, 1, 0, 0)
+			status, body = alice.json(http.MethodDelete, productPath, nil)
+			expectStatus(t, status, http.StatusOK, body)
+			status, _, body = alice.importFile("changes.jsonl", `{"external_id": "ext-1", "name": "Choco Again", "price": "1000"}`, nil, map[string]string{"dry_run": "true"})
+			expectStatus(t, status, http.StatusOK, body)
+			jobOf(t, body, 0, 1, 0)
+			status, _, body = alice.importFile("changes.jsonl", `{"external_id": "ext-1", "name": "Choco Again", "price": "1000"}`, nil, nil)
+			expectStatus(t, status, http.StatusOK, body)
+			jobOf(t, body, 0, 1, 0)
+			status, body = alice.json(http.MethodGet, productPath, nil)
+			expectStatus(t, status, http.StatusOK, body)
+			if product := productOf(t, body); product["name"] != "Choco Again" || product["external_id"] != "ext-1" {
+				t.Fatalf("expected the trashed product to be restored and updated, got %v", product)
+			}
+			status, body = alice.json(http.MethodGet, productPath+"/revisions", nil)
+			expectStatus(t, status, http.StatusOK, body)
+			if list := revisionsOf(t, body); list[0]["action"] != "update" || list[1]["action"] != "restore" {
+				t.Fatalf("expected restore and update revisions, got %v", list)
+			}
+			status, body = alice.json(http.MethodGet, api+"/trash", nil)
+			expectStatus(t, status, http.StatusOK, body)
+			if body["totalItems"] != float64(0) {
+				t.Fatalf("expected an empty trash, got %v", body)
+			}
+
+			// Error seluruh file
+			status, _, body = alice.importFile("bad.csv", "name,colour\nChoco,brown\n", nil, nil)
+			expectStatus(t, status, http.StatusBadRequest, body)
+			expectError(t, body, apperrors.CodeInvalidImportFile)
+			status, _, body = alice.importFile("products.csv", "name,price\nChoco,1000\n", []byte("not a zip"), nil)
+			expectStatus(t, status, http.StatusBadRequest, body)
+			expectError(t, body, apperrors.CodeInvalidImportArchive)
+			if status, contentType, _ := alice.export("?format=xml"); status != http.StatusBadRequest || contentType != apperrors.ContentType {
+				t.Fatalf("expected problem response for unknown export format, got %d %s", status, contentType)
+			}
+		})
+	}
+}
+
+func TestProductImportJob(t *testing.T) {
+	for _, b := range backends {
+		t.Run(b.name, func(t *testing.T) {
+			// File upload disalin ke direktori sementara selama job berjalan
+			spool := t.TempDir()
+			t.Setenv("TMPDIR", spool)
+			app := newTestApp(t, b)
+			alice := signIn(t, app, "alice")
+			bob := signIn(t, app, "bob")
+
+			// File yang lebih besar dari batas import sinkron dijalankan di background
+			var file strings.Builder
+			file.WriteString("external_id,name,price\n")
+			for i := 1; i <= 150; i++ {
+				price := "1000"
+				if i%50 == 0 {
+					price = "-1"
+				}
+				fmt.Fprintf(&file, "bulk-%d,Cookie %d,%s\n", i, i, price)
+			}
+			status, location, body := alice.importFile("bulk.csv", file.String(), nil, map[string]string{"dry_run": "true"})
+			expectStatus(t, status, http.StatusAccepted, body)
+			job := body["job"].(map[string]any)
+			if location != api+"/imports/"+job["id"].(string) || job["total_rows"] != float64(150) {
+				t.Fatalf("unexpected queued job: %s %v", location, job)
+			}
+
+			deadline := time.Now().Add(10 * time.Second)
+			for job["status"] != "succeeded" {
+				if time.Now().After(deadline) {
+					t.Fatalf("import did not finish: %v", job)
+				}
+				time.Sleep(20 * time.Millisecond)
+				status, body = alice.json(http.MethodGet, location, nil)
+				expectStatus(t, status, http.StatusOK, body)
+				job = body["job"].(map[string]any)
+			}
+			jobOf(t, body, 147, 0, 3)
+			if job["processed_rows"] != float64(150) || job["finished_at"] == nil {
+				t.Fatalf("unexpected finished job: %v", job)
+			}
+			for {
+				entries, err := os.ReadDir(spool)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(entries) == 0 {
+					break
+				}
+				if time.Now().After(deadline) {
+					t.Fatalf("import files left after the job finished: %v", entries)
+				}
+				time.Sleep(20 * time.Millisecond)
+			}
+
+			// Job user lain dianggap tidak ada
+			status, body = bob.json(http.MethodGet, location, nil)
+			expectStatus(t, status, http.StatusNotFound, body)
+			expectError(t, body, apperrors.CodeImportNotFound)
+			status, body = bob.json(http.MethodGet, api+"/imports/not-a-uuid", nil)
+			expectStatus(t, status, http.StatusBadRequest, body)
+			expectError(t, body, apperrors.CodeInvalidImportID)
+		})
+	}
+}
+
+func TestImportRejectsPrivateImageURLs(t *testing.T) {
+	internal := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("internal server was requested: %s", r.URL)
+	}))
+	defer internal.Close()
+
+	cfg := newTestConfig(t)
+	cfg.ImportAllowPrivateURLs = false
+	app := newTestAppWith(t, cfg, newMemoryRepos(t))
+	alice := signIn(t, app, "alice")
+
+	// Loopback dan metadata cloud ditolak tanpa menyebut host atau penyebabnya
+	file := "name,price,images\n" +
+		"Local,1000," + internal.URL + "/cookie\n" +
+		"Metadata,1000,http://169.254.169.254/latest/meta-data\n"
+	status, _, body := alice.importFile("products.csv", file, nil, nil)
+	expectStatus(t, status, http.StatusOK, body)
+	job := jobOf(t, body, 0, 0, 2)
+	if errors := rowErrors(t, job); errors[2] != string(apperrors.CodeImageFetchFailed) || errors[3] != string(apperrors.CodeImageFetchFailed) {
+		t.Fatalf("expected image_fetch_failed, got %v", errors)
+	}
+	if details := fmt.Sprint(job["errors"]); strings.Contains(details, "127.0.0.1") || strings.Contains(details, "169.254") {
+		t.Fatalf("row errors leak the target address: %s", details)
+	}
+}
@@ -0,0 +1,178 @@
+package routes_test
+
+import (
+	"net/http"
+	"server-cookie/apperrors"
+	"testing"
+)
+
+// stockOf mengembalikan ringkasan stok produk di response
+func stockOf(t *testing.T, body map[string]any) map[string]any {
+	t.Helper()
+	stock, ok := productOf(t, body)["stock"].(map[string]any)
+	if !ok {
+		t.Fatalf("response has no stock: %v", body)
+	}
+	return stock
+}
+
+func TestInventory(t *testing.T) {
+	for _, b := range backends {
+		t.Run(b.name, func(t *testing.T) {
+			app := newTestApp(t, b)
+			alice := signIn(t, app, "alice")
+			bob := signIn(t, app, "bob")
+
+			_, body := alice.json(http.MethodPost, api+"/login", map[string]string{"username": "alice", "password": "secret123"})
+			aliceID := body["user"].(map[string]any)["id"].(string)
+
+			create := func(name string) string {
+				status, body := alice.multipartImages(http.MethodPost, api+"/products", map[string][]string{
+					"name": {name}, "price": {"5000"}, "user_id": {aliceID},
+				}, []byte("\xff\xd8\xff\xe0 fake jpeg "+name))
+				expectStatus(t, status, http.StatusOK, body)
+				return productOf(t, body)["id"].(string)
+			}
+			mug := create("Mug")
+			shirt := create("Shirt")
+			base := api + "/products/" + mug
+
+			t.Run("adjust and threshold", func(t *testing.T) {
+				status, body := alice.json(http.MethodPost, base+"/inventory/adjustments", map[string]any{"delta": 10, "reason": "restock"})
+				expectStatus(t, status, http.StatusOK, body)
+				if stock := body["stock"].(map[string]any); stock["on_hand"] != float64(10) || stock["available"] != float64(10) {
+					t.Fatalf("unexpected stock: %v", stock)
+				}
+
+				status, body = alice.json(http.MethodPost, base+"/inventory/adjustments", map[string]any{"delta": 5, "reason": "damage"})
+				expectStatus(t, status, http.StatusBadRequest, body)
+				if fields := fieldErrors(t, body); fields["delta"] != "delta_sign" {
+					t.Fatalf("expected delta delta_sign, got %v", body)
+				}
+
+				status, body = alice.json(http.MethodPost, base+"/inventory/adjustments", map[string]any{"delta": -11, "reason": "correction"})
+				expectStatus(t, status, http.StatusConflict, body)
+				expectError(t, body, apperrors.CodeInsufficientStock)
+
+				status, body = bob.json(http.MethodPost, base+"/inventory/adjustments", map[string]any{"delta": 1, "reason": "restock"})
+				expectStatus(t, status, http.StatusForbidden, body)
+
+				status, body = alice.json(http.MethodPut, base+"/inventory/threshold", map[string]any{"low_stock_threshold": 10})
+				expectStatus(t, status, http.StatusOK, body)
+				if stock := body["stock"].(map[string]any); stock["low_stock"] != true {
+					t.Fatalf("expected low stock: %v", stock)
+				}
+
+				_, body = alice.json(http.MethodGet, api+"/products?low_stock=true&owner="+aliceID, nil)
+				if names := listedNames(t, body); names != "Mug" {
+					t.Fatalf("expected Mug for low_stock=true, got %q", names)
+				}
+
+				_, body = alice.json(http.MethodGet, base, nil)
+				if stock := stockOf(t, body); stock["on_hand"] != float64(10) || stock["low_stock"] != true {
+					t.Fatalf("unexpected product stock: %v", stock)
+				}
+			})
+
+			t.Run("reservations", func(t *testing.T) {
+				status, body := bob.json(http.MethodPost, base+"/reservations", map[string]any{"quantity": 11})
+				expectStatus(t, status, http.StatusConflict, body)
+				expectError(t, body, apperrors.CodeInsufficientStock)
+
+				status, body = bob.json(http.MethodPost, base+"/reservations", map[string]any{"quantity": 4})
+				expectStatus(t, status, http.StatusCreated, body)
+				confirmed := body["reservation"].(map[string]any)["id"].(string)
+
+				status, body = bob.json(http.MethodPost, base+"/reservations", map[string]any{"quantity": 3})
+				expectStatus(t, status, http.StatusCreated, body)
+				released := body["reservation"].(map[string]any)["id"].(string)
+
+				_, body = alice.json(http.MethodGet, base, nil)
+				if stock := stockOf(t, body); stock["reserved"] != float64(7) || stock["available"] != float64(3) {
+					t.Fatalf("unexpected stock after reserving: %v", stock)
+				}
+
+				// Stok yang sedang ditahan tidak bisa dikurangi
+				status, body = alice.json(http.MethodPost, base+"/inventory/adjustments", map[string]any{"delta": -4, "reason": "damage"})
+				expectStatus(t, status, http.StatusConflict, body)
+				expectError(t, body, apperrors.CodeInsufficientStock)
+
+				status, body = alice.json(http.MethodPost, api+"/reservations/"+confirmed+"/confirm", nil)
+				expectStatus(t, status, http.StatusForbidden, body)
+				expectError(t, body, apperrors.CodeNotReservationOwner)
+
+				status, body = bob.json(http.MethodPost, api+"/reservations/"+confirmed+"/confirm", nil)
+				expectStatus(t, status, http.StatusOK, body)
+				if reservation := body["reservation"].(map[string]any); reservation["status"] != "confirmed" {
+					t.Fatalf("reservation not confirmed: %v", reservation)
+				}
+				status, body = bob.json(http.MethodDelete, api+"/reservations/"+confirmed, nil)
+				expectStatus(t, status, http.StatusConflict, body)
+				expectError(t, body, apperrors.CodeReservationClosed)
+
+				status, body = bob.json(http.MethodDelete, api+"/reservations/"+released, nil)
+				expectStatus(t, status, http.StatusOK, body)
+				if reservation := body["reservation"].(map[string]any); reservation["status"] != "released" {
+					t.Fatalf("reservation not released: %v", reservation)
+				}
+
+				status, body = bob.json(http.MethodDelete, api+"/reservations/not-a-uuid", nil)
+				expectStatus(t, status, http.StatusBadRequest, body)
+				expectError(t, body, apperrors.CodeInvalidReservationID)
+
+				_, body = alice.json(http.MethodGet, base, nil)
+				if stock := stockOf(t, body); stock["on_hand"] != float64(6) || stock["reserved"] != float64(0) {
+					t.Fatalf("unexpected stock after confirming: %v", stock)
+				}
+
+				status, body = alice.json(http.MethodGet, base+"/inventory/movements", nil)
+				expectStatus(t, status, http.StatusOK, body)
+				movements := body["movements"].([]any)
+				if len(movements) != 2 || body["totalItems"] != float64(2) {
+					t.Fatalf("unexpected movements: %v", body)
+				}
+				if sale := movements[0].(map[string]any); sale["reason"] != "sale" || sale["delta"] != float64(-4) || sale["balance"] != float64(6) || sale["reservation_id"] != confirmed {
+					t.Fatalf("unexpected sale movement: %v", sale)
+				}
+			})
+
+			t.Run("variants have their own stock", func(t *testing.T) {
+				base := api + "/products/" + shirt
+				status, body := alice.json(http.MethodPut, base+"/options", map[string]any{
+					"options": []map[string]any{{"name": "Size", "values": []string{"S", "M"}}},
+				})
+				expectStatus(t, status, http.StatusOK, body)
+				for _, size := range []string{"S", "M"} {
+					status, body = alice.json(http.MethodPost, base+"/variants", map[string]any{
+						"sku": "SHIRT-" + size, "attributes": map[string]string{"Size": size},
+					})
+					expectStatus(t, status, http.StatusCreated, body)
+				}
+				variantID := productVariants(t, body)[0]["id"].(string)
+
+				status, body = alice.json(http.MethodPost, base+"/inventory/adjustments", map[string]any{"delta": 3, "reason": "restock"})
+				expectStatus(t, status, http.StatusBadRequest, body)
+				if fields := fieldErrors(t, body); fields["variant_id"] != "required" {
+					t.Fatalf("expected variant_id required, got %v", body)
+				}
+
+				status, body = alice.json(http.MethodPost, base+"/inventory/adjustments", map[string]any{"variant_id": variantID, "delta": 3, "reason": "restock"})
+				expectStatus(t, status, http.StatusOK, body)
+
+				status, body = alice.json(http.MethodGet, base+"/inventory", nil)
+				expectStatus(t, status, http.StatusOK, body)
+				if items := body["items"].([]any); len(items) != 2 {
+					t.Fatalf("expected one stock item per variant, got %v", items)
+				}
+
+				_, body = alice.json(http.MethodGet, base, nil)
+				if stock := stockOf(t, body); stock["on_hand"] != float64(3) {
+					t.Fatalf("product stock should sum its variants: %v", stock)
+				}
+				if variant := productVariants(t, body)[0]; variant["stock"].(map[string]any)["on_hand"] != float64(3) {
+					t.Fatalf("unexpected variant stock: %v", variant)
+				}
+			})
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package routes_test
+
+import (
+	"net/http"
+	"server-cookie/apperrors"
+	"testing"
+	"time"
+)
+
+// expectNames membandingkan nama produk di response list, diurutkan seperti productNames
+func expectNames(t *testing.T, body map[string]any, want ...string) {
+	t.Helper()
+	got := productNames(t, body)
+	if len(got) != len(want) {
+		t.Fatalf("expected products %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("expected products %v, got %v", want, got)
+		}
+	}
+}
+
+func TestProductLifecycle(t *testing.T) {
+	for _, b := range backends {
+		t.Run(b.name, func(t *testing.T) {
+			app := newTestApp(t, b)
+			alice := signIn(t, app, "alice")
+			bob := signIn(t, app, "bob")
+
+			_, body := alice.json(http.MethodPost, api+"/login", map[string]string{"username": "alice", "password": "secret123"})
+			aliceID := body["user"].(map[string]any)["id"].(string)
+			create := func(fields map[string]string) map[string]any {
+				t.Helper()
+				fields["price"], fields["user_id"] = "1000", aliceID
+				status, body := alice.multipart(http.MethodPost, api+"/products", fields, []byte("\xff\xd8\xff\xe0 fake jpeg"))
+				expectStatus(t, status, http.StatusOK, body)
+				return productOf(t, body)
+			}
+			publishAt := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+			live := create(map[string]string{"name": "Live"})
+			draft := create(map[string]string{"name": "Draft", "status": "draft"})
+			scheduled := create(map[string]string{"name": "Scheduled", "publish_at": publishAt})
+			if live["status"] != "published" || draft["status"] != "draft" || scheduled["status"] != "draft" || scheduled["publish_at"] == nil {
+				t.Fatalf("unexpected statuses: %v, %v, %v", live, draft, scheduled)
+			}
+			draftID := draft["id"].(string)
+
+			// Semua user hanya melihat produk published secara default
+			for _, session := range []*testApp{alice, bob} {
+				status, body := session.json(http.MethodGet, api+"/products", nil)
+				expectStatus(t, status, http.StatusOK, body)
+				expectNames(t, body, "Live")
+			}
+			status, body := bob.json(http.MethodGet, api+"/products/"+draftID, nil)
+			expectStatus(t, status, http.StatusNotFound, body)
+			expectError(t, body, apperrors.CodeProductNotFound)
+			status, body = alice.json(http.MethodGet, api+"/products/"+draftID, nil)
+			expectStatus(t, status, http.StatusOK, body)
+
+			// Filter status selain published hanya untuk produk milik sendiri
+			status, body = alice.json(http.MethodGet, api+"/products?status=draft", nil)
+			expectStatus(t, status, http.StatusOK, body)
+			expectNames(t, body, "Draft", "Scheduled")
+			status, body = bob.json(http.MethodGet, api+"/products?status=draft", nil)
+			expectStatus(t, status, http.StatusOK, body)
+			expectNames(t, body)
+			status, body = bob.json(http.MethodGet, api+"/products?status=draft&owner="+aliceID, nil)
+			expectStatus(t, status, http.StatusForbidden, body)
+			expectError(t, body, apperrors.CodeNotProductOwner)
+			status, body = alice.json(http.MethodGet, api+"/products?status=deleted", nil)
+			expectStatus(t, status, http.StatusBadRequest, body)
+			if fields := fieldErrors(t, body); fields["status"] != "oneof" {
+				t.Fatalf("expected status oneof error, got %v", fields)
+			}
+
+			setStatus := func(session *testApp, payload map[string]any) (int, map[string]any) {
+				t.Helper()
+				return session.json(http.MethodPut, api+"/products/"+draftID+"/status", payload)
+			}
+			status, body = setStatus(bob, map[string]any{"status": "published"})
+			expectStatus(t, status, http.StatusForbidden, body)
+			status, body = setStatus(alice, map[string]any{"status": "published", "publish_at": publishAt})
+			expectStatus(t, status, http.StatusBadRequest, body)
+			if fields := fieldErrors(t, body); fields["publish_at"] != "schedule_status" {
+				t.Fatalf("expected publish_at error, got %v", fields)
+			}
+
+			status, body = setStatus(alice, map[string]any{"status": "published"})
+			expectStatus(t, status, http.StatusOK, body)
+			if product := productOf(t, body); product["status"] != "published" || product["version"] != float64(2) {
+				t.Fatalf("unexpected published product: %v", product)
+			}
+			status, body = bob.json(http.MethodGet, api+"/products/"+draftID, nil)
+			expectStatus(t, status, http.StatusOK, body)
+
+			status, body = setStatus(alice, map[string]any{"status": "archived"})
+			expectStatus(t, status, http.StatusOK, body)
+			status, body = setStatus(alice, map[string]any{"status": "published"})
+			expectStatus(t, status, http.StatusConflict, body)
+			expectError(t, body, apperrors.CodeInvalidStatusTransition)
+			status, body = bob.json(http.MethodGet, api+"/products?status=archived", nil)
+			expectStatus(t, status, http.StatusOK, body)
+			expectNames(t, body)
+			status, body = alice.json(http.MethodGet, api+"/products?status=archived", nil)
+			expectStatus(t, status, http.StatusOK, body)
+			expectNames(t, body, "Draft")
+
+			status, body = alice.json(http.MethodGet, api+"/products/"+draftID+"/revisions", nil)
+			expectStatus(t, status, http.StatusOK, body)
+			if latest := body["revisions"].([]any)[0].(map[string]any); latest["action"] != "status" {
+				t.Fatalf("expected a status revision, got %v", latest)
+			}
+		})
+	}
+}
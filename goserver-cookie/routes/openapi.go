@@ -0,0 +1,51 @@
+package routes
+
+import (
+	"net/http"
+	"server-cookie/apperrors"
+	"server-cookie/config"
+	"server-cookie/graph"
+	"server-cookie/openapi"
+)
+
+// Path dokumen OpenAPI dan Swagger UI
+const (
+	OpenAPIPath = "/openapi.json"
+	DocsPath    = "/docs"
+	GraphQLPath = "/graphql"
+)
+
+// OpenAPIDocument membuat dokumen OpenAPI dari endpoint semua versi API
+func OpenAPIDocument(cfg *config.Config) *openapi.Document {
+	doc := openapi.New("server-cookie API", "1.0.0")
+	doc.Info.Description = "Errors are returned as " + apperrors.ContentType +
+		" with a stable `code`; `detail` and field messages follow Accept-Language (en, id)."
+	doc.UseCookieAuth(cfg.Cookie.CookieName())
+	for _, v := range Versions {
+		for _, e := range v.Endpoints {
+			e.Path = v.Prefix() + e.Path
+			doc.AddEndpoint(e)
+		}
+	}
+
+	doc.AddEndpoint(openapi.Endpoint{
+		Method: http.MethodPost, Path: GraphQLPath, Tag: "graphql", Auth: true,
+		Summary: "Run a GraphQL query or mutation. File uploads use the GraphQL multipart request spec " +
+			"(multipart/form-data with operations, map and file fields).",
+		JSONBody: graph.Request{},
+		Response: graph.Response{},
+		Errors: []apperrors.Code{
+			apperrors.CodeInvalidJSON, apperrors.CodeInvalidForm,
+			apperrors.CodeQueryTooDeep, apperrors.CodeQueryTooComplex,
+		},
+	})
+
+	// Route lama didokumentasikan sebagai deprecated
+	if legacy, ok := legacyVersion(cfg); ok {
+		for _, e := range legacy.Endpoints {
+			e.Deprecated = true
+			doc.AddEndpoint(e)
+		}
+	}
+	return doc
+}
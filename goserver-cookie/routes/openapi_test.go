@@ -0,0 +1,139 @@
+package routes_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"testing"
+
+	"server-cookie/config"
+	"server-cookie/openapi"
+	"server-cookie/routes"
+
+	"github.com/gin-gonic/gin"
+)
+
+// undocumented adalah route yang sengaja tidak masuk dokumen OpenAPI
+var undocumented = map[string]bool{
+	"GET /uploads/*filepath":                true,
+	"HEAD /uploads/*filepath":               true,
+	"GET " + routes.OpenAPIPath:             true,
+	"GET " + routes.DocsPath + "/*filepath": true,
+}
+
+// TestOpenAPIMatchesRouter memastikan dokumen OpenAPI dan router tidak berbeda
+func TestOpenAPIMatchesRouter(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cfg := &config.Config{
+		UploadDir:         t.TempDir(),
+		Cookie:            config.CookieConfig{Name: "token"},
+		LegacyRoutes:      true,
+		LegacyDeprecation: config.DefaultLegacyDeprecation,
+		LegacySunset:      config.DefaultLegacySunset,
+	}
+	r := routes.SetupRouter(cfg, routes.Handlers{})
+	doc := routes.OpenAPIDocument(cfg)
+
+	registered := map[string]bool{}
+	for _, route := range r.Routes() {
+		key := route.Method + " " + route.Path
+		if undocumented[key] {
+			continue
+		}
+		registered[route.Method+" "+openapi.Path(route.Path)] = true
+	}
+
+	documented := map[string]bool{}
+	for path, item := range doc.Paths {
+		for method, op := range *item {
+			documented[strings.ToUpper(method)+" "+path] = true
+			deprecated := !strings.HasPrefix(path, routes.APIPrefix+"/") && path != routes.GraphQLPath
+			if op.Deprecated != deprecated {
+				t.Errorf("%s %s deprecated = %v, want %v", method, path, op.Deprecated, deprecated)
+			}
+		}
+	}
+
+	for _, key := range sortedKeys(registered) {
+		if !documented[key] {
+			t.Errorf("route %s is registered but missing from the OpenAPI document", key)
+		}
+	}
+	for _, key := range sortedKeys(documented) {
+		if !registered[key] {
+			t.Errorf("route %s is documented but not registered", key)
+		}
+	}
+}
+
+// TestOpenAPIComponents memastikan semua $ref mengarah ke schema yang ada
+func TestOpenAPIComponents(t *testing.T) {
+	cfg := &config.Config{Cookie: config.CookieConfig{Name: "token", HostPrefix: true}}
+	doc := routes.OpenAPIDocument(cfg)
+
+	raw, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	for _, part := range strings.Split(string(raw), `"$ref":"#/components/schemas/`)[1:] {
+		name := part[:strings.IndexByte(part, '"')]
+		if _, ok := doc.Components.Schemas[name]; !ok {
+			t.Errorf("missing component schema %q", name)
+		}
+	}
+
+	if got := doc.Components.SecuritySchemes[openapi.CookieAuth].Name; got != "__Host-token" {
+		t.Errorf("cookie auth name = %q, want __Host-token", got)
+	}
+
+	form := doc.Components.Schemas["CreateProductForm"]
+	if form == nil || form.Properties["image"].Type != "array" || form.Properties["image"].Items.Format != "binary" {
+		t.Fatalf("CreateProductForm.image should be an array of binary files, got %+v", form)
+	}
+	if maxItems := form.Properties["image"].MaxItems; maxItems == nil || *maxItems != 10 {
+		t.Errorf("CreateProductForm.image maxItems = %v, want 10", maxItems)
+	}
+	if want := []string{"name", "price", "user_id", "image"}; strings.Join(form.Required, ",") != strings.Join(want, ",") {
+		t.Errorf("CreateProductForm required = %v, want %v", form.Required, want)
+	}
+	if len(doc.Components.Schemas["Problem"].Properties["code"].Enum) == 0 {
+		t.Error("Problem.code should list the error codes")
+	}
+}
+
+func TestOpenAPIServed(t *testing.T) {
+	app := newTestApp(t, backends[1])
+
+	status, body := app.json(http.MethodGet, routes.OpenAPIPath, nil)
+	expectStatus(t, status, http.StatusOK, body)
+	if body["openapi"] != openapi.Version {
+		t.Fatalf("openapi = %v, want %s", body["openapi"], openapi.Version)
+	}
+
+	for path, want := range map[string]string{
+		routes.DocsPath + "/":                       "swagger-ui",
+		routes.DocsPath + "/swagger-initializer.js": routes.OpenAPIPath,
+		routes.DocsPath + "/swagger-ui.css":         ".swagger-ui",
+	} {
+		resp, err := app.client.Get(app.server.URL + path)
+		if err != nil {
+			t.Fatalf("GET %s: %v", path, err)
+		}
+		raw, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK || !strings.Contains(string(raw), want) {
+			t.Errorf("GET %s = %d, body should contain %q", path, resp.StatusCode, want)
+		}
+	}
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,128 @@
+package routes_test
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func TestProductPrices(t *testing.T) {
+	for _, b := range backends {
+		t.Run(b.name, func(t *testing.T) {
+			app := newTestApp(t, b)
+			alice := signIn(t, app, "alice")
+
+			_, body := alice.json(http.MethodPost, api+"/login", map[string]string{"username": "alice", "password": "secret123"})
+			aliceID := body["user"].(map[string]any)["id"].(string)
+
+			create := func(name, price, currency string) (int, map[string]any) {
+				return alice.multipartImages(http.MethodPost, api+"/products", map[string][]string{
+					"name": {name}, "price": {price}, "currency": {currency}, "user_id": {aliceID},
+				}, []byte("\xff\xd8\xff\xe0 fake jpeg "+name))
+			}
+
+			t.Run("prices keep their currency and minor units", func(t *testing.T) {
+				status, body := create("Cookie", "12.5", "usd")
+				expectStatus(t, status, http.StatusOK, body)
+				price := productOf(t, body)["price"].(map[string]any)
+				if price["amount"] != "12.50" || price["currency"] != "USD" || price["minor_units"] != float64(1250) {
+					t.Fatalf("unexpected price: %v", price)
+				}
+
+				status, body = create("Mochi", "1500", "JPY")
+				expectStatus(t, status, http.StatusOK, body)
+				if amount := amountOf(t, productOf(t, body)["price"]); amount != "1500" {
+					t.Fatalf("JPY has no minor unit, got %q", amount)
+				}
+
+				status, body = create("Kue", "25000", "")
+				expectStatus(t, status, http.StatusOK, body)
+				if price := productOf(t, body)["price"].(map[string]any); price["currency"] != "IDR" || price["minor_units"] != float64(2500000) {
+					t.Fatalf("expected IDR by default, got %v", price)
+				}
+			})
+
+			t.Run("invalid prices are rejected", func(t *testing.T) {
+				tests := []struct {
+					price, currency string
+					fields          map[string]string
+				}{
+					{"1.5", "JPY", map[string]string{"price": "price_precision"}},
+					{"12.345", "USD", map[string]string{"price": "price_precision"}},
+					{"92233720368547758.08", "USD", map[string]string{"price": "price"}},
+					{"10000000000000", "IDR", map[string]string{"price": "price"}},
+					{"10", "XYZ", map[string]string{"currency": "currency"}},
+				}
+				for _, tt := range tests {
+					status, body := create("Cookie", tt.price, tt.currency)
+					expectStatus(t, status, http.StatusBadRequest, body)
+					fields := fieldErrors(t, body)
+					for field, code := range tt.fields {
+						if fields[field] != code {
+							t.Errorf("price %q %s: expected %s %s, got %v", tt.price, tt.currency, field, code, fields)
+						}
+					}
+				}
+			})
+
+			t.Run("variant prices follow the product currency", func(t *testing.T) {
+				status, body := create("Shirt", "10", "USD")
+				expectStatus(t, status, http.StatusOK, body)
+				base := api + "/products/" + productOf(t, body)["id"].(string)
+
+				status, body = alice.json(http.MethodPut, base+"/options", map[string]any{
+					"options": []map[string]any{{"name": "Size", "values": []string{"S", "M", "L"}}},
+				})
+				expectStatus(t, status, http.StatusOK, body)
+
+				status, body = alice.json(http.MethodPost, base+"/variants", map[string]any{
+					"sku": "SHIRT-S", "price": map[string]any{"minor_units": 1299}, "attributes": map[string]string{"Size": "S"},
+				})
+				expectStatus(t, status, http.StatusCreated, body)
+				status, body = alice.json(http.MethodPost, base+"/variants", map[string]any{
+					"sku": "SHIRT-M", "price": map[string]any{"amount": "14.5", "currency": "usd"}, "attributes": map[string]string{"Size": "M"},
+				})
+				expectStatus(t, status, http.StatusCreated, body)
+				variants := productVariants(t, body)
+				if amountOf(t, variants[0]["price"]) != "12.99" || amountOf(t, variants[1]["price_override"]) != "14.50" {
+					t.Fatalf("unexpected variant prices: %v", variants)
+				}
+
+				status, body = alice.json(http.MethodPost, base+"/variants", map[string]any{
+					"sku": "SHIRT-L", "price": map[string]any{"amount": "14.5", "currency": "EUR"}, "attributes": map[string]string{"Size": "L"},
+				})
+				expectStatus(t, status, http.StatusBadRequest, body)
+				if fields := fieldErrors(t, body); fields["currency"] != "currency_mismatch" {
+					t.Fatalf("expected currency currency_mismatch, got %v", body)
+				}
+				status, body = alice.json(http.MethodPost, base+"/variants", map[string]any{
+					"sku": "SHIRT-L", "price": true, "attributes": map[string]string{"Size": "L"},
+				})
+				expectStatus(t, status, http.StatusBadRequest, body)
+				if fields := fieldErrors(t, body); fields["price"] != "price" {
+					t.Fatalf("expected price price, got %v", body)
+				}
+			})
+
+			t.Run("price filters use price_currency", func(t *testing.T) {
+				list := func(query url.Values) string {
+					status, body := alice.json(http.MethodGet, api+"/products?"+query.Encode(), nil)
+					expectStatus(t, status, http.StatusOK, body)
+					return listedNames(t, body)
+				}
+				if names := list(url.Values{"price_currency": {"USD"}, "min_price": {"12.50"}, "sort": {"name"}}); names != "Cookie,Shirt" {
+					t.Fatalf("expected Cookie,Shirt, got %q", names)
+				}
+				if names := list(url.Values{"max_price": {"30000"}, "sort": {"name"}}); names != "Kue" {
+					t.Fatalf("expected only IDR products by default, got %q", names)
+				}
+
+				status, body := alice.json(http.MethodGet, api+"/products?price_currency=JPY&min_price=0.5", nil)
+				expectStatus(t, status, http.StatusBadRequest, body)
+				if fields := fieldErrors(t, body); fields["min_price"] != "price_precision" {
+					t.Fatalf("expected min_price price_precision, got %v", body)
+				}
+			})
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package routes_test
+
+import (
+	"net/http"
+	"net/url"
+	"server-cookie/apperrors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// listedNames mengembalikan nama produk di response list sesuai urutannya
+func listedNames(t *testing.T, body map[string]any) string {
+	t.Helper()
+	items, ok := body["products"].([]any)
+	if !ok {
+		t.Fatalf("response has no products: %v", body)
+	}
+	names := make([]string, 0, len(items))
+	for _, item := range items {
+		names = append(names, item.(map[string]any)["name"].(string))
+	}
+	return strings.Join(names, ",")
+}
+
+func TestProductFiltersAndSort(t *testing.T) {
+	for _, b := range backends {
+		t.Run(b.name, func(t *testing.T) {
+			app := newTestApp(t, b)
+			alice := signIn(t, app, "alice")
+			bob := signIn(t, app, "bob")
+			image := []byte("\xff\xd8\xff\xe0 fake jpeg content")
+
+			userID := func(session *testApp, username string) string {
+				_, body := session.json(http.MethodPost, api+"/login", map[string]string{"username": username, "password": "secret123"})
+				return body["user"].(map[string]any)["id"].(string)
+			}
+			aliceID, bobID := userID(alice, "alice"), userID(bob, "bob")
+
+			create := func(session *testApp, ownerID, name, price string) {
+				status, body := session.multipartValues(http.MethodPost, api+"/products", map[string][]string{
+					"name": {name}, "price": {price}, "user_id": {ownerID},
+				}, image)
+				expectStatus(t, status, http.StatusOK, body)
+			}
+			create(alice, aliceID, "brownie", "3000")
+			create(alice, aliceID, "Apple Pie", "5000")
+			create(bob, bobID, "Cookie", "5000")
+			create(bob, bobID, "Donut", "1000")
+
+			list := func(t *testing.T, query url.Values) (int, map[string]any) {
+				t.Helper()
+				return alice.json(http.MethodGet, api+"/products?"+query.Encode(), nil)
+			}
+
+			t.Run("filters", func(t *testing.T) {
+				tomorrow := time.Now().AddDate(0, 0, 1).Format(time.DateOnly)
+				cases := []struct {
+					query url.Values
+					want  string
+				}{
+					{url.Values{"min_price": {"3000"}, "max_price": {"5000"}, "sort": {"name"}}, "Apple Pie,brownie,Cookie"},
+					{url.Values{"owner": {bobID}, "sort": {"name"}}, "Cookie,Donut"},
+					{url.Values{"created_before": {tomorrow}, "has_image": {"true"}, "sort": {"price,name"}}, "Donut,brownie,Apple Pie,Cookie"},
+					{url.Values{"created_after": {tomorrow}}, ""},
+					{url.Values{"has_image": {"false"}}, ""},
+				}
+				for _, tc := range cases {
+					status, body := list(t, tc.query)
+					expectStatus(t, status, http.StatusOK, body)
+					if got := listedNames(t, body); got != tc.want {
+						t.Errorf("%s: got %q, want %q", tc.query.Encode(), got, tc.want)
+					}
+				}
+			})
+
+			t.Run("multi-field sort", func(t *testing.T) {
+				status, body := list(t, url.Values{"sort": {"-price,name"}})
+				expectStatus(t, status, http.StatusOK, body)
+				if got := listedNames(t, body); got != "Apple Pie,Cookie,brownie,Donut" {
+					t.Fatalf("unexpected order: %q", got)
+				}
+
+				// Tanpa sort, produk terbaru lebih dulu
+				status, body = list(t, url.Values{"limit": {"2"}})
+				expectStatus(t, status, http.StatusOK, body)
+				if got := listedNames(t, body); got != "Donut,Cookie" {
+					t.Fatalf("unexpected default order: %q", got)
+				}
+			})
+
+			t.Run("rejects invalid parameters", func(t *testing.T) {
+				status, body := list(t, url.Values{
+					"min_price": {"cheap"}, "owner": {"bob"}, "created_after": {"yesterday"},
+					"has_image": {"maybe"}, "sort": {"-price,stock,price"},
+				})
+				expectStatus(t, status, http.StatusBadRequest, body)
+				expectError(t, body, apperrors.CodeValidationFailed)
+				fields := fieldErrors(t, body)
+				want := map[string]string{
+					"min_price": "price", "owner": "uuid", "created_after": "datetime", "has_image": "boolean",
+				}
+				for field, code := range want {
+					if fields[field] != code {
+						t.Errorf("%s: got %q, want %q (body %v)", field, fields[field], code, body)
+					}
+				}
+				var sortMessages []string
+				for _, item := range body["errors"].([]any) {
+					if e := item.(map[string]any); e["field"] == "sort" {
+						sortMessages = append(sortMessages, e["message"].(string))
+					}
+				}
+				if len(sortMessages) != 2 || !strings.Contains(sortMessages[0], "stock") || !strings.Contains(sortMessages[1], "price") {
+					t.Errorf("unexpected sort errors: %v", sortMessages)
+				}
+
+				status, body = list(t, url.Values{"min_price": {"5000"}, "max_price": {"1000"}})
+				expectStatus(t, status, http.StatusBadRequest, body)
+				if fields := fieldErrors(t, body); fields["max_price"] != "gtefield" {
+					t.Fatalf("expected max_price gtefield, got %v", body)
+				}
+			})
+		})
+	}
+}
@@ -0,0 +1,238 @@
+package routes_test
+
+import (
+	"net/http"
+	"os"
+	"server-cookie/apperrors"
+	"server-cookie/models"
+	"testing"
+)
+
+// revisionsOf mengembalikan daftar revisi di response list revisi
+func revisionsOf(t *testing.T, body map[string]any) []map[string]any {
+	t.Helper()
+	items, ok := body["revisions"].([]any)
+	if !ok {
+		t.Fatalf("response has no revisions: %v", body)
+	}
+	revisions := make([]map[string]any, 0, len(items))
+	for _, item := range items {
+		revisions = append(revisions, item.(map[string]any))
+	}
+	return revisions
+}
+
+func TestProductRevisions(t *testing.T) {
+	for _, b := range backends {
+		t.Run(b.name, func(t *testing.T) {
+			app := newTestApp(t, b)
+			alice := signIn(t, app, "alice")
+			bob := signIn(t, app, "bob")
+			jpeg := func(name string) []byte { return []byte("\xff\xd8\xff\xe0 fake jpeg " + name) }
+
+			_, body := alice.json(http.MethodPost, api+"/login", map[string]string{"username": "alice", "password": "secret123"})
+			aliceID := body["user"].(map[string]any)["id"].(string)
+
+			status, body := alice.multipartImages(http.MethodPost, api+"/products", map[string][]string{
+				"name": {"Cookie"}, "price": {"1000"}, "user_id": {aliceID}, "tags": {"sweet"},
+			}, jpeg("first"))
+			expectStatus(t, status, http.StatusOK, body)
+			id := productOf(t, body)["id"].(string)
+			first := productImages(t, body)[0]
+
+			status, body = alice.multipartImages(http.MethodPut, api+"/products/"+id, map[string][]string{
+				"name": {"Chocolate Cookie"}, "price": {"1500"}, "user_id": {aliceID},
+			}, jpeg("second"))
+			expectStatus(t, status, http.StatusOK, body)
+			second := productImages(t, body)[0]
+			revisions := api + "/products/" + id + "/revisions"
+
+			t.Run("every change is recorded with its actor", func(t *testing.T) {
+				status, body := alice.json(http.MethodGet, revisions, nil)
+				expectStatus(t, status, http.StatusOK, body)
+				list := revisionsOf(t, body)
+				if len(list) != 2 || body["totalItems"] != float64(2) {
+					t.Fatalf("expected 2 revisions, got %v", body)
+				}
+				if list[0]["number"] != float64(2) || list[0]["action"] != "update" || list[1]["action"] != "create" {
+					t.Fatalf("expected the newest revision first, got %v", list)
+				}
+				if actor := list[0]["actor"].(map[string]any); actor["id"] != aliceID || actor["username"] != "alice" {
+					t.Fatalf("unexpected actor: %v", actor)
+				}
+
+				status, body = alice.json(http.MethodGet, revisions+"/1", nil)
+				expectStatus(t, status, http.StatusOK, body)
+				snapshot := body["revision"].(map[string]any)["snapshot"].(map[string]any)
+				if snapshot["name"] != "Cookie" || amountOf(t, snapshot["price"]) != "1000.00" {
+					t.Fatalf("unexpected snapshot: %v", snapshot)
+				}
+
+				status, body = bob.json(http.MethodGet, revisions, nil)
+				expectStatus(t, status, http.StatusForbidden, body)
+				expectError(t, body, apperrors.CodeNotProductOwner)
+			})
+
+			t.Run("diff lists the changed fields", func(t *testing.T) {
+				status, body := alice.json(http.MethodGet, revisions+"/diff?from=1&to=2", nil)
+				expectStatus(t, status, http.StatusOK, body)
+				changes := map[string]map[string]any{}
+				for _, item := range body["changes"].([]any) {
+					change := item.(map[string]any)
+					changes[change["field"].(string)] = change
+				}
+				if len(changes) != 4 {
+					t.Fatalf("expected 4 changes, got %v", body["changes"])
+				}
+				if name := changes["name"]; name["from"] != "Cookie" || name["to"] != "Chocolate Cookie" {
+					t.Fatalf("unexpected name change: %v", name)
+				}
+				if price := changes["price"]; amountOf(t, price["from"]) != "1000.00" || amountOf(t, price["to"]) != "1500.00" {
+					t.Fatalf("unexpected price change: %v", price)
+				}
+				if removed := changes["images["+first["id"].(string)+"]"]; removed == nil || removed["to"] != nil {
+					t.Fatalf("expected the first image to be removed, got %v", changes)
+				}
+				if added := changes["images["+second["id"].(string)+"]"]; added == nil || added["from"] != nil {
+					t.Fatalf("expected the second image to be added, got %v", changes)
+				}
+
+				status, body = alice.json(http.MethodGet, revisions+"/diff?from=1&to=abc", nil)
+				expectStatus(t, status, http.StatusBadRequest, body)
+				expectError(t, body, apperrors.CodeInvalidRevision)
+				status, body = alice.json(http.MethodGet, revisions+"/diff?from=1&to=99", nil)
+				expectStatus(t, status, http.StatusNotFound, body)
+				expectError(t, body, apperrors.CodeRevisionNotFound)
+			})
+
+			t.Run("rollback restores the snapshot and its image", func(t *testing.T) {
+				alice.expectArchived(first["path"].(string))
+
+				status, body := bob.json(http.MethodPost, revisions+"/1/rollback", nil)
+				expectStatus(t, status, http.StatusForbidden, body)
+
+				status, body = alice.json(http.MethodPost, revisions+"/1/rollback", nil)
+				expectStatus(t, status, http.StatusOK, body)
+				product := productOf(t, body)
+				if product["name"] != "Cookie" || amountOf(t, product["price"]) != "1000.00" || product["tags"].([]any)[0] != "sweet" {
+					t.Fatalf("product not rolled back: %v", product)
+				}
+				images := productImages(t, body)
+				if len(images) != 1 || images[0]["id"] != first["id"] || images[0]["path"] != first["path"] || !images[0]["primary"].(bool) {
+					t.Fatalf("image not restored: %v", images)
+				}
+				if _, err := os.Stat(alice.imageFile(first["path"].(string))); err != nil {
+					t.Fatalf("restored image is not served: %v", err)
+				}
+				alice.expectArchived(second["path"].(string))
+
+				status, body = alice.json(http.MethodGet, revisions, nil)
+				expectStatus(t, status, http.StatusOK, body)
+				if latest := revisionsOf(t, body)[0]; latest["number"] != float64(3) || latest["action"] != "rollback" || latest["rollback_of"] != float64(1) {
+					t.Fatalf("rollback not recorded: %v", latest)
+				}
+
+				status, body = alice.json(http.MethodPost, revisions+"/0/rollback", nil)
+				expectStatus(t, status, http.StatusBadRequest, body)
+				expectError(t, body, apperrors.CodeInvalidRevision)
+			})
+
+			t.Run("history survives the trash and is purged with the product", func(t *testing.T) {
+				status, body := alice.json(http.MethodDelete, api+"/products/"+id, nil)
+				expectStatus(t, status, http.StatusOK, body)
+
+				status, body = alice.json(http.MethodGet, revisions, nil)
+				expectStatus(t, status, http.StatusOK, body)
+				if latest := revisionsOf(t, body)[0]; latest["action"] != "delete" {
+					t.Fatalf("delete not recorded: %v", latest)
+				}
+				status, body = alice.json(http.MethodPost, revisions+"/1/rollback", nil)
+				expectStatus(t, status, http.StatusNotFound, body)
+				expectError(t, body, apperrors.CodeProductNotFound)
+
+				status, body = alice.json(http.MethodDelete, api+"/trash/"+id, nil)
+				expectStatus(t, status, http.StatusOK, body)
+				for _, image := range []map[string]any{first, second} {
+					if _, err := os.Stat(alice.imageFile(image["path"].(string))); !os.IsNotExist(err) {
+						t.Fatalf("image %v still exists after purge: %v", image["path"], err)
+					}
+				}
+				if entries, _ := os.ReadDir(alice.archiveDir); len(entries) != 0 {
+					t.Fatalf("archived images left after purge: %v", entries)
+				}
+				status, body = alice.json(http.MethodGet, revisions, nil)
+				expectStatus(t, status, http.StatusNotFound, body)
+			})
+		})
+	}
+}
+
+func TestRevisionFailureRollsBackProduct(t *testing.T) {
+	db := openSQLite(t)
+	app := newTestApp(t, backend{name: "sqlite", repos: func(t *testing.T) testRepos { return newGormRepos(db) }})
+	alice := signIn(t, app, "alice")
+	_, body := alice.json(http.MethodPost, api+"/login", map[string]string{"username": "alice", "password": "secret123"})
+	aliceID := body["user"].(map[string]any)["id"].(string)
+
+	status, body := alice.multipart(http.MethodPost, api+"/products", map[string]string{"name": "Cookie", "price": "1000", "user_id": aliceID}, []byte("\xff\xd8\xff\xe0 fake jpeg"))
+	expectStatus(t, status, http.StatusOK, body)
+	id := productOf(t, body)["id"].(string)
+
+	// Tanpa tabel revisi, revisi gagal disimpan dan perubahan produk ikut dibatalkan
+	if err := db.Migrator().DropTable(&models.ProductRevision{}); err != nil {
+		t.Fatal(err)
+	}
+	status, body = alice.multipart(http.MethodPut, api+"/products/"+id, map[string]string{"name": "Chocolate Cookie", "price": "1500", "user_id": aliceID}, nil)
+	expectStatus(t, status, http.StatusInternalServerError, body)
+	status, body = alice.json(http.MethodGet, api+"/products/"+id, nil)
+	expectStatus(t, status, http.StatusOK, body)
+	if product := productOf(t, body); product["name"] != "Cookie" || product["version"] != float64(1) {
+		t.Fatalf("expected the product to be unchanged, got %v", product)
+	}
+
+	status, body = alice.json(http.MethodDelete, api+"/products/"+id, nil)
+	expectStatus(t, status, http.StatusInternalServerError, body)
+	status, body = alice.json(http.MethodGet, api+"/products/"+id, nil)
+	expectStatus(t, status, http.StatusOK, body)
+}
+
+func TestPurgeFailureKeepsProduct(t *testing.T) {
+	db := openSQLite(t)
+	app := newTestApp(t, backend{name: "sqlite", repos: func(t *testing.T) testRepos { return newGormRepos(db) }})
+	alice := signIn(t, app, "alice")
+	_, body := alice.json(http.MethodPost, api+"/login", map[string]string{"username": "alice", "password": "secret123"})
+	aliceID := body["user"].(map[string]any)["id"].(string)
+
+	status, body := alice.multipart(http.MethodPost, api+"/products", map[string]string{"name": "Cookie", "price": "1000", "user_id": aliceID}, []byte("\xff\xd8\xff\xe0 fake jpeg"))
+	expectStatus(t, status, http.StatusOK, body)
+	id := productOf(t, body)["id"].(string)
+	status, body = alice.json(http.MethodDelete, api+"/products/"+id, nil)
+	expectStatus(t, status, http.StatusOK, body)
+
+	// Stok gagal dihapus, produk dan revisinya tetap di trash agar purge bisa diulang
+	if err := db.Migrator().DropTable(&models.StockReservation{}); err != nil {
+		t.Fatal(err)
+	}
+	status, body = alice.json(http.MethodDelete, api+"/trash/"+id, nil)
+	expectStatus(t, status, http.StatusInternalServerError, body)
+	status, body = alice.json(http.MethodGet, api+"/trash", nil)
+	expectStatus(t, status, http.StatusOK, body)
+	if body["totalItems"] != float64(1) {
+		t.Fatalf("expected the product to stay in the trash, got %v", body)
+	}
+	status, body = alice.json(http.MethodGet, api+"/products/"+id+"/revisions", nil)
+	expectStatus(t, status, http.StatusOK, body)
+	if len(revisionsOf(t, body)) != 2 {
+		t.Fatalf("expected revisions to be kept, got %v", body)
+	}
+
+	if err := db.AutoMigrate(&models.StockReservation{}); err != nil {
+		t.Fatal(err)
+	}
+	status, body = alice.json(http.MethodDelete, api+"/trash/"+id, nil)
+	expectStatus(t, status, http.StatusOK, body)
+	var remaining int64
+	if err := db.Model(&models.ProductRevision{}).Where("product_id = ?", id).Count(&remaining).Error; err != nil || remaining != 0 {
+		t.Fatalf("expected revisions to be purged, got %d, %v", remaining, err)
+	}
+}
@@ -0,0 +1,115 @@
+package routes
+
+import (
+	"fmt"
+	"server-cookie/apperrors"
+	"server-cookie/config"
+	"server-cookie/controllers"
+	"server-cookie/graph"
+	"server-cookie/middleware"
+	"server-cookie/openapi"
+
+	"github.com/gin-gonic/gin"
+)
+
+// APIPrefix adalah prefix semua versi API
+const APIPrefix = "/api"
+
+// LegacyVersion adalah versi yang juga dilayani di root sebagai route lama
+const LegacyVersion = "v1"
+
+// Versions adalah semua versi API yang dipasang. Versi baru (misalnya v2)
+// cukup ditambahkan di sini dan berjalan berdampingan dengan v1.
+var Versions = []Version{V1}
+
+// Version adalah satu versi API beserta route dan dokumentasinya
+type Version struct {
+	Name      string
+	Endpoints []openapi.Endpoint
+	Register  func(g Groups, h Handlers)
+}
+
+// Prefix mengembalikan prefix path versi, misalnya /api/v1
+func (v Version) Prefix() string {
+	return APIPrefix + "/" + v.Name
+}
+
+// Groups berisi group route public, yang membutuhkan login dan yang khusus admin untuk satu versi
+type Groups struct {
+	Public    *gin.RouterGroup
+	Protected *gin.RouterGroup
+	Admin     *gin.RouterGroup
+}
+
+// Handlers berisi semua handler yang didaftarkan ke router
+type Handlers struct {
+	User         *controllers.UserHandler
+	Product      *controllers.ProductHandler
+	Category     *controllers.CategoryHandler
+	Inventory    *controllers.InventoryHandler
+	ExchangeRate *controllers.ExchangeRateHandler
+	Bulk         *controllers.BulkHandler
+	GraphQL      *graph.Handler
+}
+
+// SetupRouter membuat router gin dengan semua route aplikasi
+func SetupRouter(cfg *config.Config, h Handlers) *gin.Engine {
+	r := gin.New()
+	r.HandleMethodNotAllowed = true
+
+	// ErrorMiddleware dipasang sebelum Recovery agar panic juga dikirim sebagai problem+json
+	r.Use(gin.Logger())
+	r.Use(middleware.RequestIDMiddleware())
+	r.Use(middleware.ErrorMiddleware(cfg.IsProduction()))
+	r.Use(gin.CustomRecovery(func(c *gin.Context, recovered any) {
+		middleware.AbortWithError(c, apperrors.Wrap(apperrors.CodeInternal, fmt.Errorf("panic: %v", recovered)))
+	}))
+	r.Use(middleware.CORSMiddleware())
+	r.NoRoute(func(c *gin.Context) {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeRouteNotFound))
+	})
+	r.NoMethod(func(c *gin.Context) {
+		middleware.AbortWithError(c, apperrors.New(apperrors.CodeMethodNotAllowed))
+	})
+
+	r.Static("/uploads", cfg.UploadDir)
+	r.GET(OpenAPIPath, openapi.Handler(OpenAPIDocument(cfg)))
+	r.GET(DocsPath+"/*filepath", openapi.SwaggerUI(OpenAPIPath))
+
+	for _, v := range Versions {
+		MountVersion(r, cfg, h, v)
+	}
+	r.POST(GraphQLPath, middleware.AuthMiddleware(cfg.Cookie), h.GraphQL.Serve)
+
+	// Alias lama di root untuk client yang belum memakai /api/v1
+	if legacy, ok := legacyVersion(cfg); ok {
+		deprecation := middleware.DeprecationMiddleware(cfg.LegacyDeprecation, cfg.LegacySunset, legacy.Prefix())
+		mount(r.Group("/", deprecation), cfg, h, legacy)
+	}
+
+	return r
+}
+
+// MountVersion memasang route satu versi API di bawah prefix /api/<nama versi>
+func MountVersion(r *gin.Engine, cfg *config.Config, h Handlers, v Version) {
+	mount(r.Group(v.Prefix()), cfg, h, v)
+}
+
+func mount(public *gin.RouterGroup, cfg *config.Config, h Handlers, v Version) {
+	protected := public.Group("/", middleware.AuthMiddleware(cfg.Cookie))
+	admin := protected.Group("/", middleware.RequireAdmin(cfg.AdminUsernames))
+	v.Register(Groups{Public: public, Protected: protected, Admin: admin}, h)
+}
+
+// legacyVersion mengembalikan versi yang dilayani di root jika route lama aktif
+func legacyVersion(cfg *config.Config) (Version, bool) {
+	if !cfg.LegacyRoutes {
+		return Version{}, false
+	}
+	for _, v := range Versions {
+		if v.Name == LegacyVersion {
+			return v, true
+		}
+	}
+	return Version{}, false
+}
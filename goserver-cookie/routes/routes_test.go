@@ -0,0 +1,785 @@
+package routes_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"server-cookie/apperrors"
+	"server-cookie/config"
+	"server-cookie/controllers"
+	"server-cookie/database"
+	"server-cookie/graph"
+	"server-cookie/repositories"
+	"server-cookie/routes"
+	"server-cookie/search"
+	"server-cookie/services"
+	"server-cookie/storage"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// api adalah prefix versi API yang diuji
+const api = "/api/v1"
+
+// testApp adalah server uji beserta client yang menyimpan cookie
+type testApp struct {
+	t          *testing.T
+	server     *httptest.Server
+	client     *http.Client
+	uploadDir  string
+	archiveDir string
+}
+
+// testRepos adalah repository yang dipakai server uji
+type testRepos struct {
+	users         repositories.UserRepository
+	products      repositories.ProductRepository
+	categories    repositories.CategoryRepository
+	inventory     repositories.InventoryRepository
+	exchangeRates repositories.ExchangeRateRepository
+	revisions     repositories.ProductRevisionRepository
+	importJobs    repositories.ImportJobRepository
+}
+
+type backend struct {
+	name  string
+	repos func(t *testing.T) testRepos
+}
+
+var backends = []backend{
+	{
+		name: "sqlite",
+		repos: func(t *testing.T) testRepos {
+			return newGormRepos(openSQLite(t))
+		},
+	},
+	{
+		name:  "memory",
+		repos: newMemoryRepos,
+	},
+}
+
+// openSQLite membuka database sqlite baru yang sudah dimigrasi
+func openSQLite(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := database.Open("sqlite", filepath.Join(t.TempDir(), "test.db"), logger.Silent)
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := database.Migrate(db); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	return db
+}
+
+func newGormRepos(db *gorm.DB) testRepos {
+	return testRepos{
+		users:         repositories.NewGormUserRepository(db),
+		products:      repositories.NewGormProductRepository(db),
+		categories:    repositories.NewGormCategoryRepository(db),
+		inventory:     repositories.NewGormInventoryRepository(db),
+		exchangeRates: repositories.NewGormExchangeRateRepository(db),
+		revisions:     repositories.NewGormRevisionRepository(db),
+		importJobs:    repositories.NewGormImportJobRepository(db),
+	}
+}
+
+func newMemoryRepos(t *testing.T) testRepos {
+	users := repositories.NewMemoryUserRepository()
+	categories := repositories.NewMemoryCategoryRepository()
+	inventory := repositories.NewMemoryInventoryRepository()
+	revisions := repositories.NewMemoryRevisionRepository()
+	return testRepos{
+		users:         users,
+		products:      repositories.NewMemoryProductRepository(users, categories, inventory, revisions),
+		categories:    categories,
+		inventory:     inventory,
+		exchangeRates: repositories.NewMemoryExchangeRateRepository(),
+		revisions:     revisions,
+		importJobs:    repositories.NewMemoryImportJobRepository(),
+	}
+}
+
+func newTestApp(t *testing.T, b backend) *testApp {
+	t.Helper()
+	return newTestAppWith(t, newTestConfig(t), b.repos(t))
+}
+
+// newTestConfig membuat konfigurasi server uji yang bisa diubah sebelum newTestAppWith
+func newTestConfig(t *testing.T) *config.Config {
+	return &config.Config{
+		AppEnv:          "test",
+		UploadDir:       t.TempDir(),
+		ImageArchiveDir: t.TempDir(),
+		Cookie: config.CookieConfig{
+			Name:     "token",
+			Path:     "/",
+			MaxAge:   60 * 60,
+			HTTPOnly: true,
+			SameSite: http.SameSiteLaxMode,
+		},
+		AdminUsernames:     []string{"admin"},
+		LegacyRoutes:       true,
+		LegacyDeprecation:  config.DefaultLegacyDeprecation,
+		LegacySunset:       config.DefaultLegacySunset,
+		ReservationTTL:     15 * time.Minute,
+		ExchangeRateMaxAge: 24 * time.Hour,
+		ImportSyncRows:     100,
+		// Gambar import diunduh dari server uji di loopback
+		ImportAllowPrivateURLs: true,
+	}
+}
+
+// newTestAppWith membuat server uji dari repository yang sudah disiapkan
+func newTestAppWith(t *testing.T, cfg *config.Config, repos testRepos) *testApp {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	productService := services.NewProductService(repos.products, repos.categories, repos.inventory, repos.revisions, storage.NewLocalImageStore(cfg.UploadDir, cfg.ImageArchiveDir), services.NewCursorSigner([]byte("test-cursor-secret")), search.NewMemoryIndex())
+	exchangeRateService := services.NewExchangeRateService(repos.exchangeRates, repos.users, cfg.ExchangeRateMaxAge)
+	r := routes.SetupRouter(cfg, routes.Handlers{
+		User:         controllers.NewUserHandler(repos.users, cfg.Cookie, cfg.AdminUsernames),
+		Product:      controllers.NewProductHandler(productService, exchangeRateService),
+		Category:     controllers.NewCategoryHandler(services.NewCategoryService(repos.categories)),
+		Inventory:    controllers.NewInventoryHandler(services.NewInventoryService(repos.inventory, repos.products, cfg.ReservationTTL)),
+		ExchangeRate: controllers.NewExchangeRateHandler(exchangeRateService),
+		Bulk:         controllers.NewBulkHandler(services.NewBulkService(productService, repos.importJobs, services.NewImageClient(cfg.ImportAllowPrivateURLs), cfg.ImportSyncRows)),
+		GraphQL:      graph.NewHandler(productService, repos.users, graph.DefaultLimits),
+	})
+
+	server := httptest.NewServer(r)
+	t.Cleanup(server.Close)
+
+	jar, _ := cookiejar.New(nil)
+	return &testApp{
+		t:          t,
+		server:     server,
+		client:     &http.Client{Jar: jar},
+		uploadDir:  cfg.UploadDir,
+		archiveDir: cfg.ImageArchiveDir,
+	}
+}
+
+// do mengirim request dan mengembalikan status serta body JSON
+func (a *testApp) do(method, path string, body io.Reader, contentType string) (int, map[string]any) {
+	a.t.Helper()
+
+	req, err := http.NewRequest(method, a.server.URL+path, body)
+	if err != nil {
+		a.t.Fatalf("new request: %v", err)
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		a.t.Fatalf("%s %s: %v", method, path, err)
+	}
+	defer resp.Body.Close()
+
+	raw, _ := io.ReadAll(resp.Body)
+	var decoded map[string]any
+	contentType = resp.Header.Get("Content-Type")
+	isJSON := strings.HasPrefix(contentType, "application/json") || strings.HasPrefix(contentType, apperrors.ContentType)
+	if len(raw) > 0 && isJSON {
+		if err := json.Unmarshal(raw, &decoded); err != nil {
+			a.t.Fatalf("%s %s: decode %q: %v", method, path, raw, err)
+		}
+	}
+	return resp.StatusCode, decoded
+}
+
+func (a *testApp) json(method, path string, payload any) (int, map[string]any) {
+	a.t.Helper()
+	var body io.Reader
+	switch v := payload.(type) {
+	case nil:
+	case string:
+		body = strings.NewReader(v)
+	default:
+		raw, _ := json.Marshal(v)
+		body = bytes.NewReader(raw)
+	}
+	return a.do(method, path, body, "application/json")
+}
+
+// multipart mengirim form-data dengan field dan gambar opsional bernama photo.jpg
+func (a *testApp) multipart(method, path string, fields map[string]string, image []byte) (int, map[string]any) {
+	a.t.Helper()
+	return a.multipartFile(method, path, fields, "photo.jpg", image)
+}
+
+func (a *testApp) multipartFile(method, path string, fields map[string]string, filename string, image []byte) (int, map[string]any) {
+	a.t.Helper()
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	for key, value := range fields {
+		w.WriteField(key, value)
+	}
+	if image != nil {
+		part, _ := w.CreateFormFile("image", filename)
+		part.Write(image)
+	}
+	w.Close()
+	return a.do(method, path, &buf, w.FormDataContentType())
+}
+
+// newSession membuat client baru ke server yang sama dengan cookie jar kosong
+func (a *testApp) newSession() *testApp {
+	jar, _ := cookiejar.New(nil)
+	session := *a
+	session.client = &http.Client{Jar: jar}
+	return &session
+}
+
+func (a *testApp) tokenCookie() *http.Cookie {
+	u, _ := url.Parse(a.server.URL)
+	for _, cookie := range a.client.Jar.Cookies(u) {
+		if cookie.Name == "token" {
+			return cookie
+		}
+	}
+	return nil
+}
+
+func (a *testApp) imageFile(imagePath string) string {
+	return filepath.Join(a.uploadDir, path.Base(imagePath))
+}
+
+// expectArchived memastikan gambar sudah tidak dilayani publik tetapi masih ada di arsip
+func (a *testApp) expectArchived(imagePath string) {
+	a.t.Helper()
+	if _, err := os.Stat(a.imageFile(imagePath)); !os.IsNotExist(err) {
+		a.t.Fatalf("image %s is still served: %v", imagePath, err)
+	}
+	if _, err := os.Stat(filepath.Join(a.archiveDir, path.Base(imagePath))); err != nil {
+		a.t.Fatalf("image %s was not archived: %v", imagePath, err)
+	}
+}
+
+func expectStatus(t *testing.T, got, want int, body map[string]any) {
+	t.Helper()
+	if got != want {
+		t.Fatalf("status = %d, want %d (body %v)", got, want, body)
+	}
+}
+
+// expectError memastikan body adalah problem+json dengan kode yang diharapkan
+func expectError(t *testing.T, body map[string]any, want apperrors.Code) {
+	t.Helper()
+	if got, _ := body["code"].(string); got != string(want) {
+		t.Fatalf("code = %v, want %q (body %v)", body["code"], want, body)
+	}
+	if body["status"] != float64(want.Status()) || body["detail"] == "" || body["request_id"] == "" {
+		t.Fatalf("incomplete problem body: %v", body)
+	}
+}
+
+// fieldErrors mengembalikan map field -> kode error validasi
+func fieldErrors(t *testing.T, body map[string]any) map[string]string {
+	t.Helper()
+	fields := make(map[string]string)
+	items, _ := body["errors"].([]any)
+	for _, item := range items {
+		field, _ := item.(map[string]any)
+		name, _ := field["field"].(string)
+		code, _ := field["code"].(string)
+		if message, _ := field["message"].(string); message == "" {
+			t.Fatalf("field error without message: %v", field)
+		}
+		fields[name] = code
+	}
+	return fields
+}
+
+func productOf(t *testing.T, body map[string]any) map[string]any {
+	t.Helper()
+	product, ok := body["product"].(map[string]any)
+	if !ok {
+		t.Fatalf("response has no product: %v", body)
+	}
+	return product
+}
+
+// amountOf mengembalikan jumlah desimal dari harga di response, misalnya "5000.00"
+func amountOf(t *testing.T, price any) string {
+	t.Helper()
+	money, ok := price.(map[string]any)
+	if !ok {
+		t.Fatalf("price is not a money object: %v", price)
+	}
+	return money["amount"].(string)
+}
+
+func TestEndToEnd(t *testing.T) {
+	for _, b := range backends {
+		t.Run(b.name, func(t *testing.T) {
+			app := newTestApp(t, b)
+			userID := testAuthFlow(t, app)
+			testProductFlow(t, app, userID)
+			testProfileFlow(t, app, userID)
+			testLogoutFlow(t, app)
+		})
+	}
+}
+
+func testAuthFlow(t *testing.T, app *testApp) string {
+	credentials := map[string]string{"username": "alice", "email": "alice@example.com", "password": "secret123"}
+
+	t.Run("register rejects malformed JSON", func(t *testing.T) {
+		status, body := app.json(http.MethodPost, api+"/register", "{")
+		expectStatus(t, status, http.StatusBadRequest, body)
+		expectError(t, body, apperrors.CodeInvalidJSON)
+	})
+
+	t.Run("register reports validation errors per field", func(t *testing.T) {
+		status, body := app.json(http.MethodPost, api+"/register", map[string]string{"username": "bob", "email": "not-an-email", "password": "123"})
+		expectStatus(t, status, http.StatusBadRequest, body)
+		expectError(t, body, apperrors.CodeValidationFailed)
+		fields := fieldErrors(t, body)
+		if fields["email"] != "email" || fields["password"] != "min" {
+			t.Fatalf("expected email and password errors, got %v", body)
+		}
+	})
+
+	t.Run("register succeeds", func(t *testing.T) {
+		status, body := app.json(http.MethodPost, api+"/register", credentials)
+		expectStatus(t, status, http.StatusCreated, body)
+	})
+
+	t.Run("register rejects duplicate username", func(t *testing.T) {
+		status, body := app.json(http.MethodPost, api+"/register", credentials)
+		expectStatus(t, status, http.StatusConflict, body)
+		expectError(t, body, apperrors.CodeUserExists)
+	})
+
+	t.Run("protected route without cookie", func(t *testing.T) {
+		status, body := app.json(http.MethodGet, api+"/products", nil)
+		expectStatus(t, status, http.StatusUnauthorized, body)
+		expectError(t, body, apperrors.CodeTokenMissing)
+	})
+
+	t.Run("errors are localized and carry the request ID", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, app.server.URL+api+"/products", nil)
+		req.Header.Set("Accept-Language", "id-ID,id;q=0.9")
+		req.Header.Set("X-Request-ID", "req-123")
+		resp, err := app.client.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+
+		var body map[string]any
+		json.NewDecoder(resp.Body).Decode(&body)
+		if resp.Header.Get("Content-Type") != apperrors.ContentType {
+			t.Fatalf("content type = %q", resp.Header.Get("Content-Type"))
+		}
+		if body["detail"] != "Token tidak ditemukan" || body["request_id"] != "req-123" || body["instance"] != api+"/products" {
+			t.Fatalf("unexpected problem: %v", body)
+		}
+	})
+
+	t.Run("unknown route", func(t *testing.T) {
+		status, body := app.json(http.MethodGet, "/does-not-exist", nil)
+		expectStatus(t, status, http.StatusNotFound, body)
+		expectError(t, body, apperrors.CodeRouteNotFound)
+	})
+
+	t.Run("protected route with forged cookie", func(t *testing.T) {
+		u, _ := url.Parse(app.server.URL)
+		app.client.Jar.SetCookies(u, []*http.Cookie{{Name: "token", Value: "forged", Path: "/"}})
+		status, body := app.json(http.MethodGet, api+"/products", nil)
+		expectStatus(t, status, http.StatusUnauthorized, body)
+		expectError(t, body, apperrors.CodeTokenInvalid)
+	})
+
+	t.Run("login rejects malformed JSON", func(t *testing.T) {
+		status, body := app.json(http.MethodPost, api+"/login", "{")
+		expectStatus(t, status, http.StatusBadRequest, body)
+	})
+
+	t.Run("login rejects unknown user", func(t *testing.T) {
+		status, body := app.json(http.MethodPost, api+"/login", map[string]string{"username": "nobody", "password": "secret123"})
+		expectStatus(t, status, http.StatusUnauthorized, body)
+		expectError(t, body, apperrors.CodeInvalidCredentials)
+	})
+
+	t.Run("login rejects wrong password", func(t *testing.T) {
+		status, body := app.json(http.MethodPost, api+"/login", map[string]string{"username": "alice", "password": "wrong-password"})
+		expectStatus(t, status, http.StatusUnauthorized, body)
+		expectError(t, body, apperrors.CodeInvalidCredentials)
+	})
+
+	var userID string
+	t.Run("login sets the token cookie", func(t *testing.T) {
+		status, body := app.json(http.MethodPost, api+"/login", map[string]string{"username": "alice", "password": "secret123"})
+		expectStatus(t, status, http.StatusOK, body)
+		user, _ := body["user"].(map[string]any)
+		userID, _ = user["id"].(string)
+		if userID == "" {
+			t.Fatalf("login response has no user id: %v", body)
+		}
+		if cookie := app.tokenCookie(); cookie == nil || cookie.Value == "forged" {
+			t.Fatalf("login did not set a fresh token cookie")
+		}
+	})
+	return userID
+}
+
+func testProductFlow(t *testing.T, app *testApp, userID string) {
+	image := []byte("\xff\xd8\xff\xe0 fake jpeg content")
+	var productID, imagePath, replacedPath string
+
+	t.Run("create validates every field", func(t *testing.T) {
+		tests := []struct {
+			name   string
+			fields map[string]string
+			image  []byte
+			want   map[string]string
+		}{
+			{"missing fields", map[string]string{}, nil, map[string]string{
+				"name": "required", "price": "required", "user_id": "required", "image": "required",
+			}},
+			{"non-numeric price", map[string]string{"name": "Cookie", "price": "abc", "user_id": userID}, image, map[string]string{"price": "price"}},
+			{"zero price", map[string]string{"name": "Cookie", "price": "0", "user_id": userID}, image, map[string]string{"price": "price"}},
+			{"negative price", map[string]string{"name": "Cookie", "price": "-5", "user_id": userID}, image, map[string]string{"price": "price"}},
+			{"overflowing price", map[string]string{"name": "Cookie", "price": "99999999999999999999", "user_id": userID}, image, map[string]string{"price": "price"}},
+			{"short name", map[string]string{"name": "C", "price": "1000", "user_id": userID}, image, map[string]string{"name": "min"}},
+			{"long name", map[string]string{"name": strings.Repeat("c", 101), "price": "1000", "user_id": userID}, image, map[string]string{"name": "max"}},
+			{"invalid user_id", map[string]string{"name": "Cookie", "price": "1000", "user_id": "x"}, image, map[string]string{"user_id": "uuid"}},
+			{"image that is not an image", map[string]string{"name": "Cookie", "price": "1000", "user_id": userID}, []byte("plain text"), map[string]string{"image": "image_type"}},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				status, body := app.multipart(http.MethodPost, api+"/products", tt.fields, tt.image)
+				expectStatus(t, status, http.StatusBadRequest, body)
+				expectError(t, body, apperrors.CodeValidationFailed)
+				if got := fieldErrors(t, body); !reflect.DeepEqual(got, tt.want) {
+					t.Fatalf("field errors = %v, want %v", got, tt.want)
+				}
+			})
+		}
+	})
+
+	t.Run("create rejects disallowed image extension", func(t *testing.T) {
+		status, body := app.multipartFile(http.MethodPost, api+"/products", map[string]string{"name": "Cookie", "price": "1000", "user_id": userID}, "photo.svg", image)
+		expectStatus(t, status, http.StatusBadRequest, body)
+		if fieldErrors(t, body)["image"] != "image_type" {
+			t.Fatalf("unexpected errors: %v", body)
+		}
+	})
+
+	t.Run("create with image", func(t *testing.T) {
+		status, body := app.multipart(http.MethodPost, api+"/products", map[string]string{"name": "Chocolate Cookie", "price": "15000", "user_id": userID}, image)
+		expectStatus(t, status, http.StatusOK, body)
+		product := productOf(t, body)
+		productID, _ = product["id"].(string)
+		imagePath, _ = product["image"].(string)
+		if product["name"] != "Chocolate Cookie" || amountOf(t, product["price"]) != "15000.00" {
+			t.Fatalf("unexpected product: %v", product)
+		}
+		if user, _ := product["user"].(map[string]any); user["username"] != "alice" {
+			t.Fatalf("product owner not loaded: %v", product)
+		}
+		if !strings.HasPrefix(imagePath, storage.ImageURLPrefix+"/") {
+			t.Fatalf("image path = %q", imagePath)
+		}
+		if _, err := os.Stat(app.imageFile(imagePath)); err != nil {
+			t.Fatalf("uploaded image not stored: %v", err)
+		}
+	})
+
+	t.Run("uploaded image is served", func(t *testing.T) {
+		resp, err := app.client.Get(app.server.URL + "/" + imagePath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		served, _ := io.ReadAll(resp.Body)
+		if resp.StatusCode != http.StatusOK || !bytes.Equal(served, image) {
+			t.Fatalf("GET /%s = %d %q", imagePath, resp.StatusCode, served)
+		}
+	})
+
+	t.Run("detail", func(t *testing.T) {
+		status, body := app.json(http.MethodGet, api+"/products/"+productID, nil)
+		expectStatus(t, status, http.StatusOK, body)
+		if productOf(t, body)["id"] != productID {
+			t.Fatalf("unexpected product: %v", body)
+		}
+	})
+
+	t.Run("detail of unknown product", func(t *testing.T) {
+		status, body := app.json(http.MethodGet, api+"/products/"+uuid.NewString(), nil)
+		expectStatus(t, status, http.StatusNotFound, body)
+		expectError(t, body, apperrors.CodeProductNotFound)
+
+		status, body = app.json(http.MethodGet, api+"/products/not-a-uuid", nil)
+		expectStatus(t, status, http.StatusBadRequest, body)
+		expectError(t, body, apperrors.CodeInvalidProductID)
+	})
+
+	t.Run("list paginates and searches", func(t *testing.T) {
+		for _, name := range []string{"Vanilla Cookie", "Brownies"} {
+			status, body := app.multipart(http.MethodPost, api+"/products", map[string]string{"name": name, "price": "5000", "user_id": userID}, image)
+			expectStatus(t, status, http.StatusOK, body)
+		}
+
+		status, body := app.json(http.MethodGet, api+"/products?page=1&limit=2", nil)
+		expectStatus(t, status, http.StatusOK, body)
+		if len(body["products"].([]any)) != 2 || body["totalItems"] != float64(3) ||
+			body["totalPages"] != float64(2) || body["hasNextPage"] != true {
+			t.Fatalf("unexpected first page: %v", body)
+		}
+
+		status, body = app.json(http.MethodGet, api+"/products?page=2&limit=2", nil)
+		expectStatus(t, status, http.StatusOK, body)
+		if len(body["products"].([]any)) != 1 || body["hasNextPage"] != false {
+			t.Fatalf("unexpected second page: %v", body)
+		}
+
+		status, body = app.json(http.MethodGet, api+"/products?search=COOKIE", nil)
+		expectStatus(t, status, http.StatusOK, body)
+		if body["totalItems"] != float64(2) {
+			t.Fatalf("search matched %v, want 2", body["totalItems"])
+		}
+
+		// Parameter tidak valid kembali ke nilai default
+		status, body = app.json(http.MethodGet, api+"/products?page=-1&limit=abc", nil)
+		expectStatus(t, status, http.StatusOK, body)
+		if body["page"] != float64(1) || body["limit"] != float64(10) {
+			t.Fatalf("unexpected defaults: %v", body)
+		}
+
+		// Limit terlalu besar dipotong ke batas maksimum
+		status, body = app.json(http.MethodGet, api+"/products?limit=1000000", nil)
+		expectStatus(t, status, http.StatusOK, body)
+		if body["limit"] != float64(services.MaxListLimit) {
+			t.Fatalf("expected limit capped at %d, got %v", services.MaxListLimit, body["limit"])
+		}
+	})
+
+	t.Run("update error paths", func(t *testing.T) {
+		status, body := app.multipart(http.MethodPut, api+"/products/not-a-uuid", map[string]string{"user_id": userID}, nil)
+		expectStatus(t, status, http.StatusBadRequest, body)
+		expectError(t, body, apperrors.CodeInvalidProductID)
+
+		status, body = app.multipart(http.MethodPut, api+"/products/"+uuid.NewString(), map[string]string{"user_id": userID}, nil)
+		expectStatus(t, status, http.StatusNotFound, body)
+		expectError(t, body, apperrors.CodeProductNotFound)
+
+		status, body = app.multipart(http.MethodPut, api+"/products/"+productID, map[string]string{"price": "cheap", "user_id": userID}, nil)
+		expectStatus(t, status, http.StatusBadRequest, body)
+		expectError(t, body, apperrors.CodeValidationFailed)
+		if fieldErrors(t, body)["price"] != "price" {
+			t.Fatalf("unexpected errors: %v", body)
+		}
+
+		status, body = app.multipart(http.MethodPut, api+"/products/"+productID, map[string]string{"name": "Xy"}, nil)
+		expectStatus(t, status, http.StatusBadRequest, body)
+		if fieldErrors(t, body)["user_id"] != "required" {
+			t.Fatalf("unexpected errors: %v", body)
+		}
+	})
+
+	t.Run("update replaces the image", func(t *testing.T) {
+		status, body := app.multipartFile(http.MethodPut, api+"/products/"+productID, map[string]string{"price": "17500", "user_id": userID}, "photo.png", []byte("\x89PNG\r\n\x1a\n new png"))
+		expectStatus(t, status, http.StatusOK, body)
+		product := productOf(t, body)
+		if product["name"] != "Chocolate Cookie" || amountOf(t, product["price"]) != "17500.00" {
+			t.Fatalf("unexpected product: %v", product)
+		}
+		newImage, _ := product["image"].(string)
+		if newImage == imagePath {
+			t.Fatalf("image was not replaced")
+		}
+		// File lama diarsipkan selama masih tercatat di revisi produk
+		app.expectArchived(imagePath)
+		imagePath, replacedPath = newImage, imagePath
+	})
+
+	t.Run("other users cannot modify the product", func(t *testing.T) {
+		other := app.newSession()
+		other.json(http.MethodPost, api+"/register", map[string]string{"username": "mallory", "email": "mallory@example.com", "password": "secret123"})
+		status, body := other.json(http.MethodPost, api+"/login", map[string]string{"username": "mallory", "password": "secret123"})
+		expectStatus(t, status, http.StatusOK, body)
+		otherID := body["user"].(map[string]any)["id"].(string)
+
+		status, body = other.multipart(http.MethodPost, api+"/products", map[string]string{"name": "Fake", "price": "1", "user_id": userID}, image)
+		expectStatus(t, status, http.StatusForbidden, body)
+
+		status, body = other.multipart(http.MethodPut, api+"/products/"+productID, map[string]string{"price": "1", "user_id": otherID}, nil)
+		expectStatus(t, status, http.StatusForbidden, body)
+		expectError(t, body, apperrors.CodeNotProductOwner)
+
+		status, body = app.multipart(http.MethodPut, api+"/products/"+productID, map[string]string{"user_id": otherID}, nil)
+		expectStatus(t, status, http.StatusForbidden, body)
+
+		status, body = other.json(http.MethodDelete, api+"/products/"+productID, nil)
+		expectStatus(t, status, http.StatusForbidden, body)
+	})
+
+	t.Run("delete error paths", func(t *testing.T) {
+		status, body := app.json(http.MethodDelete, api+"/products/not-a-uuid", nil)
+		expectStatus(t, status, http.StatusBadRequest, body)
+		expectError(t, body, apperrors.CodeInvalidProductID)
+
+		status, body = app.json(http.MethodDelete, api+"/products/"+uuid.NewString(), nil)
+		expectStatus(t, status, http.StatusNotFound, body)
+		expectError(t, body, apperrors.CodeProductNotFound)
+	})
+
+	t.Run("delete moves product to the trash and keeps the image", func(t *testing.T) {
+		status, body := app.json(http.MethodDelete, api+"/products/"+productID, nil)
+		expectStatus(t, status, http.StatusOK, body)
+		if _, err := os.Stat(app.imageFile(imagePath)); err != nil {
+			t.Fatalf("image removed on soft delete: %v", err)
+		}
+
+		status, body = app.json(http.MethodGet, api+"/products/"+productID, nil)
+		expectStatus(t, status, http.StatusNotFound, body)
+
+		status, body = app.json(http.MethodGet, api+"/trash", nil)
+		expectStatus(t, status, http.StatusOK, body)
+		trashed, _ := body["products"].([]any)
+		if len(trashed) != 1 || trashed[0].(map[string]any)["id"] != productID || trashed[0].(map[string]any)["DeletedAt"] == nil {
+			t.Fatalf("unexpected trash: %v", body)
+		}
+	})
+
+	t.Run("trash is private to the owner", func(t *testing.T) {
+		other := signIn(t, app, "carol")
+
+		status, body := other.json(http.MethodGet, api+"/trash", nil)
+		expectStatus(t, status, http.StatusOK, body)
+		if body["totalItems"] != float64(0) {
+			t.Fatalf("other user sees trashed products: %v", body)
+		}
+
+		status, body = other.json(http.MethodPost, api+"/trash/"+productID+"/restore", nil)
+		expectStatus(t, status, http.StatusForbidden, body)
+		expectError(t, body, apperrors.CodeNotProductOwner)
+
+		status, body = other.json(http.MethodDelete, api+"/trash/"+productID, nil)
+		expectStatus(t, status, http.StatusForbidden, body)
+	})
+
+	t.Run("restore brings the product back", func(t *testing.T) {
+		status, body := app.json(http.MethodPost, api+"/trash/"+productID+"/restore", nil)
+		expectStatus(t, status, http.StatusOK, body)
+		if product := productOf(t, body); product["DeletedAt"] != nil {
+			t.Fatalf("restored product still marked deleted: %v", product)
+		}
+
+		status, body = app.json(http.MethodGet, api+"/products/"+productID, nil)
+		expectStatus(t, status, http.StatusOK, body)
+
+		// Produk yang tidak ada di trash tidak bisa di-restore lagi
+		status, body = app.json(http.MethodPost, api+"/trash/"+productID+"/restore", nil)
+		expectStatus(t, status, http.StatusNotFound, body)
+		expectError(t, body, apperrors.CodeProductNotFound)
+	})
+
+	t.Run("purge removes product and image", func(t *testing.T) {
+		status, body := app.json(http.MethodDelete, api+"/trash/"+productID, nil)
+		expectStatus(t, status, http.StatusNotFound, body)
+
+		status, body = app.json(http.MethodDelete, api+"/products/"+productID, nil)
+		expectStatus(t, status, http.StatusOK, body)
+
+		status, body = app.json(http.MethodDelete, api+"/trash/"+productID, nil)
+		expectStatus(t, status, http.StatusOK, body)
+		for _, image := range []string{imagePath, replacedPath} {
+			if _, err := os.Stat(app.imageFile(image)); !os.IsNotExist(err) {
+				t.Fatalf("image %s still exists after purge: %v", image, err)
+			}
+		}
+
+		status, body = app.json(http.MethodPost, api+"/trash/"+productID+"/restore", nil)
+		expectStatus(t, status, http.StatusNotFound, body)
+	})
+}
+
+func testProfileFlow(t *testing.T, app *testApp, userID string) {
+	t.Run("profile error paths", func(t *testing.T) {
+		status, body := app.json(http.MethodGet, api+"/profile/not-a-uuid", nil)
+		expectStatus(t, status, http.StatusBadRequest, body)
+		expectError(t, body, apperrors.CodeInvalidUserID)
+
+		status, body = app.json(http.MethodGet, api+"/profile/"+uuid.NewString(), nil)
+		expectStatus(t, status, http.StatusNotFound, body)
+		expectError(t, body, apperrors.CodeUserNotFound)
+
+		// Profil user lain tidak boleh diubah
+		status, body = app.json(http.MethodPut, api+"/profile/"+uuid.NewString(), map[string]string{"email": "x@example.com"})
+		expectStatus(t, status, http.StatusForbidden, body)
+		expectError(t, body, apperrors.CodeNotProfileOwner)
+		eve := signIn(t, app, "eve")
+		status, body = eve.json(http.MethodPut, api+"/profile/"+userID, map[string]string{"email": "eve@example.com", "password": "hijacked"})
+		expectStatus(t, status, http.StatusForbidden, body)
+		expectError(t, body, apperrors.CodeNotProfileOwner)
+
+		// Username user lain dan username admin tidak bisa dipakai dengan mengganti nama
+		_, body = eve.json(http.MethodPost, api+"/login", map[string]string{"username": "eve", "password": "secret123"})
+		eveProfile := api + "/profile/" + body["user"].(map[string]any)["id"].(string)
+		status, body = eve.json(http.MethodPut, eveProfile, map[string]string{"username": "alice", "email": "eve@example.com"})
+		expectStatus(t, status, http.StatusConflict, body)
+		expectError(t, body, apperrors.CodeUserExists)
+		status, body = eve.json(http.MethodPut, eveProfile, map[string]string{"username": "admin", "email": "eve@example.com"})
+		expectStatus(t, status, http.StatusConflict, body)
+		expectError(t, body, apperrors.CodeUsernameReserved)
+
+		status, body = app.json(http.MethodPut, api+"/profile/"+userID, "{")
+		expectStatus(t, status, http.StatusBadRequest, body)
+	})
+
+	t.Run("profile get and update", func(t *testing.T) {
+		status, body := app.json(http.MethodGet, api+"/profile/"+userID, nil)
+		expectStatus(t, status, http.StatusOK, body)
+		if user, _ := body["user"].(map[string]any); user["email"] != "alice@example.com" {
+			t.Fatalf("unexpected profile: %v", body)
+		}
+
+		status, body = app.json(http.MethodPut, api+"/profile/"+userID, map[string]string{
+			"username": "alice", "email": "alice@cookies.test", "password": "newsecret",
+		})
+		expectStatus(t, status, http.StatusOK, body)
+
+		// Password baru harus bisa dipakai untuk login
+		status, body = app.json(http.MethodPost, api+"/login", map[string]string{"username": "alice", "password": "newsecret"})
+		expectStatus(t, status, http.StatusOK, body)
+		if user, _ := body["user"].(map[string]any); user["email"] != "alice@cookies.test" {
+			t.Fatalf("profile update not persisted: %v", body)
+		}
+	})
+}
+
+func testLogoutFlow(t *testing.T, app *testApp) {
+	t.Run("logout clears the cookie", func(t *testing.T) {
+		status, body := app.json(http.MethodGet, api+"/logout", nil)
+		expectStatus(t, status, http.StatusOK, body)
+		if app.tokenCookie() != nil {
+			t.Fatalf("token cookie still present after logout")
+		}
+
+		status, body = app.json(http.MethodGet, api+"/products", nil)
+		expectStatus(t, status, http.StatusUnauthorized, body)
+		expectError(t, body, apperrors.CodeTokenMissing)
+	})
+}
@@ -0,0 +1,114 @@
+package routes_test
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func TestProductSearch(t *testing.T) {
+	for _, b := range backends {
+		t.Run(b.name, func(t *testing.T) {
+			app := newTestApp(t, b)
+			alice := signIn(t, app, "alice")
+			image := []byte("\xff\xd8\xff\xe0 fake jpeg content")
+
+			_, body := alice.json(http.MethodPost, api+"/login", map[string]string{"username": "alice", "password": "secret123"})
+			aliceID := body["user"].(map[string]any)["id"].(string)
+
+			create := func(name string, tags ...string) string {
+				status, body := alice.multipartValues(http.MethodPost, api+"/products", map[string][]string{
+					"name": {name}, "price": {"1000"}, "user_id": {aliceID}, "tags": tags,
+				}, image)
+				expectStatus(t, status, http.StatusOK, body)
+				return productOf(t, body)["id"].(string)
+			}
+			create("Chocolate Chip Cookies", "snack")
+			oat := create("Oat Bar", "cookie")
+			create("Makanan Ringan <Pedas>")
+			create("Lemonade")
+
+			list := func(t *testing.T, query url.Values) (int, map[string]any) {
+				t.Helper()
+				return alice.json(http.MethodGet, api+"/products?"+query.Encode(), nil)
+			}
+
+			t.Run("ranks by relevance", func(t *testing.T) {
+				status, body := list(t, url.Values{"search": {"cookie"}})
+				expectStatus(t, status, http.StatusOK, body)
+				if got := listedNames(t, body); got != "Chocolate Chip Cookies,Oat Bar" {
+					t.Fatalf("unexpected order: %q", got)
+				}
+				products := body["products"].([]any)
+				first := products[0].(map[string]any)["match"].(map[string]any)
+				second := products[1].(map[string]any)["match"].(map[string]any)
+				if first["score"].(float64) <= second["score"].(float64) {
+					t.Errorf("expected descending scores, got %v and %v", first, second)
+				}
+				if first["highlight"] != "Chocolate Chip <mark>Cookies</mark>" || second["highlight"] != "<mark>cookie</mark>" {
+					t.Errorf("unexpected highlights %q and %q", first["highlight"], second["highlight"])
+				}
+
+				// Sort lain tetap bisa dipakai bersama search
+				status, body = list(t, url.Values{"search": {"cookie"}, "sort": {"name"}})
+				expectStatus(t, status, http.StatusOK, body)
+				if got := listedNames(t, body); got != "Chocolate Chip Cookies,Oat Bar" {
+					t.Fatalf("unexpected name order: %q", got)
+				}
+			})
+
+			t.Run("matches word forms and prefixes", func(t *testing.T) {
+				cases := map[string]string{
+					"makan":   "Makanan Ringan <Pedas>",
+					"choc":    "Chocolate Chip Cookies",
+					"the":     "",
+					"lemonad": "Lemonade",
+				}
+				for query, want := range cases {
+					status, body := list(t, url.Values{"search": {query}})
+					expectStatus(t, status, http.StatusOK, body)
+					if got := listedNames(t, body); got != want {
+						t.Errorf("%s: got %q, want %q", query, got, want)
+					}
+				}
+
+				_, body := list(t, url.Values{"search": {"pedas"}})
+				match := body["products"].([]any)[0].(map[string]any)["match"].(map[string]any)
+				if match["highlight"] != "Makanan Ringan &lt;<mark>Pedas</mark>&gt;" {
+					t.Errorf("highlight is not escaped: %q", match["highlight"])
+				}
+			})
+
+			t.Run("follows updates and deletes", func(t *testing.T) {
+				status, body := alice.multipartValues(http.MethodPut, api+"/products/"+oat, map[string][]string{
+					"user_id": {aliceID}, "tags": {"healthy"},
+				}, nil)
+				expectStatus(t, status, http.StatusOK, body)
+				_, body = list(t, url.Values{"search": {"cookie"}})
+				if got := listedNames(t, body); got != "Chocolate Chip Cookies" {
+					t.Fatalf("updated tags still match: %q", got)
+				}
+
+				status, body = alice.json(http.MethodDelete, api+"/products/"+oat, nil)
+				expectStatus(t, status, http.StatusOK, body)
+				_, body = list(t, url.Values{"search": {"healthy"}})
+				if got := listedNames(t, body); got != "" {
+					t.Fatalf("deleted product still matches: %q", got)
+				}
+			})
+
+			t.Run("relevance requires search", func(t *testing.T) {
+				status, body := list(t, url.Values{"sort": {"relevance"}})
+				expectStatus(t, status, http.StatusBadRequest, body)
+				if fields := fieldErrors(t, body); fields["sort"] != "required_with" {
+					t.Fatalf("expected sort required_with, got %v", body)
+				}
+
+				_, body = list(t, url.Values{})
+				if _, ok := body["products"].([]any)[0].(map[string]any)["match"]; ok {
+					t.Errorf("match should only be set when searching: %v", body)
+				}
+			})
+		})
+	}
+}
@@ -0,0 +1,525 @@
+package routes
+
+import (
+	"net/http"
+	"server-cookie/apperrors"
+	"server-cookie/controllers"
+	"server-cookie/money"
+	"server-cookie/openapi"
+)
+
+// V1 adalah versi pertama API, juga dilayani di root sebagai route lama
+var V1 = Version{
+	Name:      "v1",
+	Endpoints: v1Endpoints,
+	Register:  registerV1,
+}
+
+func registerV1(g Groups, h Handlers) {
+	g.Public.POST("/register", h.User.Register)
+	g.Public.POST("/login", h.User.Login)
+
+	g.Protected.GET("/logout", h.User.Logout)
+	g.Protected.GET("/products", h.Product.GetAllProducts)
+	g.Protected.GET("/products/export", h.Bulk.ExportProducts)
+	g.Protected.POST("/products/import", h.Bulk.ImportProducts)
+	g.Protected.POST("/products/batch", h.Product.BatchProducts)
+	g.Protected.GET("/imports/:id", h.Bulk.GetImportJob)
+	g.Protected.GET("/products/:id", h.Product.GetProductDetail)
+	g.Protected.DELETE("/products/:id", h.Product.DeleteProduct)
+	g.Protected.PUT("/products/:id", h.Product.UpdateProduct)
+	g.Protected.PUT("/products/:id/status", h.Product.SetProductStatus)
+	g.Protected.POST("/products", h.Product.CreateProduct)
+	g.Protected.POST("/products/:id/images", h.Product.AddProductImages)
+	g.Protected.PUT("/products/:id/images", h.Product.ReorderProductImages)
+	g.Protected.DELETE("/products/:id/images/:imageId", h.Product.DeleteProductImage)
+	g.Protected.PUT("/products/:id/options", h.Product.SetProductOptions)
+	g.Protected.POST("/products/:id/variants", h.Product.CreateProductVariant)
+	g.Protected.PUT("/products/:id/variants/:variantId", h.Product.UpdateProductVariant)
+	g.Protected.DELETE("/products/:id/variants/:variantId", h.Product.DeleteProductVariant)
+	g.Protected.GET("/products/:id/revisions", h.Product.ListProductRevisions)
+	g.Protected.GET("/products/:id/revisions/diff", h.Product.DiffProductRevisions)
+	g.Protected.GET("/products/:id/revisions/:number", h.Product.GetProductRevision)
+	g.Protected.POST("/products/:id/revisions/:number/rollback", h.Product.RollbackProduct)
+	g.Protected.GET("/products/:id/inventory", h.Inventory.GetInventory)
+	g.Protected.POST("/products/:id/inventory/adjustments", h.Inventory.AdjustStock)
+	g.Protected.PUT("/products/:id/inventory/threshold", h.Inventory.SetStockThreshold)
+	g.Protected.GET("/products/:id/inventory/movements", h.Inventory.ListStockMovements)
+	g.Protected.POST("/products/:id/reservations", h.Inventory.ReserveStock)
+	g.Protected.POST("/reservations/:id/confirm", h.Inventory.ConfirmReservation)
+	g.Protected.DELETE("/reservations/:id", h.Inventory.ReleaseReservation)
+	g.Protected.GET("/tags", h.Product.ListTags)
+	g.Protected.GET("/categories", h.Category.ListCategories)
+	g.Admin.POST("/categories", h.Category.CreateCategory)
+	g.Admin.PUT("/categories/:id", h.Category.UpdateCategory)
+	g.Admin.DELETE("/categories/:id", h.Category.DeleteCategory)
+	g.Protected.GET("/exchange-rates", h.ExchangeRate.ListExchangeRates)
+	g.Admin.POST("/exchange-rates/import", h.ExchangeRate.ImportExchangeRates)
+	g.Admin.PUT("/exchange-rates/:base/:quote", h.ExchangeRate.SetExchangeRate)
+	g.Admin.DELETE("/exchange-rates/:base/:quote", h.ExchangeRate.DeleteExchangeRate)
+	g.Protected.GET("/trash", h.Product.ListTrash)
+	g.Protected.POST("/trash/:id/restore", h.Product.RestoreProduct)
+	g.Protected.DELETE("/trash/:id", h.Product.PurgeProduct)
+	g.Protected.GET("/profile/:id", h.User.GetProfile)
+	g.Protected.PUT("/profile/:id", h.User.UpdateProfile)
+}
+
+// v1Endpoints adalah deskripsi route v1 untuk dokumen OpenAPI.
+// Setiap route di registerV1 harus punya entri di sini (dicek oleh test).
+var v1Endpoints = []openapi.Endpoint{
+	{
+		Method: http.MethodPost, Path: "/register", Tag: "auth",
+		Summary:  "Register a new user",
+		JSONBody: controllers.RegisterRequest{},
+		Status:   http.StatusCreated,
+		Response: controllers.MessageResponse{},
+		Errors:   []apperrors.Code{apperrors.CodeInvalidJSON, apperrors.CodeValidationFailed, apperrors.CodeUserExists},
+	},
+	{
+		Method: http.MethodPost, Path: "/login", Tag: "auth",
+		Summary:  "Log in and receive the token cookie",
+		JSONBody: controllers.LoginRequest{},
+		Response: controllers.UserEnvelope{},
+		Errors:   []apperrors.Code{apperrors.CodeInvalidJSON, apperrors.CodeInvalidCredentials},
+	},
+	{
+		Method: http.MethodGet, Path: "/logout", Tag: "auth", Auth: true,
+		Summary:  "Clear the token cookie",
+		Response: controllers.MessageResponse{},
+	},
+	{
+		Method: http.MethodGet, Path: "/products", Tag: "products", Auth: true,
+		Summary: "List published products with pagination, filters and sorting",
+		Query: []openapi.Parameter{
+			{Name: "page", In: "query", Description: "Page number, starting at 1. Ignored when cursor is set", Schema: &openapi.Schema{Type: "integer"}},
+			{Name: "limit", In: "query", Description: "Items per page, default 10 and at most 100", Schema: &openapi.Schema{Type: "integer"}},
+			{Name: "search", In: "query", Description: "Full-text search in product names and tags, English and Indonesian word forms match (cookies, cookie; makanan, makan). The last word also matches as a prefix. Matching products get a match object with score and highlight. Only the 1000 most relevant matches are listed and counted, search_truncated is true when more products match", Schema: &openapi.Schema{Type: "string"}},
+			{Name: "category", In: "query", Description: "Filter by category ID, including its sub-categories", Schema: &openapi.Schema{Type: "string", Format: "uuid"}},
+			{Name: "tags", In: "query", Description: "Filter by tags, comma-separated or repeated", Schema: &openapi.Schema{Type: "string"}},
+			{Name: "tag_mode", In: "query", Description: "Match products with any (default) or all of the tags", Schema: &openapi.Schema{Type: "string", Enum: []string{"any", "all"}}},
+			{Name: "price_currency", In: "query", Description: "Only products priced in this ISO 4217 currency. Defaults to IDR when min_price or max_price is set", Schema: &openapi.Schema{Type: "string", Enum: money.Currencies()}},
+			{Name: "min_price", In: "query", Description: "Minimum decimal price in price_currency, inclusive. Products match when any variant price is in range", Schema: &openapi.Schema{Type: "string", Pattern: `^[0-9]+(\.[0-9]+)?$`}},
+			{Name: "max_price", In: "query", Description: "Maximum decimal price in price_currency, inclusive. Products match when any variant price is in range", Schema: &openapi.Schema{Type: "string", Pattern: `^[0-9]+(\.[0-9]+)?$`}},
+			{Name: "owner", In: "query", Description: "Filter by the owner's user ID", Schema: &openapi.Schema{Type: "string", Format: "uuid"}},
+			{Name: "status", In: "query", Description: "Product status, default published. draft and archived only list the caller's own products", Schema: &openapi.Schema{Type: "string", Enum: []string{"draft", "published", "archived"}}},
+			{Name: "created_after", In: "query", Description: "Created at or after this RFC 3339 date-time or YYYY-MM-DD date", Schema: &openapi.Schema{Type: "string"}},
+			{Name: "created_before", In: "query", Description: "Created before this RFC 3339 date-time or YYYY-MM-DD date", Schema: &openapi.Schema{Type: "string"}},
+			{Name: "low_stock", In: "query", Description: "Only products with an item at or below its low stock threshold, usually combined with owner", Schema: &openapi.Schema{Type: "boolean"}},
+			{Name: "has_image", In: "query", Description: "Only products with (true) or without (false) an image", Schema: &openapi.Schema{Type: "boolean"}},
+			{Name: "sort", In: "query", Description: "Comma-separated sort fields (name, price, created_at, updated_at, relevance), prefix with - for descending, e.g. -price,name. price sorts by the lowest variant price in minor units, so combine it with price_currency when products use several currencies. relevance requires search and sorts the best match first. Default relevance when searching, otherwise -created_at", Schema: &openapi.Schema{Type: "string"}},
+			{Name: "cursor", In: "query", Description: "next_cursor or prev_cursor from an earlier page, sent with the same filters and sort. Switches to keyset pagination", Schema: &openapi.Schema{Type: "string"}},
+			{Name: "include_total", In: "query", Description: "Count totalItems and totalPages. Default true without a cursor and false with one", Schema: &openapi.Schema{Type: "boolean"}},
+			currencyParameter,
+		},
+		Response: controllers.ProductListResponse{},
+		Errors: []apperrors.Code{
+			apperrors.CodeInvalidCategoryID, apperrors.CodeCategoryNotFound, apperrors.CodeValidationFailed,
+			apperrors.CodeInvalidCursor, apperrors.CodeNotProductOwner,
+		},
+	},
+	{
+		Method: http.MethodPost, Path: "/products", Tag: "products", Auth: true,
+		Summary: "Create a product with up to 10 images, the first one is the primary image. " +
+			"Products are published right away unless status is draft or publish_at schedules the publication",
+		FormBody: controllers.CreateProductForm{},
+		Response: controllers.ProductEnvelope{},
+		Errors: []apperrors.Code{
+			apperrors.CodeInvalidForm, apperrors.CodeValidationFailed,
+			apperrors.CodeOwnerMismatch, apperrors.CodeImageUploadFailed,
+		},
+	},
+	{
+		Method: http.MethodGet, Path: "/products/export", Tag: "import", Auth: true,
+		Summary: "Download active products, oldest first, as a CSV or JSON Lines file with the columns " +
+			"id, external_id, sku, name, price, currency, category_ids, tags and images. CSV lists are separated by |. " +
+			"The caller's products are exported with every status, scope all only exports published products",
+		Query: []openapi.Parameter{
+			{Name: "format", In: "query", Description: "File format, default csv", Schema: &openapi.Schema{Type: "string", Enum: []string{"csv", "jsonl"}}},
+			{Name: "scope", In: "query", Description: "Export the caller's products (default) or the products of all users", Schema: &openapi.Schema{Type: "string", Enum: []string{"mine", "all"}}},
+		},
+		Produces: []string{"text/csv", "application/x-ndjson"},
+		Errors:   []apperrors.Code{apperrors.CodeValidationFailed},
+	},
+	{
+		Method: http.MethodPost, Path: "/products/import", Tag: "import", Auth: true,
+		Summary: "Create or update the caller's products from a CSV or JSON Lines file in the export format. " +
+			"Rows match a product by external_id, then by sku, and empty fields are left unchanged. " +
+			"images hold http(s) URLs, file names in the images ZIP archive or paths of images the product already has. " +
+			"Every row is validated and failed rows are reported in job.errors without stopping the others. " +
+			"dry_run only validates. Large files run in the background: the response is 202 with a Location header to poll",
+		FormBody: controllers.ImportProductsForm{},
+		Response: controllers.ImportJobEnvelope{},
+		Errors: []apperrors.Code{
+			apperrors.CodeInvalidForm, apperrors.CodeValidationFailed,
+			apperrors.CodeInvalidImportFile, apperrors.CodeInvalidImportArchive,
+		},
+	},
+	{
+		Method: http.MethodPost, Path: "/products/batch", Tag: "products", Auth: true,
+		Summary: "Run up to 100 update, delete and categorize operations on the caller's products. " +
+			"In atomic mode (default) all operations are saved in one transaction or none are, " +
+			"in best_effort mode every valid operation is saved. Each result has its own status and error, " +
+			"image files removed by an update are deleted only after the change is saved",
+		JSONBody: controllers.BatchProductsRequest{},
+		Response: controllers.BatchProductsResponse{},
+		Errors:   []apperrors.Code{apperrors.CodeInvalidJSON, apperrors.CodeValidationFailed},
+	},
+	{
+		Method: http.MethodGet, Path: "/imports/:id", Tag: "import", Auth: true,
+		Summary:  "Get the progress and row errors of one of the caller's import jobs",
+		Response: controllers.ImportJobEnvelope{},
+		Errors:   []apperrors.Code{apperrors.CodeInvalidImportID, apperrors.CodeImportNotFound},
+	},
+	{
+		Method: http.MethodGet, Path: "/products/:id", Tag: "products", Auth: true,
+		Summary:  "Get a product, the ETag header holds its version for If-Match. Products that are not published are only visible to their owner",
+		Query:    []openapi.Parameter{currencyParameter},
+		Response: controllers.ProductEnvelope{},
+		Errors:   []apperrors.Code{apperrors.CodeInvalidProductID, apperrors.CodeProductNotFound, apperrors.CodeValidationFailed},
+	},
+	{
+		Method: http.MethodPut, Path: "/products/:id", Tag: "products", Auth: true,
+		Summary:  "Update a product, empty fields are left unchanged and sent images replace all current images",
+		Headers:  []openapi.Parameter{ifMatchParameter},
+		FormBody: controllers.UpdateProductForm{},
+		Response: controllers.ProductEnvelope{},
+		Errors: []apperrors.Code{
+			apperrors.CodeInvalidProductID, apperrors.CodeInvalidForm, apperrors.CodeValidationFailed,
+			apperrors.CodeProductNotFound, apperrors.CodeNotProductOwner, apperrors.CodeOwnerMismatch,
+			apperrors.CodeImageUploadFailed, apperrors.CodeVersionMismatch, apperrors.CodeProductModified,
+		},
+	},
+	{
+		Method: http.MethodPut, Path: "/products/:id/status", Tag: "products", Auth: true,
+		Summary: "Change the status and publication schedule of a product. Allowed transitions: " +
+			"draft to published or archived, published to draft or archived, archived to draft. " +
+			"publish_at publishes a draft and unpublish_at archives a published product at that time, " +
+			"a schedule that is not sent is removed",
+		Headers:  []openapi.Parameter{ifMatchParameter},
+		JSONBody: controllers.ProductStatusRequest{},
+		Response: controllers.ProductEnvelope{},
+		Errors: []apperrors.Code{
+			apperrors.CodeInvalidProductID, apperrors.CodeInvalidJSON, apperrors.CodeValidationFailed,
+			apperrors.CodeProductNotFound, apperrors.CodeNotProductOwner, apperrors.CodeInvalidStatusTransition,
+			apperrors.CodeVersionMismatch, apperrors.CodeProductModified,
+		},
+	},
+	{
+		Method: http.MethodDelete, Path: "/products/:id", Tag: "products", Auth: true,
+		Summary:  "Move a product to the trash",
+		Headers:  []openapi.Parameter{ifMatchParameter},
+		Response: controllers.DeleteProductResponse{},
+		Errors: []apperrors.Code{
+			apperrors.CodeInvalidProductID, apperrors.CodeProductNotFound, apperrors.CodeNotProductOwner,
+			apperrors.CodeVersionMismatch, apperrors.CodeProductModified,
+		},
+	},
+	{
+		Method: http.MethodPost, Path: "/products/:id/images", Tag: "products", Auth: true,
+		Summary:  "Append images to a product, up to 10 images in total",
+		FormBody: controllers.AddImagesForm{},
+		Response: controllers.ProductEnvelope{},
+		Errors: []apperrors.Code{
+			apperrors.CodeInvalidProductID, apperrors.CodeInvalidForm, apperrors.CodeValidationFailed,
+			apperrors.CodeProductNotFound, apperrors.CodeNotProductOwner, apperrors.CodeImageUploadFailed,
+		},
+	},
+	{
+		Method: http.MethodPut, Path: "/products/:id/images", Tag: "products", Auth: true,
+		Summary:  "Reorder a product's images and change their alt text or the primary image",
+		JSONBody: controllers.ReorderImagesRequest{},
+		Response: controllers.ProductEnvelope{},
+		Errors: []apperrors.Code{
+			apperrors.CodeInvalidProductID, apperrors.CodeInvalidJSON, apperrors.CodeValidationFailed,
+			apperrors.CodeProductNotFound, apperrors.CodeNotProductOwner,
+		},
+	},
+	{
+		Method: http.MethodDelete, Path: "/products/:id/images/:imageId", Tag: "products", Auth: true,
+		Summary:  "Remove a product image, its file is kept while a revision still uses it",
+		Response: controllers.ProductEnvelope{},
+		Errors: []apperrors.Code{
+			apperrors.CodeInvalidProductID, apperrors.CodeInvalidImageID, apperrors.CodeProductNotFound,
+			apperrors.CodeNotProductOwner, apperrors.CodeImageNotFound,
+		},
+	},
+	{
+		Method: http.MethodPut, Path: "/products/:id/options", Tag: "products", Auth: true,
+		Summary:  "Replace a product's variant options (up to 3, e.g. size and color). Existing variants must still match the new options",
+		JSONBody: controllers.ProductOptionsRequest{},
+		Response: controllers.ProductEnvelope{},
+		Errors: []apperrors.Code{
+			apperrors.CodeInvalidProductID, apperrors.CodeInvalidJSON, apperrors.CodeValidationFailed,
+			apperrors.CodeProductNotFound, apperrors.CodeNotProductOwner, apperrors.CodeOptionInUse,
+		},
+	},
+	{
+		Method: http.MethodPost, Path: "/products/:id/variants", Tag: "products", Auth: true,
+		Summary:  "Add a variant with one value for each option, up to 100 variants per product",
+		JSONBody: controllers.VariantRequest{},
+		Status:   http.StatusCreated,
+		Response: controllers.ProductEnvelope{},
+		Errors: []apperrors.Code{
+			apperrors.CodeInvalidProductID, apperrors.CodeInvalidJSON, apperrors.CodeValidationFailed,
+			apperrors.CodeProductNotFound, apperrors.CodeNotProductOwner, apperrors.CodeVariantExists,
+			apperrors.CodeSKUTaken,
+		},
+	},
+	{
+		Method: http.MethodPut, Path: "/products/:id/variants/:variantId", Tag: "products", Auth: true,
+		Summary:  "Replace a variant's SKU, price override, image and option values",
+		JSONBody: controllers.VariantRequest{},
+		Response: controllers.ProductEnvelope{},
+		Errors: []apperrors.Code{
+			apperrors.CodeInvalidProductID, apperrors.CodeInvalidVariantID, apperrors.CodeInvalidJSON,
+			apperrors.CodeValidationFailed, apperrors.CodeProductNotFound, apperrors.CodeNotProductOwner,
+			apperrors.CodeVariantNotFound, apperrors.CodeVariantExists, apperrors.CodeSKUTaken,
+		},
+	},
+	{
+		Method: http.MethodDelete, Path: "/products/:id/variants/:variantId", Tag: "products", Auth: true,
+		Summary:  "Delete a product variant",
+		Response: controllers.ProductEnvelope{},
+		Errors: []apperrors.Code{
+			apperrors.CodeInvalidProductID, apperrors.CodeInvalidVariantID, apperrors.CodeProductNotFound,
+			apperrors.CodeNotProductOwner, apperrors.CodeVariantNotFound,
+		},
+	},
+	{
+		Method: http.MethodGet, Path: "/products/:id/revisions", Tag: "revisions", Auth: true,
+		Summary: "List the revisions of a product, newest first. Trashed products keep their history",
+		Query: []openapi.Parameter{
+			{Name: "page", In: "query", Description: "Page number, starting at 1", Schema: &openapi.Schema{Type: "integer"}},
+			{Name: "limit", In: "query", Description: "Items per page, default 20", Schema: &openapi.Schema{Type: "integer"}},
+		},
+		Response: controllers.RevisionListResponse{},
+		Errors:   []apperrors.Code{apperrors.CodeInvalidProductID, apperrors.CodeProductNotFound, apperrors.CodeNotProductOwner},
+	},
+	{
+		Method: http.MethodGet, Path: "/products/:id/revisions/diff", Tag: "revisions", Auth: true,
+		Summary: "Compare two revisions of a product field by field",
+		Query: []openapi.Parameter{
+			{Name: "from", In: "query", Required: true, Description: "Number of the older revision", Schema: &openapi.Schema{Type: "integer"}},
+			{Name: "to", In: "query", Required: true, Description: "Number of the newer revision", Schema: &openapi.Schema{Type: "integer"}},
+		},
+		Response: controllers.RevisionDiffResponse{},
+		Errors: []apperrors.Code{
+			apperrors.CodeInvalidProductID, apperrors.CodeInvalidRevision, apperrors.CodeProductNotFound,
+			apperrors.CodeNotProductOwner, apperrors.CodeRevisionNotFound,
+		},
+	},
+	{
+		Method: http.MethodGet, Path: "/products/:id/revisions/:number", Tag: "revisions", Auth: true,
+		Summary:  "Get a revision of a product with its snapshot",
+		Response: controllers.RevisionEnvelope{},
+		Errors: []apperrors.Code{
+			apperrors.CodeInvalidProductID, apperrors.CodeInvalidRevision, apperrors.CodeProductNotFound,
+			apperrors.CodeNotProductOwner, apperrors.CodeRevisionNotFound,
+		},
+	},
+	{
+		Method: http.MethodPost, Path: "/products/:id/revisions/:number/rollback", Tag: "revisions", Auth: true,
+		Summary:  "Restore a product to a revision and record it as a new revision. Stock is not changed and deleted categories are skipped",
+		Response: controllers.ProductEnvelope{},
+		Errors: []apperrors.Code{
+			apperrors.CodeInvalidProductID, apperrors.CodeInvalidRevision, apperrors.CodeProductNotFound,
+			apperrors.CodeNotProductOwner, apperrors.CodeRevisionNotFound, apperrors.CodeSKUTaken,
+		},
+	},
+	{
+		Method: http.MethodGet, Path: "/products/:id/inventory", Tag: "inventory", Auth: true,
+		Summary:  "Get the stock of a product, one item per variant. Only the product owner can see it",
+		Response: controllers.InventoryResponse{},
+		Errors:   []apperrors.Code{apperrors.CodeInvalidProductID, apperrors.CodeProductNotFound, apperrors.CodeNotProductOwner},
+	},
+	{
+		Method: http.MethodPost, Path: "/products/:id/inventory/adjustments", Tag: "inventory", Auth: true,
+		Summary:  "Add or remove stock and record it in the ledger. Stock cannot drop below the reserved quantity",
+		JSONBody: controllers.AdjustStockRequest{},
+		Response: controllers.StockLevelEnvelope{},
+		Errors: []apperrors.Code{
+			apperrors.CodeInvalidProductID, apperrors.CodeInvalidJSON, apperrors.CodeValidationFailed,
+			apperrors.CodeProductNotFound, apperrors.CodeNotProductOwner, apperrors.CodeVariantNotFound,
+			apperrors.CodeInsufficientStock,
+		},
+	},
+	{
+		Method: http.MethodPut, Path: "/products/:id/inventory/threshold", Tag: "inventory", Auth: true,
+		Summary:  "Set the low stock threshold of a product or variant, 0 turns the warning off",
+		JSONBody: controllers.StockThresholdRequest{},
+		Response: controllers.StockLevelEnvelope{},
+		Errors: []apperrors.Code{
+			apperrors.CodeInvalidProductID, apperrors.CodeInvalidJSON, apperrors.CodeValidationFailed,
+			apperrors.CodeProductNotFound, apperrors.CodeNotProductOwner, apperrors.CodeVariantNotFound,
+		},
+	},
+	{
+		Method: http.MethodGet, Path: "/products/:id/inventory/movements", Tag: "inventory", Auth: true,
+		Summary: "List the stock ledger of a product, newest first",
+		Query: []openapi.Parameter{
+			{Name: "variant_id", In: "query", Description: "Only movements of this variant", Schema: &openapi.Schema{Type: "string", Format: "uuid"}},
+			{Name: "page", In: "query", Description: "Page number, starting at 1", Schema: &openapi.Schema{Type: "integer"}},
+			{Name: "limit", In: "query", Description: "Items per page, default 20", Schema: &openapi.Schema{Type: "integer"}},
+		},
+		Response: controllers.MovementListResponse{},
+		Errors: []apperrors.Code{
+			apperrors.CodeInvalidProductID, apperrors.CodeInvalidVariantID, apperrors.CodeProductNotFound,
+			apperrors.CodeNotProductOwner, apperrors.CodeVariantNotFound,
+		},
+	},
+	{
+		Method: http.MethodPost, Path: "/products/:id/reservations", Tag: "inventory", Auth: true,
+		Summary:  "Hold stock until the reservation is confirmed, released or expires",
+		JSONBody: controllers.ReserveStockRequest{},
+		Status:   http.StatusCreated,
+		Response: controllers.ReservationEnvelope{},
+		Errors: []apperrors.Code{
+			apperrors.CodeInvalidProductID, apperrors.CodeInvalidJSON, apperrors.CodeValidationFailed,
+			apperrors.CodeProductNotFound, apperrors.CodeVariantNotFound, apperrors.CodeInsufficientStock,
+		},
+	},
+	{
+		Method: http.MethodPost, Path: "/reservations/:id/confirm", Tag: "inventory", Auth: true,
+		Summary:  "Confirm your pending reservation, the reserved stock is recorded as a sale",
+		Response: controllers.ReservationEnvelope{},
+		Errors: []apperrors.Code{
+			apperrors.CodeInvalidReservationID, apperrors.CodeReservationNotFound, apperrors.CodeNotReservationOwner,
+			apperrors.CodeReservationClosed,
+		},
+	},
+	{
+		Method: http.MethodDelete, Path: "/reservations/:id", Tag: "inventory", Auth: true,
+		Summary:  "Release your pending reservation and return its stock",
+		Response: controllers.ReservationEnvelope{},
+		Errors: []apperrors.Code{
+			apperrors.CodeInvalidReservationID, apperrors.CodeReservationNotFound, apperrors.CodeNotReservationOwner,
+			apperrors.CodeReservationClosed,
+		},
+	},
+	{
+		Method: http.MethodGet, Path: "/tags", Tag: "categories", Auth: true,
+		Summary:  "List tags with the number of active products using each",
+		Response: controllers.TagListResponse{},
+	},
+	{
+		Method: http.MethodGet, Path: "/categories", Tag: "categories", Auth: true,
+		Summary:  "List all categories as a tree",
+		Response: controllers.CategoryTreeResponse{},
+	},
+	{
+		Method: http.MethodPost, Path: "/categories", Tag: "categories", Auth: true,
+		Summary:  "Create a category (admin only)",
+		JSONBody: controllers.CategoryRequest{},
+		Status:   http.StatusCreated,
+		Response: controllers.CategoryEnvelope{},
+		Errors: []apperrors.Code{
+			apperrors.CodeAdminRequired, apperrors.CodeInvalidJSON, apperrors.CodeValidationFailed,
+			apperrors.CodeCategoryExists,
+		},
+	},
+	{
+		Method: http.MethodPut, Path: "/categories/:id", Tag: "categories", Auth: true,
+		Summary:  "Rename or move a category (admin only)",
+		JSONBody: controllers.CategoryRequest{},
+		Response: controllers.CategoryEnvelope{},
+		Errors: []apperrors.Code{
+			apperrors.CodeAdminRequired, apperrors.CodeInvalidCategoryID, apperrors.CodeInvalidJSON,
+			apperrors.CodeValidationFailed, apperrors.CodeCategoryNotFound, apperrors.CodeCategoryExists,
+		},
+	},
+	{
+		Method: http.MethodDelete, Path: "/categories/:id", Tag: "categories", Auth: true,
+		Summary:  "Delete a category without sub-categories (admin only)",
+		Response: controllers.DeleteCategoryResponse{},
+		Errors: []apperrors.Code{
+			apperrors.CodeAdminRequired, apperrors.CodeInvalidCategoryID, apperrors.CodeCategoryNotFound,
+			apperrors.CodeCategoryHasChildren,
+		},
+	},
+	{
+		Method: http.MethodGet, Path: "/exchange-rates", Tag: "exchange rates", Auth: true,
+		Summary:  "List exchange rates, stale is true when as_of is older than the configured maximum age",
+		Response: controllers.ExchangeRateListResponse{},
+	},
+	{
+		Method: http.MethodPost, Path: "/exchange-rates/import", Tag: "exchange rates", Auth: true,
+		Summary: "Import exchange rates from a CSV file with the header base,quote,rate and an optional as_of column (admin only). " +
+			"All rows are saved or none, row errors are reported as rows[<line>].<column>",
+		FormBody: controllers.ImportExchangeRatesForm{},
+		Response: controllers.ImportExchangeRatesResponse{},
+		Errors: []apperrors.Code{
+			apperrors.CodeAdminRequired, apperrors.CodeInvalidForm, apperrors.CodeInvalidCSV,
+			apperrors.CodeValidationFailed,
+		},
+	},
+	{
+		Method: http.MethodPut, Path: "/exchange-rates/:base/:quote", Tag: "exchange rates", Auth: true,
+		Summary:  "Set the rate for one currency pair, 1 base = rate quote (admin only). The reverse direction is a separate rate",
+		JSONBody: controllers.ExchangeRateRequest{},
+		Response: controllers.ExchangeRateEnvelope{},
+		Errors: []apperrors.Code{
+			apperrors.CodeAdminRequired, apperrors.CodeInvalidJSON, apperrors.CodeValidationFailed,
+		},
+	},
+	{
+		Method: http.MethodDelete, Path: "/exchange-rates/:base/:quote", Tag: "exchange rates", Auth: true,
+		Summary:  "Delete the rate for one currency pair (admin only)",
+		Response: controllers.MessageResponse{},
+		Errors:   []apperrors.Code{apperrors.CodeAdminRequired, apperrors.CodeExchangeRateNotFound},
+	},
+	{
+		Method: http.MethodGet, Path: "/trash", Tag: "trash", Auth: true,
+		Summary: "List the caller's trashed products, most recently deleted first",
+		Query: []openapi.Parameter{
+			{Name: "page", In: "query", Description: "Page number, starting at 1", Schema: &openapi.Schema{Type: "integer"}},
+			{Name: "limit", In: "query", Description: "Items per page, default 10 and at most 100", Schema: &openapi.Schema{Type: "integer"}},
+		},
+		Response: controllers.ProductListResponse{},
+	},
+	{
+		Method: http.MethodPost, Path: "/trash/:id/restore", Tag: "trash", Auth: true,
+		Summary:  "Restore a product from the trash",
+		Response: controllers.ProductEnvelope{},
+		Errors:   []apperrors.Code{apperrors.CodeInvalidProductID, apperrors.CodeProductNotFound, apperrors.CodeNotProductOwner},
+	},
+	{
+		Method: http.MethodDelete, Path: "/trash/:id", Tag: "trash", Auth: true,
+		Summary:  "Permanently delete a trashed product with its revisions and images",
+		Response: controllers.DeleteProductResponse{},
+		Errors:   []apperrors.Code{apperrors.CodeInvalidProductID, apperrors.CodeProductNotFound, apperrors.CodeNotProductOwner},
+	},
+	{
+		Method: http.MethodGet, Path: "/profile/:id", Tag: "profile", Auth: true,
+		Summary:  "Get a user profile",
+		Response: controllers.UserEnvelope{},
+		Errors:   []apperrors.Code{apperrors.CodeInvalidUserID, apperrors.CodeUserNotFound},
+	},
+	{
+		Method: http.MethodPut, Path: "/profile/:id", Tag: "profile", Auth: true,
+		Summary:  "Update the caller's own profile",
+		JSONBody: controllers.UpdateProfileRequest{},
+		Response: controllers.UserEnvelope{},
+		Errors: []apperrors.Code{
+			apperrors.CodeInvalidUserID, apperrors.CodeNotProfileOwner, apperrors.CodeUserNotFound,
+			apperrors.CodeInvalidJSON, apperrors.CodeValidationFailed, apperrors.CodeUserExists,
+			apperrors.CodeUsernameReserved,
+		},
+	},
+}
+
+// ifMatchParameter membatalkan perubahan jika produk sudah diubah sejak dimuat
+var ifMatchParameter = openapi.Parameter{
+	Name: "If-Match", In: "header",
+	Description: "ETag of the product as last loaded, for example \"3\". The request fails with version_mismatch (412) " +
+		"when the product has a different version. Without it the change is applied to the latest version",
+	Schema: &openapi.Schema{Type: "string"},
+}
+
+// currencyParameter meminta harga produk juga dikirim dalam mata uang lain
+var currencyParameter = openapi.Parameter{
+	Name: "currency", In: "query",
+	Description: "Also show prices in this ISO 4217 currency as converted_price, next to the original price. " +
+		"Defaults to the caller's profile currency. Uses the direct rate from the product currency, rounded half-even to the minor unit. " +
+		"warning is rate_stale when the rate is older than the maximum age and rate_unavailable when there is no rate",
+	Schema: &openapi.Schema{Type: "string", Enum: money.Currencies()},
+}
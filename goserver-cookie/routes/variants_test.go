@@ -0,0 +1,160 @@
+package routes_test
+
+import (
+	"net/http"
+	"server-cookie/apperrors"
+	"testing"
+)
+
+// productVariants mengembalikan daftar varian produk di response
+func productVariants(t *testing.T, body map[string]any) []map[string]any {
+	t.Helper()
+	items := productOf(t, body)["variants"].([]any)
+	variants := make([]map[string]any, 0, len(items))
+	for _, item := range items {
+		variants = append(variants, item.(map[string]any))
+	}
+	return variants
+}
+
+func TestProductVariants(t *testing.T) {
+	for _, b := range backends {
+		t.Run(b.name, func(t *testing.T) {
+			app := newTestApp(t, b)
+			alice := signIn(t, app, "alice")
+			bob := signIn(t, app, "bob")
+
+			_, body := alice.json(http.MethodPost, api+"/login", map[string]string{"username": "alice", "password": "secret123"})
+			aliceID := body["user"].(map[string]any)["id"].(string)
+
+			create := func(name, price string) string {
+				status, body := alice.multipartImages(http.MethodPost, api+"/products", map[string][]string{
+					"name": {name}, "price": {price}, "user_id": {aliceID},
+				}, []byte("\xff\xd8\xff\xe0 fake jpeg "+name))
+				expectStatus(t, status, http.StatusOK, body)
+				return productOf(t, body)["id"].(string)
+			}
+			shirt := create("Shirt", "5000")
+			mug := create("Mug", "2000")
+			base := api + "/products/" + shirt
+
+			status, body := alice.json(http.MethodPut, base+"/options", map[string]any{
+				"options": []map[string]any{
+					{"name": "Size", "values": []string{"S", "M", "L"}},
+					{"name": "Color", "values": []string{"Red", "Blue"}},
+				},
+			})
+			expectStatus(t, status, http.StatusOK, body)
+			if options := productOf(t, body)["options"].([]any); len(options) != 2 {
+				t.Fatalf("unexpected options: %v", options)
+			}
+
+			status, body = bob.json(http.MethodPut, base+"/options", map[string]any{"options": []any{}})
+			expectStatus(t, status, http.StatusForbidden, body)
+
+			status, body = alice.json(http.MethodPut, base+"/options", map[string]any{
+				"options": []map[string]any{{"name": "Size", "values": []string{"S", "s"}}},
+			})
+			expectStatus(t, status, http.StatusBadRequest, body)
+
+			t.Run("variants set the price range", func(t *testing.T) {
+				status, body := alice.json(http.MethodPost, base+"/variants", map[string]any{
+					"sku": " shirt-s-red ", "attributes": map[string]string{"size": "s", "color": "red"},
+				})
+				expectStatus(t, status, http.StatusCreated, body)
+				variants := productVariants(t, body)
+				if len(variants) != 1 || variants[0]["sku"] != "SHIRT-S-RED" || amountOf(t, variants[0]["price"]) != "5000.00" || variants[0]["price_override"] != nil {
+					t.Fatalf("unexpected variant: %v", variants)
+				}
+				if attributes := variants[0]["attributes"].(map[string]any); attributes["Size"] != "S" || attributes["Color"] != "Red" {
+					t.Fatalf("attributes not canonical: %v", attributes)
+				}
+
+				status, body = alice.json(http.MethodPost, base+"/variants", map[string]any{
+					"sku": "SHIRT-L-BLUE", "price": "7500", "attributes": map[string]string{"Size": "L", "Color": "Blue"},
+				})
+				expectStatus(t, status, http.StatusCreated, body)
+				product := productOf(t, body)
+				if amountOf(t, product["price_min"]) != "5000.00" || amountOf(t, product["price_max"]) != "7500.00" {
+					t.Fatalf("unexpected price range: %v", product)
+				}
+
+				// Filter harga cocok jika rentang harga varian beririsan
+				_, body = alice.json(http.MethodGet, api+"/products?min_price=6000&sort=price", nil)
+				if names := listedNames(t, body); names != "Shirt" {
+					t.Fatalf("expected Shirt for min_price=6000, got %q", names)
+				}
+				_, body = alice.json(http.MethodGet, api+"/products?max_price=5000&sort=price", nil)
+				if names := listedNames(t, body); names != "Mug,Shirt" {
+					t.Fatalf("expected Mug,Shirt for max_price=5000, got %q", names)
+				}
+			})
+
+			t.Run("duplicates are rejected", func(t *testing.T) {
+				status, body := alice.json(http.MethodPost, base+"/variants", map[string]any{
+					"sku": "OTHER", "attributes": map[string]string{"Size": "S", "Color": "Red"},
+				})
+				expectStatus(t, status, http.StatusConflict, body)
+				expectError(t, body, apperrors.CodeVariantExists)
+
+				status, body = alice.json(http.MethodPost, api+"/products/"+mug+"/variants", map[string]any{"sku": "shirt-s-red"})
+				expectStatus(t, status, http.StatusConflict, body)
+				expectError(t, body, apperrors.CodeSKUTaken)
+
+				status, body = alice.json(http.MethodPost, base+"/variants", map[string]any{
+					"sku": "SHIRT-XL", "attributes": map[string]string{"Size": "XL", "Color": "Red"},
+				})
+				expectStatus(t, status, http.StatusBadRequest, body)
+				if fields := fieldErrors(t, body); fields["attributes"] != "variant_attributes" {
+					t.Fatalf("expected attributes variant_attributes, got %v", body)
+				}
+			})
+
+			t.Run("update and delete", func(t *testing.T) {
+				_, body := alice.json(http.MethodGet, base, nil)
+				variants := productVariants(t, body)
+				images := productImages(t, body)
+				id := variants[1]["id"].(string)
+
+				status, body := alice.json(http.MethodPut, base+"/variants/"+id, map[string]any{
+					"sku": "SHIRT-M-BLUE", "image_id": images[0]["id"], "attributes": map[string]string{"Size": "M", "Color": "Blue"},
+				})
+				expectStatus(t, status, http.StatusOK, body)
+				updated := productVariants(t, body)[1]
+				if updated["sku"] != "SHIRT-M-BLUE" || amountOf(t, updated["price"]) != "5000.00" || updated["image"] != images[0]["path"] {
+					t.Fatalf("variant not updated: %v", updated)
+				}
+				if product := productOf(t, body); amountOf(t, product["price_max"]) != "5000.00" {
+					t.Fatalf("price range not refreshed: %v", product)
+				}
+
+				// Option yang masih dipakai varian tidak bisa dihapus
+				status, body = alice.json(http.MethodPut, base+"/options", map[string]any{
+					"options": []map[string]any{{"name": "Size", "values": []string{"S", "M"}}},
+				})
+				expectStatus(t, status, http.StatusConflict, body)
+				expectError(t, body, apperrors.CodeOptionInUse)
+
+				// Menghapus gambar melepaskannya dari varian
+				status, body = alice.json(http.MethodDelete, base+"/images/"+images[0]["id"].(string), nil)
+				expectStatus(t, status, http.StatusOK, body)
+				if variant := productVariants(t, body)[1]; variant["image_id"] != nil || variant["image"] != "" {
+					t.Fatalf("image not detached: %v", variant)
+				}
+
+				status, body = alice.json(http.MethodDelete, base+"/variants/not-a-uuid", nil)
+				expectStatus(t, status, http.StatusBadRequest, body)
+				expectError(t, body, apperrors.CodeInvalidVariantID)
+
+				status, body = alice.json(http.MethodDelete, base+"/variants/"+id, nil)
+				expectStatus(t, status, http.StatusOK, body)
+				if variants := productVariants(t, body); len(variants) != 1 {
+					t.Fatalf("variant not deleted: %v", variants)
+				}
+				status, body = alice.json(http.MethodDelete, base+"/variants/"+id, nil)
+				expectStatus(t, status, http.StatusNotFound, body)
+				expectError(t, body, apperrors.CodeVariantNotFound)
+			})
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package routes_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"server-cookie/config"
+	"server-cookie/routes"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestLegacyRoutes(t *testing.T) {
+	app := newTestApp(t, backends[1])
+
+	// Register lewat route lama, login lewat /api/v1: keduanya memakai handler yang sama
+	status, body := app.json(http.MethodPost, "/register", map[string]string{"username": "alice", "email": "alice@example.com", "password": "secret123"})
+	expectStatus(t, status, http.StatusCreated, body)
+	status, body = app.json(http.MethodPost, api+"/login", map[string]string{"username": "alice", "password": "secret123"})
+	expectStatus(t, status, http.StatusOK, body)
+
+	get := func(path string) *http.Response {
+		t.Helper()
+		resp, err := app.client.Get(app.server.URL + path)
+		if err != nil {
+			t.Fatalf("GET %s: %v", path, err)
+		}
+		resp.Body.Close()
+		return resp
+	}
+
+	resp := get("/products?page=1")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("legacy GET /products = %d, want 200", resp.StatusCode)
+	}
+	if got, want := resp.Header.Get("Deprecation"), "@"+strconv.FormatInt(config.DefaultLegacyDeprecation.Unix(), 10); got != want {
+		t.Errorf("Deprecation = %q, want %q", got, want)
+	}
+	if got, want := resp.Header.Get("Sunset"), config.DefaultLegacySunset.Format(http.TimeFormat); got != want {
+		t.Errorf("Sunset = %q, want %q", got, want)
+	}
+	if got, want := resp.Header.Get("Link"), `</api/v1/products>; rel="successor-version"`; got != want {
+		t.Errorf("Link = %q, want %q", got, want)
+	}
+
+	// Header deprecation tetap ada pada response error
+	resp = get("/products/not-a-uuid")
+	if resp.StatusCode != http.StatusBadRequest || resp.Header.Get("Deprecation") == "" {
+		t.Errorf("legacy error response = %d with Deprecation %q", resp.StatusCode, resp.Header.Get("Deprecation"))
+	}
+
+	resp = get(api + "/products")
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Deprecation") != "" || resp.Header.Get("Sunset") != "" {
+		t.Errorf("GET %s/products = %d, should not be deprecated", api, resp.StatusCode)
+	}
+}
+
+func TestLegacyRoutesDisabled(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := routes.SetupRouter(&config.Config{UploadDir: t.TempDir()}, routes.Handlers{})
+
+	for path, want := range map[string]int{
+		"/products":       http.StatusNotFound,
+		api + "/products": http.StatusUnauthorized,
+	} {
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		if rec.Code != want {
+			t.Errorf("GET %s = %d, want %d", path, rec.Code, want)
+		}
+	}
+}
+
+func TestMountVersion(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cfg := &config.Config{UploadDir: t.TempDir(), Cookie: config.CookieConfig{Name: "token"}}
+	r := routes.SetupRouter(cfg, routes.Handlers{})
+
+	v2 := routes.Version{
+		Name: "v2",
+		Register: func(g routes.Groups, h routes.Handlers) {
+			g.Public.GET("/ping", func(c *gin.Context) { c.String(http.StatusOK, "pong") })
+			g.Protected.GET("/me", func(c *gin.Context) { c.String(http.StatusOK, c.GetString("username")) })
+		},
+	}
+	routes.MountVersion(r, cfg, routes.Handlers{}, v2)
+
+	for path, want := range map[string]int{
+		"/api/v2/ping": http.StatusOK,
+		"/api/v2/me":   http.StatusUnauthorized,
+		"/api/v1/ping": http.StatusNotFound,
+	} {
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		if rec.Code != want {
+			t.Errorf("GET %s = %d, want %d", path, rec.Code, want)
+		}
+	}
+}
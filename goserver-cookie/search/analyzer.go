@@ -0,0 +1,75 @@
+package search
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// Token adalah satu kata hasil analisis beserta bentuk-bentuk yang dicocokkan
+type Token struct {
+	// Text adalah kata asli di teks, Start dan End posisinya (byte)
+	Text       string
+	Start, End int
+	// Terms berisi kata huruf kecil, bentuk dasar bahasa Inggris dan Indonesia tanpa duplikat
+	Terms []string
+}
+
+// Matches memeriksa apakah token punya term yang sama dengan token lain
+func (t Token) Matches(other Token) bool {
+	for _, a := range t.Terms {
+		for _, b := range other.Terms {
+			if a == b {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// stopwords adalah kata umum bahasa Inggris dan Indonesia yang tidak diindeks
+var stopwords = map[string]bool{
+	"a": true, "an": true, "and": true, "the": true, "of": true, "or": true, "with": true,
+	"for": true, "in": true, "on": true, "to": true, "is": true, "it": true, "by": true,
+	"dan": true, "yang": true, "di": true, "ke": true, "dari": true, "untuk": true,
+	"dengan": true, "atau": true, "ini": true, "itu": true, "pada": true, "juga": true,
+}
+
+// Analyze memecah teks menjadi token huruf dan angka, membuang stopword,
+// lalu menambahkan bentuk dasarnya
+func Analyze(text string) []Token {
+	var tokens []Token
+	start := -1
+	flush := func(end int) {
+		if start < 0 {
+			return
+		}
+		word := strings.ToLower(text[start:end])
+		if !stopwords[word] {
+			tokens = append(tokens, Token{Text: text[start:end], Start: start, End: end, Terms: termsOf(word)})
+		}
+		start = -1
+	}
+	for i, r := range text {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		flush(i)
+	}
+	flush(len(text))
+	return tokens
+}
+
+// termsOf mengembalikan kata beserta bentuk dasarnya tanpa duplikat
+func termsOf(word string) []string {
+	terms := []string{word}
+	for _, stem := range []string{StemEnglish(word), StemIndonesian(word)} {
+		if stem != "" && !slices.Contains(terms, stem) {
+			terms = append(terms, stem)
+		}
+	}
+	return terms
+}
@@ -0,0 +1,66 @@
+package search
+
+import (
+	"html"
+	"strings"
+)
+
+// snippetRadius adalah jumlah byte teks yang ditampilkan di kiri dan kanan kecocokan pertama
+const snippetRadius = 60
+
+// Highlight membuat potongan teks di sekitar kata yang cocok dengan query.
+// Teks di-escape sebagai HTML lalu kata yang cocok dibungkus <mark>...</mark>.
+// Jika tidak ada kata yang cocok, hasilnya string kosong.
+func Highlight(text, query string) string {
+	queryTokens := Analyze(query)
+	var matches []Token
+	for _, token := range Analyze(text) {
+		for _, q := range queryTokens {
+			if token.Matches(q) {
+				matches = append(matches, token)
+				break
+			}
+		}
+	}
+	if len(matches) == 0 {
+		return ""
+	}
+
+	// Potong di batas spasi agar kata tidak terpotong
+	start, end := 0, len(text)
+	if from := matches[0].Start - snippetRadius; from > 0 {
+		if space := strings.IndexByte(text[from:matches[0].Start], ' '); space >= 0 {
+			start = from + space + 1
+		} else {
+			start = matches[0].Start
+		}
+	}
+	if to := matches[0].End + snippetRadius; to < len(text) {
+		if space := strings.LastIndexByte(text[matches[0].End:to], ' '); space >= 0 {
+			end = matches[0].End + space
+		} else {
+			end = matches[0].End
+		}
+	}
+
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString("…")
+	}
+	pos := start
+	for _, match := range matches {
+		if match.Start < start || match.End > end {
+			continue
+		}
+		b.WriteString(html.EscapeString(text[pos:match.Start]))
+		b.WriteString("<mark>")
+		b.WriteString(html.EscapeString(match.Text))
+		b.WriteString("</mark>")
+		pos = match.End
+	}
+	b.WriteString(html.EscapeString(text[pos:end]))
+	if end < len(text) {
+		b.WriteString("…")
+	}
+	return b.String()
+}
@@ -0,0 +1,156 @@
+package search
+
+import (
+	"context"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// Parameter BM25 standar
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// MemoryIndex adalah inverted index di memory dengan skor BM25.
+// Isinya hilang saat restart, jadi perlu diisi ulang dari database saat start.
+type MemoryIndex struct {
+	mu       sync.RWMutex
+	postings map[string]map[uuid.UUID]int // term -> dokumen -> frekuensi
+	lengths  map[uuid.UUID]int            // jumlah token per dokumen
+	terms    map[uuid.UUID][]string       // term per dokumen, untuk Remove
+	total    int                          // jumlah token semua dokumen
+}
+
+var _ Index = (*MemoryIndex)(nil)
+
+// NewMemoryIndex membuat MemoryIndex kosong
+func NewMemoryIndex() *MemoryIndex {
+	return &MemoryIndex{
+		postings: make(map[string]map[uuid.UUID]int),
+		lengths:  make(map[uuid.UUID]int),
+		terms:    make(map[uuid.UUID][]string),
+	}
+}
+
+func (i *MemoryIndex) Index(ctx context.Context, doc Document) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.remove(doc.ID)
+	tokens := doc.terms()
+	var terms []string
+	for _, token := range tokens {
+		for _, term := range token.Terms {
+			docs, ok := i.postings[term]
+			if !ok {
+				docs = make(map[uuid.UUID]int)
+				i.postings[term] = docs
+			}
+			if docs[doc.ID] == 0 {
+				terms = append(terms, term)
+			}
+			docs[doc.ID]++
+		}
+	}
+	i.lengths[doc.ID] = len(tokens)
+	i.terms[doc.ID] = terms
+	i.total += len(tokens)
+	return nil
+}
+
+func (i *MemoryIndex) Remove(ctx context.Context, id uuid.UUID) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.remove(id)
+	return nil
+}
+
+func (i *MemoryIndex) remove(id uuid.UUID) {
+	for _, term := range i.terms[id] {
+		delete(i.postings[term], id)
+		if len(i.postings[term]) == 0 {
+			delete(i.postings, term)
+		}
+	}
+	i.total -= i.lengths[id]
+	delete(i.lengths, id)
+	delete(i.terms, id)
+}
+
+// Search menjumlahkan skor BM25 setiap token query. Skor satu token adalah
+// skor tertinggi dari bentuk-bentuknya, agar kata yang cocok sebagai kata asli
+// dan bentuk dasar sekaligus tidak dihitung dua kali. Token terakhir juga
+// dicocokkan sebagai awalan kata, untuk pencarian sambil mengetik.
+func (i *MemoryIndex) Search(ctx context.Context, query string, limit int) ([]Hit, error) {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	docs := len(i.lengths)
+	if docs == 0 {
+		return nil, nil
+	}
+	avgLength := float64(i.total) / float64(docs)
+
+	scores := make(map[uuid.UUID]float64)
+	tokens := Analyze(query)
+	for n, token := range tokens {
+		terms := token.Terms
+		if n == len(tokens)-1 {
+			terms = i.withPrefix(terms)
+		}
+		best := make(map[uuid.UUID]float64)
+		for _, term := range terms {
+			postings := i.postings[term]
+			idf := math.Log(1 + (float64(docs)-float64(len(postings))+0.5)/(float64(len(postings))+0.5))
+			for id, freq := range postings {
+				tf := float64(freq)
+				norm := tf + bm25K1*(1-bm25B+bm25B*float64(i.lengths[id])/avgLength)
+				best[id] = max(best[id], idf*tf*(bm25K1+1)/norm)
+			}
+		}
+		for id, score := range best {
+			scores[id] += score
+		}
+	}
+
+	hits := make([]Hit, 0, len(scores))
+	for id, score := range scores {
+		hits = append(hits, Hit{ID: id, Score: score})
+	}
+	sortHits(hits)
+	if limit > 0 && len(hits) > limit {
+		hits = hits[:limit]
+	}
+	return hits, nil
+}
+
+// withPrefix menambahkan term di index yang diawali kata asli token
+func (i *MemoryIndex) withPrefix(terms []string) []string {
+	prefix := terms[0]
+	if len(prefix) < minPrefix {
+		return terms
+	}
+	expanded := slices.Clone(terms)
+	for term := range i.postings {
+		if strings.HasPrefix(term, prefix) && !slices.Contains(expanded, term) {
+			expanded = append(expanded, term)
+		}
+	}
+	return expanded
+}
+
+// sortHits mengurutkan hasil dari skor tertinggi, lalu ID agar stabil
+func sortHits(hits []Hit) {
+	sort.Slice(hits, func(a, b int) bool {
+		if hits[a].Score != hits[b].Score {
+			return hits[a].Score > hits[b].Score
+		}
+		return hits[a].ID.String() < hits[b].ID.String()
+	})
+}
@@ -0,0 +1,101 @@
+package search
+
+import (
+	"context"
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// mysqlDocument adalah baris tabel product_search. Content berisi semua term
+// hasil Analyze dipisah spasi, sehingga FULLTEXT MySQL ikut mencocokkan bentuk dasar kata.
+type mysqlDocument struct {
+	ProductId uuid.UUID `gorm:"type:char(36);primaryKey"`
+	Content   string    `gorm:"type:text;index:idx_product_search_content,class:FULLTEXT"`
+}
+
+func (mysqlDocument) TableName() string {
+	return "product_search"
+}
+
+// MigrateMySQL membuat tabel product_search beserta FULLTEXT index-nya
+func MigrateMySQL(db *gorm.DB) error {
+	return db.AutoMigrate(&mysqlDocument{})
+}
+
+// MySQLIndex adalah Index yang memakai FULLTEXT index MySQL (InnoDB)
+// dengan skor relevansi dari MATCH ... AGAINST. Driver Postgres belum dipakai
+// di aplikasi ini, implementasi tsvector bisa ditambahkan dengan interface yang sama.
+type MySQLIndex struct {
+	db *gorm.DB
+}
+
+var _ Index = (*MySQLIndex)(nil)
+
+// NewMySQLIndex membuat Index berbasis tabel product_search
+func NewMySQLIndex(db *gorm.DB) *MySQLIndex {
+	return &MySQLIndex{db: db}
+}
+
+func (i *MySQLIndex) Index(ctx context.Context, doc Document) error {
+	row := mysqlDocument{ProductId: doc.ID, Content: joinTerms(doc.terms(), false)}
+	return i.db.WithContext(ctx).
+		Clauses(clause.OnConflict{UpdateAll: true}).
+		Create(&row).Error
+}
+
+func (i *MySQLIndex) Remove(ctx context.Context, id uuid.UUID) error {
+	return i.db.WithContext(ctx).Delete(&mysqlDocument{}, "product_id = ?", id).Error
+}
+
+// Search memakai BOOLEAN MODE tanpa operator wajib, sehingga dokumen cukup cocok
+// dengan salah satu term dan kata terakhir bisa dicari sebagai awalan (cook*)
+func (i *MySQLIndex) Search(ctx context.Context, query string, limit int) ([]Hit, error) {
+	tokens := Analyze(query)
+	if len(tokens) == 0 {
+		return nil, nil
+	}
+	// Term hanya berisi huruf dan angka, jadi aman dari operator boolean MySQL
+	terms := joinTerms(tokens, true)
+	if last := tokens[len(tokens)-1].Terms[0]; len(last) >= minPrefix {
+		terms += " " + last + "*"
+	}
+
+	var rows []struct {
+		ProductId uuid.UUID
+		Score     float64
+	}
+	err := i.db.WithContext(ctx).Model(&mysqlDocument{}).
+		Select("product_id, MATCH(content) AGAINST(? IN BOOLEAN MODE) AS score", terms).
+		Where("MATCH(content) AGAINST(? IN BOOLEAN MODE)", terms).
+		Order("score DESC").Order("product_id").
+		Limit(limit).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	hits := make([]Hit, len(rows))
+	for n, row := range rows {
+		hits[n] = Hit{ID: row.ProductId, Score: row.Score}
+	}
+	return hits, nil
+}
+
+// joinTerms menggabungkan term semua token dipisah spasi, unique membuang term ganda
+func joinTerms(tokens []Token, unique bool) string {
+	var terms []string
+	seen := make(map[string]bool)
+	for _, token := range tokens {
+		for _, term := range token.Terms {
+			if unique && seen[term] {
+				continue
+			}
+			seen[term] = true
+			terms = append(terms, term)
+		}
+	}
+	return strings.Join(terms, " ")
+}
@@ -0,0 +1,55 @@
+// Package search menyediakan pencarian full-text produk dengan skor relevansi.
+//
+// Teks dipecah oleh Analyze menjadi token, setiap token disimpan beserta
+// bentuk dasarnya dalam bahasa Inggris dan Indonesia, sehingga "cookies",
+// "cookie" dan "kue-kue" tetap cocok tanpa perlu tahu bahasa produknya.
+// Ada dua implementasi Index: MySQLIndex memakai FULLTEXT index MySQL,
+// MemoryIndex adalah inverted index di memory untuk SQLite dan test.
+package search
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// MaxHits adalah batas jumlah hasil pencarian yang dipakai daftar produk. Query
+// yang cocok dengan lebih banyak produk hanya menampilkan dan menghitung MaxHits
+// produk paling relevan, daftar produk menandainya dengan SearchTruncated.
+const MaxHits = 1000
+
+// minPrefix adalah panjang minimal kata terakhir query agar dicocokkan sebagai awalan
+const minPrefix = 3
+
+// Document adalah teks produk yang diindeks. Token di Title dihitung dua kali
+// agar kecocokan di nama produk lebih relevan daripada di tag.
+type Document struct {
+	ID    uuid.UUID
+	Title string
+	Body  []string
+}
+
+// Hit adalah satu produk yang cocok beserta skor relevansinya, makin besar makin relevan
+type Hit struct {
+	ID    uuid.UUID
+	Score float64
+}
+
+// Index adalah penyimpanan pencarian full-text. Index menimpa dokumen dengan ID
+// yang sama, Search mengembalikan paling banyak limit hasil terurut relevansi.
+// Kata terakhir query juga dicocokkan sebagai awalan, misalnya "cook" cocok dengan "cookies".
+type Index interface {
+	Index(ctx context.Context, doc Document) error
+	Remove(ctx context.Context, id uuid.UUID) error
+	Search(ctx context.Context, query string, limit int) ([]Hit, error)
+}
+
+// terms mengembalikan semua term dokumen untuk disimpan di index, Title dua kali
+func (d Document) terms() []Token {
+	title := Analyze(d.Title)
+	tokens := append(title, title...)
+	for _, text := range d.Body {
+		tokens = append(tokens, Analyze(text)...)
+	}
+	return tokens
+}
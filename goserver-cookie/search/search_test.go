@@ -0,0 +1,110 @@
+package search_test
+
+import (
+	"context"
+	"server-cookie/search"
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestStemmers(t *testing.T) {
+	english := map[string]string{"cookies": "cooki", "cookie": "cooki", "running": "run", "baking": "bak"}
+	for word, want := range english {
+		if got := search.StemEnglish(word); got != want {
+			t.Errorf("StemEnglish(%q) = %q, want %q", word, got, want)
+		}
+	}
+	indonesian := map[string]string{"makanan": "makan", "pembelian": "beli", "minuman": "minum", "berlari": "lari"}
+	for word, want := range indonesian {
+		if got := search.StemIndonesian(word); got != want {
+			t.Errorf("StemIndonesian(%q) = %q, want %q", word, got, want)
+		}
+	}
+}
+
+func TestAnalyze(t *testing.T) {
+	tokens := search.Analyze("Kue-kue dan Cookies, the best!")
+	var texts []string
+	for _, token := range tokens {
+		texts = append(texts, token.Text)
+	}
+	if want := []string{"Kue", "kue", "Cookies", "best"}; !slices.Equal(texts, want) {
+		t.Fatalf("got tokens %v, want %v", texts, want)
+	}
+	if cookies := tokens[2]; cookies.Start != 12 || cookies.End != 19 || !slices.Contains(cookies.Terms, "cooki") {
+		t.Fatalf("unexpected token %+v", cookies)
+	}
+}
+
+func TestMemoryIndex(t *testing.T) {
+	ctx := context.Background()
+	index := search.NewMemoryIndex()
+	chip, oat, bread := uuid.New(), uuid.New(), uuid.New()
+	docs := []search.Document{
+		{ID: chip, Title: "Chocolate Chip Cookies", Body: []string{"snack"}},
+		{ID: oat, Title: "Oat Bar", Body: []string{"cookie", "healthy"}},
+		{ID: bread, Title: "Roti Manis", Body: []string{"makanan"}},
+	}
+	for _, doc := range docs {
+		if err := index.Index(ctx, doc); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ids := func(query string) []uuid.UUID {
+		t.Helper()
+		hits, err := index.Search(ctx, query, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		result := make([]uuid.UUID, len(hits))
+		for i, hit := range hits {
+			result[i] = hit.ID
+		}
+		return result
+	}
+
+	// Kecocokan di judul lebih relevan daripada di tag
+	if got := ids("cookie"); !slices.Equal(got, []uuid.UUID{chip, oat}) {
+		t.Errorf("cookie: got %v", got)
+	}
+	if got := ids("makan"); !slices.Equal(got, []uuid.UUID{bread}) {
+		t.Errorf("makan: got %v", got)
+	}
+	// Kata terakhir dicocokkan sebagai awalan
+	if got := ids("choco"); !slices.Equal(got, []uuid.UUID{chip}) {
+		t.Errorf("choco: got %v", got)
+	}
+	if got := ids("the"); len(got) != 0 {
+		t.Errorf("stopword should not match, got %v", got)
+	}
+
+	if err := index.Index(ctx, search.Document{ID: chip, Title: "Brownie"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := index.Remove(ctx, oat); err != nil {
+		t.Fatal(err)
+	}
+	if got := ids("cookie"); len(got) != 0 {
+		t.Errorf("replaced and removed documents should not match, got %v", got)
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	cases := []struct{ text, query, want string }{
+		{"Chocolate <Chip> Cookies & Cream", "cookie", "Chocolate &lt;Chip&gt; <mark>Cookies</mark> &amp; Cream"},
+		{"Roti Manis", "cookie", ""},
+		{
+			"A very long description that goes on and on before it finally mentions the word cookies somewhere in the middle of it",
+			"cookies",
+			"…that goes on and on before it finally mentions the word <mark>cookies</mark> somewhere in the middle of it",
+		},
+	}
+	for _, tc := range cases {
+		if got := search.Highlight(tc.text, tc.query); got != tc.want {
+			t.Errorf("Highlight(%q, %q) = %q, want %q", tc.text, tc.query, got, tc.want)
+		}
+	}
+}
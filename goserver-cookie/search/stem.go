@@ -0,0 +1,117 @@
+package search
+
+import "strings"
+
+// minStem adalah panjang minimal bentuk dasar, imbuhan tidak dibuang jika sisanya lebih pendek
+const minStem = 3
+
+// StemEnglish membuang akhiran umum bahasa Inggris (versi ringan dari algoritma Porter):
+// bentuk jamak, -ing, -ed dan -ly, lalu menyeragamkan akhiran y/e.
+// Contoh: cookies dan cookie menjadi cooki, baking dan baked menjadi bak.
+func StemEnglish(word string) string {
+	if len(word) <= minStem || !isASCIILetters(word) {
+		return word
+	}
+
+	switch {
+	case strings.HasSuffix(word, "sses"):
+		word = word[:len(word)-2]
+	case strings.HasSuffix(word, "ies"):
+		word = word[:len(word)-2]
+	case strings.HasSuffix(word, "ss"), strings.HasSuffix(word, "us"), strings.HasSuffix(word, "is"):
+	case strings.HasSuffix(word, "s"):
+		word = word[:len(word)-1]
+	}
+
+	for _, suffix := range []string{"ing", "ed", "ly"} {
+		stem, ok := strings.CutSuffix(word, suffix)
+		if !ok || len(stem) < minStem || !hasVowel(stem) {
+			continue
+		}
+		word = stem
+		// running -> runn -> run
+		if suffix != "ly" && doubleConsonant(word) {
+			word = word[:len(word)-1]
+		}
+		break
+	}
+
+	// Seragamkan akhiran agar cherry/cherries dan bake/baked sama
+	if n := len(word); n > minStem && word[n-1] == 'y' && !isVowel(word[n-2]) {
+		word = word[:n-1] + "i"
+	}
+	if n := len(word); n > minStem && word[n-1] == 'e' {
+		word = word[:n-1]
+	}
+	return word
+}
+
+// StemIndonesian membuang partikel, kata ganti milik, akhiran dan awalan bahasa Indonesia
+// (versi ringan dari algoritma Nazief-Adriani tanpa kamus kata dasar).
+// Contoh: makanannya menjadi makan, menyapu menjadi sapu, memukul menjadi pukul.
+func StemIndonesian(word string) string {
+	if len(word) <= minStem || !isASCIILetters(word) {
+		return word
+	}
+
+	cut := func(suffixes ...string) {
+		for _, suffix := range suffixes {
+			if stem, ok := strings.CutSuffix(word, suffix); ok && len(stem) >= minStem+1 {
+				word = stem
+				return
+			}
+		}
+	}
+	cut("lah", "kah", "tah", "pun")
+	cut("nya", "ku", "mu")
+	cut("kan", "an")
+
+	// Awalan meN-/peN- mengubah huruf pertama kata dasar
+	type prefix struct{ prefix, replace string }
+	for _, p := range []prefix{
+		{"meny", "s"}, {"peny", "s"},
+		{"meng", ""}, {"peng", ""},
+		{"mem", ""}, {"pem", ""},
+		{"men", ""}, {"pen", ""},
+		{"ber", ""}, {"ter", ""}, {"per", ""},
+		{"me", ""}, {"pe", ""}, {"di", ""}, {"ke", ""}, {"se", ""},
+	} {
+		rest, ok := strings.CutPrefix(word, p.prefix)
+		if !ok || len(rest) < minStem {
+			continue
+		}
+		switch {
+		case p.replace != "":
+			rest = p.replace + rest
+		case (p.prefix == "mem" || p.prefix == "pem") && isVowel(rest[0]):
+			rest = "p" + rest // memukul -> pukul
+		case (p.prefix == "men" || p.prefix == "pen") && isVowel(rest[0]):
+			rest = "t" + rest // menulis -> tulis
+		}
+		word = rest
+		break
+	}
+	return word
+}
+
+func isVowel(c byte) bool {
+	return strings.IndexByte("aeiou", c) >= 0
+}
+
+func hasVowel(word string) bool {
+	return strings.ContainsAny(word, "aeiouy")
+}
+
+func doubleConsonant(word string) bool {
+	n := len(word)
+	return n >= 2 && word[n-1] == word[n-2] && !isVowel(word[n-1]) && strings.IndexByte("lsz", word[n-1]) < 0
+}
+
+func isASCIILetters(word string) bool {
+	for i := 0; i < len(word); i++ {
+		if word[i] < 'a' || word[i] > 'z' {
+			return false
+		}
+	}
+	return true
+}
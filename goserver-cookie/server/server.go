@@ -0,0 +1,103 @@
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"server-cookie/config"
+
+	"google.golang.org/grpc"
+)
+
+// Run menjalankan server HTTP atau HTTPS sesuai konfigurasi,
+// dan server gRPC di port terpisah jika grpcServer tidak nil
+func Run(cfg *config.Config, handler http.Handler, grpcServer *grpc.Server) error {
+	errCh := make(chan error, 3)
+
+	if !cfg.TLSEnabled() {
+		if grpcServer != nil {
+			go func() { errCh <- serveGRPC(cfg.GRPCAddr, grpcServer, nil) }()
+		}
+		go func() {
+			fmt.Println("🚀 Server HTTP berjalan di", cfg.HTTPAddr)
+			errCh <- http.ListenAndServe(cfg.HTTPAddr, handler)
+		}()
+		return <-errCh
+	}
+
+	reloader, err := NewCertReloader(cfg.TLSCertFile, cfg.TLSKeyFile)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go reloader.Watch(ctx, cfg.TLSReloadInterval)
+
+	httpsServer := &http.Server{
+		Addr:    cfg.HTTPSAddr,
+		Handler: handler,
+		TLSConfig: &tls.Config{
+			MinVersion:     tls.VersionTLS12,
+			GetCertificate: reloader.GetCertificate,
+		},
+	}
+
+	go func() {
+		fmt.Println("🚀 Server HTTPS berjalan di", cfg.HTTPSAddr)
+		// Cert dan key diambil dari GetCertificate
+		errCh <- httpsServer.ListenAndServeTLS("", "")
+	}()
+
+	if cfg.RedirectHTTP {
+		go func() {
+			fmt.Println("🚀 Redirect HTTP -> HTTPS berjalan di", cfg.HTTPAddr)
+			errCh <- http.ListenAndServe(cfg.HTTPAddr, RedirectHandler(cfg.HTTPSAddr))
+		}()
+	}
+
+	if grpcServer != nil {
+		// gRPC memakai sertifikat yang sama dan ikut ter-reload
+		grpcTLS := &tls.Config{
+			MinVersion:     tls.VersionTLS12,
+			GetCertificate: reloader.GetCertificate,
+			NextProtos:     []string{"h2"},
+		}
+		go func() { errCh <- serveGRPC(cfg.GRPCAddr, grpcServer, grpcTLS) }()
+	}
+
+	return <-errCh
+}
+
+// serveGRPC menjalankan server gRPC, dengan TLS jika tlsConfig tidak nil
+func serveGRPC(addr string, grpcServer *grpc.Server, tlsConfig *tls.Config) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	if tlsConfig != nil {
+		listener = tls.NewListener(listener, tlsConfig)
+	}
+	fmt.Println("🚀 Server gRPC berjalan di", addr)
+	return grpcServer.Serve(listener)
+}
+
+// RedirectHandler mengarahkan semua request HTTP ke alamat HTTPS yang sama
+func RedirectHandler(httpsAddr string) http.Handler {
+	_, httpsPort, _ := net.SplitHostPort(httpsAddr)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := r.Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		if httpsPort != "" && httpsPort != "443" {
+			host = net.JoinHostPort(host, httpsPort)
+		}
+
+		// 308 agar method dan body tetap dipertahankan saat redirect
+		http.Redirect(w, r, "https://"+host+r.URL.RequestURI(), http.StatusPermanentRedirect)
+	})
+}
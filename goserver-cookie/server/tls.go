@@ -0,0 +1,108 @@
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+// CertReloader memuat ulang sertifikat TLS ketika file cert/key berubah
+type CertReloader struct {
+	certFile string
+	keyFile  string
+
+	mu      sync.RWMutex
+	cert    *tls.Certificate
+	certMod time.Time
+	keyMod  time.Time
+}
+
+// NewCertReloader memuat sertifikat awal dari file cert dan key
+func NewCertReloader(certFile, keyFile string) (*CertReloader, error) {
+	r := &CertReloader{certFile: certFile, keyFile: keyFile}
+	if err := r.reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// GetCertificate dipakai sebagai tls.Config.GetCertificate
+func (r *CertReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.cert, nil
+}
+
+// Watch memeriksa perubahan file secara berkala sampai ctx selesai
+func (r *CertReloader) Watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			changed, err := r.changed()
+			if err != nil {
+				log.Println("❌ Gagal memeriksa file sertifikat:", err)
+				continue
+			}
+			if !changed {
+				continue
+			}
+			// Sertifikat lama tetap dipakai jika file baru tidak valid
+			if err := r.reload(); err != nil {
+				log.Println("❌ Gagal memuat ulang sertifikat:", err)
+				continue
+			}
+			log.Println("✅ Sertifikat TLS dimuat ulang")
+		}
+	}
+}
+
+func (r *CertReloader) changed() (bool, error) {
+	certMod, keyMod, err := r.modTimes()
+	if err != nil {
+		return false, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return !certMod.Equal(r.certMod) || !keyMod.Equal(r.keyMod), nil
+}
+
+func (r *CertReloader) reload() error {
+	certMod, keyMod, err := r.modTimes()
+	if err != nil {
+		return err
+	}
+
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return fmt.Errorf("failed to load key pair: %w", err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cert = &cert
+	r.certMod = certMod
+	r.keyMod = keyMod
+	return nil
+}
+
+func (r *CertReloader) modTimes() (time.Time, time.Time, error) {
+	certInfo, err := os.Stat(r.certFile)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	keyInfo, err := os.Stat(r.keyFile)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	return certInfo.ModTime(), keyInfo.ModTime(), nil
+}
@@ -0,0 +1,618 @@
+package services
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"path"
+	"path/filepath"
+	"server-cookie/apperrors"
+	"server-cookie/models"
+	"server-cookie/repositories"
+	"server-cookie/storage"
+	"server-cookie/validation"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// listSeparator memisahkan isi kolom daftar di CSV, misalnya tags
+	listSeparator = "|"
+	// importProgressInterval adalah jumlah baris di antara penyimpanan progres job
+	importProgressInterval = 50
+	// imageFetchTimeout membatasi waktu mengunduh satu gambar dari URL
+	imageFetchTimeout = 30 * time.Second
+)
+
+// productColumns adalah kolom file import dan export sesuai urutan di CSV.
+// Kolom id hanya untuk dibaca, produk dicocokkan dengan external_id atau sku.
+var productColumns = []string{"id", "external_id", "sku", "name", "price", "currency", "category_ids", "tags", "images"}
+
+// productRow adalah satu produk di file import atau export. Di CSV, kolom daftar
+// dipisahkan "|" dan sel kosong berarti tidak diubah. Di JSON Lines, field yang
+// tidak ada berarti tidak diubah dan daftar kosong mengosongkan isinya.
+type productRow struct {
+	ID          string   `json:"id,omitempty"`
+	ExternalID  string   `json:"external_id" validate:"max=100"`
+	SKU         string   `json:"sku" validate:"max=64"`
+	Name        string   `json:"name" validate:"omitempty,min=2,max=100"`
+	Price       string   `json:"price" validate:"omitempty,price"`
+	Currency    string   `json:"currency" validate:"omitempty,currency"`
+	CategoryIDs []string `json:"category_ids" validate:"max=10,dive,uuid"`
+	Tags        []string `json:"tags" validate:"max=20,dive,max=50"`
+	// Images berisi URL http(s), nama file di arsip ZIP, atau path gambar produk yang sudah ada
+	Images []string `json:"images" validate:"max_items=10,dive,required,max=2048"`
+
+	line int
+	// err diisi jika baris tidak bisa dibaca, misalnya JSON tidak valid
+	err error
+}
+
+// ImportFile adalah file upload import yang dibaca bertahap, tanpa dimuat seluruhnya
+// ke memory, misalnya file sementara di disk
+type ImportFile interface {
+	io.Reader
+	io.ReaderAt
+	io.Seeker
+	io.Closer
+}
+
+// ImportInput adalah file import produk dari satu request. BulkService menutup
+// File dan Archive setelah import selesai, termasuk job yang berjalan di background.
+type ImportInput struct {
+	// Format adalah models.FormatCSV atau models.FormatJSONL
+	Format string
+	File   ImportFile
+	// Archive adalah arsip ZIP berisi gambar yang dirujuk kolom images, boleh nil
+	Archive ImportFile
+	DryRun  bool
+	// Lang adalah bahasa pesan error per baris
+	Lang apperrors.Lang
+}
+
+// close menutup file import, kegagalan hanya dicatat di log
+func (input ImportInput) close() {
+	files := []ImportFile{input.File}
+	if input.Archive != nil {
+		files = append(files, input.Archive)
+	}
+	for _, file := range files {
+		if err := file.Close(); err != nil {
+			log.Println("❌ Gagal menutup file import:", err)
+		}
+	}
+}
+
+// BulkService mengimpor dan mengekspor produk dalam CSV atau JSON Lines
+type BulkService struct {
+	products *ProductService
+	jobs     repositories.ImportJobRepository
+	client   *http.Client
+	syncRows int
+}
+
+// NewBulkService membuat BulkService dengan dependency yang diberikan. client
+// dipakai mengunduh gambar dari URL, biasanya dari NewImageClient. Import
+// dengan paling banyak syncRows baris selesai di dalam request, yang lebih besar
+// dijalankan sebagai job di background.
+func NewBulkService(products *ProductService, jobs repositories.ImportJobRepository, client *http.Client, syncRows int) *BulkService {
+	return &BulkService{products: products, jobs: jobs, client: client, syncRows: syncRows}
+}
+
+// Export menulis produk aktif, yang terlama lebih dulu, ke w sesuai format.
+// ownerID nil berarti produk semua user.
+func (s *BulkService) Export(ctx context.Context, w io.Writer, format string, ownerID *uuid.UUID) error {
+	if format == models.FormatJSONL {
+		encoder := json.NewEncoder(w)
+		return s.products.Export(ctx, ownerID, func(product models.ProductResponse) error {
+			return encoder.Encode(newProductRow(product))
+		})
+	}
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(productColumns); err != nil {
+		return err
+	}
+	err := s.products.Export(ctx, ownerID, func(product models.ProductResponse) error {
+		return writer.Write(newProductRow(product).record())
+	})
+	if err != nil {
+		return err
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// Import memeriksa semua baris file lalu membuat job import milik actor. Job kecil
+// langsung diproses dan dikembalikan dalam keadaan selesai, job besar dikembalikan
+// dengan status queued dan progresnya bisa dibaca dengan Job. Baris dibaca
+// bertahap dari file, tidak pernah dimuat sekaligus.
+func (s *BulkService) Import(ctx context.Context, actor Actor, input ImportInput) (*models.ImportJobResponse, error) {
+	background := false
+	defer func() {
+		if !background {
+			input.close()
+		}
+	}()
+
+	// Baris pertama kali dibaca hanya untuk dihitung dan memeriksa error seluruh file
+	total := 0
+	if err := eachProductRow(input.Format, input.File, func(productRow) error {
+		total++
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	if total == 0 {
+		return nil, Validation(apperrors.FieldError{Field: "file", Code: "required"})
+	}
+	var archive *zip.Reader
+	if input.Archive != nil {
+		size, err := input.Archive.Seek(0, io.SeekEnd)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read import archive: %w", err)
+		}
+		archive, err = zip.NewReader(input.Archive, size)
+		if err != nil {
+			return nil, Invalid(apperrors.CodeInvalidImportArchive)
+		}
+	}
+
+	job := models.ImportJob{
+		UserId:    actor.UserID,
+		Format:    input.Format,
+		DryRun:    input.DryRun,
+		Status:    models.ImportQueued,
+		TotalRows: total,
+	}
+	if err := s.jobs.Create(ctx, &job); err != nil {
+		return nil, fmt.Errorf("failed to create import job: %w", err)
+	}
+
+	// Job tetap berjalan walaupun client memutus request
+	ctx = context.WithoutCancel(ctx)
+	if total <= s.syncRows {
+		job = s.run(ctx, actor, job, input, archive)
+		response := models.NewImportJobResponse(job)
+		return &response, nil
+	}
+
+	response := models.NewImportJobResponse(job)
+	background = true
+	go func() {
+		defer input.close()
+		defer func() {
+			if r := recover(); r != nil {
+				log.Println("❌ Import", job.Id, "berhenti karena panic:", r)
+				job.Status, job.ErrorCode = models.ImportFailed, apperrors.CodeInternal
+				s.finish(ctx, &job)
+			}
+		}()
+		s.run(ctx, actor, job, input, archive)
+	}()
+	return &response, nil
+}
+
+// Job mengambil job import milik actor
+func (s *BulkService) Job(ctx context.Context, actor Actor, id uuid.UUID) (*models.ImportJobResponse, error) {
+	job, err := s.jobs.FindByID(ctx, id)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil, NotFound(apperrors.CodeImportNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve import job: %w", err)
+	}
+	// Job user lain dianggap tidak ada
+	if job.UserId != actor.UserID {
+		return nil, NotFound(apperrors.CodeImportNotFound)
+	}
+	response := models.NewImportJobResponse(*job)
+	return &response, nil
+}
+
+// FailInterrupted menandai job yang masih berjalan saat server berhenti sebagai failed
+func (s *BulkService) FailInterrupted(ctx context.Context) (int64, error) {
+	failed, err := s.jobs.FailUnfinished(ctx, apperrors.CodeImportInterrupted)
+	if err != nil {
+		return 0, fmt.Errorf("failed to update import jobs: %w", err)
+	}
+	return failed, nil
+}
+
+// run membaca ulang file dan memproses baris satu per satu. Baris yang gagal
+// dicatat dan dilewati, sehingga satu baris tidak membatalkan baris lainnya.
+func (s *BulkService) run(ctx context.Context, actor Actor, job models.ImportJob, input ImportInput, archive *zip.Reader) models.ImportJob {
+	job.Status = models.ImportRunning
+	s.save(ctx, &job)
+
+	seen := make(map[string]int)
+	err := eachProductRow(input.Format, input.File, func(row productRow) error {
+		created, err := s.importRow(ctx, actor, row, archive, job.DryRun, seen)
+		switch {
+		case err != nil:
+			job.Failed++
+			if len(job.Errors) < models.MaxImportRowErrors {
+				job.Errors = append(job.Errors, newRowError(row.line, err, input.Lang))
+			}
+			if apperrors.From(err).Code == apperrors.CodeInternal {
+				log.Println("❌ Import", job.Id, "gagal di baris", row.line, ":", err)
+			}
+		case created:
+			job.Created++
+		default:
+			job.Updated++
+		}
+
+		job.ProcessedRows++
+		if job.ProcessedRows%importProgressInterval == 0 && job.ProcessedRows < job.TotalRows {
+			s.save(ctx, &job)
+		}
+		return nil
+	})
+	if err != nil {
+		// File sudah diperiksa di Import, error di sini berarti file tidak bisa dibaca ulang
+		log.Println("❌ Import", job.Id, "gagal membaca file:", err)
+		job.Status, job.ErrorCode = models.ImportFailed, apperrors.From(err).Code
+		s.finish(ctx, &job)
+		return job
+	}
+
+	job.Status = models.ImportSucceeded
+	s.finish(ctx, &job)
+	return job
+}
+
+// importRow memvalidasi dan menyimpan satu baris. seen mencatat baris pertama
+// setiap external_id dan sku agar baris ganda dalam satu file ditolak.
+func (s *BulkService) importRow(ctx context.Context, actor Actor, row productRow, archive *zip.Reader, dryRun bool, seen map[string]int) (bool, error) {
+	if row.err != nil {
+		return false, row.err
+	}
+	if err := validation.Struct(row); err != nil {
+		return false, err
+	}
+	for field, value := range map[string]string{"external_id": row.ExternalID, "sku": strings.ToUpper(row.SKU)} {
+		if value == "" {
+			continue
+		}
+		key := field + "=" + value
+		if first, ok := seen[key]; ok && first != row.line {
+			return false, Validation(apperrors.FieldError{Field: field, Code: "duplicate", Param: value})
+		}
+		seen[key] = row.line
+	}
+
+	input := ImportProductInput{
+		ExternalID: row.ExternalID,
+		SKU:        row.SKU,
+		Name:       row.Name,
+		Price:      row.Price,
+		Currency:   row.Currency,
+		Tags:       row.Tags,
+	}
+	if row.CategoryIDs != nil {
+		input.CategoryIDs = parseIDs(row.CategoryIDs)
+	}
+	if row.Images != nil {
+		images, err := s.resolveImages(ctx, row.Images, archive, dryRun)
+		if err != nil {
+			return false, err
+		}
+		input.Images = images
+	}
+	return s.products.ImportProduct(ctx, actor, input, dryRun)
+}
+
+// resolveImages membaca gambar dari path produk, URL atau arsip ZIP. Pada dry-run
+// gambar dari URL tidak diunduh, hanya alamatnya yang dicek.
+func (s *BulkService) resolveImages(ctx context.Context, refs []string, archive *zip.Reader, dryRun bool) ([]ImportImage, error) {
+	images := make([]ImportImage, 0, len(refs))
+	for _, ref := range refs {
+		if strings.HasPrefix(ref, storage.ImageURLPrefix+"/") {
+			images = append(images, ImportImage{Path: ref})
+			continue
+		}
+
+		if strings.HasPrefix(ref, "http://") || strings.HasPrefix(ref, "https://") {
+			target, err := url.Parse(ref)
+			if err != nil || target.Host == "" {
+				return nil, Validation(apperrors.FieldError{Field: "images", Code: "invalid"})
+			}
+			if dryRun {
+				images = append(images, ImportImage{Upload: &ImageUpload{Filename: path.Base(target.Path)}})
+				continue
+			}
+			upload, err := s.fetchImage(ctx, target)
+			if err != nil {
+				return nil, err
+			}
+			images = append(images, ImportImage{Upload: upload})
+			continue
+		}
+
+		upload, err := archiveImage(archive, ref)
+		if err != nil {
+			return nil, err
+		}
+		images = append(images, ImportImage{Upload: upload})
+	}
+	return images, nil
+}
+
+// fetchImage mengunduh gambar dari URL. Penyebab kegagalan (status upstream atau
+// error koneksi) tidak dikembalikan agar import tidak bisa dipakai memetakan host
+// dan port internal.
+func (s *BulkService) fetchImage(ctx context.Context, target *url.URL) (*ImageUpload, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target.String(), nil)
+	if err != nil {
+		return nil, apperrors.New(apperrors.CodeImageFetchFailed)
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, apperrors.New(apperrors.CodeImageFetchFailed)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, apperrors.New(apperrors.CodeImageFetchFailed)
+	}
+
+	content, err := io.ReadAll(io.LimitReader(resp.Body, models.MaxProductImageSize+1))
+	if err != nil {
+		return nil, apperrors.New(apperrors.CodeImageFetchFailed)
+	}
+	return newImageUpload(path.Base(target.Path), content)
+}
+
+// archiveImage membaca gambar dari arsip ZIP berdasarkan nama file di dalam arsip
+func archiveImage(archive *zip.Reader, name string) (*ImageUpload, error) {
+	if archive == nil {
+		return nil, Validation(apperrors.FieldError{Field: "images", Code: "exists"})
+	}
+	file, err := archive.Open(path.Clean(strings.TrimPrefix(name, "/")))
+	if err != nil {
+		return nil, Validation(apperrors.FieldError{Field: "images", Code: "exists"})
+	}
+	defer file.Close()
+
+	content, err := io.ReadAll(io.LimitReader(file, models.MaxProductImageSize+1))
+	if err != nil {
+		return nil, Invalid(apperrors.CodeInvalidImportArchive)
+	}
+	return newImageUpload(name, content)
+}
+
+// newImageUpload memastikan isi file adalah gambar yang diizinkan. Ekstensi
+// diganti sesuai isi file jika tidak cocok, misalnya URL tanpa ekstensi.
+func newImageUpload(name string, content []byte) (*ImageUpload, error) {
+	if len(content) > models.MaxProductImageSize {
+		return nil, Validation(apperrors.FieldError{Field: "images", Code: "max_file_size", Param: strconv.Itoa(models.MaxProductImageSize)})
+	}
+	detected := http.DetectContentType(content)
+	ext := strings.ToLower(filepath.Ext(name))
+	if models.AllowedImageTypes[ext] != detected {
+		ext = ""
+		for candidate, contentType := range models.AllowedImageTypes {
+			if contentType == detected && (ext == "" || candidate < ext) {
+				ext = candidate
+			}
+		}
+		if ext == "" {
+			return nil, Validation(apperrors.FieldError{Field: "images", Code: "image_type"})
+		}
+		name = strings.TrimSuffix(path.Base(name), filepath.Ext(name)) + ext
+	}
+	return &ImageUpload{Filename: name, Content: bytes.NewReader(content)}, nil
+}
+
+// save menyimpan progres job, kegagalan hanya dicatat di log
+func (s *BulkService) save(ctx context.Context, job *models.ImportJob) {
+	if err := s.jobs.Update(ctx, job); err != nil {
+		log.Println("❌ Gagal menyimpan progres import", job.Id, ":", err)
+	}
+}
+
+// finish menandai job selesai dengan status yang sudah diisi
+func (s *BulkService) finish(ctx context.Context, job *models.ImportJob) {
+	now := time.Now()
+	job.FinishedAt = &now
+	s.save(ctx, job)
+}
+
+// newRowError mengubah error satu baris menjadi pesan yang sama dengan response error API
+func newRowError(line int, err error, lang apperrors.Lang) models.ImportRowError {
+	problem := apperrors.NewProblem(err, lang, false)
+	return models.ImportRowError{Row: line, Code: problem.Code, Message: problem.Detail, Errors: problem.Errors}
+}
+
+// eachProductRow membaca baris file dari awal dan memanggil each untuk setiap baris,
+// sehingga file besar tidak dimuat sekaligus. Error di sini membatalkan seluruh
+// import, sedangkan error per baris dicatat di productRow.err. Error dari each
+// menghentikan pembacaan dan dikembalikan apa adanya.
+func eachProductRow(format string, file io.ReadSeeker, each func(productRow) error) error {
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to read import file: %w", err)
+	}
+	reader := bufio.NewReader(file)
+	if bom, err := reader.Peek(3); err == nil && bytes.Equal(bom, []byte("\xef\xbb\xbf")) {
+		reader.Discard(len(bom))
+	}
+
+	// Jumlah baris dibatasi di sini agar semua format mendapat batas yang sama
+	count := 0
+	limited := func(row productRow) error {
+		if count >= models.MaxImportRows {
+			return Validation(apperrors.FieldError{Field: "file", Code: "max_items", Param: strconv.Itoa(models.MaxImportRows)})
+		}
+		count++
+		return each(row)
+	}
+	switch format {
+	case models.FormatCSV:
+		return eachCSVRow(reader, limited)
+	case models.FormatJSONL:
+		return eachJSONLRow(reader, limited)
+	default:
+		return Validation(apperrors.FieldError{Field: "format", Code: "oneof", Param: models.FormatCSV + " " + models.FormatJSONL})
+	}
+}
+
+func eachCSVRow(file io.Reader, each func(productRow) error) error {
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	reader.ReuseRecord = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil
+	}
+	if err != nil {
+		return Invalid(apperrors.CodeInvalidImportFile)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if _, ok := columns[name]; ok || !isProductColumn(name) {
+			return Invalid(apperrors.CodeInvalidImportFile)
+		}
+		columns[name] = i
+	}
+	width := len(header)
+
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return Invalid(apperrors.CodeInvalidImportFile)
+		}
+
+		line, _ := reader.FieldPos(0)
+		row := productRow{line: line}
+		if len(record) != width {
+			row.err = Validation(apperrors.FieldError{Field: "row", Code: "invalid"})
+		} else {
+			cell := func(name string) string {
+				if i, ok := columns[name]; ok {
+					return strings.TrimSpace(record[i])
+				}
+				return ""
+			}
+			row.ExternalID = cell("external_id")
+			row.SKU = cell("sku")
+			row.Name = cell("name")
+			row.Price = cell("price")
+			row.Currency = cell("currency")
+			row.CategoryIDs = splitList(cell("category_ids"))
+			row.Tags = splitList(cell("tags"))
+			row.Images = splitList(cell("images"))
+		}
+		if err := each(row); err != nil {
+			return err
+		}
+	}
+}
+
+func eachJSONLRow(file io.Reader, each func(productRow) error) error {
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), models.MaxImportFileSize)
+
+	for line := 1; scanner.Scan(); line++ {
+		text := bytes.TrimSpace(scanner.Bytes())
+		if len(text) == 0 {
+			continue
+		}
+
+		row := productRow{line: line}
+		decoder := json.NewDecoder(bytes.NewReader(text))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&row); err != nil || decoder.More() {
+			row = productRow{line: line, err: apperrors.Wrap(apperrors.CodeInvalidJSON, err)}
+		}
+		if err := each(row); err != nil {
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return Invalid(apperrors.CodeInvalidImportFile)
+	}
+	return nil
+}
+
+func newProductRow(product models.ProductResponse) productRow {
+	row := productRow{
+		ID:          product.Id,
+		Name:        product.Name,
+		Price:       product.Price.Decimal(),
+		Currency:    product.Price.Currency,
+		CategoryIDs: make([]string, 0, len(product.Categories)),
+		Tags:        product.Tags,
+		Images:      make([]string, 0, len(product.Images)),
+	}
+	if product.ExternalId != nil {
+		row.ExternalID = *product.ExternalId
+	}
+	// Varian pertama mewakili produk, SKU varian mana pun cocok dengan produknya saat import
+	if len(product.Variants) > 0 {
+		row.SKU = product.Variants[0].SKU
+	}
+	for _, category := range product.Categories {
+		row.CategoryIDs = append(row.CategoryIDs, category.Id)
+	}
+	for _, image := range product.Images {
+		row.Images = append(row.Images, image.Path)
+	}
+	return row
+}
+
+// record mengubah baris menjadi kolom CSV sesuai urutan productColumns
+func (r productRow) record() []string {
+	return []string{
+		r.ID, r.ExternalID, r.SKU, r.Name, r.Price, r.Currency,
+		strings.Join(r.CategoryIDs, listSeparator), strings.Join(r.Tags, listSeparator), strings.Join(r.Images, listSeparator),
+	}
+}
+
+func isProductColumn(name string) bool {
+	for _, column := range productColumns {
+		if column == name {
+			return true
+		}
+	}
+	return false
+}
+
+// splitList memisahkan isi sel CSV, sel kosong menjadi nil (tidak diubah)
+func splitList(value string) []string {
+	if value == "" {
+		return nil
+	}
+	items := make([]string, 0)
+	for _, item := range strings.Split(value, listSeparator) {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// parseIDs mengubah daftar UUID yang sudah divalidasi
+func parseIDs(values []string) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(values))
+	for _, value := range values {
+		ids = append(ids, uuid.MustParse(value))
+	}
+	return ids
+}
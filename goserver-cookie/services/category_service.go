@@ -0,0 +1,223 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"server-cookie/apperrors"
+	"server-cookie/models"
+	"server-cookie/repositories"
+	"strings"
+	"unicode"
+
+	"github.com/google/uuid"
+)
+
+// CategoryInput adalah data untuk membuat atau mengubah kategori.
+// Slug kosong dibuat dari Name, ParentID nil berarti kategori utama.
+type CategoryInput struct {
+	Name     string
+	Slug     string
+	ParentID *uuid.UUID
+}
+
+// CategoryService berisi aturan pohon kategori produk
+type CategoryService struct {
+	categories repositories.CategoryRepository
+}
+
+// NewCategoryService membuat CategoryService dengan dependency yang diberikan
+func NewCategoryService(categories repositories.CategoryRepository) *CategoryService {
+	return &CategoryService{categories: categories}
+}
+
+// Tree mengambil semua kategori sebagai pohon, diurutkan berdasarkan nama
+func (s *CategoryService) Tree(ctx context.Context) ([]models.CategoryNode, error) {
+	categories, err := s.categories.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve categories: %w", err)
+	}
+	return buildTree(categories, nil), nil
+}
+
+// Create membuat kategori baru
+func (s *CategoryService) Create(ctx context.Context, input CategoryInput) (*models.CategoryNode, error) {
+	category := models.Category{}
+	if err := s.apply(ctx, &category, input); err != nil {
+		return nil, err
+	}
+	if err := s.categories.Create(ctx, &category); err != nil {
+		return nil, fmt.Errorf("failed to create category: %w", err)
+	}
+	node := toCategoryNode(category)
+	return &node, nil
+}
+
+// Update mengubah nama, slug atau parent kategori
+func (s *CategoryService) Update(ctx context.Context, id uuid.UUID, input CategoryInput) (*models.CategoryNode, error) {
+	category, err := s.find(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.apply(ctx, category, input); err != nil {
+		return nil, err
+	}
+	if err := s.categories.Update(ctx, category); err != nil {
+		return nil, fmt.Errorf("failed to update category: %w", err)
+	}
+	node := toCategoryNode(*category)
+	return &node, nil
+}
+
+// Delete menghapus kategori yang tidak punya sub-kategori, produknya tidak ikut terhapus
+func (s *CategoryService) Delete(ctx context.Context, id uuid.UUID) error {
+	category, err := s.find(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	categories, err := s.categories.List(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve categories: %w", err)
+	}
+	for _, other := range categories {
+		if other.ParentId != nil && *other.ParentId == id {
+			return Conflict(apperrors.CodeCategoryHasChildren)
+		}
+	}
+
+	if err := s.categories.Delete(ctx, category); err != nil {
+		return fmt.Errorf("failed to delete category: %w", err)
+	}
+	return nil
+}
+
+// apply memvalidasi input lalu mengisinya ke category
+func (s *CategoryService) apply(ctx context.Context, category *models.Category, input CategoryInput) error {
+	slug := slugify(input.Slug)
+	if input.Slug == "" {
+		slug = slugify(input.Name)
+	}
+	if slug == "" {
+		return Validation(apperrors.FieldError{Field: "slug", Code: "required"})
+	}
+
+	existing, err := s.categories.FindBySlug(ctx, slug)
+	if err != nil && !errors.Is(err, repositories.ErrNotFound) {
+		return fmt.Errorf("failed to check category slug: %w", err)
+	}
+	if existing != nil && existing.Id != category.Id {
+		return Conflict(apperrors.CodeCategoryExists)
+	}
+
+	if input.ParentID != nil {
+		categories, err := s.categories.List(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to retrieve categories: %w", err)
+		}
+		if !containsCategory(categories, *input.ParentID) {
+			return Validation(apperrors.FieldError{Field: "parent_id", Code: "exists"})
+		}
+		// Parent tidak boleh kategori ini sendiri atau turunannya
+		if category.Id != uuid.Nil {
+			for _, id := range subtree(categories, category.Id) {
+				if id == *input.ParentID {
+					return Validation(apperrors.FieldError{Field: "parent_id", Code: "no_cycle"})
+				}
+			}
+		}
+	}
+
+	category.Name = strings.TrimSpace(input.Name)
+	category.Slug = slug
+	category.ParentId = input.ParentID
+	return nil
+}
+
+func (s *CategoryService) find(ctx context.Context, id uuid.UUID) (*models.Category, error) {
+	category, err := s.categories.FindByID(ctx, id)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil, NotFound(apperrors.CodeCategoryNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve category: %w", err)
+	}
+	return category, nil
+}
+
+// subtree mengembalikan ID root beserta semua turunannya
+func subtree(categories []models.Category, root uuid.UUID) []uuid.UUID {
+	children := make(map[uuid.UUID][]uuid.UUID)
+	for _, category := range categories {
+		if category.ParentId != nil {
+			children[*category.ParentId] = append(children[*category.ParentId], category.Id)
+		}
+	}
+
+	ids := []uuid.UUID{root}
+	for i := 0; i < len(ids); i++ {
+		ids = append(ids, children[ids[i]]...)
+	}
+	return ids
+}
+
+func containsCategory(categories []models.Category, id uuid.UUID) bool {
+	for _, category := range categories {
+		if category.Id == id {
+			return true
+		}
+	}
+	return false
+}
+
+// buildTree menyusun kategori dengan parent yang diberikan beserta turunannya
+func buildTree(categories []models.Category, parent *uuid.UUID) []models.CategoryNode {
+	nodes := []models.CategoryNode{}
+	for _, category := range categories {
+		if !sameParent(category.ParentId, parent) {
+			continue
+		}
+		node := toCategoryNode(category)
+		node.Children = buildTree(categories, &category.Id)
+		nodes = append(nodes, node)
+	}
+	return nodes
+}
+
+func sameParent(a, b *uuid.UUID) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func toCategoryNode(category models.Category) models.CategoryNode {
+	node := models.CategoryNode{
+		Id:       category.Id.String(),
+		Name:     category.Name,
+		Slug:     category.Slug,
+		Children: []models.CategoryNode{},
+	}
+	if category.ParentId != nil {
+		parent := category.ParentId.String()
+		node.ParentId = &parent
+	}
+	return node
+}
+
+// slugify mengubah teks menjadi slug huruf kecil dengan pemisah "-"
+func slugify(text string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(strings.TrimSpace(text)) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(r)
+			dash = false
+		case b.Len() > 0 && !dash:
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+	return strings.TrimSuffix(b.String(), "-")
+}
@@ -0,0 +1,106 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"server-cookie/repositories"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CursorSigner membuat dan memeriksa cursor pagination yang ditandatangani HMAC-SHA256,
+// sehingga client tidak bisa mengubah isinya
+type CursorSigner struct {
+	secret []byte
+}
+
+// NewCursorSigner membuat CursorSigner dengan secret yang diberikan
+func NewCursorSigner(secret []byte) *CursorSigner {
+	return &CursorSigner{secret: secret}
+}
+
+// errInvalidCursor dikembalikan untuk cursor yang rusak atau tanda tangannya salah
+var errInvalidCursor = errors.New("invalid cursor")
+
+// productCursor adalah isi cursor list produk. Query berisi fingerprint sort dan filter
+// saat cursor dibuat, agar cursor tidak dipakai dengan query yang berbeda.
+type productCursor struct {
+	Query    string                  `json:"q"`
+	Key      repositories.ProductKey `json:"k"`
+	Backward bool                    `json:"b,omitempty"`
+}
+
+// Encode membuat cursor berbentuk <payload>.<signature> dalam base64 URL-safe
+func (s *CursorSigner) Encode(cursor productCursor) string {
+	payload, _ := json.Marshal(cursor)
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(s.sign(encoded))
+}
+
+// Decode memeriksa tanda tangan lalu membaca isi cursor
+func (s *CursorSigner) Decode(value string) (productCursor, error) {
+	var cursor productCursor
+	encoded, signature, ok := strings.Cut(value, ".")
+	if !ok {
+		return cursor, errInvalidCursor
+	}
+	got, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(got, s.sign(encoded)) {
+		return cursor, errInvalidCursor
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return cursor, errInvalidCursor
+	}
+	if err := json.Unmarshal(payload, &cursor); err != nil {
+		return cursor, fmt.Errorf("%w: %v", errInvalidCursor, err)
+	}
+	return cursor, nil
+}
+
+func (s *CursorSigner) sign(payload string) []byte {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(payload))
+	return mac.Sum(nil)
+}
+
+// fingerprint meringkas sort dan filter list produk, tanpa page dan limit
+func (input ListProductsInput) fingerprint() string {
+	canonical := struct {
+		Search        string
+		CategoryID    *uuid.UUID
+		Tags          []string
+		AllTags       bool
+		Currency      string
+		MinPrice      *int64
+		MaxPrice      *int64
+		OwnerID       *uuid.UUID
+		CreatedAfter  *time.Time
+		CreatedBefore *time.Time
+		HasImage      *bool
+		LowStock      bool
+		Status        string
+		Sort          []repositories.ProductSort
+	}{
+		input.Search, input.CategoryID, normalizeTags(input.Tags), input.AllTags,
+		input.Currency, input.MinPrice, input.MaxPrice, input.OwnerID, input.CreatedAfter, input.CreatedBefore,
+		input.HasImage, input.LowStock, input.Status, input.sortOrDefault(),
+	}
+	data, _ := json.Marshal(canonical)
+	sum := sha256.Sum256(data)
+	return base64.RawURLEncoding.EncodeToString(sum[:12])
+}
+
+// sortOrDefault mengembalikan urutan yang benar-benar dipakai repository
+func (input ListProductsInput) sortOrDefault() []repositories.ProductSort {
+	if len(input.Sort) == 0 {
+		return repositories.DefaultProductSort
+	}
+	return input.Sort
+}
@@ -0,0 +1,66 @@
+package services
+
+import (
+	"errors"
+	"server-cookie/apperrors"
+)
+
+// Jenis error domain, dicek dengan errors.Is
+var (
+	ErrNotFound   = errors.New("not found")
+	ErrForbidden  = errors.New("forbidden")
+	ErrValidation = errors.New("validation failed")
+	ErrConflict   = errors.New("conflict")
+	// ErrPreconditionFailed berarti syarat dari client, misalnya If-Match, tidak terpenuhi
+	ErrPreconditionFailed = errors.New("precondition failed")
+)
+
+// Error adalah error domain dengan kode dari katalog apperrors
+type Error struct {
+	Kind   error
+	Code   apperrors.Code
+	Fields []apperrors.FieldError
+}
+
+func (e *Error) Error() string {
+	return e.Code.Message(apperrors.DefaultLang)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Kind
+}
+
+// AppError mengubah error domain menjadi error aplikasi untuk frontend
+func (e *Error) AppError() *apperrors.Error {
+	return &apperrors.Error{Code: e.Code, Fields: e.Fields}
+}
+
+// NotFound membuat error untuk data yang tidak ditemukan
+func NotFound(code apperrors.Code) *Error {
+	return &Error{Kind: ErrNotFound, Code: code}
+}
+
+// Forbidden membuat error untuk aksi yang tidak diizinkan
+func Forbidden(code apperrors.Code) *Error {
+	return &Error{Kind: ErrForbidden, Code: code}
+}
+
+// Validation membuat error validation_failed untuk input yang tidak valid
+func Validation(fields ...apperrors.FieldError) *Error {
+	return &Error{Kind: ErrValidation, Code: apperrors.CodeValidationFailed, Fields: fields}
+}
+
+// Invalid membuat error untuk input tidak valid yang punya kode sendiri
+func Invalid(code apperrors.Code) *Error {
+	return &Error{Kind: ErrValidation, Code: code}
+}
+
+// PreconditionFailed membuat error untuk syarat request yang tidak terpenuhi
+func PreconditionFailed(code apperrors.Code) *Error {
+	return &Error{Kind: ErrPreconditionFailed, Code: code}
+}
+
+// Conflict membuat error untuk data yang bentrok dengan data lain
+func Conflict(code apperrors.Code) *Error {
+	return &Error{Kind: ErrConflict, Code: code}
+}
@@ -0,0 +1,320 @@
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"server-cookie/apperrors"
+	"server-cookie/models"
+	"server-cookie/money"
+	"server-cookie/repositories"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ConversionRounding adalah pembulatan hasil konversi harga ke minor unit
+const ConversionRounding = money.RoundHalfEven
+
+// ExchangeRateInput adalah kurs dari admin, 1 Base = Rate Quote.
+// AsOf nil berarti kurs berlaku saat disimpan.
+type ExchangeRateInput struct {
+	Base  string
+	Quote string
+	Rate  string
+	AsOf  *time.Time
+}
+
+// ExchangeRateService berisi aturan kurs dan konversi harga produk
+type ExchangeRateService struct {
+	rates  repositories.ExchangeRateRepository
+	users  repositories.UserRepository
+	maxAge time.Duration
+	now    func() time.Time
+}
+
+// NewExchangeRateService membuat ExchangeRateService dengan dependency yang diberikan.
+// Kurs yang AsOf-nya lebih lama dari maxAge dianggap basi.
+func NewExchangeRateService(rates repositories.ExchangeRateRepository, users repositories.UserRepository, maxAge time.Duration) *ExchangeRateService {
+	return &ExchangeRateService{rates: rates, users: users, maxAge: maxAge, now: time.Now}
+}
+
+// List mengambil semua kurs beserta status basinya
+func (s *ExchangeRateService) List(ctx context.Context) ([]models.ExchangeRateResponse, error) {
+	rates, err := s.rates.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve exchange rates: %w", err)
+	}
+	now := s.now()
+	responses := make([]models.ExchangeRateResponse, 0, len(rates))
+	for _, rate := range rates {
+		responses = append(responses, s.toResponse(rate, now))
+	}
+	return responses, nil
+}
+
+// Set menyimpan atau mengganti kurs satu pasangan mata uang
+func (s *ExchangeRateService) Set(ctx context.Context, input ExchangeRateInput) (*models.ExchangeRateResponse, error) {
+	now := s.now()
+	rate, fields := s.build(input, models.RateSourceManual, now, "")
+	if len(fields) > 0 {
+		return nil, Validation(fields...)
+	}
+	if err := s.rates.Save(ctx, []models.ExchangeRate{rate}); err != nil {
+		return nil, fmt.Errorf("failed to save exchange rate: %w", err)
+	}
+	response := s.toResponse(rate, now)
+	return &response, nil
+}
+
+// Import membaca kurs dari CSV dengan header base,quote,rate dan kolom as_of opsional,
+// lalu menyimpan semuanya sekaligus. Jika satu baris tidak valid tidak ada yang disimpan,
+// error field ditulis sebagai rows[<nomor baris>].<kolom>.
+func (s *ExchangeRateService) Import(ctx context.Context, file io.Reader) ([]models.ExchangeRateResponse, error) {
+	reader := csv.NewReader(file)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, Validation(apperrors.FieldError{Field: "file", Code: "required"})
+	}
+	if err != nil {
+		return nil, Invalid(apperrors.CodeInvalidCSV)
+	}
+	columns, ok := csvColumns(header)
+	if !ok {
+		return nil, Invalid(apperrors.CodeInvalidCSV)
+	}
+
+	now := s.now()
+	var rates []models.ExchangeRate
+	var fields []apperrors.FieldError
+	seen := make(map[[2]string]bool)
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, Invalid(apperrors.CodeInvalidCSV)
+		}
+		line, _ := reader.FieldPos(0)
+		if len(rates) >= models.MaxExchangeRateImportRows {
+			return nil, Validation(apperrors.FieldError{Field: "file", Code: "max_items", Param: strconv.Itoa(models.MaxExchangeRateImportRows)})
+		}
+
+		input := ExchangeRateInput{Base: record[columns["base"]], Quote: record[columns["quote"]], Rate: record[columns["rate"]]}
+		prefix := fmt.Sprintf("rows[%d].", line)
+		if i, ok := columns["as_of"]; ok && strings.TrimSpace(record[i]) != "" {
+			asOf, ok := parseTimestamp(strings.TrimSpace(record[i]))
+			if !ok {
+				fields = append(fields, apperrors.FieldError{Field: prefix + "as_of", Code: "datetime"})
+				continue
+			}
+			input.AsOf = &asOf
+		}
+
+		rate, rowFields := s.build(input, models.RateSourceImport, now, prefix)
+		if len(rowFields) > 0 {
+			fields = append(fields, rowFields...)
+			continue
+		}
+		pair := [2]string{rate.Base, rate.Quote}
+		if seen[pair] {
+			fields = append(fields, apperrors.FieldError{Field: prefix + "quote", Code: "duplicate", Param: rate.Base + "/" + rate.Quote})
+			continue
+		}
+		seen[pair] = true
+		rates = append(rates, rate)
+	}
+
+	if len(fields) > 0 {
+		return nil, Validation(fields...)
+	}
+	if len(rates) == 0 {
+		return nil, Validation(apperrors.FieldError{Field: "file", Code: "required"})
+	}
+	if err := s.rates.Save(ctx, rates); err != nil {
+		return nil, fmt.Errorf("failed to import exchange rates: %w", err)
+	}
+
+	responses := make([]models.ExchangeRateResponse, 0, len(rates))
+	for _, rate := range rates {
+		responses = append(responses, s.toResponse(rate, now))
+	}
+	return responses, nil
+}
+
+// Delete menghapus kurs satu pasangan mata uang
+func (s *ExchangeRateService) Delete(ctx context.Context, base, quote string) error {
+	err := s.rates.Delete(ctx, strings.ToUpper(base), strings.ToUpper(quote))
+	if errors.Is(err, repositories.ErrNotFound) {
+		return NotFound(apperrors.CodeExchangeRateNotFound)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to delete exchange rate: %w", err)
+	}
+	return nil
+}
+
+// Converter menyiapkan konversi harga ke currency. Jika currency kosong dipakai
+// mata uang pilihan actor, nil berarti harga tidak perlu dikonversi.
+func (s *ExchangeRateService) Converter(ctx context.Context, actor Actor, currency string) (*PriceConverter, error) {
+	if currency == "" {
+		user, err := s.users.FindByID(ctx, actor.UserID)
+		if err != nil && !errors.Is(err, repositories.ErrNotFound) {
+			return nil, fmt.Errorf("failed to retrieve user: %w", err)
+		}
+		if user == nil || user.Currency == "" {
+			return nil, nil
+		}
+		currency = user.Currency
+	}
+	target, err := money.LookupCurrency(currency)
+	if err != nil {
+		return nil, Validation(apperrors.FieldError{Field: "currency", Code: "currency"})
+	}
+
+	rates, err := s.rates.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve exchange rates: %w", err)
+	}
+	converter := &PriceConverter{currency: target.Code, rates: make(map[string]models.ExchangeRate), maxAge: s.maxAge, now: s.now()}
+	for _, rate := range rates {
+		if rate.Quote == target.Code {
+			converter.rates[rate.Base] = rate
+		}
+	}
+	return converter, nil
+}
+
+// build memvalidasi input lalu membuat ExchangeRate, nama field error diawali prefix
+func (s *ExchangeRateService) build(input ExchangeRateInput, source string, now time.Time, prefix string) (models.ExchangeRate, []apperrors.FieldError) {
+	var fields []apperrors.FieldError
+	base, err := money.LookupCurrency(input.Base)
+	if err != nil {
+		fields = append(fields, apperrors.FieldError{Field: prefix + "base", Code: "currency"})
+	}
+	quote, err := money.LookupCurrency(input.Quote)
+	if err != nil {
+		fields = append(fields, apperrors.FieldError{Field: prefix + "quote", Code: "currency"})
+	} else if quote.Code == base.Code {
+		fields = append(fields, apperrors.FieldError{Field: prefix + "quote", Code: "different", Param: "base"})
+	}
+	if _, err := money.ParseRate(input.Rate); err != nil {
+		fields = append(fields, apperrors.FieldError{Field: prefix + "rate", Code: "exchange_rate", Param: strconv.Itoa(money.MaxRateDecimals)})
+	}
+
+	asOf := now
+	if input.AsOf != nil {
+		asOf = *input.AsOf
+		if asOf.After(now) {
+			fields = append(fields, apperrors.FieldError{Field: prefix + "as_of", Code: "not_future"})
+		}
+	}
+	return models.ExchangeRate{
+		Base:   base.Code,
+		Quote:  quote.Code,
+		Rate:   strings.TrimSpace(input.Rate),
+		AsOf:   asOf.UTC(),
+		Source: source,
+	}, fields
+}
+
+func (s *ExchangeRateService) toResponse(rate models.ExchangeRate, now time.Time) models.ExchangeRateResponse {
+	return models.ExchangeRateResponse{
+		Base:      rate.Base,
+		Quote:     rate.Quote,
+		Rate:      rate.Rate,
+		AsOf:      rate.AsOf,
+		Source:    rate.Source,
+		UpdatedAt: rate.UpdatedAt,
+		Stale:     now.Sub(rate.AsOf) > s.maxAge,
+	}
+}
+
+// csvColumns mencari posisi kolom dari header CSV. Kolom base, quote dan rate wajib ada,
+// as_of opsional dan kolom lain tidak diizinkan.
+func csvColumns(header []string) (map[string]int, bool) {
+	columns := make(map[string]int)
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		switch name {
+		case "base", "quote", "rate", "as_of":
+		default:
+			return nil, false
+		}
+		if _, ok := columns[name]; ok {
+			return nil, false
+		}
+		columns[name] = i
+	}
+	for _, name := range []string{"base", "quote", "rate"} {
+		if _, ok := columns[name]; !ok {
+			return nil, false
+		}
+	}
+	return columns, true
+}
+
+// PriceConverter mengonversi harga produk ke satu mata uang tampilan dengan kurs
+// yang dibaca saat converter dibuat
+type PriceConverter struct {
+	currency string
+	// rates adalah kurs ke currency, key-nya mata uang asal
+	rates  map[string]models.ExchangeRate
+	maxAge time.Duration
+	now    time.Time
+}
+
+// Apply mengisi ConvertedPrice setiap produk. Converter nil tidak mengubah apa pun.
+func (c *PriceConverter) Apply(products ...*models.ProductResponse) {
+	if c == nil {
+		return
+	}
+	for _, product := range products {
+		product.ConvertedPrice = c.convert(product)
+	}
+}
+
+// convert memakai kurs langsung dari mata uang produk ke currency tanpa kurs perantara,
+// hasilnya dibulatkan dengan ConversionRounding
+func (c *PriceConverter) convert(product *models.ProductResponse) *models.PriceConversion {
+	conversion := &models.PriceConversion{Currency: c.currency, Rounding: ConversionRounding.String()}
+	if product.Price.Currency == c.currency {
+		price, priceMin, priceMax := product.Price, product.PriceMin, product.PriceMax
+		conversion.Price, conversion.PriceMin, conversion.PriceMax = &price, &priceMin, &priceMax
+		conversion.Rate = "1"
+		return conversion
+	}
+
+	rate, ok := c.rates[product.Price.Currency]
+	if !ok {
+		conversion.Warning = models.WarningRateUnavailable
+		return conversion
+	}
+	r, err := money.ParseRate(rate.Rate)
+	if err != nil {
+		conversion.Warning = models.WarningRateUnavailable
+		return conversion
+	}
+	prices := make([]money.Money, 0, 3)
+	for _, price := range []money.Money{product.Price, product.PriceMin, product.PriceMax} {
+		converted, err := price.Convert(c.currency, r, ConversionRounding)
+		if err != nil {
+			conversion.Warning = models.WarningRateUnavailable
+			return conversion
+		}
+		prices = append(prices, converted)
+	}
+
+	conversion.Price, conversion.PriceMin, conversion.PriceMax = &prices[0], &prices[1], &prices[2]
+	conversion.Rate = rate.Rate
+	conversion.RateAsOf = &rate.AsOf
+	if c.now.Sub(rate.AsOf) > c.maxAge {
+		conversion.Warning = models.WarningRateStale
+	}
+	return conversion
+}
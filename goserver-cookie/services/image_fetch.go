@@ -0,0 +1,84 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+	"time"
+)
+
+// maxImageRedirects membatasi jumlah redirect saat mengunduh gambar import
+const maxImageRedirects = 3
+
+// errPrivateAddress dikembalikan jika URL gambar mengarah ke alamat yang bukan publik
+var errPrivateAddress = errors.New("image URL resolves to a non-public address")
+
+// nonPublicPrefixes adalah rentang alamat yang tidak tercakup netip.Addr.IsPrivate
+// dan sejenisnya, misalnya shared address space (CGNAT) dan jaringan benchmark
+var nonPublicPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+	netip.MustParsePrefix("64:ff9b::/96"),
+}
+
+// NewImageClient membuat HTTP client untuk mengunduh gambar import dari URL
+// yang dikirim user. Tanpa allowPrivate, koneksi ke loopback, jaringan privat,
+// link-local (termasuk metadata cloud 169.254.169.254) dan alamat non-publik
+// lainnya ditolak setelah DNS di-resolve, termasuk untuk setiap redirect.
+// allowPrivate hanya untuk development, misalnya storage gambar di jaringan lokal.
+func NewImageClient(allowPrivate bool) *http.Client {
+	dialer := &net.Dialer{Timeout: 10 * time.Second}
+	if !allowPrivate {
+		dialer.Control = func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			ip, err := netip.ParseAddr(host)
+			if err != nil || !publicAddr(ip) {
+				return errPrivateAddress
+			}
+			return nil
+		}
+	}
+
+	return &http.Client{
+		Timeout: imageFetchTimeout,
+		// Proxy dari environment tidak dipakai agar alamat tujuan selalu dicek dialer
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: 10 * time.Second,
+			MaxIdleConns:        10,
+			IdleConnTimeout:     90 * time.Second,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) > maxImageRedirects {
+				return fmt.Errorf("stopped after %d redirects", maxImageRedirects)
+			}
+			if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+				return fmt.Errorf("redirect to unsupported scheme %q", req.URL.Scheme)
+			}
+			return nil
+		},
+	}
+}
+
+// publicAddr melaporkan apakah ip adalah alamat unicast publik
+func publicAddr(ip netip.Addr) bool {
+	ip = ip.Unmap()
+	if !ip.IsGlobalUnicast() || ip.IsPrivate() || ip.IsLoopback() || ip.IsLinkLocalUnicast() {
+		return false
+	}
+	for _, prefix := range nonPublicPrefixes {
+		if prefix.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
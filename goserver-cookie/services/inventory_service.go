@@ -0,0 +1,367 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"server-cookie/apperrors"
+	"server-cookie/models"
+	"server-cookie/repositories"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AdjustStockInput adalah perubahan stok manual oleh pemilik produk.
+// VariantID wajib diisi untuk produk yang punya varian dan harus kosong untuk yang tidak.
+type AdjustStockInput struct {
+	VariantID *uuid.UUID
+	Delta     int64
+	Reason    string
+	Note      string
+}
+
+// MovementListInput adalah parameter pagination ledger stok, VariantID nil berarti semua item
+type MovementListInput struct {
+	VariantID *uuid.UUID
+	Page      int
+	Limit     int
+}
+
+// MovementList adalah satu halaman ledger stok
+type MovementList struct {
+	Movements  []models.InventoryMovementResponse
+	Page       int
+	Limit      int
+	TotalItems int64
+	TotalPages int
+}
+
+// InventoryService berisi aturan bisnis stok, ledger dan reservasi
+type InventoryService struct {
+	inventory      repositories.InventoryRepository
+	products       repositories.ProductRepository
+	reservationTTL time.Duration
+}
+
+// NewInventoryService membuat InventoryService. Reservasi yang belum dikonfirmasi
+// kedaluwarsa setelah reservationTTL.
+func NewInventoryService(inventory repositories.InventoryRepository, products repositories.ProductRepository, reservationTTL time.Duration) *InventoryService {
+	return &InventoryService{inventory: inventory, products: products, reservationTTL: reservationTTL}
+}
+
+// Levels mengambil stok setiap item produk milik actor, termasuk item yang stoknya belum dicatat
+func (s *InventoryService) Levels(ctx context.Context, actor Actor, productID uuid.UUID) ([]models.StockLevelResponse, error) {
+	product, err := s.findOwned(ctx, actor, productID)
+	if err != nil {
+		return nil, err
+	}
+	levels, err := s.inventory.Levels(ctx, []uuid.UUID{product.Id})
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve stock: %w", err)
+	}
+	product.Stock = levels
+
+	if len(product.Variants) == 0 {
+		return []models.StockLevelResponse{models.NewStockLevelResponse(nil, product.StockOf(uuid.Nil))}, nil
+	}
+	responses := make([]models.StockLevelResponse, 0, len(product.Variants))
+	for i := range product.Variants {
+		variant := &product.Variants[i]
+		responses = append(responses, models.NewStockLevelResponse(variant, product.StockOf(variant.Id)))
+	}
+	return responses, nil
+}
+
+// Adjust mengubah stok item produk milik actor dan mencatatnya di ledger.
+// Stok tidak boleh dikurangi sampai di bawah jumlah yang sedang direservasi.
+func (s *InventoryService) Adjust(ctx context.Context, actor Actor, productID uuid.UUID, input AdjustStockInput) (*models.StockLevelResponse, error) {
+	product, err := s.findOwned(ctx, actor, productID)
+	if err != nil {
+		return nil, err
+	}
+	variant, err := stockVariant(product, input.VariantID)
+	if err != nil {
+		return nil, err
+	}
+	if !deltaMatchesReason(input.Delta, input.Reason) {
+		return nil, Validation(apperrors.FieldError{Field: "delta", Code: "delta_sign"})
+	}
+
+	movement := models.InventoryMovement{
+		ProductId: product.Id,
+		VariantId: variantKey(variant),
+		Delta:     input.Delta,
+		Reason:    input.Reason,
+		Note:      input.Note,
+		UserId:    actor.UserID,
+	}
+	level, err := s.inventory.Adjust(ctx, &movement)
+	if errors.Is(err, repositories.ErrInsufficientStock) {
+		return nil, Conflict(apperrors.CodeInsufficientStock)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to adjust stock: %w", err)
+	}
+	response := models.NewStockLevelResponse(variant, level)
+	return &response, nil
+}
+
+// SetThreshold mengubah batas stok menipis item produk milik actor, 0 mematikan peringatan
+func (s *InventoryService) SetThreshold(ctx context.Context, actor Actor, productID uuid.UUID, variantID *uuid.UUID, threshold int64) (*models.StockLevelResponse, error) {
+	product, err := s.findOwned(ctx, actor, productID)
+	if err != nil {
+		return nil, err
+	}
+	variant, err := stockVariant(product, variantID)
+	if err != nil {
+		return nil, err
+	}
+
+	level, err := s.inventory.SetThreshold(ctx, repositories.StockItem{ProductID: product.Id, VariantID: variantKey(variant)}, threshold)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update low stock threshold: %w", err)
+	}
+	response := models.NewStockLevelResponse(variant, level)
+	return &response, nil
+}
+
+// Movements mengambil ledger stok produk milik actor, yang terbaru lebih dulu
+func (s *InventoryService) Movements(ctx context.Context, actor Actor, productID uuid.UUID, input MovementListInput) (*MovementList, error) {
+	if input.Page < 1 {
+		input.Page = 1
+	}
+	if input.Limit < 1 {
+		input.Limit = 20
+	}
+	product, err := s.findOwned(ctx, actor, productID)
+	if err != nil {
+		return nil, err
+	}
+	if input.VariantID != nil && variantIndex(product, *input.VariantID) < 0 {
+		return nil, NotFound(apperrors.CodeVariantNotFound)
+	}
+
+	movements, total, err := s.inventory.Movements(ctx, product.Id, input.VariantID, input.Page, input.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve stock movements: %w", err)
+	}
+	list := &MovementList{
+		Movements:  make([]models.InventoryMovementResponse, 0, len(movements)),
+		Page:       input.Page,
+		Limit:      input.Limit,
+		TotalItems: total,
+		TotalPages: int((total + int64(input.Limit) - 1) / int64(input.Limit)),
+	}
+	for _, movement := range movements {
+		list.Movements = append(list.Movements, models.NewInventoryMovementResponse(movement))
+	}
+	return list, nil
+}
+
+// Reserve menahan stok item produk untuk actor sampai dikonfirmasi, dilepas atau kedaluwarsa
+func (s *InventoryService) Reserve(ctx context.Context, actor Actor, productID uuid.UUID, variantID *uuid.UUID, quantity int64) (*models.StockReservationResponse, error) {
+	product, err := s.find(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	// Produk yang tidak published hanya bisa dipesan oleh pemiliknya, misalnya untuk mencoba
+	if !product.VisibleTo(actor.UserID) {
+		return nil, NotFound(apperrors.CodeProductNotFound)
+	}
+	variant, err := stockVariant(product, variantID)
+	if err != nil {
+		return nil, err
+	}
+
+	reservation := models.StockReservation{
+		ProductId: product.Id,
+		VariantId: variantKey(variant),
+		UserId:    actor.UserID,
+		Quantity:  quantity,
+		ExpiresAt: time.Now().Add(s.reservationTTL),
+	}
+	err = s.inventory.Reserve(ctx, &reservation)
+	if errors.Is(err, repositories.ErrInsufficientStock) {
+		return nil, Conflict(apperrors.CodeInsufficientStock)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to reserve stock: %w", err)
+	}
+	response := models.NewStockReservationResponse(reservation)
+	return &response, nil
+}
+
+// ConfirmReservation mengubah reservasi pending milik actor menjadi penjualan,
+// stok dikurangi dan dicatat di ledger
+func (s *InventoryService) ConfirmReservation(ctx context.Context, actor Actor, id uuid.UUID) (*models.StockReservationResponse, error) {
+	reservation, err := s.findReservation(ctx, actor, id)
+	if err != nil {
+		return nil, err
+	}
+	err = s.inventory.Confirm(ctx, reservation, time.Now())
+	if errors.Is(err, repositories.ErrReservationClosed) {
+		return nil, Conflict(apperrors.CodeReservationClosed)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to confirm reservation: %w", err)
+	}
+	response := models.NewStockReservationResponse(*reservation)
+	return &response, nil
+}
+
+// ReleaseReservation membatalkan reservasi pending milik actor dan mengembalikan stoknya
+func (s *InventoryService) ReleaseReservation(ctx context.Context, actor Actor, id uuid.UUID) (*models.StockReservationResponse, error) {
+	reservation, err := s.findReservation(ctx, actor, id)
+	if err != nil {
+		return nil, err
+	}
+	err = s.inventory.Release(ctx, reservation, models.ReservationReleased)
+	if errors.Is(err, repositories.ErrReservationClosed) {
+		return nil, Conflict(apperrors.CodeReservationClosed)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to release reservation: %w", err)
+	}
+	response := models.NewStockReservationResponse(*reservation)
+	return &response, nil
+}
+
+// ExpireReservations melepas stok semua reservasi pending yang kedaluwarsa
+// sebelum atau tepat pada now, dan mengembalikan jumlahnya
+func (s *InventoryService) ExpireReservations(ctx context.Context, now time.Time) (int, error) {
+	const batchSize = 100
+
+	expired := 0
+	for {
+		reservations, err := s.inventory.ListExpired(ctx, now, batchSize)
+		if err != nil {
+			return expired, fmt.Errorf("failed to retrieve expired reservations: %w", err)
+		}
+		for i := range reservations {
+			err := s.inventory.Release(ctx, &reservations[i], models.ReservationExpired)
+			// Reservasi yang baru saja dikonfirmasi atau dilepas request lain dilewati
+			if errors.Is(err, repositories.ErrReservationClosed) {
+				continue
+			}
+			if err != nil {
+				return expired, fmt.Errorf("failed to expire reservation: %w", err)
+			}
+			expired++
+		}
+		if len(reservations) < batchSize {
+			return expired, nil
+		}
+	}
+}
+
+// WatchReservations menjalankan ExpireReservations setiap interval sampai ctx dibatalkan
+func (s *InventoryService) WatchReservations(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		expired, err := s.ExpireReservations(ctx, time.Now())
+		if err != nil {
+			log.Println("❌ Gagal melepas reservasi stok yang kedaluwarsa:", err)
+		} else if expired > 0 {
+			log.Printf("⏱️ %d reservasi stok kedaluwarsa dilepas", expired)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *InventoryService) find(ctx context.Context, id uuid.UUID) (*models.Product, error) {
+	product, err := s.products.FindByID(ctx, id)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil, NotFound(apperrors.CodeProductNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve product: %w", err)
+	}
+	return product, nil
+}
+
+// findOwned mengambil produk dan memastikan actor adalah pemiliknya
+func (s *InventoryService) findOwned(ctx context.Context, actor Actor, id uuid.UUID) (*models.Product, error) {
+	product, err := s.find(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if product.UserId != actor.UserID {
+		return nil, Forbidden(apperrors.CodeNotProductOwner)
+	}
+	return product, nil
+}
+
+// findReservation mengambil reservasi dan memastikan actor yang membuatnya
+func (s *InventoryService) findReservation(ctx context.Context, actor Actor, id uuid.UUID) (*models.StockReservation, error) {
+	reservation, err := s.inventory.FindReservation(ctx, id)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil, NotFound(apperrors.CodeReservationNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve reservation: %w", err)
+	}
+	if reservation.UserId != actor.UserID {
+		return nil, Forbidden(apperrors.CodeNotReservationOwner)
+	}
+	return reservation, nil
+}
+
+// stockVariant menentukan item stok: varian untuk produk yang punya varian,
+// atau nil untuk stok produk itu sendiri
+func stockVariant(product *models.Product, variantID *uuid.UUID) (*models.ProductVariant, error) {
+	if len(product.Variants) == 0 {
+		if variantID != nil {
+			return nil, NotFound(apperrors.CodeVariantNotFound)
+		}
+		return nil, nil
+	}
+	if variantID == nil {
+		return nil, Validation(apperrors.FieldError{Field: "variant_id", Code: "required"})
+	}
+	index := variantIndex(product, *variantID)
+	if index < 0 {
+		return nil, NotFound(apperrors.CodeVariantNotFound)
+	}
+	return &product.Variants[index], nil
+}
+
+// deltaMatchesReason memastikan arah perubahan sesuai alasannya,
+// hanya correction yang boleh menambah maupun mengurangi stok
+func deltaMatchesReason(delta int64, reason string) bool {
+	switch reason {
+	case models.ReasonRestock, models.ReasonReturn:
+		return delta > 0
+	case models.ReasonSale, models.ReasonDamage:
+		return delta < 0
+	}
+	return delta != 0
+}
+
+// variantKey mengembalikan VariantId baris stok, uuid.Nil untuk stok produk tanpa varian
+func variantKey(variant *models.ProductVariant) uuid.UUID {
+	if variant == nil {
+		return uuid.Nil
+	}
+	return variant.Id
+}
+
+// stockKeys mengembalikan VariantId semua baris stok yang berlaku untuk produk
+func stockKeys(product *models.Product) []uuid.UUID {
+	if len(product.Variants) == 0 {
+		return []uuid.UUID{uuid.Nil}
+	}
+	keys := make([]uuid.UUID, 0, len(product.Variants))
+	for _, variant := range product.Variants {
+		keys = append(keys, variant.Id)
+	}
+	return keys
+}
@@ -0,0 +1,121 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"server-cookie/apperrors"
+	"server-cookie/models"
+	"server-cookie/money"
+	"server-cookie/repositories"
+	"server-cookie/search"
+	"server-cookie/services"
+
+	"github.com/google/uuid"
+)
+
+// newStockedProduct membuat produk milik owner dengan stok awal onHand
+func newStockedProduct(t *testing.T, products *services.ProductService, inventory *services.InventoryService, owner services.Actor, onHand int64) uuid.UUID {
+	t.Helper()
+	ctx := context.Background()
+	created, err := products.Create(ctx, owner, services.CreateProductInput{Name: "Cookie", Price: money.Input{Amount: "1000"}, OwnerID: owner.UserID, Images: upload("photo")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := uuid.MustParse(created.Id)
+	if _, err := inventory.Adjust(ctx, owner, id, services.AdjustStockInput{Delta: onHand, Reason: models.ReasonRestock}); err != nil {
+		t.Fatal(err)
+	}
+	return id
+}
+
+func newInventoryServices(t *testing.T) (*services.ProductService, *services.InventoryService, *repositories.MemoryUserRepository) {
+	t.Helper()
+	users := repositories.NewMemoryUserRepository()
+	categories := repositories.NewMemoryCategoryRepository()
+	revisions := repositories.NewMemoryRevisionRepository()
+	inventoryRepo := repositories.NewMemoryInventoryRepository()
+	productRepo := repositories.NewMemoryProductRepository(users, categories, inventoryRepo, revisions)
+	products := services.NewProductService(productRepo, categories, inventoryRepo, revisions, newFakeImageStore(), services.NewCursorSigner([]byte("test-cursor-secret")), search.NewMemoryIndex())
+	return products, services.NewInventoryService(inventoryRepo, productRepo, time.Minute), users
+}
+
+func TestInventoryServiceDoesNotOversell(t *testing.T) {
+	ctx := context.Background()
+	products, inventory, users := newInventoryServices(t)
+	alice := newUser(t, users, "alice")
+	bob := newUser(t, users, "bob")
+	id := newStockedProduct(t, products, inventory, alice, 10)
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	reserved, rejected := 0, 0
+	for range 25 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := inventory.Reserve(ctx, bob, id, nil, 1)
+			mu.Lock()
+			defer mu.Unlock()
+			var serviceErr *services.Error
+			switch {
+			case err == nil:
+				reserved++
+			case errors.As(err, &serviceErr) && serviceErr.Code == apperrors.CodeInsufficientStock:
+				rejected++
+			default:
+				t.Errorf("unexpected error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if reserved != 10 || rejected != 15 {
+		t.Fatalf("reserved %d, rejected %d, want 10 and 15", reserved, rejected)
+	}
+	levels, err := inventory.Levels(ctx, alice, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if levels[0].Reserved != 10 || levels[0].Available != 0 {
+		t.Fatalf("unexpected stock: %+v", levels[0])
+	}
+}
+
+func TestInventoryServiceExpiresReservations(t *testing.T) {
+	ctx := context.Background()
+	products, inventory, users := newInventoryServices(t)
+	alice := newUser(t, users, "alice")
+	id := newStockedProduct(t, products, inventory, alice, 5)
+
+	reservation, err := inventory.Reserve(ctx, alice, id, nil, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expired, err := inventory.ExpireReservations(ctx, time.Now())
+	if err != nil || expired != 0 {
+		t.Fatalf("ExpireReservations before TTL = %d, %v; want 0", expired, err)
+	}
+	expired, err = inventory.ExpireReservations(ctx, time.Now().Add(2*time.Minute))
+	if err != nil || expired != 1 {
+		t.Fatalf("ExpireReservations after TTL = %d, %v; want 1", expired, err)
+	}
+
+	levels, err := inventory.Levels(ctx, alice, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if levels[0].Reserved != 0 || levels[0].Available != 5 {
+		t.Fatalf("stock not returned: %+v", levels[0])
+	}
+
+	_, err = inventory.ConfirmReservation(ctx, alice, uuid.MustParse(reservation.Id))
+	var serviceErr *services.Error
+	if !errors.As(err, &serviceErr) || serviceErr.Code != apperrors.CodeReservationClosed {
+		t.Fatalf("ConfirmReservation after expiry = %v, want reservation_closed", err)
+	}
+}
@@ -0,0 +1,308 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"server-cookie/apperrors"
+	"server-cookie/models"
+	"server-cookie/money"
+	"server-cookie/repositories"
+	"server-cookie/validation"
+	"strconv"
+
+	"github.com/google/uuid"
+)
+
+// MaxBatchOperations adalah jumlah operasi terbanyak dalam satu batch
+const MaxBatchOperations = 100
+
+// Jenis operasi batch
+const (
+	BatchUpdate     = "update"
+	BatchDelete     = "delete"
+	BatchCategorize = "categorize"
+)
+
+// Status hasil satu operasi batch
+const (
+	BatchApplied = "applied"
+	BatchFailed  = "failed"
+	// BatchSkipped berarti operasi valid tetapi tidak disimpan karena operasi lain
+	// di batch atomic gagal
+	BatchSkipped = "skipped"
+)
+
+// BatchOperation adalah satu operasi pada produk milik actor. Field yang tidak
+// dikirim tidak diubah, aturan validasinya sama dengan update produk.
+type BatchOperation struct {
+	Op string `json:"op" validate:"required,oneof=update delete categorize"`
+	ID string `json:"id" validate:"required,uuid"`
+	// ExpectedVersion sama dengan header If-Match, versi tidak dicek jika kosong
+	ExpectedVersion *int64 `json:"expected_version,omitempty"`
+
+	// Field untuk op update. remove_image_ids menghapus gambar dari produk,
+	// filenya baru dihapus setelah perubahan tersimpan.
+	Name           *string      `json:"name,omitempty" validate:"omitnil,min=2,max=100"`
+	Price          *money.Input `json:"price,omitempty"`
+	Tags           *[]string    `json:"tags,omitempty" validate:"omitnil,max=20,dive,max=50"`
+	CategoryIDs    *[]string    `json:"category_ids,omitempty" validate:"omitnil,max=10,dive,uuid"`
+	RemoveImageIDs []string     `json:"remove_image_ids,omitempty" validate:"max_items=10,dive,uuid"`
+
+	// Field untuk op categorize, menambah atau melepas kategori tanpa mengganti yang lain
+	AddCategoryIDs    []string `json:"add_category_ids,omitempty" validate:"max=10,dive,uuid"`
+	RemoveCategoryIDs []string `json:"remove_category_ids,omitempty" validate:"max=10,dive,uuid"`
+}
+
+// BatchItem adalah hasil satu operasi batch sesuai urutan di request
+type BatchItem struct {
+	Status string
+	// Product berisi produk setelah op update atau categorize yang tersimpan
+	Product *models.ProductResponse
+	Err     error
+}
+
+// BatchResult adalah hasil semua operasi batch
+type BatchResult struct {
+	Atomic bool
+	Items  []BatchItem
+}
+
+// Applied menghitung operasi yang tersimpan
+func (r BatchResult) Applied() int {
+	applied := 0
+	for _, item := range r.Items {
+		if item.Status == BatchApplied {
+			applied++
+		}
+	}
+	return applied
+}
+
+// batchChange adalah operasi yang sudah divalidasi dan siap disimpan
+type batchChange struct {
+	repositories.ProductChange
+	ifMatch []int64
+	// removed adalah gambar yang dilepas, filenya dihapus setelah produk tersimpan
+	removed []models.ProductImage
+}
+
+// Batch menjalankan beberapa operasi produk sekaligus. Dengan atomic, semua
+// operasi disimpan dalam satu transaksi atau tidak sama sekali. Tanpa atomic,
+// setiap operasi disimpan sendiri dan yang gagal tidak menghentikan lainnya.
+// Error per operasi ada di BatchItem, error yang dikembalikan hanya untuk
+// kegagalan penyimpanan di luar operasi tertentu.
+func (s *ProductService) Batch(ctx context.Context, actor Actor, operations []BatchOperation, atomic bool) (*BatchResult, error) {
+	if len(operations) > MaxBatchOperations {
+		return nil, Validation(apperrors.FieldError{Field: "operations", Code: "max_items", Param: strconv.Itoa(MaxBatchOperations)})
+	}
+	result := &BatchResult{Atomic: atomic, Items: make([]BatchItem, len(operations))}
+	changes := make([]*batchChange, len(operations))
+	seen := make(map[string]bool, len(operations))
+	failed := false
+	for i, operation := range operations {
+		// Satu produk hanya boleh muncul sekali agar versinya tidak bentrok di batch yang sama
+		if seen[operation.ID] && operation.ID != "" {
+			result.Items[i] = BatchItem{Status: BatchFailed, Err: Validation(apperrors.FieldError{Field: "id", Code: "duplicate", Param: operation.ID})}
+			failed = true
+			continue
+		}
+		seen[operation.ID] = true
+
+		change, err := s.prepareBatch(ctx, actor, operation)
+		if err != nil {
+			result.Items[i] = BatchItem{Status: BatchFailed, Err: err}
+			failed = true
+			continue
+		}
+		changes[i] = change
+	}
+
+	if !atomic {
+		for i, change := range changes {
+			if change != nil {
+				result.Items[i] = s.applyBatch(ctx, actor, change)
+			}
+		}
+		return result, nil
+	}
+
+	if failed {
+		skipValid(result, changes)
+		return result, nil
+	}
+	productChanges := make([]repositories.ProductChange, len(changes))
+	for i, change := range changes {
+		productChanges[i] = change.ProductChange
+	}
+	if err := s.products.SaveBatch(ctx, productChanges); err != nil {
+		var batchErr *repositories.BatchError
+		if !errors.As(err, &batchErr) {
+			return nil, fmt.Errorf("failed to save batch: %w", err)
+		}
+		// Hanya konflik versi yang menjadi error operasi, error lain gagal seluruhnya
+		itemErr := saveFailed(batchErr.Err, changes[batchErr.Index].ifMatch, "failed to save batch")
+		if apperrors.From(itemErr).Code == apperrors.CodeInternal {
+			return nil, itemErr
+		}
+		result.Items[batchErr.Index] = BatchItem{Status: BatchFailed, Err: itemErr}
+		changes[batchErr.Index] = nil
+		skipValid(result, changes)
+		return result, nil
+	}
+
+	// Index dan file gambar diproses setelah transaksi selesai
+	for i, change := range changes {
+		result.Items[i] = s.afterSave(ctx, change)
+	}
+	return result, nil
+}
+
+// prepareBatch membaca produk milik actor lalu menerapkan operasi tanpa menyimpannya
+func (s *ProductService) prepareBatch(ctx context.Context, actor Actor, operation BatchOperation) (*batchChange, error) {
+	if err := validation.Struct(operation); err != nil {
+		return nil, err
+	}
+	product, err := s.findOwned(ctx, actor, uuid.MustParse(operation.ID))
+	if err != nil {
+		return nil, err
+	}
+	change := &batchChange{ProductChange: repositories.ProductChange{Product: product}}
+	if operation.ExpectedVersion != nil {
+		change.ifMatch = []int64{*operation.ExpectedVersion}
+	}
+	if err := checkVersion(product, change.ifMatch); err != nil {
+		return nil, err
+	}
+
+	switch operation.Op {
+	case BatchDelete:
+		change.Delete = true
+	case BatchUpdate:
+		if err := s.updateBatch(ctx, change, operation); err != nil {
+			return nil, err
+		}
+	case BatchCategorize:
+		if err := s.categorizeBatch(ctx, product, operation); err != nil {
+			return nil, err
+		}
+	}
+	if change.Delete {
+		change.Revision = newRevision(actor, models.RevisionDelete, nil)
+	} else {
+		change.Revision = newRevision(actor, models.RevisionUpdate, nil)
+	}
+	return change, nil
+}
+
+func (s *ProductService) updateBatch(ctx context.Context, change *batchChange, operation BatchOperation) error {
+	product := change.Product
+	if operation.Name != nil {
+		product.Name = *operation.Name
+	}
+	if operation.Price != nil {
+		price, fields := resolvePrice(*operation.Price, product.Price.Currency)
+		if len(fields) > 0 {
+			return Validation(fields...)
+		}
+		product.Price = price
+	}
+	if operation.CategoryIDs != nil {
+		categories, err := s.resolveCategories(ctx, parseIDs(*operation.CategoryIDs))
+		if err != nil {
+			return err
+		}
+		product.Categories = categories
+	}
+	if operation.Tags != nil {
+		product.Tags = toTags(*operation.Tags)
+	}
+
+	if len(operation.RemoveImageIDs) > 0 {
+		remove := make(map[uuid.UUID]bool, len(operation.RemoveImageIDs))
+		for _, id := range parseIDs(operation.RemoveImageIDs) {
+			if !hasImage(product, id) {
+				return NotFound(apperrors.CodeImageNotFound)
+			}
+			remove[id] = true
+		}
+		images := make([]models.ProductImage, 0, len(product.Images))
+		for _, image := range product.Images {
+			if remove[image.Id] {
+				change.removed = append(change.removed, image)
+				continue
+			}
+			images = append(images, image)
+		}
+		product.Images = arrangeImages(images)
+		detachVariantImages(product)
+	}
+	product.RefreshPriceRange()
+	return nil
+}
+
+func (s *ProductService) categorizeBatch(ctx context.Context, product *models.Product, operation BatchOperation) error {
+	remove := make(map[uuid.UUID]bool, len(operation.RemoveCategoryIDs))
+	for _, id := range parseIDs(operation.RemoveCategoryIDs) {
+		remove[id] = true
+	}
+	ids := make([]uuid.UUID, 0, len(product.Categories)+len(operation.AddCategoryIDs))
+	for _, category := range product.Categories {
+		if !remove[category.Id] {
+			ids = append(ids, category.Id)
+		}
+	}
+	for _, id := range parseIDs(operation.AddCategoryIDs) {
+		if !remove[id] {
+			ids = append(ids, id)
+		}
+	}
+
+	categories, err := s.resolveCategories(ctx, ids)
+	if err != nil {
+		return err
+	}
+	if len(categories) > models.MaxProductCategories {
+		return Validation(apperrors.FieldError{Field: "add_category_ids", Code: "max_items", Param: strconv.Itoa(models.MaxProductCategories)})
+	}
+	product.Categories = categories
+	return nil
+}
+
+// applyBatch menyimpan satu operasi pada batch yang tidak atomic
+func (s *ProductService) applyBatch(ctx context.Context, actor Actor, change *batchChange) BatchItem {
+	var err error
+	if change.Delete {
+		err = s.products.Delete(ctx, change.Product, change.Revision)
+	} else {
+		err = s.products.Update(ctx, change.Product, change.Revision)
+	}
+	if err != nil {
+		return BatchItem{Status: BatchFailed, Err: saveFailed(err, change.ifMatch, "failed to save product")}
+	}
+	return s.afterSave(ctx, change)
+}
+
+// afterSave memperbarui index pencarian lalu melepas gambar yang dihapus dari
+// produk yang sudah tersimpan bersama revisinya
+func (s *ProductService) afterSave(ctx context.Context, change *batchChange) BatchItem {
+	product := change.Product
+	if change.Delete {
+		s.unindexProduct(ctx, product.Id)
+		return BatchItem{Status: BatchApplied}
+	}
+
+	s.releaseImages(ctx, product.Id, change.removed)
+	s.indexProduct(ctx, product)
+	response := models.NewProductResponse(*product)
+	return BatchItem{Status: BatchApplied, Product: &response}
+}
+
+// skipValid menandai operasi valid yang tidak disimpan karena batch atomic gagal
+func skipValid(result *BatchResult, changes []*batchChange) {
+	for i, change := range changes {
+		if change != nil {
+			result.Items[i] = BatchItem{Status: BatchSkipped}
+		}
+	}
+}
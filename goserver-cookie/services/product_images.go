@@ -0,0 +1,209 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"server-cookie/apperrors"
+	"server-cookie/models"
+	"slices"
+	"strconv"
+
+	"github.com/google/uuid"
+)
+
+// ImageOrderItem adalah satu gambar pada urutan baru, field nil tidak diubah
+type ImageOrderItem struct {
+	ID      uuid.UUID
+	Alt     *string
+	Primary *bool
+}
+
+// AddImages menambahkan gambar di akhir urutan gambar produk milik actor.
+// Jika produk belum punya gambar, gambar pertama menjadi gambar utama.
+func (s *ProductService) AddImages(ctx context.Context, actor Actor, id uuid.UUID, uploads []ImageUpload) (*models.ProductResponse, error) {
+	product, err := s.findOwned(ctx, actor, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(product.Images)+len(uploads) > models.MaxProductImages {
+		return nil, Validation(apperrors.FieldError{Field: "image", Code: "max_items", Param: strconv.Itoa(models.MaxProductImages)})
+	}
+
+	added, err := s.uploadImages(uploads)
+	if err != nil {
+		return nil, err
+	}
+	product.Images = arrangeImages(append(product.Images, added...))
+	if err := s.products.Update(ctx, product, newRevision(actor, models.RevisionUpdate, nil)); err != nil {
+		s.cleanupImages(added)
+		return nil, saveFailed(err, nil, "failed to update product images")
+	}
+
+	response := models.NewProductResponse(*product)
+	return &response, nil
+}
+
+// RemoveImage menghapus satu gambar produk milik actor. Filenya ikut dihapus jika
+// tidak tercatat di revisi produk. Jika gambar utama dihapus, gambar pertama yang
+// tersisa menjadi gambar utama.
+func (s *ProductService) RemoveImage(ctx context.Context, actor Actor, id, imageID uuid.UUID) (*models.ProductResponse, error) {
+	product, err := s.findOwned(ctx, actor, id)
+	if err != nil {
+		return nil, err
+	}
+
+	images := make([]models.ProductImage, 0, len(product.Images))
+	var removed []models.ProductImage
+	for _, image := range product.Images {
+		if image.Id == imageID {
+			removed = append(removed, image)
+			continue
+		}
+		images = append(images, image)
+	}
+	if removed == nil {
+		return nil, NotFound(apperrors.CodeImageNotFound)
+	}
+
+	product.Images = arrangeImages(images)
+	detachVariantImages(product)
+	if err := s.products.Update(ctx, product, newRevision(actor, models.RevisionUpdate, nil)); err != nil {
+		return nil, saveFailed(err, nil, "failed to update product images")
+	}
+	s.releaseImages(ctx, product.Id, removed)
+
+	response := models.NewProductResponse(*product)
+	return &response, nil
+}
+
+// ReorderImages menyusun ulang gambar produk milik actor. order harus berisi
+// setiap gambar produk tepat satu kali, sekaligus bisa mengubah alt dan gambar utama.
+func (s *ProductService) ReorderImages(ctx context.Context, actor Actor, id uuid.UUID, order []ImageOrderItem) (*models.ProductResponse, error) {
+	product, err := s.findOwned(ctx, actor, id)
+	if err != nil {
+		return nil, err
+	}
+
+	current := make(map[uuid.UUID]models.ProductImage, len(product.Images))
+	for _, image := range product.Images {
+		current[image.Id] = image
+	}
+	if len(order) != len(current) {
+		return nil, Validation(apperrors.FieldError{Field: "images", Code: "image_order"})
+	}
+
+	images := make([]models.ProductImage, 0, len(order))
+	primaries := 0
+	for _, item := range order {
+		image, ok := current[item.ID]
+		if !ok {
+			return nil, Validation(apperrors.FieldError{Field: "images", Code: "image_order"})
+		}
+		// Hapus dari map agar ID ganda ikut ditolak
+		delete(current, item.ID)
+
+		if item.Alt != nil {
+			image.Alt = *item.Alt
+		}
+		if item.Primary != nil && *item.Primary {
+			primaries++
+		}
+		images = append(images, image)
+	}
+	if primaries > 1 {
+		return nil, Validation(apperrors.FieldError{Field: "images", Code: "single_primary"})
+	}
+	// Gambar utama hanya berubah jika ada gambar yang ditandai primary
+	if primaries == 1 {
+		for i, item := range order {
+			images[i].Primary = item.Primary != nil && *item.Primary
+		}
+	}
+
+	product.Images = arrangeImages(images)
+	if err := s.products.Update(ctx, product, newRevision(actor, models.RevisionUpdate, nil)); err != nil {
+		return nil, saveFailed(err, nil, "failed to update product images")
+	}
+
+	response := models.NewProductResponse(*product)
+	return &response, nil
+}
+
+// uploadImages menyimpan semua file gambar. Jika salah satu gagal,
+// file yang sudah tersimpan dihapus kembali.
+func (s *ProductService) uploadImages(uploads []ImageUpload) ([]models.ProductImage, error) {
+	images := make([]models.ProductImage, 0, len(uploads))
+	for _, upload := range uploads {
+		imagePath, err := s.images.UploadImage(upload.Filename, upload.Content)
+		if err != nil {
+			s.cleanupImages(images)
+			return nil, apperrors.Wrap(apperrors.CodeImageUploadFailed, err)
+		}
+		images = append(images, models.ProductImage{Path: imagePath, Alt: upload.Alt})
+	}
+	return images, nil
+}
+
+// arrangeImages mengisi Position sesuai urutan slice dan memastikan
+// tepat satu gambar utama, gambar pertama jika belum ada
+func arrangeImages(images []models.ProductImage) []models.ProductImage {
+	primary := -1
+	for i := range images {
+		images[i].Position = i
+		if images[i].Primary {
+			if primary >= 0 {
+				images[i].Primary = false
+			} else {
+				primary = i
+			}
+		}
+	}
+	if primary < 0 && len(images) > 0 {
+		images[0].Primary = true
+	}
+	return images
+}
+
+// releaseImages menghapus file gambar yang sudah dilepas dari produk. File yang
+// masih tercatat di revisi produk diarsipkan agar bisa dipulihkan dengan rollback.
+func (s *ProductService) releaseImages(ctx context.Context, productID uuid.UUID, images []models.ProductImage) {
+	paths, err := s.revisions.ImagePaths(ctx, productID)
+	if err != nil {
+		log.Println("❌ Gagal membaca revisi produk, gambar tidak dihapus:", err)
+		return
+	}
+	unused := make([]models.ProductImage, 0, len(images))
+	for _, image := range images {
+		if !slices.Contains(paths, image.Path) {
+			unused = append(unused, image)
+			continue
+		}
+		if err := s.images.ArchiveImage(image.Path); err != nil {
+			log.Println("❌ Gagal mengarsipkan gambar", image.Path+":", err)
+		}
+	}
+	s.cleanupImages(unused)
+}
+
+// restoreImages mengembalikan file gambar dari arsip sebelum dipasang lagi ke produk
+func (s *ProductService) restoreImages(images []models.ProductImage) error {
+	for _, image := range images {
+		if err := s.images.RestoreImage(image.Path); err != nil {
+			return fmt.Errorf("failed to restore image %s: %w", image.Path, err)
+		}
+	}
+	return nil
+}
+
+// cleanupImages menghapus file gambar, kegagalan hanya dicatat di log
+func (s *ProductService) cleanupImages(images []models.ProductImage) {
+	for _, image := range images {
+		if image.Path == "" {
+			continue
+		}
+		if err := s.images.DeleteImage(image.Path); err != nil {
+			log.Println("❌ Gagal menghapus gambar", image.Path+":", err)
+		}
+	}
+}
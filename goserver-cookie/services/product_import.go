@@ -0,0 +1,269 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"server-cookie/apperrors"
+	"server-cookie/models"
+	"server-cookie/money"
+	"server-cookie/repositories"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// exportBatchSize adalah jumlah produk yang dibaca sekali jalan saat export
+const exportBatchSize = 100
+
+// ImportProductInput adalah satu baris import produk. Produk dicocokkan dengan
+// ExternalID lalu SKU. Saat produk sudah ada, field kosong atau nil tidak diubah.
+type ImportProductInput struct {
+	ExternalID string
+	SKU        string
+	Name       string
+	Price      string
+	Currency   string
+	// CategoryIDs, Tags dan Images yang tidak nil mengganti seluruh isinya
+	CategoryIDs []uuid.UUID
+	Tags        []string
+	Images      []ImportImage
+}
+
+// ImportImage adalah satu gambar di baris import, berisi Path atau Upload
+type ImportImage struct {
+	// Path adalah gambar produk yang sudah ada dan dipertahankan, misalnya dari file export
+	Path string
+	// Upload adalah gambar baru dari URL atau arsip ZIP, isinya nil pada dry-run
+	Upload *ImageUpload
+}
+
+// ImportProduct membuat atau mengubah satu produk milik actor dari baris import.
+// Produk baru dengan SKU mendapat satu varian dengan SKU itu. Produk di trash
+// dengan external_id yang sama dipulihkan lalu diubah. Pada dry-run semua
+// aturan tetap dicek tetapi tidak ada yang disimpan. created bernilai true jika
+// produk dibuat (atau akan dibuat).
+func (s *ProductService) ImportProduct(ctx context.Context, actor Actor, input ImportProductInput, dryRun bool) (created bool, err error) {
+	input.SKU = strings.ToUpper(strings.TrimSpace(input.SKU))
+	product, bySKU, err := s.matchImport(ctx, actor, input)
+	if err != nil {
+		return false, err
+	}
+	created = product == nil
+
+	if created {
+		var fields []apperrors.FieldError
+		if input.Name == "" {
+			fields = append(fields, apperrors.FieldError{Field: "name", Code: "required"})
+		}
+		if input.Price == "" {
+			fields = append(fields, apperrors.FieldError{Field: "price", Code: "required"})
+		}
+		if len(fields) > 0 {
+			return false, Validation(fields...)
+		}
+		product = &models.Product{UserId: actor.UserID}
+	}
+
+	if input.ExternalID != "" {
+		product.ExternalId = &input.ExternalID
+	}
+	if input.Name != "" {
+		product.Name = input.Name
+	}
+	// Mata uang hanya dibaca bersama harga, seperti pada UpdateProduct
+	if input.Price != "" {
+		price, fields := resolvePrice(money.Input{Amount: input.Price, Currency: input.Currency}, product.Price.Currency)
+		if len(fields) > 0 {
+			return false, Validation(fields...)
+		}
+		product.Price = price
+	}
+	if input.CategoryIDs != nil {
+		categories, err := s.resolveCategories(ctx, input.CategoryIDs)
+		if err != nil {
+			return false, err
+		}
+		product.Categories = categories
+	}
+	if input.Tags != nil {
+		product.Tags = toTags(input.Tags)
+	}
+
+	// SKU yang belum dipakai menjadi satu-satunya varian produk
+	if input.SKU != "" && !bySKU {
+		if len(product.Variants) > 0 || len(product.Options) > 0 {
+			return false, Validation(apperrors.FieldError{Field: "sku", Code: "exists"})
+		}
+		product.Variants = []models.ProductVariant{{SKU: input.SKU, Attributes: map[string]string{}}}
+	}
+
+	oldImages := product.Images
+	var kept, uploads []models.ProductImage
+	if input.Images != nil {
+		images := make([]models.ProductImage, 0, len(input.Images))
+		for _, image := range input.Images {
+			if image.Upload != nil {
+				images = append(images, models.ProductImage{Alt: image.Upload.Alt})
+				continue
+			}
+			existing, ok := imageByPath(oldImages, image.Path)
+			if !ok {
+				return false, Validation(apperrors.FieldError{Field: "images", Code: "exists"})
+			}
+			if hasImagePath(kept, image.Path) {
+				return false, Validation(apperrors.FieldError{Field: "images", Code: "duplicate", Param: image.Path})
+			}
+			kept = append(kept, existing)
+			images = append(images, existing)
+		}
+		if dryRun {
+			return created, nil
+		}
+
+		// Gambar baru diunggah lalu disisipkan sesuai urutan di baris
+		newUploads := make([]ImageUpload, 0, len(input.Images))
+		for _, image := range input.Images {
+			if image.Upload != nil {
+				newUploads = append(newUploads, *image.Upload)
+			}
+		}
+		uploads, err = s.uploadImages(newUploads)
+		if err != nil {
+			return false, err
+		}
+		next := 0
+		for i, image := range input.Images {
+			if image.Upload != nil {
+				images[i] = uploads[next]
+				next++
+			}
+		}
+		product.Images = arrangeImages(images)
+		detachVariantImages(product)
+	}
+	if dryRun {
+		return created, nil
+	}
+	product.RefreshPriceRange()
+
+	if created {
+		if err := s.products.Create(ctx, product, newRevision(actor, models.RevisionCreate, nil)); err != nil {
+			s.cleanupImages(uploads)
+			return false, saveFailed(err, nil, "failed to create product")
+		}
+	} else {
+		if product.DeletedAt.Valid {
+			if err := s.products.Restore(ctx, product, newRevision(actor, models.RevisionRestore, nil)); err != nil {
+				s.cleanupImages(uploads)
+				return false, fmt.Errorf("failed to restore product: %w", err)
+			}
+		}
+		if err := s.products.Update(ctx, product, newRevision(actor, models.RevisionUpdate, nil)); err != nil {
+			s.cleanupImages(uploads)
+			return false, saveFailed(err, nil, "failed to update product")
+		}
+		if input.Images != nil {
+			s.releaseImages(ctx, product.Id, removedImages(oldImages, kept))
+		}
+	}
+	s.indexProduct(ctx, product)
+	return created, nil
+}
+
+// matchImport mencari produk milik actor untuk baris import, nil jika produk
+// harus dibuat. bySKU bernilai true jika SKU baris adalah varian produk itu.
+func (s *ProductService) matchImport(ctx context.Context, actor Actor, input ImportProductInput) (product *models.Product, bySKU bool, err error) {
+	if input.ExternalID != "" {
+		product, err = s.products.FindByExternalID(ctx, actor.UserID, input.ExternalID)
+		if err != nil && !errors.Is(err, repositories.ErrNotFound) {
+			return nil, false, fmt.Errorf("failed to retrieve product: %w", err)
+		}
+	}
+	if input.SKU == "" {
+		return product, false, nil
+	}
+
+	variant, err := s.products.FindVariantBySKU(ctx, input.SKU)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return product, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to check variant SKU: %w", err)
+	}
+	if product != nil {
+		if variant.ProductId != product.Id {
+			return nil, false, Conflict(apperrors.CodeImportKeyMismatch)
+		}
+		return product, true, nil
+	}
+
+	// SKU milik produk lain atau produk di trash tidak bisa dipakai
+	product, err = s.products.FindByID(ctx, variant.ProductId)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil, false, Conflict(apperrors.CodeSKUTaken)
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to retrieve product: %w", err)
+	}
+	if product.UserId != actor.UserID {
+		return nil, false, Conflict(apperrors.CodeSKUTaken)
+	}
+	// external_id baru tidak boleh sudah dipakai produk lain milik actor
+	if input.ExternalID != "" && product.ExternalId != nil && *product.ExternalId != input.ExternalID {
+		return nil, false, Conflict(apperrors.CodeImportKeyMismatch)
+	}
+	return product, true, nil
+}
+
+// Export memanggil each untuk setiap produk aktif, yang terlama lebih dulu.
+// ownerID nil berarti produk published semua user, produk milik ownerID
+// diambil dengan semua statusnya. Produk dibaca per batch dengan keyset
+// agar export besar tidak dimuat sekaligus.
+func (s *ProductService) Export(ctx context.Context, ownerID *uuid.UUID, each func(models.ProductResponse) error) error {
+	params := repositories.ProductListParams{
+		Page:      1,
+		Limit:     exportBatchSize,
+		OwnerID:   ownerID,
+		Sort:      []repositories.ProductSort{{Field: repositories.SortByCreatedAt}},
+		SkipCount: true,
+	}
+	if ownerID == nil {
+		params.Statuses = []string{models.ProductPublished}
+	}
+	for {
+		products, _, err := s.products.List(ctx, params)
+		if err != nil {
+			return fmt.Errorf("failed to retrieve products: %w", err)
+		}
+		for _, product := range products {
+			if err := each(models.NewProductResponse(product)); err != nil {
+				return err
+			}
+		}
+		if len(products) < exportBatchSize {
+			return nil
+		}
+		params.Cursor = &repositories.ProductCursor{Key: repositories.KeyOf(products[len(products)-1], nil)}
+	}
+}
+
+func imageByPath(images []models.ProductImage, path string) (models.ProductImage, bool) {
+	for _, image := range images {
+		if image.Path == path {
+			return image, true
+		}
+	}
+	return models.ProductImage{}, false
+}
+
+// removedImages mengembalikan gambar lama yang tidak dipertahankan
+func removedImages(old, kept []models.ProductImage) []models.ProductImage {
+	removed := make([]models.ProductImage, 0, len(old))
+	for _, image := range old {
+		if !hasImagePath(kept, image.Path) {
+			removed = append(removed, image)
+		}
+	}
+	return removed
+}
@@ -0,0 +1,245 @@
+package services
+
+import (
+	"cmp"
+	"errors"
+	"net/url"
+	"server-cookie/apperrors"
+	"server-cookie/models"
+	"server-cookie/money"
+	"server-cookie/repositories"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// productQuery membaca parameter list produk satu per satu
+// dan mengumpulkan semua error validasinya
+type productQuery struct {
+	values url.Values
+	fields []apperrors.FieldError
+}
+
+// ParseProductQuery membangun ListProductsInput dari query string, misalnya
+// ?min_price=1000.50&owner=<uuid>&created_after=2024-01-01&has_image=true&sort=-price,name.
+// Halaman berikutnya diminta dengan query yang sama ditambah cursor=<next_cursor>.
+// Nilai yang tidak valid dan field sort yang tidak dikenal dilaporkan sekaligus
+// sebagai error validasi. Page dan limit yang tidak valid diganti default oleh List.
+func ParseProductQuery(values url.Values) (ListProductsInput, error) {
+	q := &productQuery{values: values}
+	input := ListProductsInput{
+		Page:          q.intOr("page", 1),
+		Limit:         q.intOr("limit", 10),
+		Search:        values.Get("search"),
+		Tags:          q.list("tags"),
+		OwnerID:       q.uuid("owner"),
+		CreatedAfter:  q.time("created_after"),
+		CreatedBefore: q.time("created_before"),
+		HasImage:      q.bool("has_image"),
+		Sort:          q.sort("sort"),
+		Cursor:        values.Get("cursor"),
+		IncludeTotal:  q.bool("include_total"),
+		Status:        values.Get("status"),
+	}
+
+	switch mode := values.Get("tag_mode"); mode {
+	case "", "any":
+	case "all":
+		input.AllTags = true
+	default:
+		q.fail("tag_mode", "oneof", "any all")
+	}
+
+	switch input.Status {
+	case "", models.ProductDraft, models.ProductPublished, models.ProductArchived:
+	default:
+		q.fail("status", "oneof", strings.Join(productStatuses, " "))
+	}
+
+	// Filter harga dibaca sesuai jumlah digit desimal mata uangnya
+	input.Currency = q.currency("price_currency")
+	currency := cmp.Or(input.Currency, models.DefaultCurrency)
+	input.MinPrice = q.price("min_price", currency)
+	input.MaxPrice = q.price("max_price", currency)
+
+	if lowStock := q.bool("low_stock"); lowStock != nil {
+		input.LowStock = *lowStock
+	}
+
+	// Kategori yang tidak valid punya kode error sendiri
+	if category := values.Get("category"); category != "" {
+		categoryID, err := uuid.Parse(category)
+		if err != nil {
+			return input, apperrors.Wrap(apperrors.CodeInvalidCategoryID, err)
+		}
+		input.CategoryID = &categoryID
+	}
+
+	if len(q.fields) > 0 {
+		return input, Validation(q.fields...)
+	}
+	return input, nil
+}
+
+// ParseProductSort membaca daftar field dipisah koma, awalan "-" berarti menurun.
+// Hanya field di repositories.ProductSortFields yang diizinkan, masing-masing sekali.
+func ParseProductSort(value string) ([]repositories.ProductSort, []apperrors.FieldError) {
+	var sort []repositories.ProductSort
+	var fields []apperrors.FieldError
+	seen := map[repositories.ProductSortField]bool{}
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		key := repositories.ProductSort{Field: repositories.ProductSortField(strings.TrimPrefix(part, "-"))}
+		key.Desc = strings.HasPrefix(part, "-")
+		switch {
+		case !key.Field.Valid():
+			fields = append(fields, apperrors.FieldError{Field: "sort", Code: "sort_field", Param: string(key.Field)})
+		case seen[key.Field]:
+			fields = append(fields, apperrors.FieldError{Field: "sort", Code: "duplicate", Param: string(key.Field)})
+		default:
+			seen[key.Field] = true
+			sort = append(sort, key)
+		}
+	}
+	return sort, fields
+}
+
+func (q *productQuery) fail(field, code, param string) {
+	q.fields = append(q.fields, apperrors.FieldError{Field: field, Code: code, Param: param})
+}
+
+func (q *productQuery) intOr(name string, fallback int) int {
+	value, err := strconv.Atoi(q.values.Get(name))
+	if err != nil {
+		return fallback
+	}
+	return value
+}
+
+// list menerima nilai dipisah koma maupun parameter yang diulang
+func (q *productQuery) list(name string) []string {
+	var items []string
+	for _, value := range q.values[name] {
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+	}
+	return items
+}
+
+// price membaca harga desimal dalam currency sebagai minor unit
+func (q *productQuery) price(name, currency string) *int64 {
+	value := q.values.Get(name)
+	if value == "" {
+		return nil
+	}
+	price, err := money.Parse(value, currency)
+	switch {
+	case errors.Is(err, money.ErrTooPrecise):
+		c, _ := money.LookupCurrency(currency)
+		q.fail(name, "price_precision", strconv.Itoa(c.Exponent))
+		return nil
+	case err != nil, price.Amount < 0:
+		q.fail(name, "price", "")
+		return nil
+	}
+	return &price.Amount
+}
+
+func (q *productQuery) currency(name string) string {
+	value := q.values.Get(name)
+	if value == "" {
+		return ""
+	}
+	c, err := money.LookupCurrency(value)
+	if err != nil {
+		q.fail(name, "currency", "")
+		return ""
+	}
+	return c.Code
+}
+
+func (q *productQuery) uuid(name string) *uuid.UUID {
+	value := q.values.Get(name)
+	if value == "" {
+		return nil
+	}
+	id, err := uuid.Parse(value)
+	if err != nil {
+		q.fail(name, "uuid", "")
+		return nil
+	}
+	return &id
+}
+
+// time menerima RFC 3339 atau tanggal saja (YYYY-MM-DD, tengah malam UTC)
+func (q *productQuery) time(name string) *time.Time {
+	value := q.values.Get(name)
+	if value == "" {
+		return nil
+	}
+	if t, ok := parseTimestamp(value); ok {
+		return &t
+	}
+	q.fail(name, "datetime", "")
+	return nil
+}
+
+// parseTimestamp membaca waktu RFC 3339 atau tanggal saja (YYYY-MM-DD, tengah malam UTC)
+func parseTimestamp(value string) (time.Time, bool) {
+	for _, layout := range []string{time.RFC3339Nano, time.DateOnly} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+func (q *productQuery) bool(name string) *bool {
+	value := q.values.Get(name)
+	if value == "" {
+		return nil
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		q.fail(name, "boolean", "")
+		return nil
+	}
+	return &b
+}
+
+func (q *productQuery) sort(name string) []repositories.ProductSort {
+	sort, fields := ParseProductSort(q.values.Get(name))
+	q.fields = append(q.fields, fields...)
+	return sort
+}
+
+// validate memeriksa rentang filter yang saling bergantung
+func (input ListProductsInput) validate() error {
+	var fields []apperrors.FieldError
+	if input.MinPrice != nil && input.MaxPrice != nil && *input.MaxPrice < *input.MinPrice {
+		fields = append(fields, apperrors.FieldError{Field: "max_price", Code: "gtefield", Param: "min_price"})
+	}
+	if input.CreatedAfter != nil && input.CreatedBefore != nil && !input.CreatedBefore.After(*input.CreatedAfter) {
+		fields = append(fields, apperrors.FieldError{Field: "created_before", Code: "gtfield", Param: "created_after"})
+	}
+	for _, key := range input.Sort {
+		switch {
+		case !key.Field.Valid():
+			fields = append(fields, apperrors.FieldError{Field: "sort", Code: "sort_field", Param: string(key.Field)})
+		case key.Field == repositories.SortByRelevance && strings.TrimSpace(input.Search) == "":
+			fields = append(fields, apperrors.FieldError{Field: "sort", Code: "required_with", Param: "search"})
+		}
+	}
+	if len(fields) > 0 {
+		return Validation(fields...)
+	}
+	return nil
+}
@@ -0,0 +1,326 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"server-cookie/apperrors"
+	"server-cookie/models"
+	"server-cookie/repositories"
+
+	"github.com/google/uuid"
+)
+
+// RevisionList adalah satu halaman riwayat revisi produk
+type RevisionList struct {
+	Revisions  []models.ProductRevisionResponse
+	Page       int
+	Limit      int
+	TotalItems int64
+	TotalPages int
+}
+
+// RevisionDiff adalah perbedaan isi produk dari revisi From ke revisi To
+type RevisionDiff struct {
+	From    int
+	To      int
+	Changes []models.FieldChange
+}
+
+// Revisions mengambil riwayat revisi produk milik actor, yang terbaru lebih dulu.
+// Riwayat produk di trash tetap bisa dibaca.
+func (s *ProductService) Revisions(ctx context.Context, actor Actor, id uuid.UUID, page, limit int) (*RevisionList, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 20
+	}
+	product, err := s.findHistoryOwned(ctx, actor, id)
+	if err != nil {
+		return nil, err
+	}
+
+	revisions, total, err := s.revisions.List(ctx, product.Id, page, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve product revisions: %w", err)
+	}
+	list := &RevisionList{
+		Revisions:  make([]models.ProductRevisionResponse, 0, len(revisions)),
+		Page:       page,
+		Limit:      limit,
+		TotalItems: total,
+		TotalPages: int((total + int64(limit) - 1) / int64(limit)),
+	}
+	for _, revision := range revisions {
+		list.Revisions = append(list.Revisions, models.NewProductRevisionResponse(revision, false))
+	}
+	return list, nil
+}
+
+// Revision mengambil satu revisi produk milik actor beserta snapshot-nya
+func (s *ProductService) Revision(ctx context.Context, actor Actor, id uuid.UUID, number int) (*models.ProductRevisionResponse, error) {
+	product, err := s.findHistoryOwned(ctx, actor, id)
+	if err != nil {
+		return nil, err
+	}
+	revision, err := s.findRevision(ctx, product.Id, number)
+	if err != nil {
+		return nil, err
+	}
+	response := models.NewProductRevisionResponse(*revision, true)
+	return &response, nil
+}
+
+// DiffRevisions membandingkan dua revisi produk milik actor per field
+func (s *ProductService) DiffRevisions(ctx context.Context, actor Actor, id uuid.UUID, from, to int) (*RevisionDiff, error) {
+	product, err := s.findHistoryOwned(ctx, actor, id)
+	if err != nil {
+		return nil, err
+	}
+	older, err := s.findRevision(ctx, product.Id, from)
+	if err != nil {
+		return nil, err
+	}
+	newer, err := s.findRevision(ctx, product.Id, to)
+	if err != nil {
+		return nil, err
+	}
+	return &RevisionDiff{From: from, To: to, Changes: diffSnapshots(older.Snapshot, newer.Snapshot)}, nil
+}
+
+// Rollback mengembalikan isi produk milik actor ke snapshot revisi number, lalu
+// mencatatnya sebagai revisi baru. Gambar dipulihkan dari file yang disimpan revisi,
+// kategori yang sudah dihapus tidak ikut dipulihkan dan stok tidak diubah.
+func (s *ProductService) Rollback(ctx context.Context, actor Actor, id uuid.UUID, number int) (*models.ProductResponse, error) {
+	product, err := s.findOwned(ctx, actor, id)
+	if err != nil {
+		return nil, err
+	}
+	revision, err := s.findRevision(ctx, product.Id, number)
+	if err != nil {
+		return nil, err
+	}
+	snapshot := revision.Snapshot
+
+	categories := make([]models.Category, 0, len(snapshot.Categories))
+	for _, item := range snapshot.Categories {
+		category, err := s.categories.FindByID(ctx, uuid.MustParse(item.Id))
+		if errors.Is(err, repositories.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve category: %w", err)
+		}
+		categories = append(categories, *category)
+	}
+
+	images := make([]models.ProductImage, 0, len(snapshot.Images))
+	for _, image := range snapshot.Images {
+		images = append(images, models.ProductImage{
+			Id:      uuid.MustParse(image.Id),
+			Path:    image.Path,
+			Alt:     image.Alt,
+			Primary: image.Primary,
+		})
+	}
+	options := make([]models.ProductOption, 0, len(snapshot.Options))
+	for i, option := range snapshot.Options {
+		options = append(options, models.ProductOption{Id: uuid.MustParse(option.Id), Name: option.Name, Values: option.Values, Position: i})
+	}
+	variants := make([]models.ProductVariant, 0, len(snapshot.Variants))
+	for i, item := range snapshot.Variants {
+		variant := models.ProductVariant{Id: uuid.MustParse(item.Id), SKU: item.SKU, Position: i}
+		// SKU varian yang sudah dihapus bisa saja dipakai produk lain
+		existing, err := s.products.FindVariantBySKU(ctx, item.SKU)
+		if err != nil && !errors.Is(err, repositories.ErrNotFound) {
+			return nil, fmt.Errorf("failed to check variant SKU: %w", err)
+		}
+		if existing != nil && existing.ProductId != product.Id {
+			return nil, Conflict(apperrors.CodeSKUTaken)
+		}
+		if item.Price != nil {
+			variant.Price = &item.Price.Amount
+		}
+		if item.ImageId != nil {
+			imageID := uuid.MustParse(*item.ImageId)
+			variant.ImageId = &imageID
+		}
+		variant.Attributes, variant.Combination, _ = matchAttributes(options, item.Attributes)
+		if index := variantIndex(product, variant.Id); index >= 0 {
+			variant.CreatedAt = product.Variants[index].CreatedAt
+		}
+		variants = append(variants, variant)
+	}
+
+	oldImages := product.Images
+	restored := removedImages(images, oldImages)
+	if err := s.restoreImages(restored); err != nil {
+		return nil, err
+	}
+	product.Name = snapshot.Name
+	product.Price = snapshot.Price
+	product.Categories = categories
+	product.Tags = toTags(snapshot.Tags)
+	product.Images = arrangeImages(images)
+	product.Options = options
+	product.Variants = variants
+	detachVariantImages(product)
+	product.RefreshPriceRange()
+
+	if err := s.products.Update(ctx, product, newRevision(actor, models.RevisionRollback, &number)); err != nil {
+		s.releaseImages(ctx, product.Id, restored)
+		return nil, saveFailed(err, nil, "failed to roll back product")
+	}
+	s.releaseImages(ctx, product.Id, removedImages(oldImages, product.Images))
+	s.indexProduct(ctx, product)
+
+	if err := s.inventory.Prune(ctx, product.Id, stockKeys(product)); err != nil {
+		return nil, fmt.Errorf("failed to update product stock: %w", err)
+	}
+	product, err = s.withStock(ctx, product)
+	if err != nil {
+		return nil, err
+	}
+	response := models.NewProductResponse(*product)
+	return &response, nil
+}
+
+// newRevision membuat revisi untuk aksi actor. ProductRepository mengisi nomor dan
+// snapshot produk lalu menyimpannya dalam transaksi yang sama dengan perubahannya.
+func newRevision(actor Actor, action string, rollbackOf *int) *models.ProductRevision {
+	return &models.ProductRevision{
+		Action:        action,
+		ActorId:       actor.UserID,
+		ActorUsername: actor.Username,
+		RollbackOf:    rollbackOf,
+	}
+}
+
+// findHistoryOwned mengambil produk milik actor, baik yang aktif maupun di trash
+func (s *ProductService) findHistoryOwned(ctx context.Context, actor Actor, id uuid.UUID) (*models.Product, error) {
+	product, err := s.findOwned(ctx, actor, id)
+	var serviceErr *Error
+	if errors.As(err, &serviceErr) && serviceErr.Code == apperrors.CodeProductNotFound {
+		return s.findTrashedOwned(ctx, actor, id)
+	}
+	return product, err
+}
+
+func (s *ProductService) findRevision(ctx context.Context, productID uuid.UUID, number int) (*models.ProductRevision, error) {
+	revision, err := s.revisions.Find(ctx, productID, number)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil, NotFound(apperrors.CodeRevisionNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve product revision: %w", err)
+	}
+	return revision, nil
+}
+
+func hasImagePath(images []models.ProductImage, path string) bool {
+	for _, image := range images {
+		if image.Path == path {
+			return true
+		}
+	}
+	return false
+}
+
+// snapshotItem adalah satu gambar, option atau varian di snapshot. Item yang ada
+// di kedua revisi dibandingkan per field, selain itu dilaporkan utuh.
+type snapshotItem struct {
+	key    string
+	value  any
+	fields []snapshotField
+}
+
+type snapshotField struct {
+	name  string
+	value any
+}
+
+// diffSnapshots mencari field yang berbeda dari snapshot from ke to. Gambar dan
+// varian dicocokkan berdasarkan ID, option berdasarkan nama karena ID option
+// dibuat ulang setiap kali option diganti.
+func diffSnapshots(from, to models.ProductSnapshot) []models.FieldChange {
+	changes := make([]models.FieldChange, 0)
+	changes = appendChange(changes, "name", from.Name, to.Name)
+	changes = appendChange(changes, "status", from.Status, to.Status)
+	changes = appendChange(changes, "publish_at", from.PublishAt, to.PublishAt)
+	changes = appendChange(changes, "unpublish_at", from.UnpublishAt, to.UnpublishAt)
+	changes = appendChange(changes, "price", from.Price, to.Price)
+	changes = appendChange(changes, "categories", from.Categories, to.Categories)
+	changes = appendChange(changes, "tags", from.Tags, to.Tags)
+	changes = diffItems(changes, imageItems(from.Images), imageItems(to.Images))
+	changes = diffItems(changes, optionItems(from.Options), optionItems(to.Options))
+	changes = diffItems(changes, variantItems(from.Variants), variantItems(to.Variants))
+	return changes
+}
+
+// appendChange menambahkan perubahan jika bentuk JSON kedua nilai berbeda
+func appendChange(changes []models.FieldChange, field string, from, to any) []models.FieldChange {
+	a, _ := json.Marshal(from)
+	b, _ := json.Marshal(to)
+	if string(a) == string(b) {
+		return changes
+	}
+	return append(changes, models.FieldChange{Field: field, From: from, To: to})
+}
+
+// diffItems membandingkan item sesuai urutan to, item yang dihapus dilaporkan terakhir
+func diffItems(changes []models.FieldChange, from, to []snapshotItem) []models.FieldChange {
+	previous := make(map[string]snapshotItem, len(from))
+	for _, item := range from {
+		previous[item.key] = item
+	}
+	for _, item := range to {
+		old, ok := previous[item.key]
+		if !ok {
+			changes = append(changes, models.FieldChange{Field: item.key, To: item.value})
+			continue
+		}
+		delete(previous, item.key)
+		for i, field := range item.fields {
+			changes = appendChange(changes, item.key+"."+field.name, old.fields[i].value, field.value)
+		}
+	}
+	for _, item := range from {
+		if _, ok := previous[item.key]; ok {
+			changes = append(changes, models.FieldChange{Field: item.key, From: item.value})
+		}
+	}
+	return changes
+}
+
+func imageItems(images []models.ProductImageResponse) []snapshotItem {
+	items := make([]snapshotItem, 0, len(images))
+	for _, image := range images {
+		items = append(items, snapshotItem{key: "images[" + image.Id + "]", value: image, fields: []snapshotField{
+			{"path", image.Path}, {"alt", image.Alt}, {"position", image.Position}, {"primary", image.Primary},
+		}})
+	}
+	return items
+}
+
+func optionItems(options []models.ProductOptionResponse) []snapshotItem {
+	items := make([]snapshotItem, 0, len(options))
+	for i, option := range options {
+		items = append(items, snapshotItem{key: "options[" + option.Name + "]", value: option.Values, fields: []snapshotField{
+			{"values", option.Values}, {"position", i},
+		}})
+	}
+	return items
+}
+
+func variantItems(variants []models.VariantSnapshot) []snapshotItem {
+	items := make([]snapshotItem, 0, len(variants))
+	for i, variant := range variants {
+		items = append(items, snapshotItem{key: "variants[" + variant.Id + "]", value: variant, fields: []snapshotField{
+			{"sku", variant.SKU}, {"price", variant.Price}, {"image_id", variant.ImageId},
+			{"attributes", variant.Attributes}, {"position", i},
+		}})
+	}
+	return items
+}
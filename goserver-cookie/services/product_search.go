@@ -0,0 +1,106 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"server-cookie/models"
+	"server-cookie/repositories"
+	"server-cookie/search"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// searchProducts mencari query di index dan mengembalikan paling banyak search.MaxHits
+// ID terurut relevansi beserta skornya. truncated bernilai true jika ada hasil lain
+// yang tidak diambil.
+func (s *ProductService) searchProducts(ctx context.Context, query string) (ranking []uuid.UUID, scores map[uuid.UUID]float64, truncated bool, err error) {
+	// Satu hasil tambahan menandakan ada hasil di luar batas
+	hits, err := s.search.Search(ctx, query, search.MaxHits+1)
+	if err != nil {
+		return nil, nil, false, fmt.Errorf("failed to search products: %w", err)
+	}
+	if len(hits) > search.MaxHits {
+		hits, truncated = hits[:search.MaxHits], true
+	}
+	ranking = make([]uuid.UUID, 0, len(hits))
+	scores = make(map[uuid.UUID]float64, len(hits))
+	for _, hit := range hits {
+		ranking = append(ranking, hit.ID)
+		scores[hit.ID] = hit.Score
+	}
+	return ranking, scores, truncated, nil
+}
+
+// attachMatches menambahkan skor dan potongan teks yang di-highlight ke hasil pencarian.
+// Nama produk diutamakan, tag dipakai jika nama tidak mengandung kata yang dicari.
+func attachMatches(products []models.ProductResponse, query string, scores map[uuid.UUID]float64) {
+	for i := range products {
+		product := &products[i]
+		highlight := search.Highlight(product.Name, query)
+		if highlight == "" {
+			highlight = search.Highlight(strings.Join(product.Tags, ", "), query)
+		}
+		product.Match = &models.SearchMatch{
+			Score:     scores[uuid.MustParse(product.Id)],
+			Highlight: highlight,
+		}
+	}
+}
+
+// Reindex mengisi ulang index pencarian dari semua produk aktif,
+// dipanggil saat start karena MemoryIndex kosong setelah restart
+func (s *ProductService) Reindex(ctx context.Context) (int, error) {
+	const batchSize = 200
+
+	indexed := 0
+	for page := 1; ; page++ {
+		products, _, err := s.products.List(ctx, repositories.ProductListParams{
+			Page:        page,
+			Limit:       batchSize,
+			Sort:        []repositories.ProductSort{{Field: repositories.SortByCreatedAt}},
+			SkipCount:   true,
+			WithoutUser: true,
+		})
+		if err != nil {
+			return indexed, fmt.Errorf("failed to retrieve products: %w", err)
+		}
+		for i := range products {
+			if err := s.search.Index(ctx, searchDocument(&products[i])); err != nil {
+				return indexed, fmt.Errorf("failed to index product: %w", err)
+			}
+			indexed++
+		}
+		if len(products) < batchSize {
+			return indexed, nil
+		}
+	}
+}
+
+// indexProduct memperbarui index pencarian setelah produk disimpan.
+// Kegagalan hanya dicatat karena data produk sudah tersimpan.
+func (s *ProductService) indexProduct(ctx context.Context, product *models.Product) {
+	if err := s.search.Index(ctx, searchDocument(product)); err != nil {
+		log.Println("❌ Gagal memperbarui index pencarian", product.Id.String()+":", err)
+	}
+}
+
+// unindexProduct menghapus produk dari index pencarian
+func (s *ProductService) unindexProduct(ctx context.Context, id uuid.UUID) {
+	if err := s.search.Remove(ctx, id); err != nil {
+		log.Println("❌ Gagal menghapus index pencarian", id.String()+":", err)
+	}
+}
+
+// searchDocument berisi nama, tag dan SKU varian produk yang bisa dicari
+func searchDocument(product *models.Product) search.Document {
+	body := make([]string, 0, len(product.Tags)+len(product.Variants))
+	for _, tag := range product.Tags {
+		body = append(body, tag.Name)
+	}
+	for _, variant := range product.Variants {
+		body = append(body, variant.SKU)
+	}
+	return search.Document{ID: product.Id, Title: product.Name, Body: body}
+}
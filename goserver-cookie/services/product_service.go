@@ -0,0 +1,754 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"server-cookie/apperrors"
+	"server-cookie/models"
+	"server-cookie/money"
+	"server-cookie/repositories"
+	"server-cookie/search"
+	"server-cookie/storage"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Actor adalah user yang melakukan aksi, diambil dari klaim JWT
+type Actor struct {
+	UserID   uuid.UUID
+	Username string
+}
+
+// ImageUpload adalah file gambar yang diunggah bersama input produk
+type ImageUpload struct {
+	Filename string
+	Content  io.Reader
+	// Alt adalah teks alternatif gambar, boleh kosong
+	Alt string
+}
+
+// CreateProductInput adalah data untuk membuat produk baru
+type CreateProductInput struct {
+	Name string
+	// Price tanpa mata uang memakai models.DefaultCurrency
+	Price   money.Input
+	OwnerID uuid.UUID
+	// Images disimpan sesuai urutan, gambar pertama menjadi gambar utama
+	Images      []ImageUpload
+	CategoryIDs []uuid.UUID
+	Tags        []string
+	// Status kosong berarti published, atau draft jika PublishAt diisi.
+	// Jadwal sama dengan ProductStatusInput.
+	Status      string
+	PublishAt   *time.Time
+	UnpublishAt *time.Time
+}
+
+// UpdateProductInput adalah data perubahan produk, field nil tidak diubah
+type UpdateProductInput struct {
+	Name *string
+	// Price harus dalam mata uang produk, mata uang tidak bisa diganti
+	Price   *money.Input
+	OwnerID uuid.UUID
+	// Images yang diisi mengganti semua gambar produk
+	Images      []ImageUpload
+	CategoryIDs *[]uuid.UUID
+	Tags        *[]string
+	// IfMatch berisi versi produk yang dianggap client masih berlaku (header If-Match),
+	// nil berarti versi tidak dicek
+	IfMatch []int64
+}
+
+// ListProductsInput adalah parameter pagination dan pencarian produk
+type ListProductsInput struct {
+	Page   int
+	Limit  int
+	Search string
+
+	// CategoryID membatasi produk pada kategori ini beserta sub-kategorinya
+	CategoryID *uuid.UUID
+	// Tags membatasi produk yang punya salah satu tag, atau semuanya jika AllTags
+	Tags    []string
+	AllTags bool
+
+	// Filter tambahan, nil berarti tidak difilter (lihat repositories.ProductListParams).
+	// MinPrice dan MaxPrice adalah minor unit dalam Currency, yang juga membatasi
+	// produk pada mata uang itu. Currency default models.DefaultCurrency jika filter harga diisi.
+	Currency      string
+	MinPrice      *int64
+	MaxPrice      *int64
+	OwnerID       *uuid.UUID
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	HasImage      *bool
+	// LowStock hanya mengambil produk yang stoknya menipis, biasanya bersama OwnerID
+	LowStock bool
+	// Status kosong berarti published. Status lain hanya bisa dipakai untuk produk
+	// milik ViewerID, yaitu user yang login, dan OwnerID kosong diisi dengan ViewerID.
+	Status   string
+	ViewerID uuid.UUID
+	// Sort kosong berarti produk terbaru lebih dulu
+	Sort []repositories.ProductSort
+
+	// Cursor berisi next_cursor atau prev_cursor dari halaman sebelumnya.
+	// Jika diisi, pagination memakai keyset dan Page diabaikan.
+	Cursor string
+	// IncludeTotal menghitung total produk, default true tanpa Cursor dan false dengan Cursor
+	IncludeTotal *bool
+
+	// WithoutOwner tidak memuat data pemilik, hanya ID-nya
+	WithoutOwner bool
+}
+
+// ProductList adalah satu halaman produk beserta metadata pagination.
+// Page 0 berarti halaman diambil dengan cursor. TotalItems dan TotalPages
+// hanya diisi jika Counted.
+type ProductList struct {
+	Products    []models.ProductResponse
+	Page        int
+	Limit       int
+	Counted     bool
+	TotalItems  int64
+	TotalPages  int
+	HasNextPage bool
+
+	// NextCursor dan PrevCursor kosong jika tidak ada halaman ke arah itu
+	NextCursor string
+	PrevCursor string
+
+	// SearchTruncated bernilai true jika search cocok dengan lebih dari search.MaxHits
+	// produk. Hanya MaxHits produk paling relevan yang ditampilkan dan dihitung di TotalItems.
+	SearchTruncated bool
+}
+
+// ProductService berisi aturan bisnis produk yang tidak bergantung pada HTTP
+type ProductService struct {
+	products   repositories.ProductRepository
+	categories repositories.CategoryRepository
+	inventory  repositories.InventoryRepository
+	revisions  repositories.ProductRevisionRepository
+	images     storage.ImageStore
+	cursors    *CursorSigner
+	search     search.Index
+}
+
+// NewProductService membuat ProductService dengan dependency yang diberikan
+func NewProductService(products repositories.ProductRepository, categories repositories.CategoryRepository, inventory repositories.InventoryRepository, revisions repositories.ProductRevisionRepository, images storage.ImageStore, cursors *CursorSigner, index search.Index) *ProductService {
+	return &ProductService{products: products, categories: categories, inventory: inventory, revisions: revisions, images: images, cursors: cursors, search: index}
+}
+
+// List mengambil produk dengan pagination, nilai page/limit tidak valid diganti default
+func (s *ProductService) List(ctx context.Context, input ListProductsInput) (*ProductList, error) {
+	input = input.withDefaults()
+	if err := input.validate(); err != nil {
+		return nil, err
+	}
+	// Hasil pencarian diurutkan berdasarkan relevansi jika sort tidak diisi
+	input.Search = strings.TrimSpace(input.Search)
+	if input.Search != "" && len(input.Sort) == 0 {
+		input.Sort = []repositories.ProductSort{{Field: repositories.SortByRelevance}}
+	}
+
+	statuses, err := visibleStatuses(&input)
+	if err != nil {
+		return nil, err
+	}
+
+	params := repositories.ProductListParams{
+		Page:          input.Page,
+		Limit:         input.Limit,
+		Tags:          normalizeTags(input.Tags),
+		AllTags:       input.AllTags,
+		Currency:      input.Currency,
+		MinPrice:      input.MinPrice,
+		MaxPrice:      input.MaxPrice,
+		OwnerID:       input.OwnerID,
+		CreatedAfter:  input.CreatedAfter,
+		CreatedBefore: input.CreatedBefore,
+		HasImage:      input.HasImage,
+		Statuses:      statuses,
+		Sort:          input.Sort,
+		LookAhead:     true,
+		SkipCount:     !input.includeTotal(),
+		WithoutUser:   input.WithoutOwner,
+	}
+	fingerprint := input.fingerprint()
+	if input.Cursor != "" {
+		cursor, err := s.cursors.Decode(input.Cursor)
+		if err != nil || cursor.Query != fingerprint {
+			return nil, Invalid(apperrors.CodeInvalidCursor)
+		}
+		params.Cursor = &repositories.ProductCursor{Key: cursor.Key, Backward: cursor.Backward}
+	}
+	if input.CategoryID != nil {
+		categories, err := s.categories.List(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve categories: %w", err)
+		}
+		if !containsCategory(categories, *input.CategoryID) {
+			return nil, NotFound(apperrors.CodeCategoryNotFound)
+		}
+		params.CategoryIDs = subtree(categories, *input.CategoryID)
+	}
+	if input.LowStock {
+		ids, err := s.inventory.LowStockProductIDs(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve low stock products: %w", err)
+		}
+		params.IDs = ids
+	}
+	var scores map[uuid.UUID]float64
+	truncated := false
+	if input.Search != "" {
+		ranking, hitScores, hitsTruncated, err := s.searchProducts(ctx, input.Search)
+		if err != nil {
+			return nil, err
+		}
+		params.Ranking, scores, truncated = ranking, hitScores, hitsTruncated
+	}
+
+	products, totalItems, err := s.products.List(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve products: %w", err)
+	}
+	if err := s.loadStock(ctx, products); err != nil {
+		return nil, err
+	}
+
+	// Repository mengambil satu produk tambahan untuk mengetahui ada halaman berikutnya
+	// (atau sebelumnya jika mundur dengan cursor), produk itu dibuang dari hasil
+	more := len(products) > input.Limit
+	backward := params.Cursor != nil && params.Cursor.Backward
+	if more {
+		if backward {
+			products = products[1:]
+		} else {
+			products = products[:input.Limit]
+		}
+	}
+
+	list := newProductList(products, totalItems, input)
+	if params.SkipCount {
+		list.Counted, list.TotalPages = false, 0
+	}
+	hasPrev := input.Page > 1
+	list.HasNextPage = more
+	if params.Cursor != nil {
+		list.Page = 0
+		hasPrev = !backward || more
+		list.HasNextPage = backward || more
+	}
+	if len(products) > 0 {
+		if list.HasNextPage {
+			list.NextCursor = s.cursors.Encode(productCursor{Query: fingerprint, Key: repositories.KeyOf(products[len(products)-1], params.Ranking)})
+		}
+		if hasPrev {
+			list.PrevCursor = s.cursors.Encode(productCursor{Query: fingerprint, Key: repositories.KeyOf(products[0], params.Ranking), Backward: true})
+		}
+	}
+	if input.Search != "" {
+		attachMatches(list.Products, input.Search, scores)
+		list.SearchTruncated = truncated
+	}
+	return list, nil
+}
+
+// Get mengambil detail produk berdasarkan ID. Produk yang tidak published hanya
+// bisa dilihat pemiliknya, viewer lain mendapat error not found.
+func (s *ProductService) Get(ctx context.Context, viewer Actor, id uuid.UUID) (*models.ProductResponse, error) {
+	product, err := s.find(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !product.VisibleTo(viewer.UserID) {
+		return nil, NotFound(apperrors.CodeProductNotFound)
+	}
+	response := models.NewProductResponse(*product)
+	return &response, nil
+}
+
+// Create membuat produk baru milik actor
+func (s *ProductService) Create(ctx context.Context, actor Actor, input CreateProductInput) (*models.ProductResponse, error) {
+	if input.OwnerID != actor.UserID {
+		return nil, Forbidden(apperrors.CodeOwnerMismatch)
+	}
+
+	price, fields := resolvePrice(input.Price, "")
+	if len(fields) > 0 {
+		return nil, Validation(fields...)
+	}
+	categories, err := s.resolveCategories(ctx, input.CategoryIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	product := models.Product{
+		Name:       input.Name,
+		Price:      price,
+		UserId:     input.OwnerID,
+		Categories: categories,
+		Tags:       toTags(input.Tags),
+		// Produk baru diperlakukan sebagai draft sehingga status awalnya dicek seperti transisi
+		Status: models.ProductDraft,
+	}
+	status := input.Status
+	if status == "" {
+		status = models.ProductPublished
+		if input.PublishAt != nil {
+			status = models.ProductDraft
+		}
+	}
+	if err := applyStatus(&product, ProductStatusInput{Status: status, PublishAt: input.PublishAt, UnpublishAt: input.UnpublishAt}, time.Now()); err != nil {
+		return nil, err
+	}
+
+	//handle upload image
+	images, err := s.uploadImages(input.Images)
+	if err != nil {
+		return nil, err
+	}
+	product.Images = arrangeImages(images)
+	product.RefreshPriceRange()
+
+	if err := s.products.Create(ctx, &product, newRevision(actor, models.RevisionCreate, nil)); err != nil {
+		s.cleanupImages(images)
+		return nil, fmt.Errorf("failed to create product: %w", err)
+	}
+	s.indexProduct(ctx, &product)
+
+	response := models.NewProductResponse(product)
+	return &response, nil
+}
+
+// Update mengubah produk milik actor. File gambar lama yang diganti tetap disimpan
+// selama masih tercatat di revisi produk.
+func (s *ProductService) Update(ctx context.Context, actor Actor, id uuid.UUID, input UpdateProductInput) (*models.ProductResponse, error) {
+	product, err := s.findOwned(ctx, actor, id)
+	if err != nil {
+		return nil, err
+	}
+	if input.OwnerID != actor.UserID {
+		return nil, Forbidden(apperrors.CodeOwnerMismatch)
+	}
+	if err := checkVersion(product, input.IfMatch); err != nil {
+		return nil, err
+	}
+
+	// Hanya update field yang diisi
+	if input.Name != nil {
+		product.Name = *input.Name
+	}
+	if input.Price != nil {
+		price, fields := resolvePrice(*input.Price, product.Price.Currency)
+		if len(fields) > 0 {
+			return nil, Validation(fields...)
+		}
+		product.Price = price
+	}
+	if input.CategoryIDs != nil {
+		categories, err := s.resolveCategories(ctx, *input.CategoryIDs)
+		if err != nil {
+			return nil, err
+		}
+		product.Categories = categories
+	}
+	if input.Tags != nil {
+		product.Tags = toTags(*input.Tags)
+	}
+
+	oldImages := product.Images
+	var newImages []models.ProductImage
+	if len(input.Images) > 0 {
+		newImages, err = s.uploadImages(input.Images)
+		if err != nil {
+			return nil, err
+		}
+		product.Images = arrangeImages(newImages)
+		detachVariantImages(product)
+	}
+	// Varian tanpa harga sendiri ikut harga produk
+	product.RefreshPriceRange()
+
+	if err := s.products.Update(ctx, product, newRevision(actor, models.RevisionUpdate, nil)); err != nil {
+		s.cleanupImages(newImages)
+		return nil, saveFailed(err, input.IfMatch, "failed to update product")
+	}
+
+	// Gambar lama dilepas setelah gambar baru tersimpan
+	if newImages != nil {
+		s.releaseImages(ctx, product.Id, oldImages)
+	}
+	s.indexProduct(ctx, product)
+
+	response := models.NewProductResponse(*product)
+	return &response, nil
+}
+
+// Delete memindahkan produk milik actor ke trash. Gambar tetap disimpan
+// agar produk bisa di-restore, dan baru dihapus saat produk di-purge.
+// ifMatch sama dengan UpdateProductInput.IfMatch.
+func (s *ProductService) Delete(ctx context.Context, actor Actor, id uuid.UUID, ifMatch []int64) error {
+	product, err := s.findOwned(ctx, actor, id)
+	if err != nil {
+		return err
+	}
+	if err := checkVersion(product, ifMatch); err != nil {
+		return err
+	}
+
+	if err := s.products.Delete(ctx, product, newRevision(actor, models.RevisionDelete, nil)); err != nil {
+		return saveFailed(err, ifMatch, "failed to delete product")
+	}
+	// Produk di trash tidak muncul di pencarian, diindeks lagi saat di-restore
+	s.unindexProduct(ctx, product.Id)
+	return nil
+}
+
+// ListTrash mengambil produk di trash milik actor dengan pagination
+func (s *ProductService) ListTrash(ctx context.Context, actor Actor, input ListProductsInput) (*ProductList, error) {
+	input = input.withDefaults()
+
+	products, totalItems, err := s.products.ListTrashed(ctx, actor.UserID, repositories.ProductListParams{
+		Page:  input.Page,
+		Limit: input.Limit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve trash: %w", err)
+	}
+	if err := s.loadStock(ctx, products); err != nil {
+		return nil, err
+	}
+	return newProductList(products, totalItems, input), nil
+}
+
+// Restore mengembalikan produk milik actor dari trash
+func (s *ProductService) Restore(ctx context.Context, actor Actor, id uuid.UUID) (*models.ProductResponse, error) {
+	product, err := s.findTrashedOwned(ctx, actor, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.products.Restore(ctx, product, newRevision(actor, models.RevisionRestore, nil)); errors.Is(err, repositories.ErrNotFound) {
+		// Sudah di-restore atau di-purge oleh request lain
+		return nil, NotFound(apperrors.CodeProductNotFound)
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to restore product: %w", err)
+	}
+	s.indexProduct(ctx, product)
+
+	response := models.NewProductResponse(*product)
+	return &response, nil
+}
+
+// Purge menghapus permanen produk di trash milik actor beserta revisi dan gambarnya
+func (s *ProductService) Purge(ctx context.Context, actor Actor, id uuid.UUID) error {
+	product, err := s.findTrashedOwned(ctx, actor, id)
+	if err != nil {
+		return err
+	}
+	return s.purge(ctx, product)
+}
+
+// PurgeTrash menghapus permanen semua produk yang masuk trash sebelum waktu before,
+// dan mengembalikan jumlah produk yang terhapus
+func (s *ProductService) PurgeTrash(ctx context.Context, before time.Time) (int, error) {
+	const batchSize = 100
+
+	purged := 0
+	for {
+		products, err := s.products.ListTrashedBefore(ctx, before, batchSize)
+		if err != nil {
+			return purged, fmt.Errorf("failed to retrieve trash: %w", err)
+		}
+		for i := range products {
+			if err := s.purge(ctx, &products[i]); err != nil {
+				return purged, err
+			}
+			purged++
+		}
+		if len(products) < batchSize {
+			return purged, nil
+		}
+	}
+}
+
+// WatchTrash menjalankan PurgeTrash setiap interval untuk produk yang
+// sudah lebih lama dari retention di trash, sampai ctx dibatalkan
+func (s *ProductService) WatchTrash(ctx context.Context, retention, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		purged, err := s.PurgeTrash(ctx, time.Now().Add(-retention))
+		if err != nil {
+			log.Println("❌ Gagal mengosongkan trash produk:", err)
+		} else if purged > 0 {
+			log.Printf("🗑️ %d produk dihapus permanen dari trash", purged)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// purge menghapus produk beserta stok dan revisinya dari database dalam satu
+// transaksi, lalu semua file gambar produk termasuk gambar yang hanya tercatat di revisi
+func (s *ProductService) purge(ctx context.Context, product *models.Product) error {
+	paths, err := s.revisions.ImagePaths(ctx, product.Id)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve product revisions: %w", err)
+	}
+	if err := s.products.Purge(ctx, product); err != nil {
+		return fmt.Errorf("failed to purge product: %w", err)
+	}
+	s.unindexProduct(ctx, product.Id)
+
+	images := product.Images
+	for _, path := range paths {
+		if !hasImagePath(images, path) {
+			images = append(images, models.ProductImage{Path: path})
+		}
+	}
+	s.cleanupImages(images)
+	return nil
+}
+
+func (s *ProductService) find(ctx context.Context, id uuid.UUID) (*models.Product, error) {
+	product, err := s.products.FindByID(ctx, id)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil, NotFound(apperrors.CodeProductNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve product: %w", err)
+	}
+	return s.withStock(ctx, product)
+}
+
+// withStock mengembalikan produk yang Stock-nya sudah diisi
+func (s *ProductService) withStock(ctx context.Context, product *models.Product) (*models.Product, error) {
+	products := []models.Product{*product}
+	if err := s.loadStock(ctx, products); err != nil {
+		return nil, err
+	}
+	return &products[0], nil
+}
+
+// loadStock mengisi Stock produk-produk dari InventoryRepository
+func (s *ProductService) loadStock(ctx context.Context, products []models.Product) error {
+	if len(products) == 0 {
+		return nil
+	}
+	ids := make([]uuid.UUID, 0, len(products))
+	for _, product := range products {
+		ids = append(ids, product.Id)
+	}
+	levels, err := s.inventory.Levels(ctx, ids)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve stock: %w", err)
+	}
+	byProduct := make(map[uuid.UUID][]models.StockLevel, len(products))
+	for _, level := range levels {
+		byProduct[level.ProductId] = append(byProduct[level.ProductId], level)
+	}
+	for i := range products {
+		products[i].Stock = byProduct[products[i].Id]
+	}
+	return nil
+}
+
+// findOwned mengambil produk dan memastikan actor adalah pemiliknya
+func (s *ProductService) findOwned(ctx context.Context, actor Actor, id uuid.UUID) (*models.Product, error) {
+	product, err := s.find(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if product.UserId != actor.UserID {
+		return nil, Forbidden(apperrors.CodeNotProductOwner)
+	}
+	return product, nil
+}
+
+// checkVersion memastikan versi produk termasuk salah satu isi ifMatch,
+// ifMatch nil berarti versi tidak dicek
+func checkVersion(product *models.Product, ifMatch []int64) error {
+	if ifMatch == nil || slices.Contains(ifMatch, product.Version) {
+		return nil
+	}
+	return PreconditionFailed(apperrors.CodeVersionMismatch)
+}
+
+// saveFailed mengubah error saat menyimpan produk. ErrVersionConflict berarti produk
+// diubah request lain di antara dibaca dan disimpan: version_mismatch jika client
+// mengirim If-Match, selain itu product_modified agar client mencoba lagi.
+// ErrExternalIDTaken menjadi external_id_taken.
+func saveFailed(err error, ifMatch []int64, message string) error {
+	if errors.Is(err, repositories.ErrVersionConflict) {
+		if ifMatch != nil {
+			return PreconditionFailed(apperrors.CodeVersionMismatch)
+		}
+		return Conflict(apperrors.CodeProductModified)
+	}
+	if errors.Is(err, repositories.ErrExternalIDTaken) {
+		return Conflict(apperrors.CodeExternalIDTaken)
+	}
+	return fmt.Errorf("%s: %w", message, err)
+}
+
+// TagCounts mengambil jumlah produk aktif per tag
+func (s *ProductService) TagCounts(ctx context.Context) ([]models.TagCount, error) {
+	counts, err := s.products.TagCounts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count tags: %w", err)
+	}
+	return counts, nil
+}
+
+// resolveCategories memastikan semua ID kategori ada, ID ganda diabaikan
+func (s *ProductService) resolveCategories(ctx context.Context, ids []uuid.UUID) ([]models.Category, error) {
+	categories := make([]models.Category, 0, len(ids))
+	seen := make(map[uuid.UUID]bool)
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		category, err := s.categories.FindByID(ctx, id)
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, Validation(apperrors.FieldError{Field: "category_ids", Code: "exists"})
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve category: %w", err)
+		}
+		categories = append(categories, *category)
+	}
+	return categories, nil
+}
+
+// findTrashedOwned mengambil produk di trash dan memastikan actor adalah pemiliknya
+func (s *ProductService) findTrashedOwned(ctx context.Context, actor Actor, id uuid.UUID) (*models.Product, error) {
+	product, err := s.products.FindTrashedByID(ctx, id)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil, NotFound(apperrors.CodeProductNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve product: %w", err)
+	}
+	if product.UserId != actor.UserID {
+		return nil, Forbidden(apperrors.CodeNotProductOwner)
+	}
+	return s.withStock(ctx, product)
+}
+
+// MaxListLimit adalah jumlah produk terbanyak per halaman, limit yang lebih besar dipotong
+const MaxListLimit = 100
+
+// withDefaults mengganti nilai page/limit yang tidak valid dengan default
+// dan membatasi limit ke MaxListLimit, untuk pagination offset maupun cursor
+func (input ListProductsInput) withDefaults() ListProductsInput {
+	if input.Page < 1 {
+		input.Page = 1
+	}
+	if input.Limit < 1 {
+		input.Limit = 10
+	}
+	input.Limit = min(input.Limit, MaxListLimit)
+	if input.Currency == "" && (input.MinPrice != nil || input.MaxPrice != nil) {
+		input.Currency = models.DefaultCurrency
+	}
+	return input
+}
+
+// includeTotal menentukan apakah total produk dihitung
+func (input ListProductsInput) includeTotal() bool {
+	if input.IncludeTotal != nil {
+		return *input.IncludeTotal
+	}
+	return input.Cursor == ""
+}
+
+// newProductList membuat satu halaman ProductList beserta metadata pagination
+func newProductList(products []models.Product, totalItems int64, input ListProductsInput) *ProductList {
+	// Hitung total halaman
+	totalPages := int((totalItems + int64(input.Limit) - 1) / int64(input.Limit))
+
+	return &ProductList{
+		Products:    toProductResponses(products),
+		Page:        input.Page,
+		Limit:       input.Limit,
+		Counted:     true,
+		TotalItems:  totalItems,
+		TotalPages:  totalPages,
+		HasNextPage: input.Page < totalPages,
+	}
+}
+
+// normalizeTags mengubah tag menjadi huruf kecil dengan spasi tunggal,
+// tag kosong dan ganda dibuang
+func normalizeTags(tags []string) []string {
+	normalized := make([]string, 0, len(tags))
+	seen := make(map[string]bool)
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.Join(strings.Fields(tag), " "))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	return normalized
+}
+
+func toTags(names []string) []models.Tag {
+	tags := make([]models.Tag, 0, len(names))
+	for _, name := range normalizeTags(names) {
+		tags = append(tags, models.Tag{Name: name})
+	}
+	return tags
+}
+
+func toProductResponses(products []models.Product) []models.ProductResponse {
+	responses := make([]models.ProductResponse, 0, len(products))
+	for _, product := range products {
+		responses = append(responses, models.NewProductResponse(product))
+	}
+	return responses
+}
+
+// resolvePrice membaca harga produk atau varian. currency adalah mata uang produk
+// yang harus diikuti input, kosong saat produk baru dibuat. Harga harus berada di
+// antara models.MinProductPrice dan models.MaxProductPrice minor unit.
+func resolvePrice(input money.Input, currency string) (money.Money, []apperrors.FieldError) {
+	code := strings.ToUpper(strings.TrimSpace(input.Currency))
+	switch {
+	case code == "" && currency == "":
+		code = models.DefaultCurrency
+	case code == "":
+		code = currency
+	case currency != "" && code != currency:
+		return money.Money{}, []apperrors.FieldError{{Field: "currency", Code: "currency_mismatch", Param: currency}}
+	}
+	c, err := money.LookupCurrency(code)
+	if err != nil {
+		return money.Money{}, []apperrors.FieldError{{Field: "currency", Code: "currency"}}
+	}
+
+	price, err := input.Resolve(c.Code)
+	switch {
+	case errors.Is(err, money.ErrTooPrecise):
+		return money.Money{}, []apperrors.FieldError{{Field: "price", Code: "price_precision", Param: strconv.Itoa(c.Exponent)}}
+	case err != nil, price.Amount < models.MinProductPrice, price.Amount > models.MaxProductPrice:
+		return money.Money{}, []apperrors.FieldError{{Field: "price", Code: "price"}}
+	}
+	return price, nil
+}
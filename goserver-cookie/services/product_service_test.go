@@ -0,0 +1,531 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"server-cookie/apperrors"
+	"server-cookie/models"
+	"server-cookie/money"
+	"server-cookie/repositories"
+	"server-cookie/search"
+	"server-cookie/services"
+
+	"github.com/google/uuid"
+)
+
+// fakeImageStore mencatat gambar yang tersimpan dan yang diarsipkan tanpa menyentuh disk
+type fakeImageStore struct {
+	stored   map[string]string
+	archived map[string]string
+}
+
+func newFakeImageStore() *fakeImageStore {
+	return &fakeImageStore{stored: make(map[string]string), archived: make(map[string]string)}
+}
+
+func (s *fakeImageStore) UploadImage(filename string, content io.Reader) (string, error) {
+	raw, err := io.ReadAll(content)
+	if err != nil {
+		return "", err
+	}
+	imagePath := "uploads/" + uuid.NewString() + "-" + filename
+	s.stored[imagePath] = string(raw)
+	return imagePath, nil
+}
+
+func (s *fakeImageStore) DeleteImage(imagePath string) error {
+	delete(s.stored, imagePath)
+	delete(s.archived, imagePath)
+	return nil
+}
+
+func (s *fakeImageStore) ArchiveImage(imagePath string) error {
+	if content, ok := s.stored[imagePath]; ok {
+		s.archived[imagePath] = content
+		delete(s.stored, imagePath)
+	}
+	return nil
+}
+
+func (s *fakeImageStore) RestoreImage(imagePath string) error {
+	if _, ok := s.stored[imagePath]; ok {
+		return nil
+	}
+	content, ok := s.archived[imagePath]
+	if !ok {
+		return errors.New("image not archived")
+	}
+	s.stored[imagePath] = content
+	delete(s.archived, imagePath)
+	return nil
+}
+
+// failingProductRepository gagal saat menyimpan perubahan produk
+type failingProductRepository struct {
+	*repositories.MemoryProductRepository
+}
+
+func (r failingProductRepository) Create(ctx context.Context, product *models.Product, revision *models.ProductRevision) error {
+	return errors.New("disk full")
+}
+
+func (r failingProductRepository) Update(ctx context.Context, product *models.Product, revision *models.ProductRevision) error {
+	return errors.New("disk full")
+}
+
+// racingProductRepository meniru request lain yang menyimpan produk tepat
+// sebelum Update pertama dijalankan
+type racingProductRepository struct {
+	*repositories.MemoryProductRepository
+	raced bool
+}
+
+func (r *racingProductRepository) Update(ctx context.Context, product *models.Product, revision *models.ProductRevision) error {
+	if !r.raced {
+		r.raced = true
+		other, err := r.FindByID(ctx, product.Id)
+		if err != nil {
+			return err
+		}
+		other.Name = "Other Edit"
+		if err := r.MemoryProductRepository.Update(ctx, other, &models.ProductRevision{Action: models.RevisionUpdate}); err != nil {
+			return err
+		}
+	}
+	return r.MemoryProductRepository.Update(ctx, product, revision)
+}
+
+// SaveBatch mengubah produk terakhir di batch sebelum batch disimpan
+func (r *racingProductRepository) SaveBatch(ctx context.Context, changes []repositories.ProductChange) error {
+	if !r.raced {
+		r.raced = true
+		other, err := r.FindByID(ctx, changes[len(changes)-1].Product.Id)
+		if err != nil {
+			return err
+		}
+		other.Name = "Other Edit"
+		if err := r.MemoryProductRepository.Update(ctx, other, &models.ProductRevision{Action: models.RevisionUpdate}); err != nil {
+			return err
+		}
+	}
+	return r.MemoryProductRepository.SaveBatch(ctx, changes)
+}
+
+func newUser(t *testing.T, users *repositories.MemoryUserRepository, username string) services.Actor {
+	t.Helper()
+	user := models.User{Username: username, Email: username + "@example.com", Password: "x"}
+	if err := users.Create(context.Background(), &user); err != nil {
+		t.Fatal(err)
+	}
+	return services.Actor{UserID: user.Id, Username: username}
+}
+
+func upload(contents ...string) []services.ImageUpload {
+	uploads := make([]services.ImageUpload, 0, len(contents))
+	for _, content := range contents {
+		uploads = append(uploads, services.ImageUpload{Filename: "photo.jpg", Content: strings.NewReader(content)})
+	}
+	return uploads
+}
+
+func TestProductServiceReplacesImageAfterSave(t *testing.T) {
+	ctx := context.Background()
+	users := repositories.NewMemoryUserRepository()
+	categories := repositories.NewMemoryCategoryRepository()
+	images := newFakeImageStore()
+	revisions := repositories.NewMemoryRevisionRepository()
+	inventory := repositories.NewMemoryInventoryRepository()
+	service := services.NewProductService(repositories.NewMemoryProductRepository(users, categories, inventory, revisions), categories, inventory, revisions, images, services.NewCursorSigner([]byte("test-cursor-secret")), search.NewMemoryIndex())
+	alice := newUser(t, users, "alice")
+
+	created, err := service.Create(ctx, alice, services.CreateProductInput{Name: "Cookie", Price: money.Input{Amount: "1000"}, OwnerID: alice.UserID, Images: upload("old")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.User.Username != "alice" {
+		t.Fatalf("owner not loaded: %+v", created.User)
+	}
+
+	id := uuid.MustParse(created.Id)
+	updated, err := service.Update(ctx, alice, id, services.UpdateProductInput{OwnerID: alice.UserID, Images: upload("new")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.Name != "Cookie" || updated.Price != money.New(100000, "IDR") {
+		t.Fatalf("unchanged fields were modified: %+v", updated)
+	}
+	// Gambar lama masih tercatat di revisi pertama sehingga diarsipkan
+	if _, ok := images.stored[created.Image]; ok || images.archived[created.Image] != "old" {
+		t.Fatalf("old image %q of the first revision was not archived", created.Image)
+	}
+	if images.stored[updated.Image] != "new" {
+		t.Fatalf("new image not stored: %v", images.stored)
+	}
+
+	if err := service.Delete(ctx, alice, id, nil); err != nil {
+		t.Fatal(err)
+	}
+	if len(images.stored) != 1 || len(images.archived) != 1 {
+		t.Fatalf("image removed before purge: %v, %v", images.stored, images.archived)
+	}
+	if _, err := service.Get(ctx, alice, id); !errors.Is(err, services.ErrNotFound) {
+		t.Fatalf("Get after delete = %v, want ErrNotFound", err)
+	}
+
+	if err := service.Purge(ctx, alice, id); err != nil {
+		t.Fatal(err)
+	}
+	if len(images.stored) != 0 || len(images.archived) != 0 {
+		t.Fatalf("images left after purge: %v, %v", images.stored, images.archived)
+	}
+}
+
+func TestProductServiceManagesImages(t *testing.T) {
+	ctx := context.Background()
+	users := repositories.NewMemoryUserRepository()
+	categories := repositories.NewMemoryCategoryRepository()
+	images := newFakeImageStore()
+	revisions := repositories.NewMemoryRevisionRepository()
+	inventory := repositories.NewMemoryInventoryRepository()
+	service := services.NewProductService(repositories.NewMemoryProductRepository(users, categories, inventory, revisions), categories, inventory, revisions, images, services.NewCursorSigner([]byte("test-cursor-secret")), search.NewMemoryIndex())
+	alice := newUser(t, users, "alice")
+
+	created, err := service.Create(ctx, alice, services.CreateProductInput{Name: "Cookie", Price: money.Input{Amount: "1000"}, OwnerID: alice.UserID, Images: upload("a", "b")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := uuid.MustParse(created.Id)
+	if len(created.Images) != 2 || !created.Images[0].Primary || created.Image != created.Images[0].Path {
+		t.Fatalf("first image should be primary: %+v", created.Images)
+	}
+
+	added, err := service.AddImages(ctx, alice, id, upload("c"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(added.Images) != 3 || added.Images[2].Position != 2 || added.Images[2].Primary {
+		t.Fatalf("image not appended: %+v", added.Images)
+	}
+	if _, err := service.AddImages(ctx, alice, id, upload("1", "2", "3", "4", "5", "6", "7", "8")); !errors.Is(err, services.ErrValidation) {
+		t.Fatalf("adding past the limit = %v, want ErrValidation", err)
+	}
+
+	// Balik urutan dan jadikan gambar terakhir gambar utama
+	alt := "close-up"
+	primary := true
+	order := []services.ImageOrderItem{
+		{ID: uuid.MustParse(added.Images[2].Id), Alt: &alt, Primary: &primary},
+		{ID: uuid.MustParse(added.Images[1].Id)},
+		{ID: uuid.MustParse(added.Images[0].Id)},
+	}
+	reordered, err := service.ReorderImages(ctx, alice, id, order)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reordered.Images[0].Id != added.Images[2].Id || !reordered.Images[0].Primary || reordered.Images[0].Alt != "close-up" || reordered.Images[2].Primary {
+		t.Fatalf("unexpected order: %+v", reordered.Images)
+	}
+	if _, err := service.ReorderImages(ctx, alice, id, order[:2]); !errors.Is(err, services.ErrValidation) {
+		t.Fatalf("incomplete order = %v, want ErrValidation", err)
+	}
+
+	// Gambar utama dihapus, gambar berikutnya menggantikannya
+	removed, err := service.RemoveImage(ctx, alice, id, uuid.MustParse(reordered.Images[0].Id))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(removed.Images) != 2 || !removed.Images[0].Primary || removed.Images[0].Position != 0 {
+		t.Fatalf("unexpected images after remove: %+v", removed.Images)
+	}
+	// File gambar yang dihapus tidak lagi tersimpan, salinannya diarsipkan untuk rollback
+	removedPath := reordered.Images[0].Path
+	if _, ok := images.stored[removedPath]; ok || len(images.stored) != 2 {
+		t.Fatalf("removed image file is still stored: %v", images.stored)
+	}
+	if _, ok := images.archived[removedPath]; !ok {
+		t.Fatalf("removed image file was not archived: %v", images.archived)
+	}
+	if _, err := service.RemoveImage(ctx, alice, id, uuid.New()); !errors.Is(err, services.ErrNotFound) {
+		t.Fatalf("removing unknown image = %v, want ErrNotFound", err)
+	}
+}
+
+func TestProductServicePurgesExpiredTrash(t *testing.T) {
+	ctx := context.Background()
+	users := repositories.NewMemoryUserRepository()
+	categories := repositories.NewMemoryCategoryRepository()
+	images := newFakeImageStore()
+	revisions := repositories.NewMemoryRevisionRepository()
+	inventory := repositories.NewMemoryInventoryRepository()
+	service := services.NewProductService(repositories.NewMemoryProductRepository(users, categories, inventory, revisions), categories, inventory, revisions, images, services.NewCursorSigner([]byte("test-cursor-secret")), search.NewMemoryIndex())
+	alice := newUser(t, users, "alice")
+
+	var ids []uuid.UUID
+	for _, name := range []string{"Cookie", "Brownie", "Muffin"} {
+		created, err := service.Create(ctx, alice, services.CreateProductInput{Name: name, Price: money.Input{Amount: "1000"}, OwnerID: alice.UserID, Images: upload(name)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, uuid.MustParse(created.Id))
+	}
+	for _, id := range ids[:2] {
+		if err := service.Delete(ctx, alice, id, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Produk yang baru dihapus belum melewati retention
+	purged, err := service.PurgeTrash(ctx, time.Now().Add(-time.Hour))
+	if err != nil || purged != 0 {
+		t.Fatalf("PurgeTrash before retention = %d, %v", purged, err)
+	}
+
+	purged, err = service.PurgeTrash(ctx, time.Now().Add(time.Second))
+	if err != nil || purged != 2 {
+		t.Fatalf("PurgeTrash = %d, %v, want 2", purged, err)
+	}
+	if len(images.stored) != 1 {
+		t.Fatalf("expected only the live product's image, got %v", images.stored)
+	}
+	if _, err := service.Get(ctx, alice, ids[2]); err != nil {
+		t.Fatalf("live product purged: %v", err)
+	}
+	if _, err := service.Restore(ctx, alice, ids[0]); !errors.Is(err, services.ErrNotFound) {
+		t.Fatalf("Restore after purge = %v, want ErrNotFound", err)
+	}
+}
+
+func TestProductServiceCleansUpImageWhenSaveFails(t *testing.T) {
+	ctx := context.Background()
+	users := repositories.NewMemoryUserRepository()
+	categories := repositories.NewMemoryCategoryRepository()
+	images := newFakeImageStore()
+	revisions := repositories.NewMemoryRevisionRepository()
+	inventory := repositories.NewMemoryInventoryRepository()
+	service := services.NewProductService(failingProductRepository{repositories.NewMemoryProductRepository(users, categories, inventory, revisions)}, categories, inventory, revisions, images, services.NewCursorSigner([]byte("test-cursor-secret")), search.NewMemoryIndex())
+	alice := newUser(t, users, "alice")
+
+	_, err := service.Create(ctx, alice, services.CreateProductInput{Name: "Cookie", Price: money.Input{Amount: "1000"}, OwnerID: alice.UserID, Images: upload("x")})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	var domainErr *services.Error
+	if errors.As(err, &domainErr) {
+		t.Fatalf("storage failure reported as domain error: %v", err)
+	}
+	if len(images.stored) != 0 {
+		t.Fatalf("uploaded image not cleaned up: %v", images.stored)
+	}
+}
+
+func TestProductServiceOwnership(t *testing.T) {
+	ctx := context.Background()
+	users := repositories.NewMemoryUserRepository()
+	categories := repositories.NewMemoryCategoryRepository()
+	revisions := repositories.NewMemoryRevisionRepository()
+	inventory := repositories.NewMemoryInventoryRepository()
+	service := services.NewProductService(repositories.NewMemoryProductRepository(users, categories, inventory, revisions), categories, inventory, revisions, newFakeImageStore(), services.NewCursorSigner([]byte("test-cursor-secret")), search.NewMemoryIndex())
+	alice := newUser(t, users, "alice")
+	bob := newUser(t, users, "bob")
+
+	if _, err := service.Create(ctx, bob, services.CreateProductInput{Name: "X", Price: money.Input{Amount: "1"}, OwnerID: alice.UserID}); !errors.Is(err, services.ErrForbidden) {
+		t.Fatalf("create for another user = %v, want ErrForbidden", err)
+	}
+
+	created, err := service.Create(ctx, alice, services.CreateProductInput{Name: "Cookie", Price: money.Input{Amount: "1000"}, OwnerID: alice.UserID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := uuid.MustParse(created.Id)
+
+	if _, err := service.Update(ctx, bob, id, services.UpdateProductInput{OwnerID: bob.UserID}); !errors.Is(err, services.ErrForbidden) {
+		t.Fatalf("update by non-owner = %v, want ErrForbidden", err)
+	}
+	if err := service.Delete(ctx, bob, id, nil); !errors.Is(err, services.ErrForbidden) {
+		t.Fatalf("delete by non-owner = %v, want ErrForbidden", err)
+	}
+	if err := service.Delete(ctx, alice, uuid.New(), nil); !errors.Is(err, services.ErrNotFound) {
+		t.Fatalf("delete unknown = %v, want ErrNotFound", err)
+	}
+}
+
+func TestProductServiceRejectsConcurrentUpdate(t *testing.T) {
+	ctx := context.Background()
+	users := repositories.NewMemoryUserRepository()
+	categories := repositories.NewMemoryCategoryRepository()
+	revisions := repositories.NewMemoryRevisionRepository()
+	inventory := repositories.NewMemoryInventoryRepository()
+	products := &racingProductRepository{MemoryProductRepository: repositories.NewMemoryProductRepository(users, categories, inventory, revisions)}
+	service := services.NewProductService(products, categories, inventory, revisions, newFakeImageStore(), services.NewCursorSigner([]byte("test-cursor-secret")), search.NewMemoryIndex())
+	alice := newUser(t, users, "alice")
+
+	created, err := service.Create(ctx, alice, services.CreateProductInput{Name: "Cookie", Price: money.Input{Amount: "1000"}, OwnerID: alice.UserID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := uuid.MustParse(created.Id)
+	name := "My Edit"
+
+	// Perubahan request lain tidak boleh ditimpa walaupun versi tidak dicek
+	_, err = service.Update(ctx, alice, id, services.UpdateProductInput{Name: &name, OwnerID: alice.UserID})
+	var domainErr *services.Error
+	if !errors.As(err, &domainErr) || !errors.Is(err, services.ErrConflict) || domainErr.Code != apperrors.CodeProductModified {
+		t.Fatalf("racing update = %v, want product_modified", err)
+	}
+	current, err := service.Get(ctx, alice, id)
+	if err != nil || current.Name != "Other Edit" || current.Version != 2 {
+		t.Fatalf("product after race = %v, %v", current, err)
+	}
+
+	if _, err := service.Update(ctx, alice, id, services.UpdateProductInput{Name: &name, OwnerID: alice.UserID, IfMatch: []int64{1}}); !errors.Is(err, services.ErrPreconditionFailed) {
+		t.Fatalf("update with stale version = %v, want ErrPreconditionFailed", err)
+	}
+	updated, err := service.Update(ctx, alice, id, services.UpdateProductInput{Name: &name, OwnerID: alice.UserID, IfMatch: []int64{2}})
+	if err != nil || updated.Version != 3 {
+		t.Fatalf("update with current version = %v, %v", updated, err)
+	}
+}
+
+func TestProductServiceBatchRollsBackOnConflict(t *testing.T) {
+	ctx := context.Background()
+	users := repositories.NewMemoryUserRepository()
+	categories := repositories.NewMemoryCategoryRepository()
+	revisions := repositories.NewMemoryRevisionRepository()
+	inventory := repositories.NewMemoryInventoryRepository()
+	products := &racingProductRepository{MemoryProductRepository: repositories.NewMemoryProductRepository(users, categories, inventory, revisions)}
+	service := services.NewProductService(products, categories, inventory, revisions, newFakeImageStore(), services.NewCursorSigner([]byte("test-cursor-secret")), search.NewMemoryIndex())
+	alice := newUser(t, users, "alice")
+
+	var ids []string
+	for _, name := range []string{"First", "Second"} {
+		created, err := service.Create(ctx, alice, services.CreateProductInput{Name: name, Price: money.Input{Amount: "1000"}, OwnerID: alice.UserID})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, created.Id)
+	}
+
+	// Produk kedua diubah request lain saat batch disimpan, produk pertama ikut dibatalkan
+	name := "Batch Edit"
+	result, err := service.Batch(ctx, alice, []services.BatchOperation{
+		{Op: services.BatchUpdate, ID: ids[0], Name: &name},
+		{Op: services.BatchUpdate, ID: ids[1], Name: &name},
+	}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Items[0].Status != services.BatchSkipped || result.Items[1].Status != services.BatchFailed {
+		t.Fatalf("batch items = %+v", result.Items)
+	}
+	var domainErr *services.Error
+	if !errors.As(result.Items[1].Err, &domainErr) || domainErr.Code != apperrors.CodeProductModified {
+		t.Fatalf("conflicting item error = %v, want product_modified", result.Items[1].Err)
+	}
+	first, err := service.Get(ctx, alice, uuid.MustParse(ids[0]))
+	if err != nil || first.Name != "First" || first.Version != 1 {
+		t.Fatalf("first product after rollback = %v, %v", first, err)
+	}
+}
+
+func TestProductServicePublishScheduled(t *testing.T) {
+	ctx := context.Background()
+	users := repositories.NewMemoryUserRepository()
+	categories := repositories.NewMemoryCategoryRepository()
+	revisions := repositories.NewMemoryRevisionRepository()
+	inventory := repositories.NewMemoryInventoryRepository()
+	service := services.NewProductService(repositories.NewMemoryProductRepository(users, categories, inventory, revisions), categories, inventory, revisions, newFakeImageStore(), services.NewCursorSigner([]byte("test-cursor-secret")), search.NewMemoryIndex())
+	alice := newUser(t, users, "alice")
+	bob := newUser(t, users, "bob")
+
+	now := time.Now()
+	publishAt, unpublishAt := now.Add(time.Hour), now.Add(2*time.Hour)
+	scheduled, err := service.Create(ctx, alice, services.CreateProductInput{
+		Name: "Seasonal", Price: money.Input{Amount: "1000"}, OwnerID: alice.UserID,
+		PublishAt: &publishAt, UnpublishAt: &unpublishAt,
+	})
+	if err != nil || scheduled.Status != models.ProductDraft {
+		t.Fatalf("create scheduled product = %v, %v", scheduled, err)
+	}
+	// Draft yang jadwal publish dan unpublish-nya sudah lewat langsung diarsipkan
+	lateUnpublishAt := now.Add(80 * time.Minute)
+	late, err := service.Create(ctx, alice, services.CreateProductInput{
+		Name: "Late", Price: money.Input{Amount: "1000"}, OwnerID: alice.UserID,
+		PublishAt: &publishAt, UnpublishAt: &lateUnpublishAt,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	id, lateID := uuid.MustParse(scheduled.Id), uuid.MustParse(late.Id)
+	if _, err := service.Get(ctx, bob, id); !errors.Is(err, services.ErrNotFound) {
+		t.Fatalf("Get draft as other user = %v, want ErrNotFound", err)
+	}
+
+	if changed, err := service.PublishScheduled(ctx, now); err != nil || changed != 0 {
+		t.Fatalf("PublishScheduled before schedule = %d, %v", changed, err)
+	}
+	if changed, err := service.PublishScheduled(ctx, now.Add(90*time.Minute)); err != nil || changed != 2 {
+		t.Fatalf("PublishScheduled = %d, %v, want 2", changed, err)
+	}
+	if product, err := service.Get(ctx, alice, lateID); err != nil || product.Status != models.ProductArchived {
+		t.Fatalf("late product = %v, %v, want archived", product, err)
+	}
+	published, err := service.Get(ctx, bob, id)
+	if err != nil || published.Status != models.ProductPublished || published.PublishAt != nil || published.UnpublishAt == nil {
+		t.Fatalf("product after publish = %v, %v", published, err)
+	}
+	history, err := service.Revisions(ctx, alice, id, 1, 10)
+	if err != nil || history.Revisions[0].Action != models.RevisionStatus || history.Revisions[0].Actor != nil {
+		t.Fatalf("expected a status revision without actor, got %v, %v", history, err)
+	}
+
+	if changed, err := service.PublishScheduled(ctx, now.Add(3*time.Hour)); err != nil || changed != 1 {
+		t.Fatalf("PublishScheduled unpublish = %d, %v, want 1", changed, err)
+	}
+	if _, err := service.Get(ctx, bob, id); !errors.Is(err, services.ErrNotFound) {
+		t.Fatalf("Get archived product as other user = %v, want ErrNotFound", err)
+	}
+	archived, err := service.Get(ctx, alice, id)
+	if err != nil || archived.Status != models.ProductArchived || archived.UnpublishAt != nil {
+		t.Fatalf("product after unpublish = %v, %v", archived, err)
+	}
+}
+
+func TestProductServiceMarksTruncatedSearch(t *testing.T) {
+	ctx := context.Background()
+	users := repositories.NewMemoryUserRepository()
+	categories := repositories.NewMemoryCategoryRepository()
+	revisions := repositories.NewMemoryRevisionRepository()
+	inventory := repositories.NewMemoryInventoryRepository()
+	products := repositories.NewMemoryProductRepository(users, categories, inventory, revisions)
+	service := services.NewProductService(products, categories, inventory, revisions, newFakeImageStore(), services.NewCursorSigner([]byte("test-cursor-secret")), search.NewMemoryIndex())
+	alice := newUser(t, users, "alice")
+
+	names := []string{"Brownie"}
+	for i := 0; i <= search.MaxHits; i++ {
+		names = append(names, fmt.Sprintf("Cookie %d", i))
+	}
+	for _, name := range names {
+		product := models.Product{Name: name, Price: money.New(1000, "IDR"), UserId: alice.UserID}
+		if err := products.Create(ctx, &product, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := service.Reindex(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	list, err := service.List(ctx, services.ListProductsInput{Page: 1, Limit: 10, Search: "cookie"})
+	if err != nil || !list.SearchTruncated || list.TotalItems != search.MaxHits {
+		t.Fatalf("expected a truncated search with %d items, got %+v, %v", search.MaxHits, list, err)
+	}
+	list, err = service.List(ctx, services.ListProductsInput{Page: 1, Limit: 10, Search: "brownie"})
+	if err != nil || list.SearchTruncated || list.TotalItems != 1 {
+		t.Fatalf("expected one untruncated result, got %+v, %v", list, err)
+	}
+}
@@ -0,0 +1,187 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"server-cookie/apperrors"
+	"server-cookie/models"
+	"server-cookie/repositories"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ProductStatusInput adalah status baru produk beserta jadwalnya. Jadwal yang
+// tidak diisi dihapus, sehingga setiap perubahan mengirim jadwal yang masih berlaku.
+type ProductStatusInput struct {
+	Status string
+	// PublishAt mempublikasikan draft pada waktu tersebut
+	PublishAt *time.Time
+	// UnpublishAt mengarsipkan produk published pada waktu tersebut, boleh diisi
+	// untuk draft yang dijadwalkan agar produk hanya tampil dalam rentang waktu tertentu
+	UnpublishAt *time.Time
+	// IfMatch sama dengan UpdateProductInput.IfMatch
+	IfMatch []int64
+}
+
+// SetStatus mengubah status dan jadwal publikasi produk milik actor sesuai
+// models.ProductTransitions. Status yang sama boleh dikirim untuk mengubah jadwal saja.
+func (s *ProductService) SetStatus(ctx context.Context, actor Actor, id uuid.UUID, input ProductStatusInput) (*models.ProductResponse, error) {
+	product, err := s.findOwned(ctx, actor, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkVersion(product, input.IfMatch); err != nil {
+		return nil, err
+	}
+	if err := applyStatus(product, input, time.Now()); err != nil {
+		return nil, err
+	}
+
+	if err := s.products.Update(ctx, product, newRevision(actor, models.RevisionStatus, nil)); err != nil {
+		return nil, saveFailed(err, input.IfMatch, "failed to update product status")
+	}
+	s.indexProduct(ctx, product)
+
+	response := models.NewProductResponse(*product)
+	return &response, nil
+}
+
+// applyStatus memeriksa perubahan status dan jadwal lalu menerapkannya ke produk
+func applyStatus(product *models.Product, input ProductStatusInput, now time.Time) error {
+	if _, ok := models.ProductTransitions[input.Status]; !ok {
+		return Validation(apperrors.FieldError{Field: "status", Code: "oneof", Param: strings.Join(productStatuses, " ")})
+	}
+	if input.Status != product.Status && !slices.Contains(models.ProductTransitions[product.Status], input.Status) {
+		return Conflict(apperrors.CodeInvalidStatusTransition)
+	}
+
+	var fields []apperrors.FieldError
+	if input.PublishAt != nil {
+		switch {
+		case input.Status != models.ProductDraft:
+			fields = append(fields, apperrors.FieldError{Field: "publish_at", Code: "schedule_status", Param: models.ProductDraft})
+		case !input.PublishAt.After(now):
+			fields = append(fields, apperrors.FieldError{Field: "publish_at", Code: "future"})
+		}
+	}
+	if input.UnpublishAt != nil {
+		switch {
+		case input.Status == models.ProductArchived:
+			fields = append(fields, apperrors.FieldError{Field: "unpublish_at", Code: "schedule_status", Param: models.ProductDraft + " " + models.ProductPublished})
+		case !input.UnpublishAt.After(now):
+			fields = append(fields, apperrors.FieldError{Field: "unpublish_at", Code: "future"})
+		case input.PublishAt != nil && !input.UnpublishAt.After(*input.PublishAt):
+			fields = append(fields, apperrors.FieldError{Field: "unpublish_at", Code: "gtfield", Param: "publish_at"})
+		}
+	}
+	if len(fields) > 0 {
+		return Validation(fields...)
+	}
+
+	product.Status = input.Status
+	product.PublishAt = utcTime(input.PublishAt)
+	product.UnpublishAt = utcTime(input.UnpublishAt)
+	return nil
+}
+
+// productStatuses adalah semua status produk untuk pesan error, urut sesuai siklusnya
+var productStatuses = []string{models.ProductDraft, models.ProductPublished, models.ProductArchived}
+
+// utcTime menyalin waktu dalam UTC tanpa monotonic clock agar sama setelah dibaca dari database
+func utcTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	utc := t.UTC().Round(0)
+	return &utc
+}
+
+// PublishScheduled menjalankan jadwal yang jatuh sebelum waktu now: draft dengan
+// PublishAt dipublikasikan dan produk published dengan UnpublishAt diarsipkan.
+// Perubahan dicatat sebagai revisi tanpa actor. Jumlah produk yang diubah dikembalikan.
+func (s *ProductService) PublishScheduled(ctx context.Context, now time.Time) (int, error) {
+	const batchSize = 100
+
+	changed := 0
+	for {
+		products, err := s.products.ListScheduledBefore(ctx, now, batchSize)
+		if err != nil {
+			return changed, fmt.Errorf("failed to retrieve scheduled products: %w", err)
+		}
+		for i := range products {
+			product := &products[i]
+			runSchedule(product, now)
+			// Produk yang baru diubah user dibaca ulang pada putaran berikutnya
+			err := s.products.Update(ctx, product, newRevision(Actor{}, models.RevisionStatus, nil))
+			if errors.Is(err, repositories.ErrVersionConflict) {
+				continue
+			}
+			if err != nil {
+				return changed, fmt.Errorf("failed to update product status: %w", err)
+			}
+			s.indexProduct(ctx, product)
+			changed++
+		}
+		if len(products) < batchSize {
+			return changed, nil
+		}
+	}
+}
+
+// runSchedule menerapkan jadwal produk yang sudah jatuh tempo. Draft yang jadwal
+// unpublish-nya juga sudah lewat langsung diarsipkan.
+func runSchedule(product *models.Product, now time.Time) {
+	if product.Status == models.ProductDraft && product.PublishAt != nil && product.PublishAt.Before(now) {
+		product.Status = models.ProductPublished
+		product.PublishAt = nil
+	}
+	if product.Status == models.ProductPublished && product.UnpublishAt != nil && product.UnpublishAt.Before(now) {
+		product.Status = models.ProductArchived
+		product.UnpublishAt = nil
+	}
+}
+
+// WatchSchedule menjalankan PublishScheduled setiap interval sampai ctx dibatalkan
+func (s *ProductService) WatchSchedule(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		changed, err := s.PublishScheduled(ctx, time.Now())
+		if err != nil {
+			log.Println("❌ Gagal menjalankan jadwal publikasi produk:", err)
+		} else if changed > 0 {
+			log.Printf("📅 Status %d produk diubah sesuai jadwal", changed)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// visibleStatuses menentukan status produk yang diambil List. Produk published
+// terlihat semua user, status lain hanya untuk produk milik viewer sendiri.
+func visibleStatuses(input *ListProductsInput) ([]string, error) {
+	switch input.Status {
+	case "", models.ProductPublished:
+		return []string{models.ProductPublished}, nil
+	case models.ProductDraft, models.ProductArchived:
+	default:
+		return nil, Validation(apperrors.FieldError{Field: "status", Code: "oneof", Param: strings.Join(productStatuses, " ")})
+	}
+	if input.OwnerID == nil {
+		input.OwnerID = &input.ViewerID
+	}
+	if input.ViewerID == uuid.Nil || *input.OwnerID != input.ViewerID {
+		return nil, Forbidden(apperrors.CodeNotProductOwner)
+	}
+	return []string{input.Status}, nil
+}
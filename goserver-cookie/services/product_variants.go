@@ -0,0 +1,287 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"server-cookie/apperrors"
+	"server-cookie/models"
+	"server-cookie/money"
+	"server-cookie/repositories"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OptionInput adalah satu definisi option varian, misalnya size dengan nilai S, M, L
+type OptionInput struct {
+	Name   string
+	Values []string
+}
+
+// VariantInput adalah data satu varian. Price nil berarti mengikuti harga produk,
+// selain itu harus dalam mata uang produk. Attributes berisi satu nilai untuk
+// setiap option produk.
+type VariantInput struct {
+	SKU        string
+	Price      *money.Input
+	ImageID    *uuid.UUID
+	Attributes map[string]string
+}
+
+// SetOptions mengganti definisi option produk milik actor. Varian yang ada
+// harus tetap valid dengan option baru, nilainya disesuaikan dengan penulisan option baru.
+func (s *ProductService) SetOptions(ctx context.Context, actor Actor, id uuid.UUID, inputs []OptionInput) (*models.ProductResponse, error) {
+	product, err := s.findOwned(ctx, actor, id)
+	if err != nil {
+		return nil, err
+	}
+	options, err := buildOptions(inputs)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool, len(product.Variants))
+	for i := range product.Variants {
+		variant := &product.Variants[i]
+		attributes, combination, ok := matchAttributes(options, variant.Attributes)
+		if !ok {
+			return nil, Conflict(apperrors.CodeOptionInUse)
+		}
+		if seen[combination] {
+			return nil, Conflict(apperrors.CodeOptionInUse)
+		}
+		seen[combination] = true
+		variant.Attributes, variant.Combination = attributes, combination
+	}
+
+	product.Options = options
+	return s.saveVariants(ctx, actor, product)
+}
+
+// CreateVariant menambahkan varian baru di akhir daftar varian produk milik actor
+func (s *ProductService) CreateVariant(ctx context.Context, actor Actor, id uuid.UUID, input VariantInput) (*models.ProductResponse, error) {
+	product, err := s.findOwned(ctx, actor, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(product.Variants) >= models.MaxProductVariants {
+		return nil, Validation(apperrors.FieldError{Field: "variants", Code: "max_items", Param: strconv.Itoa(models.MaxProductVariants)})
+	}
+
+	var variant models.ProductVariant
+	if err := s.applyVariant(ctx, product, &variant, input); err != nil {
+		return nil, err
+	}
+	variant.Position = len(product.Variants)
+	product.Variants = append(product.Variants, variant)
+	return s.saveVariants(ctx, actor, product)
+}
+
+// UpdateVariant mengganti data varian produk milik actor
+func (s *ProductService) UpdateVariant(ctx context.Context, actor Actor, id, variantID uuid.UUID, input VariantInput) (*models.ProductResponse, error) {
+	product, err := s.findOwned(ctx, actor, id)
+	if err != nil {
+		return nil, err
+	}
+	index := variantIndex(product, variantID)
+	if index < 0 {
+		return nil, NotFound(apperrors.CodeVariantNotFound)
+	}
+
+	if err := s.applyVariant(ctx, product, &product.Variants[index], input); err != nil {
+		return nil, err
+	}
+	product.Variants[index].UpdatedAt = time.Now()
+	return s.saveVariants(ctx, actor, product)
+}
+
+// DeleteVariant menghapus varian produk milik actor
+func (s *ProductService) DeleteVariant(ctx context.Context, actor Actor, id, variantID uuid.UUID) (*models.ProductResponse, error) {
+	product, err := s.findOwned(ctx, actor, id)
+	if err != nil {
+		return nil, err
+	}
+	index := variantIndex(product, variantID)
+	if index < 0 {
+		return nil, NotFound(apperrors.CodeVariantNotFound)
+	}
+
+	product.Variants = append(product.Variants[:index], product.Variants[index+1:]...)
+	for i := range product.Variants {
+		product.Variants[i].Position = i
+	}
+	return s.saveVariants(ctx, actor, product)
+}
+
+// saveVariants menyimpan produk setelah option atau varian berubah
+func (s *ProductService) saveVariants(ctx context.Context, actor Actor, product *models.Product) (*models.ProductResponse, error) {
+	product.RefreshPriceRange()
+	if err := s.products.Update(ctx, product, newRevision(actor, models.RevisionUpdate, nil)); err != nil {
+		return nil, saveFailed(err, nil, "failed to update product variants")
+	}
+	s.indexProduct(ctx, product)
+
+	// Stok varian yang dihapus, atau stok produk setelah varian pertama dibuat, tidak berlaku lagi
+	if err := s.inventory.Prune(ctx, product.Id, stockKeys(product)); err != nil {
+		return nil, fmt.Errorf("failed to update product stock: %w", err)
+	}
+	product, err := s.withStock(ctx, product)
+	if err != nil {
+		return nil, err
+	}
+
+	response := models.NewProductResponse(*product)
+	return &response, nil
+}
+
+// applyVariant memvalidasi input lalu mengisinya ke variant. Kombinasi option
+// dan SKU tidak boleh sama dengan varian lain.
+func (s *ProductService) applyVariant(ctx context.Context, product *models.Product, variant *models.ProductVariant, input VariantInput) error {
+	var fields []apperrors.FieldError
+	sku := strings.ToUpper(strings.TrimSpace(input.SKU))
+	if sku == "" {
+		fields = append(fields, apperrors.FieldError{Field: "sku", Code: "required"})
+	}
+	var price *int64
+	if input.Price != nil {
+		resolved, priceFields := resolvePrice(*input.Price, product.Price.Currency)
+		fields = append(fields, priceFields...)
+		price = &resolved.Amount
+	}
+	if input.ImageID != nil && !hasImage(product, *input.ImageID) {
+		fields = append(fields, apperrors.FieldError{Field: "image_id", Code: "exists"})
+	}
+	attributes, combination, ok := matchAttributes(product.Options, input.Attributes)
+	if !ok {
+		fields = append(fields, apperrors.FieldError{Field: "attributes", Code: "variant_attributes"})
+	}
+	if len(fields) > 0 {
+		return Validation(fields...)
+	}
+
+	for _, other := range product.Variants {
+		if other.Id != variant.Id && other.Combination == combination {
+			return Conflict(apperrors.CodeVariantExists)
+		}
+	}
+	existing, err := s.products.FindVariantBySKU(ctx, sku)
+	if err != nil && !errors.Is(err, repositories.ErrNotFound) {
+		return fmt.Errorf("failed to check variant SKU: %w", err)
+	}
+	if existing != nil && (variant.Id == uuid.Nil || existing.Id != variant.Id) {
+		return Conflict(apperrors.CodeSKUTaken)
+	}
+
+	variant.SKU = sku
+	variant.Price = price
+	variant.ImageId = input.ImageID
+	variant.Attributes = attributes
+	variant.Combination = combination
+	return nil
+}
+
+// buildOptions merapikan input option. Nama option dan nilai dalam satu option
+// tidak boleh sama tanpa membedakan huruf besar/kecil.
+func buildOptions(inputs []OptionInput) ([]models.ProductOption, error) {
+	options := make([]models.ProductOption, 0, len(inputs))
+	names := make(map[string]bool, len(inputs))
+	for i, input := range inputs {
+		name := strings.Join(strings.Fields(input.Name), " ")
+		if name == "" {
+			return nil, Validation(apperrors.FieldError{Field: "options", Code: "required"})
+		}
+		if names[strings.ToLower(name)] {
+			return nil, Validation(apperrors.FieldError{Field: "options", Code: "duplicate", Param: name})
+		}
+		names[strings.ToLower(name)] = true
+
+		values := make([]string, 0, len(input.Values))
+		seen := make(map[string]bool, len(input.Values))
+		for _, value := range input.Values {
+			value = strings.Join(strings.Fields(value), " ")
+			if value == "" {
+				return nil, Validation(apperrors.FieldError{Field: "options", Code: "required"})
+			}
+			if seen[strings.ToLower(value)] {
+				return nil, Validation(apperrors.FieldError{Field: "options", Code: "duplicate", Param: value})
+			}
+			seen[strings.ToLower(value)] = true
+			values = append(values, value)
+		}
+		options = append(options, models.ProductOption{Name: name, Values: values, Position: i})
+	}
+	return options, nil
+}
+
+// matchAttributes mencocokkan nilai varian dengan option produk tanpa membedakan
+// huruf besar/kecil. Hasilnya memakai penulisan option, beserta Combination kanonik
+// (name=value dipisah | sesuai urutan option). ok false jika ada option yang
+// tidak diisi, nilai yang tidak diizinkan, atau atribut di luar option.
+func matchAttributes(options []models.ProductOption, input map[string]string) (map[string]string, string, bool) {
+	if len(input) != len(options) {
+		return nil, "", false
+	}
+	attributes := make(map[string]string, len(options))
+	parts := make([]string, 0, len(options))
+	for _, option := range options {
+		value, ok := lookupFold(input, option.Name)
+		if !ok {
+			return nil, "", false
+		}
+		canonical, ok := findFold(option.Values, strings.Join(strings.Fields(value), " "))
+		if !ok {
+			return nil, "", false
+		}
+		attributes[option.Name] = canonical
+		parts = append(parts, option.Name+"="+canonical)
+	}
+	return attributes, strings.Join(parts, "|"), true
+}
+
+func lookupFold(values map[string]string, key string) (string, bool) {
+	for k, v := range values {
+		if strings.EqualFold(strings.TrimSpace(k), key) {
+			return v, true
+		}
+	}
+	return "", false
+}
+
+func findFold(values []string, value string) (string, bool) {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return v, true
+		}
+	}
+	return "", false
+}
+
+func variantIndex(product *models.Product, id uuid.UUID) int {
+	for i, variant := range product.Variants {
+		if variant.Id == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func hasImage(product *models.Product, id uuid.UUID) bool {
+	for _, image := range product.Images {
+		if image.Id == id {
+			return true
+		}
+	}
+	return false
+}
+
+// detachVariantImages melepas gambar varian yang sudah tidak ada di produk
+func detachVariantImages(product *models.Product) {
+	for i, variant := range product.Variants {
+		if variant.ImageId != nil && !hasImage(product, *variant.ImageId) {
+			product.Variants[i].ImageId = nil
+		}
+	}
+}
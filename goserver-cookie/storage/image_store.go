@@ -0,0 +1,113 @@
+package storage
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/google/uuid"
+)
+
+// ImageURLPrefix adalah prefix path gambar yang disimpan di database,
+// dilayani oleh route static /uploads
+const ImageURLPrefix = "uploads"
+
+// ImageStore menyimpan dan menghapus file gambar produk. Gambar yang dilepas
+// dari produk tetapi masih tercatat di revisi diarsipkan: tidak lagi dilayani
+// publik, tetapi bisa dikembalikan saat rollback.
+type ImageStore interface {
+	UploadImage(filename string, content io.Reader) (string, error)
+	// DeleteImage menghapus gambar beserta arsipnya
+	DeleteImage(imagePath string) error
+	// ArchiveImage memindahkan gambar ke arsip
+	ArchiveImage(imagePath string) error
+	// RestoreImage mengembalikan gambar dari arsip, gambar yang masih ada dibiarkan
+	RestoreImage(imagePath string) error
+}
+
+// LocalImageStore menyimpan gambar di folder lokal
+type LocalImageStore struct {
+	dir        string
+	archiveDir string
+}
+
+var _ ImageStore = (*LocalImageStore)(nil)
+
+// NewLocalImageStore membuat ImageStore yang menyimpan file di dir dan arsipnya
+// di archiveDir. archiveDir tidak boleh berada di dalam dir agar tidak ikut dilayani.
+func NewLocalImageStore(dir, archiveDir string) *LocalImageStore {
+	return &LocalImageStore{dir: dir, archiveDir: archiveDir}
+}
+
+// UploadImage menyimpan file ke folder upload dan mengembalikan path untuk database
+func (s *LocalImageStore) UploadImage(filename string, content io.Reader) (string, error) {
+	//cek apakah directory upload ada apa tidak
+	if _, err := os.Stat(s.dir); os.IsNotExist(err) {
+		os.MkdirAll(s.dir, os.ModePerm)
+	}
+
+	//memberi name file
+	ext := filepath.Ext(filename)
+	uniqueFilename := uuid.New().String() + ext
+	filePath := filepath.Join(s.dir, uniqueFilename)
+
+	dst, err := os.Create(filePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to create destination file: %w", err)
+	}
+	defer dst.Close()
+
+	// Salin isi file ke lokasi tujuan
+	if _, err := io.Copy(dst, content); err != nil {
+		os.Remove(filePath)
+		return "", fmt.Errorf("failed to copy file content: %w", err)
+	}
+
+	return path.Join(ImageURLPrefix, uniqueFilename), nil
+}
+
+// DeleteImage menghapus file gambar berdasarkan path yang tersimpan di database,
+// termasuk salinannya di arsip. File yang sudah tidak ada dianggap berhasil dihapus.
+func (s *LocalImageStore) DeleteImage(imagePath string) error {
+	for _, file := range []string{s.FilePath(imagePath), s.archivePath(imagePath)} {
+		if err := os.Remove(file); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// ArchiveImage memindahkan file gambar ke folder arsip. File yang sudah tidak
+// ada di folder upload dianggap sudah diarsipkan.
+func (s *LocalImageStore) ArchiveImage(imagePath string) error {
+	if err := os.MkdirAll(s.archiveDir, os.ModePerm); err != nil {
+		return err
+	}
+	err := os.Rename(s.FilePath(imagePath), s.archivePath(imagePath))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// RestoreImage memindahkan file gambar dari folder arsip kembali ke folder upload
+func (s *LocalImageStore) RestoreImage(imagePath string) error {
+	if _, err := os.Stat(s.FilePath(imagePath)); err == nil {
+		return nil
+	}
+	if err := os.MkdirAll(s.dir, os.ModePerm); err != nil {
+		return err
+	}
+	return os.Rename(s.archivePath(imagePath), s.FilePath(imagePath))
+}
+
+// FilePath mengembalikan lokasi file di disk untuk path dari database
+func (s *LocalImageStore) FilePath(imagePath string) string {
+	return filepath.Join(s.dir, filepath.Base(imagePath))
+}
+
+func (s *LocalImageStore) archivePath(imagePath string) string {
+	return filepath.Join(s.archiveDir, filepath.Base(imagePath))
+}
@@ -0,0 +1,201 @@
+package validation
+
+import (
+	"bytes"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"path/filepath"
+	"reflect"
+	"server-cookie/apperrors"
+	"server-cookie/models"
+	"server-cookie/money"
+	"strconv"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+var validate = newValidator()
+
+// File adalah file upload yang isinya sudah ada di memory (misalnya dari gRPC)
+type File struct {
+	Name    string
+	Content []byte
+}
+
+// Struct memvalidasi struct berdasarkan tag validate.
+// Error yang dikembalikan adalah validation_failed beserta daftar field.
+func Struct(v any) error {
+	if err := validate.Struct(v); err != nil {
+		return validationError(err)
+	}
+	return nil
+}
+
+// newValidator membuat validator yang melaporkan nama field sesuai tag json/form
+// dan mendaftarkan validator custom sekali saja
+func newValidator() *validator.Validate {
+	v := validator.New()
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		for _, tag := range []string{"json", "form"} {
+			name := strings.SplitN(field.Tag.Get(tag), ",", 2)[0]
+			if name == "-" {
+				return ""
+			}
+			if name != "" {
+				return name
+			}
+		}
+		return field.Name
+	})
+
+	v.RegisterValidation("price", validatePrice)
+	v.RegisterValidation("currency", validateCurrency)
+	v.RegisterValidation("image_type", validateImageType)
+	v.RegisterValidation("max_file_size", validateMaxFileSize)
+	v.RegisterValidation("max_items", validateMaxItems)
+	return v
+}
+
+// validatePrice memastikan string berisi angka desimal positif. Jumlah digit desimal
+// dan rentang harga bergantung pada mata uang, sehingga diperiksa oleh service.
+func validatePrice(fl validator.FieldLevel) bool {
+	price := fl.Field().String()
+	return money.IsDecimal(price) && !strings.HasPrefix(strings.TrimSpace(price), "-")
+}
+
+// validateCurrency memastikan string adalah kode mata uang ISO 4217 yang diterima
+func validateCurrency(fl validator.FieldLevel) bool {
+	_, err := money.LookupCurrency(fl.Field().String())
+	return err == nil
+}
+
+// validateImageType memastikan ekstensi dan isi semua file adalah gambar yang diizinkan
+func validateImageType(fl validator.FieldLevel) bool {
+	files, ok := uploadsOf(fl)
+	if !ok {
+		return false
+	}
+	for _, file := range files {
+		if !isImage(file) {
+			return false
+		}
+	}
+	return true
+}
+
+func isImage(file upload) bool {
+	expected, ok := models.AllowedImageTypes[strings.ToLower(filepath.Ext(file.name))]
+	if !ok {
+		return false
+	}
+
+	// Cek isi file, bukan hanya ekstensi atau Content-Type dari client
+	src, err := file.open()
+	if err != nil {
+		return false
+	}
+	defer src.Close()
+
+	head := make([]byte, 512)
+	n, _ := io.ReadFull(src, head)
+	return http.DetectContentType(head[:n]) == expected
+}
+
+// validateMaxFileSize memastikan ukuran setiap file tidak melebihi param (byte)
+func validateMaxFileSize(fl validator.FieldLevel) bool {
+	files, ok := uploadsOf(fl)
+	if !ok {
+		return false
+	}
+	limit, err := strconv.ParseInt(fl.Param(), 10, 64)
+	if err != nil {
+		return false
+	}
+	for _, file := range files {
+		if file.size > limit {
+			return false
+		}
+	}
+	return true
+}
+
+// validateMaxItems memastikan slice berisi paling banyak param elemen.
+// Berbeda dengan max, pesan error-nya menyebut jumlah item, bukan karakter.
+func validateMaxItems(fl validator.FieldLevel) bool {
+	limit, err := strconv.Atoi(fl.Param())
+	if err != nil {
+		return false
+	}
+	return fl.Field().Len() <= limit
+}
+
+// upload menyamakan multipart.FileHeader dan File untuk validator file
+type upload struct {
+	name string
+	size int64
+	open func() (io.ReadCloser, error)
+}
+
+// uploadsOf mengambil file dari field berupa satu file atau slice file,
+// sehingga validator file berlaku untuk setiap file di slice
+func uploadsOf(fl validator.FieldLevel) ([]upload, bool) {
+	field := fl.Field()
+	if field.Kind() != reflect.Slice {
+		file, ok := uploadOf(field)
+		return []upload{file}, ok
+	}
+	files := make([]upload, 0, field.Len())
+	for i := 0; i < field.Len(); i++ {
+		file, ok := uploadOf(field.Index(i))
+		if !ok {
+			return nil, false
+		}
+		files = append(files, file)
+	}
+	return files, true
+}
+
+func uploadOf(field reflect.Value) (upload, bool) {
+	if field.Kind() == reflect.Ptr {
+		field = field.Elem()
+	}
+	if !field.IsValid() {
+		return upload{}, false
+	}
+
+	switch file := field.Interface().(type) {
+	case multipart.FileHeader:
+		return upload{
+			name: file.Filename,
+			size: file.Size,
+			open: func() (io.ReadCloser, error) { return file.Open() },
+		}, true
+	case File:
+		return upload{
+			name: file.Name,
+			size: int64(len(file.Content)),
+			open: func() (io.ReadCloser, error) { return io.NopCloser(bytes.NewReader(file.Content)), nil },
+		}, true
+	}
+	return upload{}, false
+}
+
+// validationError mengubah error validator menjadi error validation_failed per field
+func validationError(err error) error {
+	validationErrors, ok := err.(validator.ValidationErrors)
+	if !ok {
+		return apperrors.Wrap(apperrors.CodeValidationFailed, err)
+	}
+
+	fields := make([]apperrors.FieldError, 0, len(validationErrors))
+	for _, e := range validationErrors {
+		fields = append(fields, apperrors.FieldError{
+			Field: e.Field(),
+			Code:  e.Tag(),
+			Param: e.Param(),
+		})
+	}
+	return apperrors.Validation(fields...)
+}
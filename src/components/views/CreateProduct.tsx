@@ -145,7 +145,7 @@ const CreateProduct: React.FC = () => {
       setLoading(true);
       setErrors({});
 
-      const response = await fetch("http://localhost:8080/products", {
+      const response = await fetch("http://localhost:8080/api/v1/products", {
         method: "POST",
         body: submitFormData,
         credentials: "include",
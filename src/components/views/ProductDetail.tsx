@@ -1,11 +1,11 @@
 import { useEffect, useState } from "react";
 import { useParams, useNavigate, Link } from "react-router-dom";
-import { formatRupiah } from "../../utils/format-currency";
+import { formatPrice, type Money } from "../../utils/format-currency";
 
 interface Product {
   id: string;
   name: string;
-  price: number;
+  price: Money;
   image?: string;
   description?: string;
 }
@@ -20,7 +20,7 @@ export default function ProductDetail() {
   useEffect(() => {
     const fetchProduct = async () => {
       try {
-        const response = await fetch(`http://localhost:8080/products/${id}`, {
+        const response = await fetch(`http://localhost:8080/api/v1/products/${id}`, {
           method: "GET",
           credentials: "include",
         });
@@ -48,7 +48,7 @@ export default function ProductDetail() {
       return;
 
     try {
-      const response = await fetch(`http://localhost:8080/products/${id}`, {
+      const response = await fetch(`http://localhost:8080/api/v1/products/${id}`, {
         method: "DELETE",
         credentials: "include",
       });
@@ -69,7 +69,7 @@ export default function ProductDetail() {
   return (
     <div className="max-w-2xl mx-auto p-6 bg-white shadow-lg rounded-2xl mt-32">
       <h2 className="text-3xl font-semibold mb-6">{product.name}</h2>
-      <p className="text-gray-700 mb-4">{formatRupiah(product.price)}</p>
+      <p className="text-gray-700 mb-4">{formatPrice(product.price)}</p>
       {product.image && (
         <img
           src={`http://localhost:8080/${product.image}`}
@@ -1,12 +1,12 @@
 import { useState, useEffect } from "react";
 import { ClipLoader } from "react-spinners";
-import { formatRupiah } from "../../utils/format-currency";
+import { formatPrice, type Money } from "../../utils/format-currency";
 import { Link } from "react-router";
 
 interface Product {
   id: string;
   name: string;
-  price: number;
+  price: Money;
   image?: string;
   description?: string;
 }
@@ -19,7 +19,7 @@ export default function ProductsPage() {
   useEffect(() => {
     const fetchProducts = async () => {
       try {
-        const response = await fetch("http://localhost:8080/products", {
+        const response = await fetch("http://localhost:8080/api/v1/products", {
           method: "GET",
           credentials: "include",
         });
@@ -84,7 +84,7 @@ export default function ProductsPage() {
                 <h3 className="text-md font-semibold text-indigo-800 mt-4">
                   {product.name}
                 </h3>
-                <p className="text-gray-700">{formatRupiah(product.price)}</p>
+                <p className="text-gray-700">{formatPrice(product.price)}</p>
               </Link>
             </div>
           ))}
@@ -30,7 +30,7 @@ export default function Profile() {
   useEffect(() => {
     const fetchProfile = async () => {
       try {
-        const response = await fetch(`http://localhost:8080/profile/${id}`, {
+        const response = await fetch(`http://localhost:8080/api/v1/profile/${id}`, {
           method: "GET",
           credentials: "include",
         });
@@ -82,7 +82,7 @@ export default function Profile() {
         ? { ...updatedProfile, password }
         : updatedProfile;
 
-      const response = await fetch(`http://localhost:8080/profile/${id}`, {
+      const response = await fetch(`http://localhost:8080/api/v1/profile/${id}`, {
         method: "PUT",
         headers: { "Content-Type": "application/json" },
         body: JSON.stringify(payload),
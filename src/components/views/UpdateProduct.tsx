@@ -24,7 +24,7 @@ export default function UpdateProduct() {
   useEffect(() => {
     const fetchProduct = async () => {
       try {
-        const response = await fetch(`http://localhost:8080/products/${id}`, {
+        const response = await fetch(`http://localhost:8080/api/v1/products/${id}`, {
           method: "GET",
           credentials: "include",
         });
@@ -35,7 +35,7 @@ export default function UpdateProduct() {
 
         const data = await response.json();
         setName(data.product.name);
-        setPrice(data.product.price.toString());
+        setPrice(data.product.price.amount);
       } catch (error) {
         console.error("Gagal memuat produk:", error);
         alert("Gagal memuat produk.");
@@ -63,7 +63,7 @@ export default function UpdateProduct() {
 
     try {
       setLoading(true);
-      const response = await fetch(`http://localhost:8080/products/${id}`, {
+      const response = await fetch(`http://localhost:8080/api/v1/products/${id}`, {
         method: "PUT",
         body: formData,
         credentials: "include",
@@ -55,7 +55,7 @@ export const AuthProvider = ({ children }: AuthProviderProps) => {
     const password = (form.elements.namedItem("password") as HTMLInputElement)
       .value;
 
-    const response = await fetch("http://localhost:8080/login", {
+    const response = await fetch("http://localhost:8080/api/v1/login", {
       method: "POST",
       headers: {
         "Content-Type": "application/json",
@@ -77,7 +77,7 @@ export const AuthProvider = ({ children }: AuthProviderProps) => {
   };
 
   const logoutUser = async () => {
-    await fetch("http://localhost:8080/logout", {
+    await fetch("http://localhost:8080/api/v1/logout", {
       method: "GET",
       headers: {
         "Content-Type": "application/json",
@@ -1,7 +1,12 @@
-export function formatRupiah(amount: number): string {
+export interface Money {
+  amount: string;
+  currency: string;
+  minor_units: number;
+}
+
+export function formatPrice(price: Money): string {
   return new Intl.NumberFormat("id-ID", {
     style: "currency",
-    currency: "IDR",
-    minimumFractionDigits: 0,
-  }).format(amount);
+    currency: price.currency,
+  }).format(Number(price.amount));
 }